@@ -6,17 +6,24 @@
 package app
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/DataDog/datadog-agent/cmd/agent/common"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/diagnose"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/connectivity"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
+var diagnoseJSON bool
+
 func init() {
+	diagnoseConnectivityCommand.Flags().BoolVarP(&diagnoseJSON, "json", "j", false, "print out the results as JSON")
+	diagnoseCommand.AddCommand(diagnoseConnectivityCommand)
 	AgentCmd.AddCommand(diagnoseCommand)
 }
 
@@ -27,7 +34,49 @@ var diagnoseCommand = &cobra.Command{
 	RunE:  doDiagnose,
 }
 
+var diagnoseConnectivityCommand = &cobra.Command{
+	Use:   "connectivity",
+	Short: "Check the connectivity to every Datadog endpoint the agent sends data to",
+	Long: `Check the DNS resolution, TCP connection, proxy tunnel, TLS handshake and API key
+validity for the forwarder, logs, APM, process and orchestrator endpoints`,
+	RunE: doDiagnoseConnectivity,
+}
+
 func doDiagnose(cmd *cobra.Command, args []string) error {
+	if err := setupDiagnose(); err != nil {
+		return err
+	}
+
+	return diagnose.RunAll(color.Output)
+}
+
+func doDiagnoseConnectivity(cmd *cobra.Command, args []string) error {
+	if err := setupDiagnose(); err != nil {
+		return err
+	}
+
+	report, err := connectivity.Run(context.Background(), connectivity.DefaultOptions())
+	if err != nil {
+		return err
+	}
+
+	if diagnoseJSON {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(color.Output, string(out))
+	} else {
+		report.WriteText(color.Output)
+	}
+
+	if failures := report.Failures(); failures > 0 {
+		return fmt.Errorf("%d out of %d endpoints could not be reached", failures, len(report.Results))
+	}
+	return nil
+}
+
+func setupDiagnose() error {
 	// Global config setup
 	err := common.SetupConfig(confFilePath)
 	if err != nil {
@@ -38,9 +87,15 @@ func doDiagnose(cmd *cobra.Command, args []string) error {
 		color.NoColor = true
 	}
 
+	logLevel := config.Datadog.GetString("log_level")
+	if diagnoseJSON {
+		// keep the output parseable
+		logLevel = "off"
+	}
+
 	err = config.SetupLogger(
 		loggerName,
-		config.Datadog.GetString("log_level"),
+		logLevel,
 		common.DefaultLogFile,
 		config.GetSyslogURI(),
 		config.Datadog.GetBool("syslog_rfc"),
@@ -50,6 +105,5 @@ func doDiagnose(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("Error while setting up logging, exiting: %v", err)
 	}
-
-	return diagnose.RunAll(color.Output)
+	return nil
 }
@@ -9,7 +9,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -30,6 +32,8 @@ var (
 	profileMutexFraction int
 	profileBlocking      bool
 	profileBlockingRate  int
+	listProviders        bool
+	onlyProviders        []string
 )
 
 func init() {
@@ -43,6 +47,8 @@ func init() {
 	flareCmd.Flags().IntVarP(&profileMutexFraction, "profile-mutex-fraction", "", 100, "Set the fraction of mutex contention events that are reported in the mutex profile")
 	flareCmd.Flags().BoolVarP(&profileBlocking, "profile-blocking", "B", false, "Add gorouting blocking profile to the performance data in the flare")
 	flareCmd.Flags().IntVarP(&profileBlockingRate, "profile-blocking-rate", "", 10000, "Set the fraction of goroutine blocking events that are reported in the blocking profile")
+	flareCmd.Flags().BoolVarP(&listProviders, "list", "", false, "List the providers contributing to the flare, which can be selected with --only")
+	flareCmd.Flags().StringSliceVarP(&onlyProviders, "only", "", nil, "Restrict the flare to the given comma-separated list of providers")
 	flareCmd.SetArgs([]string{"caseID"})
 }
 
@@ -56,6 +62,13 @@ var flareCmd = &cobra.Command{
 			color.NoColor = true
 		}
 
+		if listProviders {
+			for _, name := range flare.ProviderNames() {
+				fmt.Println(name)
+			}
+			return nil
+		}
+
 		err := common.SetupConfig(confFilePath)
 		if err != nil {
 			return fmt.Errorf("unable to set up global agent configuration: %v", err)
@@ -186,6 +199,9 @@ func requestArchive(logFiles []string, pdata flare.ProfileData) (string, error)
 	}
 
 	urlstr := fmt.Sprintf("https://%v:%v/agent/flare", ipcAddress, config.Datadog.GetInt("cmd_port"))
+	if len(onlyProviders) > 0 {
+		urlstr += "?providers=" + url.QueryEscape(strings.Join(onlyProviders, ","))
+	}
 
 	// Set session token
 	e = util.SetAuthToken()
@@ -216,7 +232,7 @@ func requestArchive(logFiles []string, pdata flare.ProfileData) (string, error)
 
 func createArchive(logFiles []string, pdata flare.ProfileData, ipcError error) (string, error) {
 	fmt.Fprintln(color.Output, color.YellowString("Initiating flare locally."))
-	filePath, e := flare.CreateArchive(true, common.GetDistPath(), common.PyChecksPath, logFiles, pdata, ipcError)
+	filePath, e := flare.CreateArchive(true, common.GetDistPath(), common.PyChecksPath, logFiles, pdata, ipcError, onlyProviders)
 	if e != nil {
 		fmt.Printf("The flare zipfile failed to be created: %s\n", e)
 		return "", e
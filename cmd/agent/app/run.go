@@ -39,6 +39,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/metadata"
 	"github.com/DataDog/datadog-agent/pkg/metadata/host"
 	"github.com/DataDog/datadog-agent/pkg/metadata/inventories"
+	"github.com/DataDog/datadog-agent/pkg/netflow"
 	"github.com/DataDog/datadog-agent/pkg/otlp"
 	"github.com/DataDog/datadog-agent/pkg/pidfile"
 	"github.com/DataDog/datadog-agent/pkg/snmp/traps"
@@ -67,6 +68,7 @@ import (
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/embed"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/net"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/nvidia/jetson"
+	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/openmetrics"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/cpu"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/disk"
@@ -76,6 +78,9 @@ import (
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/winproc"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/systemd"
 
+	// register the exec check loader
+	_ "github.com/DataDog/datadog-agent/pkg/collector/exec"
+
 	// register metadata providers
 	_ "github.com/DataDog/datadog-agent/pkg/collector/metadata"
 	_ "github.com/DataDog/datadog-agent/pkg/metadata"
@@ -258,17 +263,17 @@ func StartAgent() error {
 
 	// Setup Internal Profiling
 	if v := config.Datadog.GetInt("internal_profiling.block_profile_rate"); v > 0 {
-		if err := settings.SetRuntimeSetting("runtime_block_profile_rate", v); err != nil {
+		if err := settings.SetRuntimeSetting("runtime_block_profile_rate", v, config.SourceAgentRuntime); err != nil {
 			log.Errorf("Error setting block profile rate: %v", err)
 		}
 	}
 	if v := config.Datadog.GetInt("internal_profiling.mutex_profile_fraction"); v > 0 {
-		if err := settings.SetRuntimeSetting("runtime_mutex_profile_fraction", v); err != nil {
+		if err := settings.SetRuntimeSetting("runtime_mutex_profile_fraction", v, config.SourceAgentRuntime); err != nil {
 			log.Errorf("Error mutex profile fraction: %v", err)
 		}
 	}
 	if config.Datadog.GetBool("internal_profiling.enabled") {
-		err := settings.SetRuntimeSetting("internal_profiling", true)
+		err := settings.SetRuntimeSetting("internal_profiling", true, config.SourceAgentRuntime)
 		if err != nil {
 			log.Errorf("Error starting profiler: %v", err)
 		}
@@ -388,7 +393,7 @@ func StartAgent() error {
 	inventories.SetAgentMetadata(inventories.AgentOTLPEnabled, otlpEnabled)
 	if otlpEnabled {
 		var err error
-		common.OTLP, err = otlp.BuildAndStart(common.MainCtx, config.Datadog, demux.Serializer())
+		common.OTLP, err = otlp.BuildAndStart(common.MainCtx, config.Datadog, demux.Serializer(), logs.GetOTLPChannel())
 		if err != nil {
 			log.Errorf("Could not start OTLP: %s", err)
 		} else {
@@ -411,6 +416,13 @@ func StartAgent() error {
 		}
 	}
 
+	// Start NetFlow server
+	if netflow.IsEnabled() {
+		if err = netflow.StartServer(demux); err != nil {
+			log.Errorf("Failed to start NetFlow server: %s", err)
+		}
+	}
+
 	// start logs-agent
 	if config.Datadog.GetBool("logs_enabled") || config.Datadog.GetBool("log_enabled") {
 		if config.Datadog.GetBool("log_enabled") {
@@ -453,6 +465,12 @@ func StartAgent() error {
 		}
 	}
 
+	if config.Datadog.GetBool("software_inventory.enabled") {
+		if err := metadata.SetupSoftwareInventory(common.MetadataScheduler); err != nil {
+			return err
+		}
+	}
+
 	// start dependent services
 	go startDependentServices()
 
@@ -486,6 +504,7 @@ func StopAgent() {
 		common.MetadataScheduler.Stop()
 	}
 	traps.StopServer()
+	netflow.StopServer()
 	api.StopServer()
 	clcrunnerapi.StopCLCRunnerServer()
 	jmx.StopJmxfetch()
@@ -8,6 +8,8 @@ package settings
 import (
 	"fmt"
 	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
 )
 
 // DsdCaptureDurationRuntimeSetting wraps operations to change the duration, in seconds, of traffic captures
@@ -35,7 +37,7 @@ func (l DsdCaptureDurationRuntimeSetting) Get() (interface{}, error) {
 }
 
 // Set changes the value of the runtime setting
-func (l DsdCaptureDurationRuntimeSetting) Set(v interface{}) error {
+func (l DsdCaptureDurationRuntimeSetting) Set(v interface{}, source config.Source) error {
 	var err error
 
 	s, ok := v.(string)
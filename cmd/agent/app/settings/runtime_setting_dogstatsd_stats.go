@@ -38,7 +38,7 @@ func (s DsdStatsRuntimeSetting) Get() (interface{}, error) {
 }
 
 // Set changes the value of the runtime setting
-func (s DsdStatsRuntimeSetting) Set(v interface{}) error {
+func (s DsdStatsRuntimeSetting) Set(v interface{}, source config.Source) error {
 	var newValue bool
 	var err error
 
@@ -52,6 +52,6 @@ func (s DsdStatsRuntimeSetting) Set(v interface{}) error {
 		common.DSD.DisableMetricsStats()
 	}
 
-	config.Datadog.Set("dogstatsd_metrics_stats_enable", newValue)
+	config.Datadog.SetWithSource("dogstatsd_metrics_stats_enable", newValue, source)
 	return nil
 }
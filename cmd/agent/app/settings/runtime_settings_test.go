@@ -11,6 +11,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/cmd/agent/common"
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/dogstatsd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -32,7 +33,7 @@ func TestDogstatsdMetricsStats(t *testing.T) {
 
 	// true string
 
-	err = s.Set("true")
+	err = s.Set("true", config.SourceCLI)
 	assert.Nil(err)
 	assert.Equal(atomic.LoadUint64(&common.DSD.Debug.Enabled), uint64(1))
 	v, err := s.Get()
@@ -41,7 +42,7 @@ func TestDogstatsdMetricsStats(t *testing.T) {
 
 	// false string
 
-	err = s.Set("false")
+	err = s.Set("false", config.SourceCLI)
 	assert.Nil(err)
 	assert.Equal(atomic.LoadUint64(&common.DSD.Debug.Enabled), uint64(0))
 	v, err = s.Get()
@@ -50,7 +51,7 @@ func TestDogstatsdMetricsStats(t *testing.T) {
 
 	// true boolean
 
-	err = s.Set(true)
+	err = s.Set(true, config.SourceCLI)
 	assert.Nil(err)
 	assert.Equal(atomic.LoadUint64(&common.DSD.Debug.Enabled), uint64(1))
 	v, err = s.Get()
@@ -59,7 +60,7 @@ func TestDogstatsdMetricsStats(t *testing.T) {
 
 	// false boolean
 
-	err = s.Set(false)
+	err = s.Set(false, config.SourceCLI)
 	assert.Nil(err)
 	assert.Equal(atomic.LoadUint64(&common.DSD.Debug.Enabled), uint64(0))
 	v, err = s.Get()
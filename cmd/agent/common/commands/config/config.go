@@ -15,12 +15,17 @@ import (
 
 // Config returns the main cobra config command.
 func Config(getClient settings.ClientBuilder) *cobra.Command {
+	var withSources bool
+
 	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Print the runtime configuration of a running agent",
 		Long:  ``,
-		RunE:  func(cmd *cobra.Command, args []string) error { return showRuntimeConfiguration(getClient, cmd, args) },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showRuntimeConfiguration(getClient, withSources, cmd, args)
+		},
 	}
+	cmd.Flags().BoolVar(&withSources, "with-sources", false, "print the source of every setting, and the unknown and deprecated keys")
 
 	cmd.AddCommand(listRuntime(getClient))
 	cmd.AddCommand(set(getClient))
@@ -61,13 +66,18 @@ func get(getClient settings.ClientBuilder) *cobra.Command {
 	}
 }
 
-func showRuntimeConfiguration(getClient settings.ClientBuilder, cmd *cobra.Command, args []string) error {
+func showRuntimeConfiguration(getClient settings.ClientBuilder, withSources bool, cmd *cobra.Command, args []string) error {
 	c, err := getClient(cmd, args)
 	if err != nil {
 		return err
 	}
 
-	runtimeConfig, err := c.FullConfig()
+	var runtimeConfig string
+	if withSources {
+		runtimeConfig, err = c.FullConfigWithSources()
+	} else {
+		runtimeConfig, err = c.FullConfig()
+	}
 	if err != nil {
 		return err
 	}
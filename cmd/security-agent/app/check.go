@@ -10,6 +10,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/compliance/agent"
 	"github.com/DataDog/datadog-agent/pkg/compliance/checks"
 	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/export"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/logs/restart"
@@ -38,6 +40,8 @@ var (
 		overrideRegoInput string
 		dumpRegoInput     string
 		dumpReports       string
+		exportPath        string
+		exportFormat      string
 	}{}
 )
 
@@ -49,6 +53,8 @@ func setupCheckCmd(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&checkArgs.overrideRegoInput, "override-rego-input", "", "", "Rego input to use when running rego checks")
 	cmd.Flags().StringVarP(&checkArgs.dumpRegoInput, "dump-rego-input", "", "", "Path to file where to dump the Rego input JSON")
 	cmd.Flags().StringVarP(&checkArgs.dumpReports, "dump-reports", "", "", "Path to file where to dump reports")
+	cmd.Flags().StringVarP(&checkArgs.exportPath, "export-path", "", "", "Path to file where to export the results")
+	cmd.Flags().StringVarP(&checkArgs.exportFormat, "export-format", "", string(export.FormatJSON), "Format of the exported results (json, junit or sarif)")
 }
 
 // CheckCmd returns a cobra command to run security agent checks
@@ -131,6 +137,15 @@ func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
 		return err
 	}
 
+	if checkArgs.exportPath != "" {
+		format, err := export.ParseFormat(checkArgs.exportFormat)
+		if err != nil {
+			return err
+		}
+		reporter.exportPath = checkArgs.exportPath
+		reporter.exportFormat = format
+	}
+
 	if ruleID != "" {
 		log.Infof("Looking for rule with ID=%s", ruleID)
 		options = append(options, checks.WithMatchRule(checks.IsRuleID(ruleID)))
@@ -167,6 +182,15 @@ func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
 		return err
 	}
 
+	if err := reporter.exportReports(); err != nil {
+		log.Errorf("Failed to export reports %v", err)
+		return err
+	}
+
+	if export.HasFailures(reporter.allEvents()) {
+		return errors.New("some compliance rules did not pass")
+	}
+
 	return nil
 }
 
@@ -193,6 +217,8 @@ type RunCheckReporter struct {
 	reporter        event.Reporter
 	events          map[string][]*event.Event
 	dumpReportsPath string
+	exportPath      string
+	exportFormat    export.Format
 }
 
 func NewCheckReporter(stopper restart.Stopper, report bool, dumpReportsPath string) (*RunCheckReporter, error) {
@@ -251,6 +277,21 @@ func (r *RunCheckReporter) dumpReports() error {
 	return nil
 }
 
+func (r *RunCheckReporter) allEvents() []*event.Event {
+	var events []*event.Event
+	for _, ruleEvents := range r.events {
+		events = append(events, ruleEvents...)
+	}
+	return events
+}
+
+func (r *RunCheckReporter) exportReports() error {
+	if r.exportPath != "" {
+		return export.WriteFile(r.exportPath, r.exportFormat, r.allEvents())
+	}
+	return nil
+}
+
 func init() {
 	complianceCmd.AddCommand(CheckCmd(func() []string {
 		return confPathArray
@@ -18,6 +18,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/compliance/agent"
 	"github.com/DataDog/datadog-agent/pkg/compliance/checks"
 	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/export"
 	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/logs"
 	"github.com/DataDog/datadog-agent/pkg/logs/client"
@@ -117,6 +118,17 @@ func startCompliance(hostname string, stopper restart.Stopper, statsdClient *ddg
 		return nil, err
 	}
 
+	var exportReporter *export.Reporter
+	if exportPath := coreconfig.Datadog.GetString("compliance_config.export.path"); exportPath != "" {
+		format, err := export.ParseFormat(coreconfig.Datadog.GetString("compliance_config.export.format"))
+		if err != nil {
+			return nil, err
+		}
+		log.Infof("Exporting compliance results to %s using %s format", exportPath, format)
+		exportReporter = export.NewReporter(reporter, exportPath, format)
+		reporter = exportReporter
+	}
+
 	runner := runner.NewRunner()
 	stopper.Add(runner)
 
@@ -163,6 +175,12 @@ func startCompliance(hostname string, stopper restart.Stopper, statsdClient *ddg
 	}
 	stopper.Add(agent)
 
+	if exportReporter != nil {
+		// stopped after the agent, to write the last results
+		exportReporter.Start()
+		stopper.Add(exportReporter)
+	}
+
 	log.Infof("Running compliance checks every %s", checkInterval.String())
 
 	// Send the compliance 'running' metrics periodically
@@ -16,6 +16,7 @@ package app
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -35,6 +36,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
 	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
 	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules/tester"
 	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	ddgostatsd "github.com/DataDog/datadog-go/statsd"
@@ -60,6 +62,17 @@ var (
 		dir string
 	}{}
 
+	testPoliciesCmd = &cobra.Command{
+		Use:   "test-policies",
+		Short: "Evaluate policies against a file of recorded or handwritten events and return a report",
+		RunE:  testPolicies,
+	}
+
+	testPoliciesArgs = struct {
+		dir    string
+		events string
+	}{}
+
 	dumpCmd = &cobra.Command{
 		Use:   "dump",
 		Short: "Dump security module information",
@@ -96,6 +109,11 @@ func init() {
 	runtimeCmd.AddCommand(checkPoliciesCmd)
 	checkPoliciesCmd.Flags().StringVar(&checkPoliciesArgs.dir, "policies-dir", coreconfig.DefaultRuntimePoliciesDir, "Path to policies directory")
 
+	runtimeCmd.AddCommand(testPoliciesCmd)
+	testPoliciesCmd.Flags().StringVar(&testPoliciesArgs.dir, "policies-dir", coreconfig.DefaultRuntimePoliciesDir, "Path to policies directory")
+	testPoliciesCmd.Flags().StringVar(&testPoliciesArgs.events, "events", "", "Path to a JSON file containing the events to evaluate")
+	_ = testPoliciesCmd.MarkFlagRequired("events")
+
 	runtimeCmd.AddCommand(selfTestCmd)
 	runtimeCmd.AddCommand(reloadPoliciesCmd)
 }
@@ -164,6 +182,47 @@ func checkPolicies(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func testPolicies(cmd *cobra.Command, args []string) error {
+	// enabled all the rules
+	enabled := map[eval.EventType]bool{"*": true}
+
+	var opts rules.Opts
+	opts.
+		WithConstants(model.SECLConstants).
+		WithVariables(model.SECLVariables).
+		WithVariableScopers(model.SECLVariableScopers).
+		WithSupportedDiscarders(sprobe.SupportedDiscarders).
+		WithEventTypeEnabled(enabled).
+		WithReservedRuleIDs(sprobe.AllCustomRuleIDs()).
+		WithLegacyFields(model.SECLLegacyFields).
+		WithLogger(&seclog.PatternLogger{})
+
+	policyTester, loadErr := tester.NewTester(testPoliciesArgs.dir, &opts)
+	if loadErr.ErrorOrNil() != nil {
+		return loadErr
+	}
+
+	f, err := os.Open(testPoliciesArgs.events)
+	if err != nil {
+		return errors.Wrap(err, "unable to open events file")
+	}
+	defer f.Close()
+
+	report, err := policyTester.TestReader(f)
+	if err != nil {
+		return err
+	}
+
+	content, _ := json.MarshalIndent(report, "", "\t")
+	fmt.Printf("%s\n", string(content))
+
+	if !report.Passed {
+		return errors.New("some events didn't match the expected rules")
+	}
+
+	return nil
+}
+
 func runRuntimeSelfTest(cmd *cobra.Command, args []string) error {
 	client, err := secagent.NewRuntimeSecurityClient()
 	if err != nil {
@@ -208,7 +267,7 @@ func newRuntimeReporter(stopper restart.Stopper, sourceName, sourceType string,
 	stopper.Add(auditor)
 
 	// setup the pipeline provider that provides pairs of processor and sender
-	pipelineProvider := pipeline.NewProvider(config.NumberOfPipelines, auditor, &diagnostic.NoopMessageReceiver{}, nil, endpoints, context)
+	pipelineProvider := pipeline.NewProvider(config.NumberOfPipelines, auditor, &diagnostic.NoopMessageReceiver{}, nil, nil, endpoints, context)
 	pipelineProvider.Start()
 	stopper.Add(pipelineProvider)
 
@@ -14,6 +14,7 @@ import (
 // setupConfigHandlers adds the specific handlers for /config endpoints
 func setupConfigHandlers(r *mux.Router) {
 	r.HandleFunc("/config", settingshttp.Server.GetFull(config.Namespace)).Methods("GET")
+	r.HandleFunc("/config/sources", settingshttp.Server.GetFullWithSources(config.Namespace)).Methods("GET")
 	r.HandleFunc("/config/list-runtime", settingshttp.Server.ListConfigurable).Methods("GET")
 	r.HandleFunc("/config/{setting}", settingshttp.Server.GetValue).Methods("GET")
 	r.HandleFunc("/config/{setting}", settingshttp.Server.SetValue).Methods("POST")
@@ -15,6 +15,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -90,6 +91,22 @@ func (nt *networkTracer) Register(httpMux *module.Router) error {
 		logRequests(id, count, len(cs.Conns), start)
 	}))
 
+	// /dns/domains returns the DNS stats aggregated by domain and server since the last request of the client.
+	// an optional ?top= argument limits the response to the N most queried domains
+	httpMux.HandleFunc("/dns/domains", func(w http.ResponseWriter, req *http.Request) {
+		var top int
+		if rawTop := req.URL.Query().Get("top"); rawTop != "" {
+			var err error
+			if top, err = strconv.Atoi(rawTop); err != nil {
+				log.Errorf("invalid top parameter %q: %s", rawTop, err)
+				w.WriteHeader(400)
+				return
+			}
+		}
+
+		utils.WriteAsJSON(w, nt.tracer.GetDNSDomainStats(getClientID(req), top))
+	})
+
 	httpMux.HandleFunc("/debug/net_maps", func(w http.ResponseWriter, req *http.Request) {
 		cs, err := nt.tracer.DebugNetworkMaps()
 		if err != nil {
@@ -546,6 +546,12 @@ func (ac *AutoConfig) resolveTemplate(tpl integration.Config) []integration.Conf
 // resolveTemplateForService calls the config resolver for the template against the service,
 // decrypts secrets and stores the resolved config and service mapping if successful
 func (ac *AutoConfig) resolveTemplateForService(tpl integration.Config, svc listeners.Service) (integration.Config, error) {
+	if tpl.ServiceNamespace != "" {
+		if metadataSvc, ok := svc.(listeners.MetadataService); !ok || metadataSvc.GetNamespace() != tpl.ServiceNamespace {
+			log.Debugf("Template %s is restricted to the services of namespace %s, ignoring service %s", tpl.Name, tpl.ServiceNamespace, svc.GetEntity())
+			return tpl, fmt.Errorf("service %s is not in namespace %s", svc.GetEntity(), tpl.ServiceNamespace)
+		}
+	}
 	config, tagsHash, err := configresolver.Resolve(tpl, svc)
 	if err != nil {
 		newErr := fmt.Errorf("error resolving template %s for service %s: %v", tpl.Name, svc.GetEntity(), err)
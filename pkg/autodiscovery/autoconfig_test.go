@@ -25,6 +25,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/scheduler"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/retry"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
 type MockProvider struct {
@@ -325,6 +326,40 @@ func TestResolveTemplate(t *testing.T) {
 	assert.Len(t, res, 1)
 }
 
+// namespacedService is a service of a container running in a kubernetes namespace
+type namespacedService struct {
+	dummyService
+	namespace string
+}
+
+func (s *namespacedService) GetLabels() map[string]string      { return nil }
+func (s *namespacedService) GetAnnotations() map[string]string { return nil }
+func (s *namespacedService) GetNamespace() string              { return s.namespace }
+func (s *namespacedService) GetPodName() string                { return "" }
+func (s *namespacedService) GetPodUID() string                 { return "" }
+func (s *namespacedService) GetImage() (workloadmeta.ContainerImage, bool) {
+	return workloadmeta.ContainerImage{}, false
+}
+
+func TestResolveTemplateServiceNamespace(t *testing.T) {
+	ctx := context.Background()
+
+	ac := NewAutoConfig(scheduler.NewMetaScheduler())
+	tpl := integration.Config{
+		Name:             "redisdb",
+		ADIdentifiers:    []string{"redis"},
+		ServiceNamespace: "team-a",
+	}
+
+	ac.processNewService(ctx, &namespacedService{dummyService: dummyService{ID: "docker://a", ADIdentifiers: []string{"redis"}}, namespace: "team-a"})
+	ac.processNewService(ctx, &namespacedService{dummyService: dummyService{ID: "docker://b", ADIdentifiers: []string{"redis"}}, namespace: "team-b"})
+	ac.processNewService(ctx, &dummyService{ID: "docker://c", ADIdentifiers: []string{"redis"}})
+
+	res := ac.resolveTemplate(tpl)
+	require.Len(t, res, 1)
+	assert.Equal(t, "docker://a", res[0].Entity)
+}
+
 func countLoadedConfigs(ac *AutoConfig) int {
 	count := -1 // -1 would indicate f was not called
 	ac.MapOverLoadedConfigs(func(loadedConfigs map[string]integration.Config) {
@@ -15,10 +15,25 @@ import (
 )
 
 const (
-	openmetricsCheckName  = "openmetrics"
-	openmetricsInitConfig = "{}"
+	openmetricsCheckName = "openmetrics"
 )
 
+// openmetricsInitConfig returns the init config of the openmetrics checks, it selects
+// the loader of the checks when `prometheus_scrape.loader` is set
+func openmetricsInitConfig() integration.Data {
+	loader := config.Datadog.GetString("prometheus_scrape.loader")
+	if loader == "" {
+		return integration.Data("{}")
+	}
+
+	initConfig, err := json.Marshal(map[string]string{"loader": loader})
+	if err != nil {
+		log.Warnf("Error processing prometheus init config: %v", err)
+		return integration.Data("{}")
+	}
+	return initConfig
+}
+
 // buildInstances generates check config instances based on the Prometheus config and the object annotations
 // The second returned value is true if more than one instance is found
 func buildInstances(pc *types.PrometheusCheck, annotations map[string]string, namespacedName string) ([]integration.Data, bool) {
@@ -35,7 +35,7 @@ func ConfigsForPod(pc *types.PrometheusCheck, pod *kubelet.Pod) []integration.Co
 			}
 			configs = append(configs, integration.Config{
 				Name:          openmetricsCheckName,
-				InitConfig:    integration.Data(openmetricsInitConfig),
+				InitConfig:    openmetricsInitConfig(),
 				Instances:     instances,
 				Provider:      names.PrometheusPods,
 				Source:        "prometheus_pods:" + container.ID,
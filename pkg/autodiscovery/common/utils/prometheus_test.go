@@ -0,0 +1,25 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func TestOpenmetricsInitConfig(t *testing.T) {
+	mockConfig := config.Mock()
+
+	assert.Equal(t, integration.Data("{}"), openmetricsInitConfig())
+
+	mockConfig.Set("prometheus_scrape.loader", "core")
+	defer mockConfig.Set("prometheus_scrape.loader", "")
+	assert.Equal(t, integration.Data(`{"loader":"core"}`), openmetricsInitConfig())
+}
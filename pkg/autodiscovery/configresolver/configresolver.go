@@ -10,6 +10,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 
@@ -24,12 +25,28 @@ import (
 type variableGetter func(ctx context.Context, key []byte, svc listeners.Service) ([]byte, error)
 
 var templateVariables = map[string]variableGetter{
-	"host":     getHost,
-	"pid":      getPid,
-	"port":     getPort,
-	"hostname": getHostname,
-	"extra":    getAdditionalTplVariables,
-	"kube":     getAdditionalTplVariables,
+	"host":       getHost,
+	"pid":        getPid,
+	"port":       getPort,
+	"hostname":   getHostname,
+	"extra":      getAdditionalTplVariables,
+	"kube":       getAdditionalTplVariables,
+	"label":      getLabel,
+	"annotation": getAnnotation,
+	"namespace":  getNamespace,
+	"pod":        getPod,
+	"image":      getImage,
+}
+
+// defaultModifier can prefix the default value of a template variable, e.g. `%%port_metrics|default:9090%%`,
+// to use a default value that is also the name of a function, such as `lower`
+const defaultModifier = "default:"
+
+// templateFunctions can be applied to the value of a template variable, e.g. `%%label_app|lower%%`
+var templateFunctions = map[string]func([]byte) []byte{
+	"lower":     bytes.ToLower,
+	"upper":     bytes.ToUpper,
+	"urlencode": func(value []byte) []byte { return []byte(url.QueryEscape(string(value))) },
 }
 
 // SubstituteTemplateEnvVars replaces %%ENV_VARIABLE%% from environment
@@ -158,18 +175,58 @@ func resolveDataWithTemplateVars(ctx context.Context, data integration.Data, svc
 
 	templateVars := tmplvar.Parse(data)
 	for _, tVar := range templateVars {
-		if f, found := templateVariables[string(tVar.Name)]; found {
-			resolvedVar, err := f(ctx, tVar.Key, svc)
-			if err != nil {
-				return res, err
-			}
-			res = bytes.Replace(res, tVar.Raw, resolvedVar, -1)
+		if "env" == string(tVar.Name) {
+			// resolved by SubstituteTemplateEnvVars
+			continue
+		}
+		f, found := templateVariables[string(tVar.Name)]
+		if !found {
+			return res, fmt.Errorf("unknown template variable %s", tVar.Raw)
 		}
+		resolvedVar, err := f(ctx, tVar.Key, svc)
+		resolvedVar, err = applyModifiers(tVar, resolvedVar, err)
+		if err != nil {
+			return res, err
+		}
+		res = bytes.Replace(res, tVar.Raw, resolvedVar, -1)
 	}
 
 	return res, nil
 }
 
+// applyModifiers replaces the value of a template variable that can't be resolved by its default
+// value, if any, then applies its functions in order, e.g. `%%port_metrics|9090%%` or `%%label_app|lower%%`.
+// The modifiers that aren't functions are the default value, which can also be given explicitly
+// with the `default:` prefix, e.g. `%%label_case|default:lower%%`.
+func applyModifiers(tVar tmplvar.TemplateVar, value []byte, err error) ([]byte, error) {
+	var functions []func([]byte) []byte
+	var defaultValue []byte
+	hasDefault := false
+	for _, modifier := range tVar.Modifiers {
+		f, isFunction := templateFunctions[string(modifier)]
+		if isFunction {
+			functions = append(functions, f)
+			continue
+		}
+		if hasDefault {
+			return nil, fmt.Errorf("template variable %s has more than one default value", tVar.Raw)
+		}
+		defaultValue, hasDefault = bytes.TrimPrefix(modifier, []byte(defaultModifier)), true
+	}
+
+	if err != nil {
+		if !hasDefault {
+			return nil, err
+		}
+		log.Debugf("Using the default value of the template variable %s: %s", tVar.Raw, err)
+		value = defaultValue
+	}
+	for _, f := range functions {
+		value = f(value)
+	}
+	return value, nil
+}
+
 func resolveDataWithEnvs(data integration.Data) ([]byte, error) {
 	var retErr error
 	res := append([]byte(nil), data...)
@@ -178,6 +235,7 @@ func resolveDataWithEnvs(data integration.Data) ([]byte, error) {
 	for _, tVar := range templateVars {
 		if "env" == string(tVar.Name) {
 			resolvedVar, err := getEnvvar(tVar.Key)
+			resolvedVar, err = applyModifiers(tVar, resolvedVar, err)
 			if err != nil {
 				log.Warnf("variable not replaced: %s", err)
 				if retErr == nil {
@@ -318,3 +376,99 @@ func getEnvvar(envVar []byte) ([]byte, error) {
 	}
 	return []byte(value), nil
 }
+
+// getMetadataService returns the service if it exposes the metadata of its workload
+func getMetadataService(name string, svc listeners.Service) (listeners.MetadataService, error) {
+	metadataSvc, ok := svc.(listeners.MetadataService)
+	if !ok {
+		return nil, fmt.Errorf("the %s template variable isn't supported by service %s", name, svc.GetEntity())
+	}
+	return metadataSvc, nil
+}
+
+// getLabel returns the value of a label of the container or pod of the service
+func getLabel(_ context.Context, tplVar []byte, svc listeners.Service) ([]byte, error) {
+	metadataSvc, err := getMetadataService("label", svc)
+	if err != nil {
+		return nil, err
+	}
+	value, found := metadataSvc.GetLabels()[string(tplVar)]
+	if !found {
+		return nil, fmt.Errorf("label %q not found on service %s", tplVar, svc.GetEntity())
+	}
+	return []byte(value), nil
+}
+
+// getAnnotation returns the value of an annotation of the container or pod of the service
+func getAnnotation(_ context.Context, tplVar []byte, svc listeners.Service) ([]byte, error) {
+	metadataSvc, err := getMetadataService("annotation", svc)
+	if err != nil {
+		return nil, err
+	}
+	value, found := metadataSvc.GetAnnotations()[string(tplVar)]
+	if !found {
+		return nil, fmt.Errorf("annotation %q not found on service %s", tplVar, svc.GetEntity())
+	}
+	return []byte(value), nil
+}
+
+// getNamespace returns the kubernetes namespace of the service
+func getNamespace(_ context.Context, _ []byte, svc listeners.Service) ([]byte, error) {
+	metadataSvc, err := getMetadataService("namespace", svc)
+	if err != nil {
+		return nil, err
+	}
+	namespace := metadataSvc.GetNamespace()
+	if namespace == "" {
+		return nil, fmt.Errorf("no kubernetes namespace found for service %s", svc.GetEntity())
+	}
+	return []byte(namespace), nil
+}
+
+// getPod returns the name or the uid of the pod of the service
+func getPod(_ context.Context, tplVar []byte, svc listeners.Service) ([]byte, error) {
+	metadataSvc, err := getMetadataService("pod", svc)
+	if err != nil {
+		return nil, err
+	}
+	var value string
+	switch string(tplVar) {
+	case "name":
+		value = metadataSvc.GetPodName()
+	case "uid":
+		value = metadataSvc.GetPodUID()
+	default:
+		return nil, fmt.Errorf("unknown pod template variable %q, expected pod_name or pod_uid", tplVar)
+	}
+	if value == "" {
+		return nil, fmt.Errorf("no pod found for service %s", svc.GetEntity())
+	}
+	return []byte(value), nil
+}
+
+// getImage returns the name, short name or tag of the image of the container of the service
+func getImage(_ context.Context, tplVar []byte, svc listeners.Service) ([]byte, error) {
+	metadataSvc, err := getMetadataService("image", svc)
+	if err != nil {
+		return nil, err
+	}
+	image, found := metadataSvc.GetImage()
+	if !found {
+		return nil, fmt.Errorf("no image found for service %s", svc.GetEntity())
+	}
+	var value string
+	switch string(tplVar) {
+	case "name":
+		value = image.Name
+	case "short_name":
+		value = image.ShortName
+	case "tag":
+		value = image.Tag
+	default:
+		return nil, fmt.Errorf("unknown image template variable %q, expected image_name, image_short_name or image_tag", tplVar)
+	}
+	if value == "" {
+		return nil, fmt.Errorf("image %s of service %s has no %s", image.RawName, svc.GetEntity(), tplVar)
+	}
+	return []byte(value), nil
+}
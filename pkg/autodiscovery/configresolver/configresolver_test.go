@@ -17,6 +17,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/listeners"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 
 	// we need some valid check in the catalog to run tests
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system"
@@ -99,6 +100,49 @@ func (s *dummyService) GetExtraConfig(key []byte) ([]byte, error) {
 	return []byte(s.ExtraConfig[string(key)]), nil
 }
 
+type dummyMetadataService struct {
+	dummyService
+	Labels      map[string]string
+	Annotations map[string]string
+	Namespace   string
+	PodName     string
+	PodUID      string
+	Image       *workloadmeta.ContainerImage
+}
+
+// GetLabels returns dummy labels
+func (s *dummyMetadataService) GetLabels() map[string]string {
+	return s.Labels
+}
+
+// GetAnnotations returns dummy annotations
+func (s *dummyMetadataService) GetAnnotations() map[string]string {
+	return s.Annotations
+}
+
+// GetNamespace returns a dummy namespace
+func (s *dummyMetadataService) GetNamespace() string {
+	return s.Namespace
+}
+
+// GetPodName returns a dummy pod name
+func (s *dummyMetadataService) GetPodName() string {
+	return s.PodName
+}
+
+// GetPodUID returns a dummy pod uid
+func (s *dummyMetadataService) GetPodUID() string {
+	return s.PodUID
+}
+
+// GetImage returns a dummy image
+func (s *dummyMetadataService) GetImage() (workloadmeta.ContainerImage, bool) {
+	if s.Image == nil {
+		return workloadmeta.ContainerImage{}, false
+	}
+	return *s.Image, true
+}
+
 func TestGetFallbackHost(t *testing.T) {
 	ip, err := getFallbackHost(map[string]string{"bridge": "172.17.0.1"})
 	assert.Equal(t, "172.17.0.1", ip)
@@ -531,7 +575,117 @@ func TestResolve(t *testing.T) {
 				ADIdentifiers: []string{"redis"},
 				Instances:     []integration.Data{integration.Data("host: %%FOO%%")},
 			},
-			errorString: "unable to add tags for service 'a5901276aed1', err: yaml: found character that cannot start any token",
+			errorString: "unknown template variable %%FOO%%",
+		},
+		//// workload metadata
+		{
+			testName: "labels, annotations, namespace, pod and image",
+			svc: &dummyMetadataService{
+				dummyService: dummyService{
+					ID:            "a5901276aed1",
+					ADIdentifiers: []string{"redis"},
+				},
+				Labels:      map[string]string{"app.kubernetes.io/name": "Redis"},
+				Annotations: map[string]string{"team": "storage"},
+				Namespace:   "default",
+				PodName:     "redis-0",
+				PodUID:      "05567616-cb47-41ea-af04-295c1297e957",
+				Image:       &workloadmeta.ContainerImage{RawName: "docker.io/library/redis:6.2", Name: "docker.io/library/redis", ShortName: "redis", Tag: "6.2"},
+			},
+			tpl: integration.Config{
+				Name:                    "redis",
+				ADIdentifiers:           []string{"redis"},
+				Instances:               []integration.Data{integration.Data("app: %%label_app.kubernetes.io/name%%\nteam: %%annotation_team%%\nnamespace: %%namespace%%\npod: %%pod_name%%\nuid: %%pod_uid%%\nimage: %%image_name%%\nshort: %%image_short_name%%\ntag: %%image_tag%%")},
+				IgnoreAutodiscoveryTags: true,
+			},
+			out: integration.Config{
+				Name:          "redis",
+				ADIdentifiers: []string{"redis"},
+				Instances:     []integration.Data{integration.Data("app: Redis\nteam: storage\nnamespace: default\npod: redis-0\nuid: 05567616-cb47-41ea-af04-295c1297e957\nimage: docker.io/library/redis\nshort: redis\ntag: 6.2")},
+				Entity:        "a5901276aed1",
+			},
+		},
+		{
+			testName: "missing label",
+			svc: &dummyMetadataService{
+				dummyService: dummyService{
+					ID:            "a5901276aed1",
+					ADIdentifiers: []string{"redis"},
+				},
+			},
+			tpl: integration.Config{
+				Name:          "redis",
+				ADIdentifiers: []string{"redis"},
+				Instances:     []integration.Data{integration.Data("team: %%label_team%%")},
+			},
+			errorString: "label \"team\" not found on service a5901276aed1",
+		},
+		{
+			testName: "labels not supported by the service",
+			svc: &dummyService{
+				ID:            "a5901276aed1",
+				ADIdentifiers: []string{"redis"},
+			},
+			tpl: integration.Config{
+				Name:          "redis",
+				ADIdentifiers: []string{"redis"},
+				Instances:     []integration.Data{integration.Data("team: %%label_team%%")},
+			},
+			errorString: "the label template variable isn't supported by service a5901276aed1",
+		},
+		//// defaults and functions
+		{
+			testName: "default values and functions",
+			svc: &dummyMetadataService{
+				dummyService: dummyService{
+					ID:            "a5901276aed1",
+					ADIdentifiers: []string{"redis"},
+					Ports:         newFakeContainerPorts(),
+				},
+				Labels: map[string]string{"app": "Redis", "password": "p@ss:w/rd"},
+			},
+			tpl: integration.Config{
+				Name:                    "redis",
+				ADIdentifiers:           []string{"redis"},
+				Instances:               []integration.Data{integration.Data("port: %%port_foo|9090%%\nmetrics_port: %%port_metrics|9090%%\nadmin_port: %%port_admin|default:9091%%\napp: %%label_app|lower%%\nteam: %%label_team|unknown|upper%%\nenv: %%label_env|%%\ncase: %%label_case|default:lower%%\nmotd: \"%%label_motd|default:100% up time%%\"\npassword: %%label_password|urlencode%%\nenvvar: %%env_test_envvar_not_set|fallback%%")},
+				IgnoreAutodiscoveryTags: true,
+			},
+			out: integration.Config{
+				Name:          "redis",
+				ADIdentifiers: []string{"redis"},
+				Instances:     []integration.Data{integration.Data("port: 1\nmetrics_port: 9090\nadmin_port: 9091\napp: redis\nteam: UNKNOWN\nenv: \ncase: lower\nmotd: \"100% up time\"\npassword: p%40ss%3Aw%2Frd\nenvvar: fallback")},
+				Entity:        "a5901276aed1",
+			},
+		},
+		{
+			testName: "more than one default value",
+			svc: &dummyService{
+				ID:            "a5901276aed1",
+				ADIdentifiers: []string{"redis"},
+				Ports:         newFakeContainerPorts(),
+			},
+			tpl: integration.Config{
+				Name:          "redis",
+				ADIdentifiers: []string{"redis"},
+				Instances:     []integration.Data{integration.Data("port: %%port_metrics|9090|default:9091%%")},
+			},
+			errorString: "template variable %%port_metrics|9090|default:9091%% has more than one default value",
+		},
+		{
+			testName: "unresolvable variable without default value",
+			svc: &dummyMetadataService{
+				dummyService: dummyService{
+					ID:            "a5901276aed1",
+					ADIdentifiers: []string{"redis"},
+				},
+				Labels: map[string]string{"app": "redis"},
+			},
+			tpl: integration.Config{
+				Name:          "redis",
+				ADIdentifiers: []string{"redis"},
+				Instances:     []integration.Data{integration.Data("team: %%label_team|lower%%")},
+			},
+			errorString: "label \"team\" not found on service a5901276aed1",
 		},
 		//// check overrides
 		{
@@ -53,6 +53,7 @@ type Config struct {
 	TaggerEntity            string                 `json:"-"`                         // the tagger entity ID (optional) (include in digest: false)
 	ClusterCheck            bool                   `json:"cluster_check"`             // cluster-check configuration flag (include in digest: false)
 	NodeName                string                 `json:"node_name"`                 // node name in case of an endpoint check backed by a pod (include in digest: true)
+	ServiceNamespace        string                 `json:"service_namespace"`         // restricts a template to the services of a kubernetes namespace (optional) (include in digest: true)
 	CreationTime            CreationTime           `json:"-"`                         // creation time of service (include in digest: false)
 	Source                  string                 `json:"source"`                    // the source of the configuration (include in digest: false)
 	IgnoreAutodiscoveryTags bool                   `json:"ignore_autodiscovery_tags"` // used to ignore tags coming from autodiscovery (include in digest: true)
@@ -369,6 +370,9 @@ func (c *Config) Digest() string {
 	h.Write([]byte(c.LogsConfig))                                  //nolint:errcheck
 	h.Write([]byte(c.Entity))                                      //nolint:errcheck
 	h.Write([]byte(strconv.FormatBool(c.IgnoreAutodiscoveryTags))) //nolint:errcheck
+	if c.ServiceNamespace != "" {
+		h.Write([]byte(c.ServiceNamespace)) //nolint:errcheck
+	}
 
 	return strconv.FormatUint(h.Sum64(), 16)
 }
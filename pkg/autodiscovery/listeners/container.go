@@ -107,6 +107,7 @@ func (l *ContainerListener) createContainerService(
 	if findKubernetesInLabels(container.Labels) {
 		pod, err := l.Store().GetKubernetesPodForContainer(container.ID)
 		if err == nil {
+			svc.pod = pod
 			svc.hosts = map[string]string{"pod": pod.IP}
 			svc.ready = pod.Ready
 		} else {
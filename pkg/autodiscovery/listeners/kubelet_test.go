@@ -233,6 +233,7 @@ func TestKubeletCreateContainerService(t *testing.T) {
 					parent: "kubernetes_pod://foobar",
 					service: &service{
 						entity: basicContainer,
+						pod:    pod,
 						adIdentifiers: []string{
 							"docker://foobarquux",
 							"gcr.io/foobar:latest",
@@ -266,6 +267,7 @@ func TestKubeletCreateContainerService(t *testing.T) {
 					parent: "kubernetes_pod://foobar",
 					service: &service{
 						entity: recentlyStoppedContainer,
+						pod:    pod,
 						adIdentifiers: []string{
 							"docker://foobarquux",
 							"foobar",
@@ -322,6 +324,7 @@ func TestKubeletCreateContainerService(t *testing.T) {
 					parent: "kubernetes_pod://foobar",
 					service: &service{
 						entity: runningContainerWithFinishedAtTime,
+						pod:    pod,
 						adIdentifiers: []string{
 							"docker://foobarquux",
 							"foobar",
@@ -354,6 +357,7 @@ func TestKubeletCreateContainerService(t *testing.T) {
 					parent: "kubernetes_pod://foobar",
 					service: &service{
 						entity: multiplePortsContainer,
+						pod:    pod,
 						adIdentifiers: []string{
 							"docker://foobarquux",
 							"foobar",
@@ -395,6 +399,7 @@ func TestKubeletCreateContainerService(t *testing.T) {
 					parent: "kubernetes_pod://foobar",
 					service: &service{
 						entity: customIDsContainer,
+						pod:    podWithAnnotations,
 						adIdentifiers: []string{
 							"customid",
 							"docker://foobarquux",
@@ -0,0 +1,456 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package listeners
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/cgroups"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	processEntityPrefix = "process://"
+	// tcpListenState is the state of the listening sockets in /proc/net/tcp
+	tcpListenState = "0A"
+)
+
+func init() {
+	Register("process", NewProcessListener)
+}
+
+// ProcessListener discovers the host processes listening on a TCP port by reading procfs.
+// The processes running in containers are left to the container listeners.
+type ProcessListener struct {
+	sync.RWMutex
+	newService chan<- Service
+	delService chan<- Service
+	stop       chan bool
+	procRoot   string
+	interval   time.Duration
+	services   map[string]*ProcessService
+}
+
+// ProcessService is a host process listening on at least one TCP port
+type ProcessService struct {
+	entityID      string
+	pid           int
+	adIdentifiers []string
+	hosts         map[string]string
+	ports         []ContainerPort
+	creationTime  integration.CreationTime
+}
+
+// Make sure ProcessService implements the Service interface
+var _ Service = &ProcessService{}
+
+// NewProcessListener creates a ProcessListener
+func NewProcessListener(Config) (ServiceListener, error) {
+	procRoot := config.Datadog.GetString("container_proc_root")
+	if _, err := os.Stat(filepath.Join(procRoot, "1", "net", "tcp")); err != nil {
+		return nil, fmt.Errorf("procfs is not available at %s: %s", procRoot, err)
+	}
+	interval := config.Datadog.GetInt("process_listener.discovery_interval")
+	if interval <= 0 {
+		return nil, fmt.Errorf("process_listener.discovery_interval must be positive, got %d", interval)
+	}
+	return &ProcessListener{
+		services: map[string]*ProcessService{},
+		stop:     make(chan bool),
+		procRoot: procRoot,
+		interval: time.Duration(interval) * time.Second,
+	}, nil
+}
+
+// Listen periodically refreshes the listening processes
+func (l *ProcessListener) Listen(newSvc chan<- Service, delSvc chan<- Service) {
+	// setup the I/O channels
+	l.newService = newSvc
+	l.delService = delSvc
+
+	go func() {
+		ticker := time.NewTicker(l.interval)
+		l.refreshServices(true)
+		for {
+			select {
+			case <-l.stop:
+				ticker.Stop()
+				return
+			case <-ticker.C:
+				l.refreshServices(false)
+			}
+		}
+	}()
+}
+
+// Stop stops the listener
+func (l *ProcessListener) Stop() {
+	l.stop <- true
+}
+
+func (l *ProcessListener) refreshServices(firstRun bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	crTime := integration.After
+	if firstRun {
+		crTime = integration.Before
+	}
+
+	processes, err := discoverListeningProcesses(l.procRoot)
+	if err != nil {
+		log.Warnf("Couldn't discover the listening processes: %s", err)
+		return
+	}
+
+	notSeen := make(map[string]struct{}, len(l.services))
+	for entityID := range l.services {
+		notSeen[entityID] = struct{}{}
+	}
+
+	for _, svc := range processes {
+		delete(notSeen, svc.entityID)
+		if old, found := l.services[svc.entityID]; found {
+			if old.equals(svc) {
+				continue
+			}
+			// the process listens on other ports, or the pid has been reused
+			l.delService <- old
+		}
+		svc.creationTime = crTime
+		l.services[svc.entityID] = svc
+		l.newService <- svc
+	}
+
+	for entityID := range notSeen {
+		l.delService <- l.services[entityID]
+		delete(l.services, entityID)
+	}
+}
+
+// listeningSocket is a socket in the LISTEN state read from /proc/<pid>/net/tcp or /proc/<pid>/net/tcp6
+type listeningSocket struct {
+	ip   net.IP
+	port int
+}
+
+// discoverListeningProcesses returns the host processes owning a listening TCP socket, sorted by pid
+func discoverListeningProcesses(procRoot string) ([]*ProcessService, error) {
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+	for _, entry := range entries {
+		if pid, err := strconv.Atoi(entry.Name()); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	// the workers forked by a server share its listening sockets, they are
+	// assigned to the process with the lowest pid, usually the parent
+	sort.Ints(pids)
+
+	var processes []*ProcessService
+	claimed := make(map[string]struct{})
+	// the listening sockets are read once per network namespace
+	namespaceSockets := make(map[string]map[string]listeningSocket)
+	for _, pid := range pids {
+		processDir := filepath.Join(procRoot, strconv.Itoa(pid))
+		inodes := processSocketInodes(processDir)
+		if len(inodes) == 0 || isContainerProcess(procRoot, pid) {
+			continue
+		}
+		sockets, err := processListeningSockets(processDir, namespaceSockets)
+		if err != nil {
+			log.Debugf("Couldn't read the listening sockets of process %d: %s", pid, err)
+			continue
+		}
+
+		var owned []listeningSocket
+		for _, inode := range inodes {
+			socket, found := sockets[inode]
+			if _, isClaimed := claimed[inode]; !found || isClaimed {
+				continue
+			}
+			claimed[inode] = struct{}{}
+			owned = append(owned, socket)
+		}
+		if len(owned) == 0 {
+			continue
+		}
+		adIdentifiers := processADIdentifiers(processDir)
+		if len(adIdentifiers) == 0 {
+			continue
+		}
+		processes = append(processes, newProcessService(pid, adIdentifiers, owned))
+	}
+	return processes, nil
+}
+
+// isContainerProcess returns whether the process runs in a container, based on its cgroups
+func isContainerProcess(procRoot string, pid int) bool {
+	references, err := cgroups.ReadCgroupReferences(procRoot, pid)
+	if err != nil {
+		// the process exited, or its cgroups can't be read
+		return false
+	}
+	return cgroups.ContainerRegexp.MatchString(references)
+}
+
+// processListeningSockets returns the listening sockets of the network namespace of the process,
+// indexed by inode. namespaceSockets caches the sockets already read, by network namespace.
+func processListeningSockets(processDir string, namespaceSockets map[string]map[string]listeningSocket) (map[string]listeningSocket, error) {
+	namespace, err := os.Readlink(filepath.Join(processDir, "ns", "net"))
+	if err == nil {
+		if sockets, found := namespaceSockets[namespace]; found {
+			return sockets, nil
+		}
+	}
+
+	sockets := make(map[string]listeningSocket)
+	for _, file := range []string{"tcp", "tcp6"} {
+		if err := readListeningSockets(filepath.Join(processDir, "net", file), sockets); err != nil {
+			if os.IsNotExist(err) && file == "tcp6" {
+				// IPv6 can be disabled
+				continue
+			}
+			return nil, err
+		}
+	}
+	if namespace != "" {
+		namespaceSockets[namespace] = sockets
+	}
+	return sockets, nil
+}
+
+// readListeningSockets adds the listening sockets of a /proc/net/tcp file to sockets, indexed by inode
+func readListeningSockets(path string, sockets map[string]listeningSocket) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	// skip the header
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpListenState {
+			continue
+		}
+		ip, port, err := parseProcNetAddress(fields[1])
+		if err != nil {
+			log.Debugf("Couldn't parse the address of a listening socket in %s: %s", path, err)
+			continue
+		}
+		sockets[fields[9]] = listeningSocket{ip: ip, port: port}
+	}
+	return scanner.Err()
+}
+
+// parseProcNetAddress parses an address of /proc/net/tcp, e.g. `0100007F:1F90` for 127.0.0.1:8080.
+// The IP is made of 32 bits words in host byte order.
+func parseProcNetAddress(address string) (net.IP, int, error) {
+	i := strings.Index(address, ":")
+	if i < 0 {
+		return nil, 0, fmt.Errorf("invalid address %s", address)
+	}
+	raw, err := hex.DecodeString(address[:i])
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return nil, 0, fmt.Errorf("invalid address %s", address)
+	}
+	port, err := strconv.ParseUint(address[i+1:], 16, 16)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid port in address %s", address)
+	}
+
+	ip := make(net.IP, len(raw))
+	for word := 0; word < len(raw); word += 4 {
+		for b := 0; b < 4; b++ {
+			ip[word+b] = raw[word+3-b]
+		}
+	}
+	return ip, int(port), nil
+}
+
+// processSocketInodes returns the inodes of the sockets opened by the process, found through its file descriptors.
+// Reading the file descriptors of the processes of other users requires the agent to be privileged.
+func processSocketInodes(processDir string) []string {
+	fds, err := os.ReadDir(filepath.Join(processDir, "fd"))
+	if err != nil {
+		return nil
+	}
+	var inodes []string
+	for _, fd := range fds {
+		target, err := os.Readlink(filepath.Join(processDir, "fd", fd.Name()))
+		if err != nil || !strings.HasPrefix(target, "socket:[") {
+			continue
+		}
+		inodes = append(inodes, strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]"))
+	}
+	return inodes
+}
+
+// processADIdentifiers returns the name of the process and the name of its executable
+func processADIdentifiers(processDir string) []string {
+	var identifiers []string
+	if comm, err := os.ReadFile(filepath.Join(processDir, "comm")); err == nil {
+		if name := strings.TrimSpace(string(comm)); name != "" {
+			identifiers = append(identifiers, name)
+		}
+	}
+	if exe, err := os.Readlink(filepath.Join(processDir, "exe")); err == nil {
+		// the link of a deleted executable ends with ` (deleted)`
+		name := filepath.Base(strings.TrimSuffix(exe, " (deleted)"))
+		if name != "" && name != "." && name != "/" && (len(identifiers) == 0 || identifiers[0] != name) {
+			identifiers = append(identifiers, name)
+		}
+	}
+	return identifiers
+}
+
+func newProcessService(pid int, adIdentifiers []string, sockets []listeningSocket) *ProcessService {
+	svc := &ProcessService{
+		entityID:      fmt.Sprintf("%s%d", processEntityPrefix, pid),
+		pid:           pid,
+		adIdentifiers: adIdentifiers,
+		hosts:         map[string]string{},
+	}
+
+	seen := make(map[int]struct{}, len(sockets))
+	for _, socket := range sockets {
+		if _, found := seen[socket.port]; !found {
+			seen[socket.port] = struct{}{}
+			svc.ports = append(svc.ports, ContainerPort{socket.port, fmt.Sprintf("p%d", socket.port)})
+		}
+		addProcessHost(svc.hosts, socket.ip)
+	}
+	sort.Slice(svc.ports, func(i, j int) bool { return svc.ports[i].Port < svc.ports[j].Port })
+	return svc
+}
+
+// addProcessHost records the address a process listens on, by IP family. The processes listening
+// on all the interfaces are reached through the loopback interface, which takes precedence.
+// The default host is the IPv4 address when the process listens on both families.
+func addProcessHost(hosts map[string]string, ip net.IP) {
+	key, loopback := "ipv6", net.IPv6loopback
+	if ip.To4() != nil {
+		key, loopback = "ipv4", net.IPv4(127, 0, 0, 1)
+	}
+	if ip.IsUnspecified() {
+		hosts[key] = loopback.String()
+	} else if _, found := hosts[key]; !found {
+		hosts[key] = ip.String()
+	}
+
+	if v4, found := hosts["ipv4"]; found {
+		hosts[""] = v4
+	} else {
+		hosts[""] = hosts["ipv6"]
+	}
+}
+
+// equals returns true if both services are the same process listening on the same ports
+func (s *ProcessService) equals(other *ProcessService) bool {
+	if s.entityID != other.entityID || len(s.ports) != len(other.ports) || len(s.adIdentifiers) != len(other.adIdentifiers) {
+		return false
+	}
+	for i := range s.ports {
+		if s.ports[i] != other.ports[i] {
+			return false
+		}
+	}
+	for i := range s.adIdentifiers {
+		if s.adIdentifiers[i] != other.adIdentifiers[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// GetEntity returns the unique entity name linked to that service
+func (s *ProcessService) GetEntity() string {
+	return s.entityID
+}
+
+// GetTaggerEntity returns the unique entity ID linked to that service
+func (s *ProcessService) GetTaggerEntity() string {
+	return s.entityID
+}
+
+// GetADIdentifiers returns the name of the process and of its executable
+func (s *ProcessService) GetADIdentifiers(context.Context) ([]string, error) {
+	return s.adIdentifiers, nil
+}
+
+// GetHosts returns the addresses the process listens on
+func (s *ProcessService) GetHosts(context.Context) (map[string]string, error) {
+	return s.hosts, nil
+}
+
+// GetPorts returns the ports the process listens on, sorted
+func (s *ProcessService) GetPorts(context.Context) ([]ContainerPort, error) {
+	return s.ports, nil
+}
+
+// GetTags returns the list of tags - currently always empty
+func (s *ProcessService) GetTags() ([]string, string, error) {
+	return []string{}, "", nil
+}
+
+// GetPid returns the pid of the process
+func (s *ProcessService) GetPid(context.Context) (int, error) {
+	return s.pid, nil
+}
+
+// GetHostname returns nothing - not supported
+func (s *ProcessService) GetHostname(context.Context) (string, error) {
+	return "", ErrNotSupported
+}
+
+// GetCreationTime returns the creation time of the Service
+func (s *ProcessService) GetCreationTime() integration.CreationTime {
+	return s.creationTime
+}
+
+// IsReady returns true
+func (s *ProcessService) IsReady(context.Context) bool {
+	return true
+}
+
+// GetCheckNames returns nil
+func (s *ProcessService) GetCheckNames(context.Context) []string {
+	return nil
+}
+
+// HasFilter returns false, the container filters don't apply to host processes
+func (s *ProcessService) HasFilter(filter containers.FilterType) bool {
+	return false
+}
+
+// GetExtraConfig isn't supported
+func (s *ProcessService) GetExtraConfig(key []byte) ([]byte, error) {
+	return []byte{}, ErrNotSupported
+}
@@ -0,0 +1,203 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package listeners
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	procNetTCPHeader = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	procNetTCP       = procNetTCPHeader +
+		// 0.0.0.0:5432 LISTEN
+		"   0: 00000000:1538 00000000:0000 0A 00000000:00000000 00:00000000 00000000   106        0 1001 1 0000000000000000 100 0 0 10 0\n" +
+		// 127.0.0.1:6379 LISTEN
+		"   1: 0100007F:18EB 00000000:0000 0A 00000000:00000000 00:00000000 00000000   107        0 1002 1 0000000000000000 100 0 0 10 0\n" +
+		// 127.0.0.1:6379 <-> 127.0.0.1:40000 ESTABLISHED
+		"   2: 0100007F:18EB 0100007F:9C40 01 00000000:00000000 00:00000000 00000000   107        0 1003 1 0000000000000000 100 0 0 10 0\n" +
+		// 10.0.0.5:80 LISTEN
+		"   3: 0500000A:0050 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1004 1 0000000000000000 100 0 0 10 0\n"
+	procNetTCP6 = procNetTCPHeader +
+		// [::]:5432 LISTEN
+		"   0: 00000000000000000000000000000000:1538 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000   106        0 1005 1 0000000000000000 100 0 0 10 0\n"
+	// the table of another network namespace, 0.0.0.0:8080 LISTEN
+	procNetTCPOtherNamespace = procNetTCPHeader +
+		"   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 2001 1 0000000000000000 100 0 0 10 0\n"
+
+	hostNetNamespace = "net:[4026531840]"
+	hostCgroup       = "0::/system.slice/postgresql.service\n"
+	containerCgroup  = "0::/system.slice/docker-a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419.scope\n"
+)
+
+type fakeProcess struct {
+	pid     int
+	comm    string
+	exe     string
+	sockets []string
+	// netNamespace and cgroup default to the ones of the host
+	netNamespace string
+	cgroup       string
+}
+
+func writeFakeProcfs(t *testing.T, root string, processes []fakeProcess) {
+	for _, p := range processes {
+		dir := filepath.Join(root, strconv.Itoa(p.pid))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "fd"), 0755))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "net"), 0755))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "ns"), 0755))
+
+		netNamespace, tcp, tcp6 := hostNetNamespace, procNetTCP, procNetTCP6
+		if p.netNamespace != "" {
+			netNamespace, tcp, tcp6 = p.netNamespace, procNetTCPOtherNamespace, procNetTCPHeader
+		}
+		require.NoError(t, os.Symlink(netNamespace, filepath.Join(dir, "ns", "net")))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "net", "tcp"), []byte(tcp), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "net", "tcp6"), []byte(tcp6), 0644))
+
+		cgroup := hostCgroup
+		if p.cgroup != "" {
+			cgroup = p.cgroup
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cgroup"), []byte(cgroup), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "comm"), []byte(p.comm+"\n"), 0644))
+		require.NoError(t, os.Symlink(p.exe, filepath.Join(dir, "exe")))
+		require.NoError(t, os.Symlink("/dev/null", filepath.Join(dir, "fd", "0")))
+		for i, inode := range p.sockets {
+			require.NoError(t, os.Symlink("socket:["+inode+"]", filepath.Join(dir, "fd", strconv.Itoa(i+3))))
+		}
+	}
+}
+
+func TestParseProcNetAddress(t *testing.T) {
+	ip, port, err := parseProcNetAddress("0100007F:1F90")
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", ip.String())
+	assert.Equal(t, 8080, port)
+
+	ip, port, err = parseProcNetAddress("0000000000000000FFFF00000100007F:0050")
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", ip.String())
+	assert.Equal(t, 80, port)
+
+	ip, port, err = parseProcNetAddress("00000000000000000000000001000000:1538")
+	assert.NoError(t, err)
+	assert.Equal(t, "::1", ip.String())
+	assert.Equal(t, 5432, port)
+
+	for _, address := range []string{"0100007F", "0100007G:1F90", "01007F:1F90", "0100007F:FFFFF"} {
+		_, _, err = parseProcNetAddress(address)
+		assert.Error(t, err, address)
+	}
+}
+
+func TestDiscoverListeningProcesses(t *testing.T) {
+	root := t.TempDir()
+	writeFakeProcfs(t, root, []fakeProcess{
+		{pid: 1, comm: "systemd", exe: "/usr/lib/systemd/systemd"},
+		{pid: 120, comm: "postgres", exe: "/usr/lib/postgresql/14/bin/postgres", sockets: []string{"1001", "1005"}},
+		// the worker shares the listening socket of its parent
+		{pid: 121, comm: "postgres", exe: "/usr/lib/postgresql/14/bin/postgres", sockets: []string{"1001"}},
+		{pid: 98, comm: "redis-server", exe: "/usr/bin/redis-server (deleted)", sockets: []string{"1002", "1003"}},
+		{pid: 300, comm: "nginx: master", exe: "/usr/sbin/nginx", sockets: []string{"1004"}},
+		// the sockets of a process are looked up in its own network namespace
+		{pid: 400, comm: "vault", exe: "/usr/bin/vault", sockets: []string{"2001"}, netNamespace: "net:[4026532281]"},
+		// the container processes are left to the container listeners
+		{pid: 350, comm: "mysqld", exe: "/usr/sbin/mysqld", sockets: []string{"2001"}, netNamespace: "net:[4026532281]", cgroup: containerCgroup},
+		{pid: 250, comm: "haproxy", exe: "/usr/sbin/haproxy", sockets: []string{"1004"}, cgroup: containerCgroup},
+	})
+
+	processes, err := discoverListeningProcesses(root)
+	require.NoError(t, err)
+	require.Len(t, processes, 4)
+
+	redis := processes[0]
+	assert.Equal(t, "process://98", redis.GetEntity())
+	ids, _ := redis.GetADIdentifiers(context.Background())
+	assert.Equal(t, []string{"redis-server"}, ids)
+	ports, _ := redis.GetPorts(context.Background())
+	assert.Equal(t, []ContainerPort{{6379, "p6379"}}, ports)
+	hosts, _ := redis.GetHosts(context.Background())
+	assert.Equal(t, map[string]string{"": "127.0.0.1", "ipv4": "127.0.0.1"}, hosts)
+
+	postgres := processes[1]
+	pid, err := postgres.GetPid(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 120, pid)
+	ids, _ = postgres.GetADIdentifiers(context.Background())
+	assert.Equal(t, []string{"postgres"}, ids)
+	ports, _ = postgres.GetPorts(context.Background())
+	assert.Equal(t, []ContainerPort{{5432, "p5432"}}, ports)
+	hosts, _ = postgres.GetHosts(context.Background())
+	assert.Equal(t, map[string]string{"": "127.0.0.1", "ipv4": "127.0.0.1", "ipv6": "::1"}, hosts)
+
+	nginx := processes[2]
+	ids, _ = nginx.GetADIdentifiers(context.Background())
+	assert.Equal(t, []string{"nginx: master", "nginx"}, ids)
+	hosts, _ = nginx.GetHosts(context.Background())
+	assert.Equal(t, map[string]string{"": "10.0.0.5", "ipv4": "10.0.0.5"}, hosts)
+
+	vault := processes[3]
+	assert.Equal(t, "process://400", vault.GetEntity())
+	ports, _ = vault.GetPorts(context.Background())
+	assert.Equal(t, []ContainerPort{{8080, "p8080"}}, ports)
+	hosts, _ = vault.GetHosts(context.Background())
+	assert.Equal(t, map[string]string{"": "127.0.0.1", "ipv4": "127.0.0.1"}, hosts)
+}
+
+func TestProcessListenerRefresh(t *testing.T) {
+	root := t.TempDir()
+	writeFakeProcfs(t, root, []fakeProcess{
+		{pid: 120, comm: "postgres", exe: "/usr/bin/postgres", sockets: []string{"1001"}},
+		{pid: 98, comm: "redis-server", exe: "/usr/bin/redis-server", sockets: []string{"1002"}},
+	})
+
+	newSvc := make(chan Service, 10)
+	delSvc := make(chan Service, 10)
+	l := &ProcessListener{
+		newService: newSvc,
+		delService: delSvc,
+		procRoot:   root,
+		services:   map[string]*ProcessService{},
+	}
+
+	l.refreshServices(true)
+	require.Len(t, newSvc, 2)
+	assert.Equal(t, "process://98", (<-newSvc).GetEntity())
+	svc := <-newSvc
+	assert.Equal(t, "process://120", svc.GetEntity())
+	assert.Equal(t, integration.Before, svc.GetCreationTime())
+
+	// nothing changed
+	l.refreshServices(false)
+	assert.Len(t, newSvc, 0)
+	assert.Len(t, delSvc, 0)
+
+	// redis stops and postgres listens on another port
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "98")))
+	require.NoError(t, os.Symlink("socket:[1004]", filepath.Join(root, "120", "fd", "4")))
+	l.refreshServices(false)
+
+	require.Len(t, delSvc, 2)
+	require.Len(t, newSvc, 1)
+	assert.Equal(t, "process://120", (<-delSvc).GetEntity())
+	assert.Equal(t, "process://98", (<-delSvc).GetEntity())
+	svc = <-newSvc
+	ports, _ := svc.GetPorts(context.Background())
+	assert.Equal(t, []ContainerPort{{80, "p80"}, {5432, "p5432"}}, ports)
+	assert.Equal(t, integration.After, svc.GetCreationTime())
+}
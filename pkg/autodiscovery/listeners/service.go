@@ -22,6 +22,7 @@ import (
 // workloadmeta.Store.
 type service struct {
 	entity          workloadmeta.Entity
+	pod             *workloadmeta.KubernetesPod // pod of the container, if any
 	adIdentifiers   []string
 	hosts           map[string]string
 	ports           []ContainerPort
@@ -36,6 +37,7 @@ type service struct {
 }
 
 var _ Service = &service{}
+var _ MetadataService = &service{}
 
 // GetEntity returns the AD entity ID of the service.
 func (s *service) GetEntity() string {
@@ -134,6 +136,80 @@ func (s *service) GetExtraConfig(key []byte) ([]byte, error) {
 	return []byte(result), nil
 }
 
+// GetLabels returns the labels of the service's container, merged with the
+// labels of its pod, or the labels of the service's pod.
+func (s *service) GetLabels() map[string]string {
+	return s.mergeMetadata(func(meta workloadmeta.EntityMeta) map[string]string { return meta.Labels })
+}
+
+// GetAnnotations returns the annotations of the service's container, merged
+// with the annotations of its pod, or the annotations of the service's pod.
+func (s *service) GetAnnotations() map[string]string {
+	return s.mergeMetadata(func(meta workloadmeta.EntityMeta) map[string]string { return meta.Annotations })
+}
+
+// mergeMetadata merges the metadata of the service's entity with the metadata
+// of its pod, the pod takes precedence.
+func (s *service) mergeMetadata(get func(workloadmeta.EntityMeta) map[string]string) map[string]string {
+	merged := make(map[string]string)
+	switch e := s.entity.(type) {
+	case *workloadmeta.Container:
+		for k, v := range get(e.EntityMeta) {
+			merged[k] = v
+		}
+	case *workloadmeta.KubernetesPod:
+		for k, v := range get(e.EntityMeta) {
+			merged[k] = v
+		}
+	}
+	if pod := s.getPod(); pod != nil {
+		for k, v := range get(pod.EntityMeta) {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// GetNamespace returns the kubernetes namespace of the service.
+func (s *service) GetNamespace() string {
+	if pod := s.getPod(); pod != nil {
+		return pod.Namespace
+	}
+	return ""
+}
+
+// GetPodName returns the name of the service's pod.
+func (s *service) GetPodName() string {
+	if pod := s.getPod(); pod != nil {
+		return pod.Name
+	}
+	return ""
+}
+
+// GetPodUID returns the uid of the service's pod.
+func (s *service) GetPodUID() string {
+	if pod := s.getPod(); pod != nil {
+		return pod.ID
+	}
+	return ""
+}
+
+// GetImage returns the image of the service's container.
+func (s *service) GetImage() (workloadmeta.ContainerImage, bool) {
+	if container, ok := s.entity.(*workloadmeta.Container); ok {
+		return container.Image, true
+	}
+	return workloadmeta.ContainerImage{}, false
+}
+
+// getPod returns the pod of the service, or the pod of its container.
+func (s *service) getPod() *workloadmeta.KubernetesPod {
+	if pod, ok := s.entity.(*workloadmeta.KubernetesPod); ok {
+		return pod
+	}
+	return s.pod
+}
+
 // svcEqual checks that two Services are equal to each other by doing a deep
 // equality check on data returned by most of Service's methods. Methods not
 // checked are HasFilter and GetExtraConfig.
@@ -12,6 +12,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
 // ContainerPort represents a network port in a Service.
@@ -39,6 +40,17 @@ type Service interface {
 	GetExtraConfig([]byte) ([]byte, error)               // Extra configuration values
 }
 
+// MetadataService is implemented by the services of containers and pods exposing
+// the metadata of their workload to the template variables.
+type MetadataService interface {
+	GetLabels() map[string]string                  // labels of the container and of its pod
+	GetAnnotations() map[string]string             // annotations of the container and of its pod
+	GetNamespace() string                          // kubernetes namespace, empty outside of kubernetes
+	GetPodName() string                            // name of the pod, empty outside of kubernetes
+	GetPodUID() string                             // uid of the pod, empty outside of kubernetes
+	GetImage() (workloadmeta.ContainerImage, bool) // image of the container, not found for pods
+}
+
 // ServiceListener monitors running services and triggers check (un)scheduling
 //
 // It holds a cache of running services, listens to new/killed services and
@@ -0,0 +1,386 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers/names"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/flavor"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// datadogCheckValidCondition is the status condition reporting the validation errors of a DatadogCheck
+	datadogCheckValidCondition = "Valid"
+	datadogCheckValidReason    = "ValidSpec"
+	datadogCheckInvalidReason  = "InvalidSpec"
+)
+
+var (
+	gvrDatadogChecks = schema.GroupVersionResource{
+		Group:    "datadoghq.com",
+		Version:  "v1alpha1",
+		Resource: "datadogchecks",
+	}
+
+	// namespacedADIdentifierPrefixes are the prefixes of the AD identifiers of the kubernetes services and endpoints,
+	// followed by their namespace
+	namespacedADIdentifierPrefixes = []string{"kube_service://", "kube_endpoint_uid://"}
+
+	// nodeCheckConfigs holds the node-local checks collected by the cluster agent,
+	// which dispatches them to the node agents with the endpoints checks
+	nodeCheckConfigs = struct {
+		sync.RWMutex
+		configs []integration.Config
+	}{}
+)
+
+// datadogCheckSpec is the spec of a DatadogCheck custom resource
+type datadogCheckSpec struct {
+	CheckName     string   `json:"checkName"`
+	ADIdentifiers []string `json:"adIdentifiers"`
+	// ClusterCheck checks are dispatched by the cluster agent, the other checks are scheduled
+	// by the node agents on the services matching their AD identifiers
+	ClusterCheck            bool              `json:"clusterCheck"`
+	InitConfig              json.RawMessage   `json:"initConfig"`
+	Instances               []json.RawMessage `json:"instances"`
+	Logs                    json.RawMessage   `json:"logs"`
+	IgnoreAutodiscoveryTags bool              `json:"ignoreAutodiscoveryTags"`
+}
+
+// KubeCRDConfigProvider implements the ConfigProvider interface for the DatadogCheck custom resources.
+// It only runs in the cluster agent, which schedules the cluster checks, keeps the node-local checks
+// for the node agents and writes the validation errors in the status of the resources.
+type KubeCRDConfigProvider struct {
+	sync.RWMutex
+	client       dynamic.Interface
+	lister       cache.GenericLister
+	upToDate     bool
+	configErrors map[string]ErrorMsgSet
+}
+
+// NewKubeCRDConfigProvider returns a new ConfigProvider watching the DatadogCheck custom resources
+// with the shared informer factory of the datadoghq types.
+func NewKubeCRDConfigProvider(*config.ConfigurationProviders) (ConfigProvider, error) {
+	if flavor.GetFlavor() != flavor.ClusterAgent {
+		return nil, fmt.Errorf("the %s provider only runs in the cluster agent, node agents get the node-local checks with the %s provider", names.KubeCRDRegisterName, names.EndpointsChecksRegisterName)
+	}
+
+	ac, err := apiserver.GetAPIClient()
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to apiserver: %s", err)
+	}
+	if ac.DDClient == nil || ac.DDInformerFactory == nil {
+		return nil, fmt.Errorf("the datadoghq informer factory is not initialized")
+	}
+
+	p := newKubeCRDConfigProvider(ac.DDClient, ac.DDInformerFactory)
+	ac.DDInformerFactory.Start(wait.NeverStop)
+
+	return p, nil
+}
+
+func newKubeCRDConfigProvider(client dynamic.Interface, informerFactory dynamicinformer.DynamicSharedInformerFactory) *KubeCRDConfigProvider {
+	informer := informerFactory.ForResource(gvrDatadogChecks)
+	p := &KubeCRDConfigProvider{
+		client:       client,
+		lister:       informer.Lister(),
+		configErrors: make(map[string]ErrorMsgSet),
+	}
+
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    p.invalidate,
+		UpdateFunc: p.invalidateIfChanged,
+		DeleteFunc: p.invalidate,
+	})
+
+	return p
+}
+
+// String returns a string representation of the KubeCRDConfigProvider
+func (p *KubeCRDConfigProvider) String() string {
+	return names.KubeCRD
+}
+
+// Collect retrieves the DatadogCheck resources from the apiserver, builds Config objects and returns
+// the cluster checks. The node-local checks are kept for the node agents, see GetKubeCRDNodeConfigs.
+func (p *KubeCRDConfigProvider) Collect(ctx context.Context) ([]integration.Config, error) {
+	// set before listing, so that the changes made meanwhile trigger another collection
+	p.Lock()
+	p.upToDate = true
+	p.Unlock()
+
+	objects, err := p.lister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var configs, nodeConfigs []integration.Config
+	configErrors := make(map[string]ErrorMsgSet)
+	for _, obj := range objects {
+		check, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			log.Errorf("Expected an Unstructured type, got: %T", obj)
+			continue
+		}
+
+		namespacedName := check.GetNamespace() + "/" + check.GetName()
+		conf, err := parseDatadogCheck(check)
+		if err != nil {
+			log.Errorf("Cannot parse DatadogCheck %s: %s", namespacedName, err)
+			configErrors[namespacedName] = ErrorMsgSet{err.Error(): {}}
+		} else if conf.ClusterCheck {
+			configs = append(configs, conf)
+		} else {
+			nodeConfigs = append(nodeConfigs, conf)
+		}
+
+		if err := p.updateStatus(ctx, check, err); err != nil {
+			log.Warnf("Cannot update the status of DatadogCheck %s: %s", namespacedName, err)
+		}
+	}
+
+	nodeCheckConfigs.Lock()
+	nodeCheckConfigs.configs = nodeConfigs
+	nodeCheckConfigs.Unlock()
+
+	p.Lock()
+	p.configErrors = configErrors
+	p.Unlock()
+	telemetry.Errors.Set(float64(len(configErrors)), names.KubeCRD)
+
+	return configs, nil
+}
+
+// IsUpToDate allows to cache configs as long as no changes are detected in the apiserver
+func (p *KubeCRDConfigProvider) IsUpToDate(ctx context.Context) (bool, error) {
+	p.RLock()
+	defer p.RUnlock()
+	return p.upToDate, nil
+}
+
+// GetConfigErrors returns a map of the validation errors for each namespace/name of DatadogCheck
+func (p *KubeCRDConfigProvider) GetConfigErrors() map[string]ErrorMsgSet {
+	p.RLock()
+	defer p.RUnlock()
+	return p.configErrors
+}
+
+// GetKubeCRDNodeConfigs returns the node-local checks of the DatadogCheck resources,
+// as of the last collection of the provider.
+func GetKubeCRDNodeConfigs() []integration.Config {
+	nodeCheckConfigs.RLock()
+	defer nodeCheckConfigs.RUnlock()
+	return nodeCheckConfigs.configs
+}
+
+func (p *KubeCRDConfigProvider) invalidate(obj interface{}) {
+	if obj != nil {
+		log.Trace("Invalidating configs on new/deleted DatadogCheck")
+		p.Lock()
+		p.upToDate = false
+		p.Unlock()
+	}
+}
+
+func (p *KubeCRDConfigProvider) invalidateIfChanged(old, obj interface{}) {
+	castedObj, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		log.Errorf("Expected an Unstructured type, got: %T", obj)
+		return
+	}
+	castedOld, ok := old.(*unstructured.Unstructured)
+	if !ok {
+		log.Errorf("Expected an Unstructured type, got: %T", old)
+		p.invalidate(obj)
+		return
+	}
+	// The generation only changes with the spec, this ignores the updates of the status
+	if castedObj.GetGeneration() == castedOld.GetGeneration() {
+		return
+	}
+	log.Trace("Invalidating configs on DatadogCheck change")
+	p.invalidate(obj)
+}
+
+// parseDatadogCheck validates a DatadogCheck and translates it into a config
+func parseDatadogCheck(check *unstructured.Unstructured) (integration.Config, error) {
+	rawSpec, found, err := unstructured.NestedMap(check.Object, "spec")
+	if err != nil || !found {
+		return integration.Config{}, fmt.Errorf("spec is missing")
+	}
+	specJSON, err := json.Marshal(rawSpec)
+	if err != nil {
+		return integration.Config{}, err
+	}
+	var spec datadogCheckSpec
+	decoder := json.NewDecoder(bytes.NewReader(specJSON))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return integration.Config{}, fmt.Errorf("invalid spec: %s", err)
+	}
+
+	if err := validateDatadogCheckSpec(check.GetNamespace(), &spec); err != nil {
+		return integration.Config{}, err
+	}
+
+	conf := integration.Config{
+		Name:                    spec.CheckName,
+		ADIdentifiers:           spec.ADIdentifiers,
+		InitConfig:              integration.Data("{}"),
+		ClusterCheck:            spec.ClusterCheck,
+		IgnoreAutodiscoveryTags: spec.IgnoreAutodiscoveryTags,
+		Source:                  "kube_crd:" + check.GetNamespace() + "/" + check.GetName(),
+	}
+	if !spec.ClusterCheck {
+		// the node-local checks only target the containers of the pods of their namespace
+		conf.ServiceNamespace = check.GetNamespace()
+	}
+	if isSet(spec.InitConfig) {
+		conf.InitConfig = integration.Data(spec.InitConfig)
+	}
+	for _, instance := range spec.Instances {
+		conf.Instances = append(conf.Instances, integration.Data(instance))
+	}
+	if isSet(spec.Logs) {
+		conf.LogsConfig = integration.Data(spec.Logs)
+	}
+	return conf, nil
+}
+
+// validateDatadogCheckSpec raises an error if the spec is misconfigured:
+// - a check needs a name and at least one instance, unless it only collects logs
+// - the node-local checks need AD identifiers, which can't target the services and endpoints of the cluster agent
+// - the cluster checks can't collect logs
+// - the cluster checks can only target the services and endpoints of their namespace
+func validateDatadogCheckSpec(namespace string, spec *datadogCheckSpec) error {
+	if len(spec.Instances) == 0 && !isSet(spec.Logs) {
+		return fmt.Errorf("at least one instance or a logs config is required")
+	}
+	if len(spec.Instances) > 0 && spec.CheckName == "" {
+		return fmt.Errorf("checkName is required")
+	}
+	if isSet(spec.InitConfig) && !isJSONObject(spec.InitConfig) {
+		return fmt.Errorf("initConfig must be an object")
+	}
+	for _, instance := range spec.Instances {
+		if !isJSONObject(instance) {
+			return fmt.Errorf("instances must be objects")
+		}
+	}
+	if isSet(spec.Logs) && !bytes.HasPrefix(bytes.TrimSpace(spec.Logs), []byte("[")) {
+		return fmt.Errorf("logs must be a list")
+	}
+
+	if !spec.ClusterCheck {
+		if len(spec.ADIdentifiers) == 0 {
+			return fmt.Errorf("adIdentifiers are required, unless clusterCheck is true")
+		}
+		for _, adID := range spec.ADIdentifiers {
+			for _, prefix := range namespacedADIdentifierPrefixes {
+				if strings.HasPrefix(adID, prefix) {
+					return fmt.Errorf("adIdentifier %s can only be used by cluster checks", adID)
+				}
+			}
+		}
+		return nil
+	}
+
+	if isSet(spec.Logs) {
+		return fmt.Errorf("logs can't be collected by cluster checks")
+	}
+	for _, adID := range spec.ADIdentifiers {
+		for _, prefix := range namespacedADIdentifierPrefixes {
+			if strings.HasPrefix(adID, prefix) && !strings.HasPrefix(strings.TrimPrefix(adID, prefix), namespace+"/") {
+				return fmt.Errorf("adIdentifier %s doesn't belong to namespace %s", adID, namespace)
+			}
+		}
+	}
+	return nil
+}
+
+func isSet(data json.RawMessage) bool {
+	return len(data) > 0 && string(data) != "null"
+}
+
+func isJSONObject(data json.RawMessage) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// updateStatus writes the validation error of a DatadogCheck, if any, in its `Valid` status condition
+func (p *KubeCRDConfigProvider) updateStatus(ctx context.Context, check *unstructured.Unstructured, validationErr error) error {
+	status, reason, message := metav1.ConditionTrue, datadogCheckValidReason, ""
+	if validationErr != nil {
+		status, reason, message = metav1.ConditionFalse, datadogCheckInvalidReason, validationErr.Error()
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(check.Object, "status", "conditions")
+	observedGeneration, _, _ := unstructured.NestedInt64(check.Object, "status", "observedGeneration")
+
+	condition := map[string]interface{}{
+		"type":               datadogCheckValidCondition,
+		"status":             string(status),
+		"reason":             reason,
+		"message":            message,
+		"lastTransitionTime": time.Now().UTC().Format(time.RFC3339),
+	}
+	index := -1
+	for i, c := range conditions {
+		existing, ok := c.(map[string]interface{})
+		if !ok || existing["type"] != datadogCheckValidCondition {
+			continue
+		}
+		index = i
+		if existing["status"] == condition["status"] {
+			if existing["message"] == condition["message"] && observedGeneration == check.GetGeneration() {
+				return nil
+			}
+			condition["lastTransitionTime"] = existing["lastTransitionTime"]
+		}
+	}
+	if index >= 0 {
+		conditions[index] = condition
+	} else {
+		conditions = append(conditions, condition)
+	}
+
+	updated := check.DeepCopy()
+	if err := unstructured.SetNestedSlice(updated.Object, conditions, "status", "conditions"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(updated.Object, check.GetGeneration(), "status", "observedGeneration"); err != nil {
+		return err
+	}
+	_, err := p.client.Resource(gvrDatadogChecks).Namespace(check.GetNamespace()).UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+func init() {
+	RegisterProvider(names.KubeCRDRegisterName, NewKubeCRDConfigProvider)
+}
@@ -0,0 +1,16 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !kubeapiserver
+// +build !kubeapiserver
+
+package providers
+
+import "github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+
+// GetKubeCRDNodeConfigs returns nil, the DatadogCheck resources are only collected with the kubeapiserver build tag
+func GetKubeCRDNodeConfigs() []integration.Config {
+	return nil
+}
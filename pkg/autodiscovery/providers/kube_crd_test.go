@@ -0,0 +1,258 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+)
+
+func newDatadogCheck(namespace, name string, generation int64, spec map[string]interface{}) *unstructured.Unstructured {
+	check := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "datadoghq.com/v1alpha1",
+			"kind":       "DatadogCheck",
+			"metadata": map[string]interface{}{
+				"namespace":  namespace,
+				"name":       name,
+				"generation": generation,
+			},
+		},
+	}
+	if spec != nil {
+		check.Object["spec"] = spec
+	}
+	return check
+}
+
+func TestParseDatadogCheck(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		check       *unstructured.Unstructured
+		expected    integration.Config
+		expectedErr string
+	}{
+		{
+			name: "node-local check",
+			check: newDatadogCheck("default", "redis", 1, map[string]interface{}{
+				"checkName":     "redisdb",
+				"adIdentifiers": []interface{}{"redis"},
+				"instances":     []interface{}{map[string]interface{}{"host": "%%host%%", "port": int64(6379)}},
+				"logs":          []interface{}{map[string]interface{}{"source": "redis"}},
+			}),
+			expected: integration.Config{
+				Name:             "redisdb",
+				ADIdentifiers:    []string{"redis"},
+				InitConfig:       integration.Data("{}"),
+				Instances:        []integration.Data{integration.Data(`{"host":"%%host%%","port":6379}`)},
+				LogsConfig:       integration.Data(`[{"source":"redis"}]`),
+				Source:           "kube_crd:default/redis",
+				ServiceNamespace: "default",
+			},
+		},
+		{
+			name: "cluster check on a service",
+			check: newDatadogCheck("default", "http", 1, map[string]interface{}{
+				"checkName":               "http_check",
+				"clusterCheck":            true,
+				"adIdentifiers":           []interface{}{"kube_service://default/my-service"},
+				"initConfig":              map[string]interface{}{"timeout": int64(5)},
+				"instances":               []interface{}{map[string]interface{}{"url": "http://%%host%%"}},
+				"ignoreAutodiscoveryTags": true,
+			}),
+			expected: integration.Config{
+				Name:                    "http_check",
+				ADIdentifiers:           []string{"kube_service://default/my-service"},
+				InitConfig:              integration.Data(`{"timeout":5}`),
+				Instances:               []integration.Data{integration.Data(`{"url":"http://%%host%%"}`)},
+				ClusterCheck:            true,
+				IgnoreAutodiscoveryTags: true,
+				Source:                  "kube_crd:default/http",
+			},
+		},
+		{
+			name:        "missing spec",
+			check:       newDatadogCheck("default", "empty", 1, nil),
+			expectedErr: "spec is missing",
+		},
+		{
+			name: "unknown field",
+			check: newDatadogCheck("default", "typo", 1, map[string]interface{}{
+				"checkName": "redisdb",
+				"instance":  []interface{}{map[string]interface{}{}},
+			}),
+			expectedErr: `invalid spec: json: unknown field "instance"`,
+		},
+		{
+			name: "missing check name",
+			check: newDatadogCheck("default", "noname", 1, map[string]interface{}{
+				"adIdentifiers": []interface{}{"redis"},
+				"instances":     []interface{}{map[string]interface{}{}},
+			}),
+			expectedErr: "checkName is required",
+		},
+		{
+			name: "node-local check without AD identifiers",
+			check: newDatadogCheck("default", "noid", 1, map[string]interface{}{
+				"checkName": "redisdb",
+				"instances": []interface{}{map[string]interface{}{}},
+			}),
+			expectedErr: "adIdentifiers are required, unless clusterCheck is true",
+		},
+		{
+			name: "node-local check on a service",
+			check: newDatadogCheck("default", "http", 1, map[string]interface{}{
+				"checkName":     "http_check",
+				"adIdentifiers": []interface{}{"kube_service://default/my-service"},
+				"instances":     []interface{}{map[string]interface{}{}},
+			}),
+			expectedErr: "adIdentifier kube_service://default/my-service can only be used by cluster checks",
+		},
+		{
+			name: "cluster check collecting logs",
+			check: newDatadogCheck("default", "logs", 1, map[string]interface{}{
+				"clusterCheck": true,
+				"logs":         []interface{}{map[string]interface{}{"source": "redis"}},
+			}),
+			expectedErr: "logs can't be collected by cluster checks",
+		},
+		{
+			name: "cluster check on a service of another namespace",
+			check: newDatadogCheck("team-a", "http", 1, map[string]interface{}{
+				"checkName":     "http_check",
+				"clusterCheck":  true,
+				"adIdentifiers": []interface{}{"kube_service://team-b/my-service"},
+				"instances":     []interface{}{map[string]interface{}{}},
+			}),
+			expectedErr: "adIdentifier kube_service://team-b/my-service doesn't belong to namespace team-a",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conf, err := parseDatadogCheck(tc.check)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, conf)
+		})
+	}
+}
+
+func TestKubeCRDConfigProviderCollect(t *testing.T) {
+	objects := []runtime.Object{
+		newDatadogCheck("default", "redis", 1, map[string]interface{}{
+			"checkName":     "redisdb",
+			"adIdentifiers": []interface{}{"redis"},
+			"instances":     []interface{}{map[string]interface{}{"host": "%%host%%"}},
+		}),
+		newDatadogCheck("default", "http", 1, map[string]interface{}{
+			"checkName":    "http_check",
+			"clusterCheck": true,
+			"instances":    []interface{}{map[string]interface{}{"url": "http://example.com"}},
+		}),
+		newDatadogCheck("default", "invalid", 2, map[string]interface{}{
+			"checkName": "redisdb",
+			"instances": []interface{}{map[string]interface{}{}},
+		}),
+	}
+
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvrDatadogChecks: "DatadogCheckList"},
+		objects...,
+	)
+	informerFactory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	provider := newKubeCRDConfigProvider(client, informerFactory)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	informerFactory.Start(stopCh)
+	require.True(t, cache.WaitForCacheSync(stopCh, informerFactory.ForResource(gvrDatadogChecks).Informer().HasSynced))
+
+	configs, err := provider.Collect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.Equal(t, "http_check", configs[0].Name)
+
+	// the node-local checks are kept for the node agents
+	nodeConfigs := GetKubeCRDNodeConfigs()
+	require.Len(t, nodeConfigs, 1)
+	assert.Equal(t, "redisdb", nodeConfigs[0].Name)
+	assert.Equal(t, "default", nodeConfigs[0].ServiceNamespace)
+
+	upToDate, err := provider.IsUpToDate(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, upToDate)
+
+	assert.Equal(t, map[string]ErrorMsgSet{
+		"default/invalid": {"adIdentifiers are required, unless clusterCheck is true": {}},
+	}, provider.GetConfigErrors())
+
+	invalid, err := client.Resource(gvrDatadogChecks).Namespace("default").Get(context.Background(), "invalid", metav1.GetOptions{})
+	require.NoError(t, err)
+	conditions, _, _ := unstructured.NestedSlice(invalid.Object, "status", "conditions")
+	require.Len(t, conditions, 1)
+	condition := conditions[0].(map[string]interface{})
+	assert.Equal(t, "Valid", condition["type"])
+	assert.Equal(t, "False", condition["status"])
+	assert.Equal(t, "InvalidSpec", condition["reason"])
+	assert.Equal(t, "adIdentifiers are required, unless clusterCheck is true", condition["message"])
+	observedGeneration, _, _ := unstructured.NestedInt64(invalid.Object, "status", "observedGeneration")
+	assert.Equal(t, int64(2), observedGeneration)
+
+	valid, err := client.Resource(gvrDatadogChecks).Namespace("default").Get(context.Background(), "redis", metav1.GetOptions{})
+	require.NoError(t, err)
+	validConditions, _, _ := unstructured.NestedSlice(valid.Object, "status", "conditions")
+	require.Len(t, validConditions, 1)
+	assert.Equal(t, "True", validConditions[0].(map[string]interface{})["status"])
+
+	// the status is only written when it changes
+	for _, name := range []string{"redis", "http", "invalid"} {
+		assert.Eventually(t, func() bool {
+			obj, err := provider.lister.ByNamespace("default").Get(name)
+			if err != nil {
+				return false
+			}
+			_, found, _ := unstructured.NestedSlice(obj.(*unstructured.Unstructured).Object, "status", "conditions")
+			return found
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	client.ClearActions()
+	_, err = provider.Collect(context.Background())
+	require.NoError(t, err)
+	for _, action := range client.Actions() {
+		assert.NotEqual(t, "update", action.GetVerb())
+	}
+}
+
+func TestKubeCRDConfigProviderInvalidateIfChanged(t *testing.T) {
+	provider := &KubeCRDConfigProvider{upToDate: true}
+
+	// status update
+	provider.invalidateIfChanged(newDatadogCheck("default", "redis", 1, nil), newDatadogCheck("default", "redis", 1, nil))
+	assert.True(t, provider.upToDate)
+
+	// spec update
+	provider.invalidateIfChanged(newDatadogCheck("default", "redis", 1, nil), newDatadogCheck("default", "redis", 2, nil))
+	assert.False(t, provider.upToDate)
+}
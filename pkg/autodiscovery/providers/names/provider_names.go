@@ -16,6 +16,7 @@ const (
 	Etcd               = "etcd"
 	File               = "file"
 	Kubernetes         = "kubernetes"
+	KubeCRD            = "kubernetes-crd"
 	KubeServices       = "kubernetes-services"
 	KubeServicesFile   = "kubernetes-services-file"
 	KubeEndpoints      = "kubernetes-endpoints"
@@ -36,6 +37,7 @@ const (
 	EndpointsChecksRegisterName    = "endpointschecks"
 	EtcdRegisterName               = "etcd"
 	KubeletRegisterName            = "kubelet"
+	KubeCRDRegisterName            = "kube_crd"
 	KubeServicesRegisterName       = "kube_services"
 	KubeServicesFileRegisterName   = "kube_services_file"
 	KubeEndpointsRegisterName      = "kube_endpoints"
@@ -10,9 +10,15 @@ package clusterchecks
 
 import (
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers"
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers/names"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
+// getNodeConfigs returns the node-local check templates served to all the node agents
+// with the endpoints checks. Overridden by the tests.
+var getNodeConfigs = providers.GetKubeCRDNodeConfigs
+
 // getEndpointsConfigs provides configs templates of endpoints checks queried by node name.
 // Exposed to node agents by the cluster agent api.
 func (d *dispatcher) getEndpointsConfigs(nodeName string) ([]integration.Config, error) {
@@ -22,7 +28,7 @@ func (d *dispatcher) getEndpointsConfigs(nodeName string) ([]integration.Config,
 		nodeConfigs = append(nodeConfigs, v)
 	}
 	d.store.RUnlock()
-	return nodeConfigs, nil
+	return append(nodeConfigs, d.getPatchedNodeConfigs()...), nil
 }
 
 // getAllEndpointsCheckConfigs provides all config templates of endpoints checks
@@ -35,7 +41,22 @@ func (d *dispatcher) getAllEndpointsCheckConfigs() ([]integration.Config, error)
 			configs = append(configs, config)
 		}
 	}
-	return configs, nil
+	return append(configs, d.getPatchedNodeConfigs()...), nil
+}
+
+// getPatchedNodeConfigs returns the node-local check templates, patched like the endpoints configs.
+// They are resolved by the node agents on their own services.
+func (d *dispatcher) getPatchedNodeConfigs() []integration.Config {
+	var configs []integration.Config
+	for _, c := range getNodeConfigs() {
+		patched, err := d.patchEndpointsConfiguration(c)
+		if err != nil {
+			log.Warnf("Cannot patch node configuration %s: %s", c.Digest(), err)
+			continue
+		}
+		configs = append(configs, patched)
+	}
+	return configs
 }
 
 // addEndpointConfig stores a given endpoint configuration by node name
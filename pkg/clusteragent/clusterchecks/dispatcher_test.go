@@ -18,6 +18,7 @@ import (
 	yaml "gopkg.in/yaml.v2"
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/clustername"
@@ -543,6 +544,58 @@ func TestGetAllEndpointsCheckConfigs(t *testing.T) {
 	requireNotLocked(t, dispatcher.store)
 }
 
+func TestGetEndpointsConfigsWithNodeConfigs(t *testing.T) {
+	nodeConfig := integration.Config{
+		Name:             "redisdb",
+		ADIdentifiers:    []string{"redis"},
+		Instances:        []integration.Data{integration.Data("tags: [\"foo:bar\"]")},
+		ServiceNamespace: "default",
+	}
+	getNodeConfigs = func() []integration.Config { return []integration.Config{nodeConfig} }
+	defer func() { getNodeConfigs = providers.GetKubeCRDNodeConfigs }()
+
+	mockConfig := config.Mock()
+	mockConfig.Set("cluster_name", "testing")
+	clustername.ResetClusterName()
+	dispatcher := newDispatcher()
+	dispatcher.addEndpointConfig(generateEndpointsIntegration("endpoints-check1", "node1"), "node1")
+
+	// The node configs are served to every node
+	for _, node := range []string{"node1", "node2"} {
+		configs, err := dispatcher.getEndpointsConfigs(node)
+		assert.NoError(t, err)
+		assert.Contains(t, extractCheckNames(configs), "redisdb")
+
+		for _, c := range configs {
+			if c.Name != "redisdb" {
+				continue
+			}
+			assert.False(t, c.ClusterCheck)
+			assert.Equal(t, []string{"redis"}, c.ADIdentifiers)
+			assert.Equal(t, "default", c.ServiceNamespace)
+
+			rawConfig := integration.RawMap{}
+			err = yaml.Unmarshal(c.Instances[0], &rawConfig)
+			assert.NoError(t, err)
+			assert.Contains(t, rawConfig["tags"], "foo:bar")
+			assert.Contains(t, rawConfig["tags"], "kube_cluster_name:testing")
+		}
+	}
+
+	configs, err := dispatcher.getEndpointsConfigs("node1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"endpoints-check1", "redisdb"}, extractCheckNames(configs))
+
+	configs, err = dispatcher.getAllEndpointsCheckConfigs()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"endpoints-check1", "redisdb"}, extractCheckNames(configs))
+
+	// The original config is not modified
+	assert.Equal(t, integration.Data("tags: [\"foo:bar\"]"), nodeConfig.Instances[0])
+
+	requireNotLocked(t, dispatcher.store)
+}
+
 // dummyClcRunnerClient mocks the clcRunnersClient
 var dummyClcRunnerClient dummyClientStruct
 
@@ -0,0 +1,291 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultTimeout            = 10
+	defaultMaxReturnedMetrics = 2000
+	defaultBearerTokenPath    = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// instanceConfig contains the options of an instance, using the option names of both the
+// version 1 (`prometheus_url`) and the version 2 (`openmetrics_endpoint`) of the Python check.
+type instanceConfig struct {
+	PrometheusURL       string `yaml:"prometheus_url"`
+	OpenMetricsEndpoint string `yaml:"openmetrics_endpoint"`
+
+	Namespace string        `yaml:"namespace"`
+	Metrics   []interface{} `yaml:"metrics"`
+
+	PrometheusMetricsPrefix string `yaml:"prometheus_metrics_prefix"`
+	RawMetricPrefix         string `yaml:"raw_metric_prefix"`
+
+	IgnoreMetrics  []string `yaml:"ignore_metrics"`
+	ExcludeMetrics []string `yaml:"exclude_metrics"`
+
+	LabelsMapper    map[string]string           `yaml:"labels_mapper"`
+	RenameLabels    map[string]string           `yaml:"rename_labels"`
+	ExcludeLabels   []string                    `yaml:"exclude_labels"`
+	LabelJoins      map[string]labelJoinsConfig `yaml:"label_joins"`
+	LabelToHostname string                      `yaml:"label_to_hostname"`
+	TypeOverrides   map[string]string           `yaml:"type_overrides"`
+
+	SendHistogramsBuckets           *bool `yaml:"send_histograms_buckets"`
+	CollectHistogramBuckets         *bool `yaml:"collect_histogram_buckets"`
+	SendDistributionBuckets         bool  `yaml:"send_distribution_buckets"`
+	HistogramBucketsAsDistributions bool  `yaml:"histogram_buckets_as_distributions"`
+	SendMonotonicCounter            *bool `yaml:"send_monotonic_counter"`
+	DistributionCountsAsMonotonic   bool  `yaml:"send_distribution_counts_as_monotonic"`
+	DistributionSumsAsMonotonic     bool  `yaml:"send_distribution_sums_as_monotonic"`
+
+	HealthServiceCheck       *bool `yaml:"health_service_check"`
+	EnableHealthServiceCheck *bool `yaml:"enable_health_service_check"`
+
+	MaxReturnedMetrics int `yaml:"max_returned_metrics"`
+
+	BearerTokenAuth bool              `yaml:"bearer_token_auth"`
+	BearerTokenPath string            `yaml:"bearer_token_path"`
+	Headers         map[string]string `yaml:"headers"`
+	ExtraHeaders    map[string]string `yaml:"extra_headers"`
+	Username        string            `yaml:"username"`
+	Password        string            `yaml:"password"`
+	TLSVerify       *bool             `yaml:"tls_verify"`
+	TLSCACert       string            `yaml:"tls_ca_cert"`
+	TLSCert         string            `yaml:"tls_cert"`
+	TLSPrivateKey   string            `yaml:"tls_private_key"`
+	SkipProxy       bool              `yaml:"skip_proxy"`
+	Timeout         int               `yaml:"timeout"`
+}
+
+// labelJoinsConfig describes how labels of a metric are added to the other metrics
+// having the same values for the labels to match.
+type labelJoinsConfig struct {
+	LabelsToMatch []string `yaml:"labels_to_match"`
+	LabelsToGet   []string `yaml:"labels_to_get"`
+}
+
+// metricMatcher matches the names of the metrics to collect, and optionally renames them.
+type metricMatcher struct {
+	pattern *regexp.Regexp
+	// newName is the name the metric is submitted with, the matched name is used when empty
+	newName string
+	// metricType overrides the type of the metric when not empty
+	metricType string
+}
+
+// config is the parsed configuration of an instance
+type config struct {
+	endpoint string
+	// v2 is true when the instance uses the options of the version 2 of the check
+	v2        bool
+	namespace string
+	prefix    string
+	metrics   []metricMatcher
+	exclude   []*regexp.Regexp
+
+	renameLabels    map[string]string
+	excludeLabels   map[string]struct{}
+	labelJoins      map[string]labelJoinsConfig
+	labelToHostname string
+	typeOverrides   map[string]string
+
+	sendBuckets                   bool
+	distributionBuckets           bool
+	monotonicCounter              bool
+	distributionCountsAsMonotonic bool
+	distributionSumsAsMonotonic   bool
+	healthServiceCheck            bool
+	maxReturnedMetrics            int
+
+	bearerTokenAuth bool
+	bearerTokenPath string
+	headers         map[string]string
+	username        string
+	password        string
+	tlsVerify       bool
+	tlsCACert       string
+	tlsCert         string
+	tlsPrivateKey   string
+	skipProxy       bool
+	timeout         time.Duration
+}
+
+func parseConfig(data []byte) (*config, error) {
+	var instance instanceConfig
+	if err := yaml.Unmarshal(data, &instance); err != nil {
+		return nil, err
+	}
+
+	c := &config{
+		endpoint:                      instance.PrometheusURL,
+		namespace:                     instance.Namespace,
+		prefix:                        instance.PrometheusMetricsPrefix,
+		renameLabels:                  instance.LabelsMapper,
+		labelJoins:                    instance.LabelJoins,
+		labelToHostname:               instance.LabelToHostname,
+		distributionBuckets:           instance.SendDistributionBuckets,
+		distributionCountsAsMonotonic: instance.DistributionCountsAsMonotonic,
+		distributionSumsAsMonotonic:   instance.DistributionSumsAsMonotonic,
+		maxReturnedMetrics:            instance.MaxReturnedMetrics,
+		bearerTokenAuth:               instance.BearerTokenAuth,
+		bearerTokenPath:               instance.BearerTokenPath,
+		username:                      instance.Username,
+		password:                      instance.Password,
+		tlsCACert:                     instance.TLSCACert,
+		tlsCert:                       instance.TLSCert,
+		tlsPrivateKey:                 instance.TLSPrivateKey,
+		skipProxy:                     instance.SkipProxy,
+		timeout:                       time.Duration(instance.Timeout) * time.Second,
+	}
+
+	sendBuckets, healthServiceCheck := instance.SendHistogramsBuckets, instance.HealthServiceCheck
+	ignoreMetrics := instance.IgnoreMetrics
+	if instance.OpenMetricsEndpoint != "" {
+		c.v2 = true
+		c.endpoint = instance.OpenMetricsEndpoint
+		c.prefix = instance.RawMetricPrefix
+		c.renameLabels = instance.RenameLabels
+		c.distributionBuckets = instance.HistogramBucketsAsDistributions
+		sendBuckets, healthServiceCheck = instance.CollectHistogramBuckets, instance.EnableHealthServiceCheck
+		ignoreMetrics = instance.ExcludeMetrics
+	}
+
+	if c.endpoint == "" {
+		return nil, fmt.Errorf("one of `prometheus_url` or `openmetrics_endpoint` must be set")
+	}
+	if len(instance.Metrics) == 0 {
+		return nil, fmt.Errorf("`metrics` must be set")
+	}
+
+	c.sendBuckets = boolValue(sendBuckets, true)
+	c.healthServiceCheck = boolValue(healthServiceCheck, true)
+	c.monotonicCounter = boolValue(instance.SendMonotonicCounter, true)
+	c.tlsVerify = boolValue(instance.TLSVerify, true)
+
+	var err error
+	if c.metrics, err = c.parseMetrics(instance.Metrics); err != nil {
+		return nil, err
+	}
+	for _, pattern := range ignoreMetrics {
+		re, err := c.compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metric exclusion %q: %s", pattern, err)
+		}
+		c.exclude = append(c.exclude, re)
+	}
+
+	c.typeOverrides = make(map[string]string, len(instance.TypeOverrides))
+	for name, metricType := range instance.TypeOverrides {
+		if !isValidType(metricType) {
+			return nil, fmt.Errorf("invalid type %q for metric %q in `type_overrides`", metricType, name)
+		}
+		c.typeOverrides[name] = metricType
+	}
+
+	c.excludeLabels = make(map[string]struct{}, len(instance.ExcludeLabels))
+	for _, label := range instance.ExcludeLabels {
+		c.excludeLabels[label] = struct{}{}
+	}
+
+	c.headers = make(map[string]string, len(instance.Headers)+len(instance.ExtraHeaders))
+	for k, v := range instance.Headers {
+		c.headers[k] = v
+	}
+	for k, v := range instance.ExtraHeaders {
+		c.headers[k] = v
+	}
+
+	if c.bearerTokenPath == "" {
+		c.bearerTokenPath = defaultBearerTokenPath
+	}
+	if c.maxReturnedMetrics <= 0 {
+		c.maxReturnedMetrics = defaultMaxReturnedMetrics
+	}
+	if c.timeout <= 0 {
+		c.timeout = defaultTimeout * time.Second
+	}
+
+	return c, nil
+}
+
+// parseMetrics parses the `metrics` option, whose entries are either patterns or
+// maps of metric names to their new names. With the version 2 of the options, the
+// new name can also be a map with a `name` and a `type`.
+func (c *config) parseMetrics(entries []interface{}) ([]metricMatcher, error) {
+	var matchers []metricMatcher
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case string:
+			re, err := c.compilePattern(e)
+			if err != nil {
+				return nil, fmt.Errorf("invalid metric pattern %q: %s", e, err)
+			}
+			matchers = append(matchers, metricMatcher{pattern: re})
+		case map[interface{}]interface{}:
+			for k, v := range e {
+				name, ok := k.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid metric name %v", k)
+				}
+				matcher := metricMatcher{pattern: regexp.MustCompile("^" + regexp.QuoteMeta(name) + "$")}
+				switch value := v.(type) {
+				case string:
+					matcher.newName = value
+				case map[interface{}]interface{}:
+					if !c.v2 {
+						return nil, fmt.Errorf("invalid new name for metric %q, it must be a string", name)
+					}
+					matcher.newName, _ = value["name"].(string)
+					matcher.metricType, _ = value["type"].(string)
+					if matcher.metricType != "" && !isValidType(matcher.metricType) {
+						return nil, fmt.Errorf("invalid type %q for metric %q", matcher.metricType, name)
+					}
+				default:
+					return nil, fmt.Errorf("invalid new name for metric %q", name)
+				}
+				matchers = append(matchers, matcher)
+			}
+		default:
+			return nil, fmt.Errorf("invalid metric %v, it must be a string or a map", entry)
+		}
+	}
+	return matchers, nil
+}
+
+// compilePattern compiles a metric pattern: a regular expression with the version 2
+// of the options, or a name with `*` wildcards with the version 1.
+func (c *config) compilePattern(pattern string) (*regexp.Regexp, error) {
+	if c.v2 {
+		return regexp.Compile("^(?:" + pattern + ")$")
+	}
+	return regexp.Compile("^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$")
+}
+
+// isValidType returns whether a metric type can be used to override the type of a metric.
+// Only the types of metrics made of a single value can be overridden.
+func isValidType(metricType string) bool {
+	switch metricType {
+	case "counter", "gauge", "untyped":
+		return true
+	}
+	return false
+}
+
+func boolValue(b *bool, defaultValue bool) bool {
+	if b == nil {
+		return defaultValue
+	}
+	return *b
+}
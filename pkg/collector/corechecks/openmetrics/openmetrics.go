@@ -0,0 +1,101 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package openmetrics implements a core check scraping the metrics exposed by an
+// endpoint in the Prometheus text or protobuf exposition formats. It supports the
+// instance options of the Python `openmetrics` check, version 1 and 2.
+package openmetrics
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const checkName = "openmetrics"
+
+// Check scrapes an OpenMetrics or Prometheus endpoint
+type Check struct {
+	core.CheckBase
+	cfg     *config
+	scraper *scraper
+}
+
+// Configure parses the check configuration and prepares the scraper
+func (c *Check) Configure(data integration.Data, initConfig integration.Data, source string) error {
+	cfg, err := parseConfig(data)
+	if err != nil {
+		log.Errorf("Error parsing configuration of the openmetrics check: %s", err)
+		return err
+	}
+
+	c.BuildID(data, initConfig)
+
+	err = c.CommonConfigure(data, source)
+	if err != nil {
+		return err
+	}
+
+	c.scraper, err = newScraper(cfg)
+	if err != nil {
+		return err
+	}
+	c.cfg = cfg
+
+	return nil
+}
+
+// Run scrapes the endpoint and submits its metrics
+func (c *Check) Run() error {
+	sender, err := aggregator.GetSender(c.ID())
+	if err != nil {
+		return err
+	}
+	defer sender.Commit()
+
+	families, err := c.scraper.scrape()
+	c.submitHealth(sender, err)
+	if err != nil {
+		return err
+	}
+
+	newSubmitter(c.cfg, sender).submit(families)
+	return nil
+}
+
+// submitHealth submits the service check reporting whether the endpoint could be scraped
+func (c *Check) submitHealth(sender aggregator.Sender, scrapeErr error) {
+	if !c.cfg.healthServiceCheck {
+		return
+	}
+
+	name := "prometheus.health"
+	if c.cfg.v2 {
+		name = "openmetrics.health"
+	}
+	if c.cfg.namespace != "" {
+		name = c.cfg.namespace + "." + name
+	}
+
+	tags := []string{"endpoint:" + c.cfg.endpoint}
+	if scrapeErr != nil {
+		sender.ServiceCheck(name, metrics.ServiceCheckCritical, "", tags, scrapeErr.Error())
+		return
+	}
+	sender.ServiceCheck(name, metrics.ServiceCheckOK, "", tags, "")
+}
+
+func openmetricsFactory() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(checkName),
+	}
+}
+
+func init() {
+	core.RegisterCheck(checkName, openmetricsFactory)
+}
@@ -0,0 +1,259 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+const exposition = `# HELP http_requests_total The total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="post",code="200"} 1027
+http_requests_total{method="post",code="400"} 3
+# HELP go_goroutines Number of goroutines that currently exist.
+# TYPE go_goroutines gauge
+go_goroutines{pod="web-1"} 42
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{le="0.1"} 10
+request_duration_seconds_bucket{le="0.5"} 25
+request_duration_seconds_bucket{le="+Inf"} 30
+request_duration_seconds_sum 8.5
+request_duration_seconds_count 30
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 0.2
+rpc_duration_seconds{quantile="0.9"} 0.7
+rpc_duration_seconds_sum 12
+rpc_duration_seconds_count 40
+# TYPE kube_pod_info gauge
+kube_pod_info{pod="web-1",node="node-a",namespace="prod"} 1
+`
+
+func newServer(t *testing.T, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", string(expfmt.FmtText))
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func runCheck(t *testing.T, instance string) *mocksender.MockSender {
+	check := openmetricsFactory().(*Check)
+	require.NoError(t, check.Configure([]byte(instance), []byte("{}"), "test"))
+
+	sender := mocksender.NewMockSender(check.ID())
+	sender.SetupAcceptAll()
+	require.NoError(t, check.Run())
+	return sender
+}
+
+func TestConfigure(t *testing.T) {
+	check := openmetricsFactory().(*Check)
+	assert.Error(t, check.Configure([]byte(`metrics: ["*"]`), nil, "test"))
+	assert.Error(t, check.Configure([]byte(`prometheus_url: http://localhost/metrics`), nil, "test"))
+	assert.Error(t, check.Configure([]byte(`
+prometheus_url: http://localhost/metrics
+metrics: ["*"]
+type_overrides:
+  foo: histogram
+`), nil, "test"))
+	assert.Error(t, check.Configure([]byte(`
+prometheus_url: http://localhost/metrics
+metrics:
+  - foo: {name: bar}
+`), nil, "test"))
+
+	require.NoError(t, check.Configure([]byte(`
+openmetrics_endpoint: http://localhost/metrics
+namespace: app
+metrics:
+  - go_.*
+  - foo: {name: bar, type: gauge}
+exclude_metrics: [go_gc_.*]
+`), nil, "test"))
+	assert.True(t, check.cfg.v2)
+	assert.Equal(t, "http://localhost/metrics", check.cfg.endpoint)
+	assert.True(t, check.cfg.sendBuckets)
+	assert.True(t, check.cfg.healthServiceCheck)
+	assert.Equal(t, defaultMaxReturnedMetrics, check.cfg.maxReturnedMetrics)
+	require.Len(t, check.cfg.metrics, 2)
+	assert.True(t, check.cfg.metrics[0].pattern.MatchString("go_goroutines"))
+	assert.False(t, check.cfg.metrics[0].pattern.MatchString("process_go_goroutines"))
+	assert.Equal(t, "bar", check.cfg.metrics[1].newName)
+	assert.Equal(t, "gauge", check.cfg.metrics[1].metricType)
+}
+
+func TestRunV1(t *testing.T) {
+	server := newServer(t, exposition)
+	sender := runCheck(t, fmt.Sprintf(`
+prometheus_url: %s
+namespace: app
+metrics:
+  - http_requests_total: requests
+  - go_*
+  - request_duration_seconds
+  - rpc_duration_seconds
+labels_mapper:
+  code: status_code
+exclude_labels: [method]
+`, server.URL))
+
+	sender.AssertServiceCheck(t, "app.prometheus.health", metrics.ServiceCheckOK, "", []string{"endpoint:" + server.URL}, "")
+
+	sender.AssertMetric(t, "MonotonicCount", "app.requests", 1027, "", []string{"status_code:200"})
+	sender.AssertMetric(t, "MonotonicCount", "app.requests", 3, "", []string{"status_code:400"})
+	sender.AssertMetricNotTaggedWith(t, "MonotonicCount", "app.requests", []string{"method:post"})
+	sender.AssertMetric(t, "Gauge", "app.go_goroutines", 42, "", []string{"pod:web-1"})
+
+	sender.AssertMetric(t, "Gauge", "app.request_duration_seconds.count", 30, "", []string{})
+	sender.AssertMetric(t, "Gauge", "app.request_duration_seconds.sum", 8.5, "", []string{})
+	sender.AssertMetric(t, "Gauge", "app.request_duration_seconds.count", 10, "", []string{"upper_bound:0.1"})
+	sender.AssertMetric(t, "Gauge", "app.request_duration_seconds.count", 30, "", []string{"upper_bound:none"})
+
+	sender.AssertMetric(t, "Gauge", "app.rpc_duration_seconds.count", 40, "", []string{})
+	sender.AssertMetric(t, "Gauge", "app.rpc_duration_seconds.quantile", 0.7, "", []string{"quantile:0.9"})
+
+	sender.AssertNotCalled(t, "Gauge", "app.kube_pod_info", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunV2(t *testing.T) {
+	server := newServer(t, exposition)
+	sender := runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s
+namespace: app
+metrics:
+  - http_requests
+  - request_duration_seconds
+  - rpc_duration_seconds
+  - go_goroutines: {name: goroutines, type: counter}
+`, server.URL))
+
+	sender.AssertServiceCheck(t, "app.openmetrics.health", metrics.ServiceCheckOK, "", []string{"endpoint:" + server.URL}, "")
+
+	sender.AssertMetric(t, "MonotonicCount", "app.http_requests.count", 1027, "", []string{"method:post", "code:200"})
+	sender.AssertMetric(t, "MonotonicCount", "app.goroutines.count", 42, "", []string{"pod:web-1"})
+	sender.AssertMetric(t, "MonotonicCount", "app.request_duration_seconds.count", 30, "", []string{})
+	sender.AssertMetric(t, "MonotonicCount", "app.request_duration_seconds.sum", 8.5, "", []string{})
+	sender.AssertMetric(t, "MonotonicCount", "app.request_duration_seconds.bucket", 25, "", []string{"upper_bound:0.5"})
+	sender.AssertMetric(t, "MonotonicCount", "app.request_duration_seconds.bucket", 30, "", []string{"upper_bound:inf"})
+	sender.AssertMetric(t, "MonotonicCount", "app.rpc_duration_seconds.sum", 12, "", []string{})
+	sender.AssertMetric(t, "Gauge", "app.rpc_duration_seconds.quantile", 0.2, "", []string{"quantile:0.5"})
+}
+
+func TestRunDistributionBuckets(t *testing.T) {
+	server := newServer(t, exposition)
+	sender := runCheck(t, fmt.Sprintf(`
+prometheus_url: %s
+metrics: [request_duration_seconds]
+send_distribution_buckets: true
+send_distribution_counts_as_monotonic: true
+`, server.URL))
+
+	sender.AssertMetric(t, "MonotonicCount", "request_duration_seconds.count", 30, "", []string{})
+	sender.AssertMetric(t, "Gauge", "request_duration_seconds.sum", 8.5, "", []string{})
+	sender.AssertHistogramBucket(t, "HistogramBucket", "request_duration_seconds", 10, 0, 0.1, true, "", []string{}, false)
+	sender.AssertHistogramBucket(t, "HistogramBucket", "request_duration_seconds", 15, 0.1, 0.5, true, "", []string{}, false)
+	sender.AssertHistogramBucket(t, "HistogramBucket", "request_duration_seconds", 5, 0.5, math.Inf(1), true, "", []string{}, false)
+	sender.AssertNotCalled(t, "MonotonicCount", "request_duration_seconds.count", mock.Anything, mock.Anything, mocksender.MatchTagsContains([]string{"upper_bound:0.1"}))
+}
+
+func TestRunLabelJoinsAndHostname(t *testing.T) {
+	server := newServer(t, exposition)
+	sender := runCheck(t, fmt.Sprintf(`
+prometheus_url: %s
+metrics: [go_goroutines]
+label_joins:
+  kube_pod_info:
+    labels_to_match: [pod]
+    labels_to_get: [node, namespace]
+label_to_hostname: node
+`, server.URL))
+
+	sender.AssertMetric(t, "Gauge", "go_goroutines", 42, "node-a", []string{"pod:web-1", "node:node-a", "namespace:prod"})
+}
+
+func TestRunMaxReturnedMetrics(t *testing.T) {
+	server := newServer(t, exposition)
+	sender := runCheck(t, fmt.Sprintf(`
+prometheus_url: %s
+metrics: ["*"]
+ignore_metrics: [rpc_*, request_*]
+health_service_check: false
+max_returned_metrics: 2
+`, server.URL))
+
+	// the text format doesn't guarantee the order of the metric families
+	submitted := 0
+	for _, call := range sender.Calls {
+		if call.Method == "MonotonicCount" || call.Method == "Gauge" {
+			submitted++
+		}
+	}
+	assert.Equal(t, 2, submitted)
+	sender.AssertNumberOfCalls(t, "ServiceCheck", 0)
+}
+
+func TestRunProtobuf(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format := expfmt.Negotiate(r.Header)
+		require.Equal(t, expfmt.FmtProtoDelim, format)
+		w.Header().Set("Content-Type", string(format))
+		encoder := expfmt.NewEncoder(w, format)
+		require.NoError(t, encoder.Encode(&dto.MetricFamily{
+			Name: proto.String("request_duration_seconds"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{{
+				Histogram: &dto.Histogram{
+					SampleCount: proto.Uint64(30),
+					SampleSum:   proto.Float64(8.5),
+					Bucket: []*dto.Bucket{
+						{UpperBound: proto.Float64(0.1), CumulativeCount: proto.Uint64(10)},
+						{UpperBound: proto.Float64(0.5), CumulativeCount: proto.Uint64(25)},
+					},
+				},
+			}},
+		}))
+	}))
+	defer server.Close()
+
+	sender := runCheck(t, fmt.Sprintf(`
+prometheus_url: %s
+metrics: ["*"]
+`, server.URL))
+
+	sender.AssertMetric(t, "Gauge", "request_duration_seconds.count", 25, "", []string{"upper_bound:0.5"})
+	sender.AssertMetric(t, "Gauge", "request_duration_seconds.count", 30, "", []string{"upper_bound:none"})
+}
+
+func TestRunUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	check := openmetricsFactory().(*Check)
+	require.NoError(t, check.Configure([]byte(fmt.Sprintf("prometheus_url: %s\nmetrics: [\"*\"]", server.URL)), nil, "test"))
+	sender := mocksender.NewMockSender(check.ID())
+	sender.SetupAcceptAll()
+
+	assert.Error(t, check.Run())
+	sender.AssertCalled(t, "ServiceCheck", "prometheus.health", metrics.ServiceCheckCritical, "", []string{"endpoint:" + server.URL}, mock.AnythingOfType("string"))
+	sender.AssertNumberOfCalls(t, "Gauge", 0)
+}
@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+)
+
+// acceptHeader prefers the protobuf format, and falls back to the text format
+const acceptHeader = `application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.7,text/plain;version=0.0.4;q=0.3,*/*;q=0.1`
+
+// scraper retrieves the metric families exposed by an endpoint
+type scraper struct {
+	cfg    *config
+	client *http.Client
+}
+
+func newScraper(cfg *config) (*scraper, error) {
+	transport := httputils.CreateHTTPTransport()
+	if cfg.skipProxy {
+		transport.Proxy = nil
+	}
+
+	tlsConfig := transport.TLSClientConfig
+	tlsConfig.InsecureSkipVerify = !cfg.tlsVerify
+	if cfg.tlsCACert != "" {
+		caCert, err := ioutil.ReadFile(cfg.tlsCACert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the CA certificate: %s", err)
+		}
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("unable to parse the CA certificate %s", cfg.tlsCACert)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+	if cfg.tlsCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.tlsCert, cfg.tlsPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &scraper{
+		cfg: cfg,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.timeout,
+		},
+	}, nil
+}
+
+// scrape queries the endpoint and returns the metric families it exposes
+func (s *scraper) scrape() ([]*dto.MetricFamily, error) {
+	req, err := http.NewRequest(http.MethodGet, s.cfg.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", acceptHeader)
+	for k, v := range s.cfg.headers {
+		req.Header.Set(k, v)
+	}
+	if s.cfg.username != "" {
+		req.SetBasicAuth(s.cfg.username, s.cfg.password)
+	}
+	if s.cfg.bearerTokenAuth {
+		// The token is read on every scrape as it can be rotated
+		token, err := ioutil.ReadFile(s.cfg.bearerTokenPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the bearer token: %s", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, s.cfg.endpoint)
+	}
+
+	var families []*dto.MetricFamily
+	decoder := expfmt.NewDecoder(resp.Body, expfmt.ResponseFormat(resp.Header))
+	for {
+		family := &dto.MetricFamily{}
+		if err := decoder.Decode(family); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("unable to parse the metrics: %s", err)
+		}
+		families = append(families, family)
+	}
+	return families, nil
+}
@@ -0,0 +1,323 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// submitter converts the metric families of a scrape into metrics submitted to a sender
+type submitter struct {
+	cfg    *config
+	sender aggregator.Sender
+	// joinedLabels are the labels to add to the metrics, by label join metric and
+	// values of the labels to match
+	joinedLabels map[string]map[string][]*dto.LabelPair
+	submitted    int
+}
+
+func newSubmitter(cfg *config, sender aggregator.Sender) *submitter {
+	return &submitter{
+		cfg:          cfg,
+		sender:       sender,
+		joinedLabels: make(map[string]map[string][]*dto.LabelPair),
+	}
+}
+
+// submit submits the metrics of the given families
+func (s *submitter) submit(families []*dto.MetricFamily) {
+	// The labels of the label join metrics must be known before processing the other metrics
+	for _, family := range families {
+		if join, found := s.cfg.labelJoins[s.trimPrefix(family.GetName())]; found {
+			s.storeJoinedLabels(s.trimPrefix(family.GetName()), join, family)
+		}
+	}
+
+	for _, family := range families {
+		name := s.trimPrefix(family.GetName())
+		if s.cfg.v2 && family.GetType() == dto.MetricType_COUNTER {
+			name = strings.TrimSuffix(name, "_total")
+		}
+		if s.isExcluded(name) {
+			continue
+		}
+		matcher, found := s.match(name)
+		if !found {
+			continue
+		}
+
+		metricName := name
+		if matcher.newName != "" {
+			metricName = matcher.newName
+		}
+		if s.cfg.namespace != "" {
+			metricName = s.cfg.namespace + "." + metricName
+		}
+
+		metricType := family.GetType()
+		if override := matcher.metricType; override != "" {
+			metricType = toMetricType(override, metricType)
+		} else if override, found := s.cfg.typeOverrides[name]; found {
+			metricType = toMetricType(override, metricType)
+		}
+
+		for _, metric := range family.GetMetric() {
+			if s.submitted >= s.cfg.maxReturnedMetrics {
+				log.Warnf("Reached the limit of %d metrics for %s, the remaining metrics are dropped, see the `max_returned_metrics` option",
+					s.cfg.maxReturnedMetrics, s.cfg.endpoint)
+				return
+			}
+			s.submitted++
+
+			hostname, tags := s.tags(metric.GetLabel())
+			switch metricType {
+			case dto.MetricType_COUNTER:
+				s.submitCounter(metricName, scalarValue(metric), hostname, tags)
+			case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+				s.sender.Gauge(metricName, scalarValue(metric), hostname, tags)
+			case dto.MetricType_HISTOGRAM:
+				s.submitHistogram(metricName, metric.GetHistogram(), hostname, tags)
+			case dto.MetricType_SUMMARY:
+				s.submitSummary(metricName, metric.GetSummary(), hostname, tags)
+			}
+		}
+	}
+}
+
+func (s *submitter) trimPrefix(name string) string {
+	return strings.TrimPrefix(name, s.cfg.prefix)
+}
+
+func (s *submitter) isExcluded(name string) bool {
+	for _, re := range s.cfg.exclude {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns the first matcher of the configuration matching the given name
+func (s *submitter) match(name string) (metricMatcher, bool) {
+	for _, matcher := range s.cfg.metrics {
+		if matcher.pattern.MatchString(name) {
+			return matcher, true
+		}
+	}
+	return metricMatcher{}, false
+}
+
+// storeJoinedLabels stores the labels of a label join metric, by values of the labels to match
+func (s *submitter) storeJoinedLabels(name string, join labelJoinsConfig, family *dto.MetricFamily) {
+	getAll := len(join.LabelsToGet) == 1 && join.LabelsToGet[0] == "*"
+	labelsToGet := make(map[string]struct{}, len(join.LabelsToGet))
+	for _, label := range join.LabelsToGet {
+		labelsToGet[label] = struct{}{}
+	}
+	labelsToMatch := make(map[string]struct{}, len(join.LabelsToMatch))
+	for _, label := range join.LabelsToMatch {
+		labelsToMatch[label] = struct{}{}
+	}
+
+	joined := make(map[string][]*dto.LabelPair)
+	for _, metric := range family.GetMetric() {
+		key, found := joinKey(join.LabelsToMatch, metric.GetLabel())
+		if !found {
+			continue
+		}
+		for _, label := range metric.GetLabel() {
+			if _, isMatched := labelsToMatch[label.GetName()]; isMatched {
+				continue
+			}
+			if _, toGet := labelsToGet[label.GetName()]; toGet || getAll {
+				joined[key] = append(joined[key], label)
+			}
+		}
+	}
+	s.joinedLabels[name] = joined
+}
+
+// joinKey returns the values of the labels to match joined in a single key, and
+// whether all the labels to match are present.
+func joinKey(labelsToMatch []string, labels []*dto.LabelPair) (string, bool) {
+	values := make([]string, 0, len(labelsToMatch))
+	for _, name := range labelsToMatch {
+		found := false
+		for _, label := range labels {
+			if label.GetName() == name {
+				values = append(values, label.GetValue())
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", false
+		}
+	}
+	return strings.Join(values, "\x00"), true
+}
+
+// tags returns the hostname and the tags of a metric, from its labels and
+// the labels joined from the label join metrics.
+func (s *submitter) tags(labels []*dto.LabelPair) (string, []string) {
+	for name, join := range s.cfg.labelJoins {
+		key, found := joinKey(join.LabelsToMatch, labels)
+		if !found {
+			continue
+		}
+		labels = append(labels[:len(labels):len(labels)], s.joinedLabels[name][key]...)
+	}
+
+	hostname := ""
+	tags := make([]string, 0, len(labels))
+	for _, label := range labels {
+		name, value := label.GetName(), label.GetValue()
+		if name == s.cfg.labelToHostname && value != "" {
+			hostname = value
+		}
+		if _, excluded := s.cfg.excludeLabels[name]; excluded || value == "" {
+			continue
+		}
+		if newName, found := s.cfg.renameLabels[name]; found {
+			name = newName
+		}
+		tags = append(tags, name+":"+value)
+	}
+	return hostname, tags
+}
+
+func (s *submitter) submitCounter(name string, value float64, hostname string, tags []string) {
+	switch {
+	case s.cfg.v2:
+		s.sender.MonotonicCount(name+".count", value, hostname, tags)
+	case s.cfg.monotonicCounter:
+		s.sender.MonotonicCount(name, value, hostname, tags)
+	default:
+		s.sender.Gauge(name, value, hostname, tags)
+	}
+}
+
+// submitCountSum submits the count and the sum of a histogram or a summary
+func (s *submitter) submitCountSum(name string, count uint64, sum float64, hostname string, tags []string) {
+	if s.cfg.v2 || s.cfg.distributionCountsAsMonotonic {
+		s.sender.MonotonicCount(name+".count", float64(count), hostname, tags)
+	} else {
+		s.sender.Gauge(name+".count", float64(count), hostname, tags)
+	}
+	if s.cfg.v2 || s.cfg.distributionSumsAsMonotonic {
+		s.sender.MonotonicCount(name+".sum", sum, hostname, tags)
+	} else {
+		s.sender.Gauge(name+".sum", sum, hostname, tags)
+	}
+}
+
+func (s *submitter) submitHistogram(name string, histogram *dto.Histogram, hostname string, tags []string) {
+	s.submitCountSum(name, histogram.GetSampleCount(), histogram.GetSampleSum(), hostname, tags)
+	if !s.cfg.sendBuckets {
+		return
+	}
+
+	buckets := histogram.GetBucket()
+	// The +Inf bucket is implicit in the protobuf format
+	if len(buckets) == 0 || !math.IsInf(buckets[len(buckets)-1].GetUpperBound(), 1) {
+		count, upperBound := histogram.GetSampleCount(), math.Inf(1)
+		buckets = append(buckets[:len(buckets):len(buckets)], &dto.Bucket{CumulativeCount: &count, UpperBound: &upperBound})
+	}
+
+	if s.cfg.distributionBuckets {
+		s.submitDistributionBuckets(name, buckets, hostname, tags)
+		return
+	}
+
+	for _, bucket := range buckets {
+		bucketTags := append(append(make([]string, 0, len(tags)+1), tags...), "upper_bound:"+s.formatUpperBound(bucket.GetUpperBound()))
+		count := float64(bucket.GetCumulativeCount())
+		switch {
+		case s.cfg.v2:
+			s.sender.MonotonicCount(name+".bucket", count, hostname, bucketTags)
+		case s.cfg.distributionCountsAsMonotonic:
+			s.sender.MonotonicCount(name+".count", count, hostname, bucketTags)
+		default:
+			s.sender.Gauge(name+".count", count, hostname, bucketTags)
+		}
+	}
+}
+
+// submitDistributionBuckets submits the buckets of a histogram as a distribution. The
+// counts of the buckets are cumulative in the exposition formats, the count of each
+// bucket is the difference with the previous one.
+func (s *submitter) submitDistributionBuckets(name string, buckets []*dto.Bucket, hostname string, tags []string) {
+	lowerBound, previousCount := 0.0, uint64(0)
+	if len(buckets) > 0 && buckets[0].GetUpperBound() <= 0 {
+		lowerBound = math.Inf(-1)
+	}
+	for _, bucket := range buckets {
+		upperBound, count := bucket.GetUpperBound(), bucket.GetCumulativeCount()
+		value := int64(0)
+		if count > previousCount {
+			value = int64(count - previousCount)
+		}
+		s.sender.HistogramBucket(name, value, lowerBound, upperBound, true, hostname, tags, false)
+		lowerBound, previousCount = upperBound, count
+	}
+}
+
+func (s *submitter) submitSummary(name string, summary *dto.Summary, hostname string, tags []string) {
+	s.submitCountSum(name, summary.GetSampleCount(), summary.GetSampleSum(), hostname, tags)
+	for _, quantile := range summary.GetQuantile() {
+		if math.IsNaN(quantile.GetValue()) {
+			continue
+		}
+		quantileTags := append(append(make([]string, 0, len(tags)+1), tags...), "quantile:"+strconv.FormatFloat(quantile.GetQuantile(), 'g', -1, 64))
+		s.sender.Gauge(name+".quantile", quantile.GetValue(), hostname, quantileTags)
+	}
+}
+
+// formatUpperBound formats the upper bound of a bucket the way the Python check does
+func (s *submitter) formatUpperBound(upperBound float64) string {
+	if math.IsInf(upperBound, 1) {
+		if s.cfg.v2 {
+			return "inf"
+		}
+		return "none"
+	}
+	return strconv.FormatFloat(upperBound, 'g', -1, 64)
+}
+
+// scalarValue returns the value of a counter, gauge or untyped metric
+func scalarValue(metric *dto.Metric) float64 {
+	switch {
+	case metric.Counter != nil:
+		return metric.GetCounter().GetValue()
+	case metric.Gauge != nil:
+		return metric.GetGauge().GetValue()
+	default:
+		return metric.GetUntyped().GetValue()
+	}
+}
+
+// toMetricType returns the metric type of an overridden type, the types of metrics
+// that aren't made of a single value can't be overridden.
+func toMetricType(override string, metricType dto.MetricType) dto.MetricType {
+	if metricType == dto.MetricType_HISTOGRAM || metricType == dto.MetricType_SUMMARY {
+		return metricType
+	}
+	switch override {
+	case "counter":
+		return dto.MetricType_COUNTER
+	case "gauge":
+		return dto.MetricType_GAUGE
+	default:
+		return dto.MetricType_UNTYPED
+	}
+}
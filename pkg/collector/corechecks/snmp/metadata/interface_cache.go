@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package metadata
+
+import (
+	"sync"
+)
+
+// interfaceNames caches the interface names of the devices monitored by the snmp check,
+// so that other network devices features (e.g. NetFlow) can enrich their data with them.
+var interfaceNames = struct {
+	sync.RWMutex
+	devices map[string]map[int32]string
+}{devices: make(map[string]map[int32]string)}
+
+// SetDeviceInterfaces replaces the cached interface names of a device
+func SetDeviceInterfaces(deviceID string, interfaces []InterfaceMetadata) {
+	names := make(map[int32]string, len(interfaces))
+	for _, networkInterface := range interfaces {
+		names[networkInterface.Index] = networkInterface.Name
+	}
+
+	interfaceNames.Lock()
+	defer interfaceNames.Unlock()
+	interfaceNames.devices[deviceID] = names
+}
+
+// GetInterfaceName returns the cached name of the interface of a device with the given ifIndex
+func GetInterfaceName(deviceID string, index int32) (string, bool) {
+	interfaceNames.RLock()
+	defer interfaceNames.RUnlock()
+	name, ok := interfaceNames.devices[deviceID][index]
+	return name, ok
+}
@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterfaceNames(t *testing.T) {
+	SetDeviceInterfaces("default:1.2.3.4", []InterfaceMetadata{
+		{DeviceID: "default:1.2.3.4", Index: 1, Name: "eth0"},
+		{DeviceID: "default:1.2.3.4", Index: 2, Name: "eth1"},
+	})
+
+	name, ok := GetInterfaceName("default:1.2.3.4", 2)
+	assert.True(t, ok)
+	assert.Equal(t, "eth1", name)
+
+	_, ok = GetInterfaceName("default:1.2.3.4", 3)
+	assert.False(t, ok)
+	_, ok = GetInterfaceName("default:5.6.7.8", 1)
+	assert.False(t, ok)
+
+	SetDeviceInterfaces("default:1.2.3.4", []InterfaceMetadata{
+		{DeviceID: "default:1.2.3.4", Index: 1, Name: "ge-0/0/0"},
+	})
+	name, ok = GetInterfaceName("default:1.2.3.4", 1)
+	assert.True(t, ok)
+	assert.Equal(t, "ge-0/0/0", name)
+	_, ok = GetInterfaceName("default:1.2.3.4", 2)
+	assert.False(t, ok)
+}
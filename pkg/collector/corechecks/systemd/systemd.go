@@ -10,7 +10,10 @@ package systemd
 
 import (
 	"fmt"
+	"path"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
@@ -35,6 +38,7 @@ const (
 	typeUnit    = "unit"
 	typeService = "service"
 	typeSocket  = "socket"
+	typeTimer   = "timer"
 
 	canConnectServiceCheck   = "systemd.can_connect"
 	systemStateServiceCheck  = "systemd.system.state"
@@ -46,6 +50,7 @@ var dbusTypeMap = map[string]string{
 	typeUnit:    "Unit",
 	typeService: "Service",
 	typeSocket:  "Socket",
+	typeTimer:   "Timer",
 }
 
 // metricConfigItem map a metric to a systemd unit property.
@@ -138,15 +143,24 @@ var systemdStatusMapping = map[string]metrics.ServiceCheckStatus{
 // SystemdCheck aggregates metrics from one SystemdCheck instance
 type SystemdCheck struct {
 	core.CheckBase
-	stats  systemdStats
-	config systemdConfig
+	stats       systemdStats
+	config      systemdConfig
+	unitRegexes []*regexp.Regexp
+	// restartCounts holds the NRestarts of the monitored services at the previous run
+	restartCounts map[string]uint64
+	// subMu guards sub, created by Run, and cancelled, set by Cancel
+	subMu     sync.Mutex
+	sub       *unitEventSubscriber
+	cancelled bool
 }
 type unitSubstateMapping = map[string]string
 
 type systemdInstanceConfig struct {
 	PrivateSocket         string                         `yaml:"private_socket"`
 	UnitNames             []string                       `yaml:"unit_names"`
+	UnitRegexes           []string                       `yaml:"unit_regexes"`
 	SubstateStatusMapping map[string]unitSubstateMapping `yaml:"substate_status_mapping"`
+	CollectEvents         bool                           `yaml:"collect_events"`
 }
 
 type systemdInitConfig struct{}
@@ -168,6 +182,9 @@ type systemdStats interface {
 	GetUnitTypeProperties(c *dbus.Conn, unitName string, unitType string) (map[string]interface{}, error)
 	GetVersion(c *dbus.Conn) (string, error)
 
+	// Events
+	SubscribeUnitProperties(c *dbus.Conn, updateCh chan<- *dbus.PropertiesUpdate, errCh chan<- error) error
+
 	// Misc
 	UnixNow() int64
 }
@@ -202,6 +219,14 @@ func (s *defaultSystemdStats) GetVersion(c *dbus.Conn) (string, error) {
 	return c.GetManagerProperty("Version")
 }
 
+func (s *defaultSystemdStats) SubscribeUnitProperties(c *dbus.Conn, updateCh chan<- *dbus.PropertiesUpdate, errCh chan<- error) error {
+	if err := c.Subscribe(); err != nil {
+		return err
+	}
+	c.SetPropertiesSubscriber(updateCh, errCh)
+	return nil
+}
+
 func (s *defaultSystemdStats) UnixNow() int64 {
 	return time.Now().Unix()
 }
@@ -222,10 +247,18 @@ func (c *SystemdCheck) Run() error {
 	c.submitVersion(conn)
 	c.submitSystemdState(sender, conn)
 
+	if c.config.instance.CollectEvents {
+		c.startEventSubscriber()
+	}
+
 	err = c.submitMetrics(sender, conn)
 	if err != nil {
 		return err
 	}
+
+	if sub := c.eventSubscriber(); sub != nil {
+		c.submitEvents(sender, sub.flush())
+	}
 	sender.Commit()
 
 	return nil
@@ -315,6 +348,7 @@ func (c *SystemdCheck) submitMetrics(sender aggregator.Sender, conn *dbus.Conn)
 	}
 
 	c.submitCountMetrics(sender, units)
+	sub := c.eventSubscriber()
 
 	loadedCount := 0
 	monitoredCount := 0
@@ -328,6 +362,10 @@ func (c *SystemdCheck) submitMetrics(sender aggregator.Sender, conn *dbus.Conn)
 		monitoredCount++
 		tags := []string{"unit:" + unit.Name}
 
+		if sub != nil {
+			sub.setInitialState(unit.Name, unit.ActiveState, unit.SubState)
+		}
+
 		sender.ServiceCheck(unitStateServiceCheck, getServiceCheckStatus(unit.ActiveState, serviceCheckStateMapping), "", tags, "")
 
 		if subStateMapping, found := c.config.instance.SubstateStatusMapping[unit.Name]; found {
@@ -340,6 +378,7 @@ func (c *SystemdCheck) submitMetrics(sender aggregator.Sender, conn *dbus.Conn)
 
 		c.submitBasicUnitMetrics(sender, conn, unit, tags)
 		c.submitPropertyMetricsAsGauge(sender, conn, unit, tags)
+		c.submitTimerMetrics(sender, conn, unit, tags)
 	}
 
 	sender.Gauge("systemd.units_total", float64(len(units)), "", nil)
@@ -412,7 +451,76 @@ func (c *SystemdCheck) submitPropertyMetricsAsGauge(sender aggregator.Sender, co
 				}
 			}
 		}
+		if unitType == typeService {
+			c.submitRestartEvent(sender, unit.Name, serviceProperties, tags)
+		}
+	}
+}
+
+// submitRestartEvent sends an event when a service restarted since the previous run
+func (c *SystemdCheck) submitRestartEvent(sender aggregator.Sender, unitName string, serviceProperties map[string]interface{}, tags []string) {
+	// NRestarts is only present from systemd v235
+	restarts, err := getPropertyUint64(serviceProperties, "NRestarts")
+	if err != nil {
+		return
+	}
+	if c.restartCounts == nil {
+		c.restartCounts = make(map[string]uint64)
+	}
+	previous, found := c.restartCounts[unitName]
+	c.restartCounts[unitName] = restarts
+	if !found || restarts <= previous {
+		return
+	}
+
+	sender.Event(metrics.Event{
+		Title:          fmt.Sprintf("Unit %s restarted", unitName),
+		Text:           fmt.Sprintf("Unit %s was restarted %d time(s) since the previous check run, %d time(s) in total", unitName, restarts-previous, restarts),
+		Ts:             c.stats.UnixNow(),
+		Priority:       metrics.EventPriorityNormal,
+		Tags:           tags,
+		AlertType:      metrics.EventAlertTypeWarning,
+		AggregationKey: "systemd:" + unitName,
+		SourceTypeName: systemdCheckName,
+		EventType:      systemdCheckName,
+	})
+}
+
+// submitTimerMetrics sends the time elapsed since the last trigger of a timer, and the time
+// left until the next one
+func (c *SystemdCheck) submitTimerMetrics(sender aggregator.Sender, conn *dbus.Conn, unit dbus.UnitStatus, tags []string) {
+	if !strings.HasSuffix(unit.Name, "."+typeTimer) {
+		return
+	}
+	timerProperties, err := c.stats.GetUnitTypeProperties(conn, unit.Name, dbusTypeMap[typeTimer])
+	if err != nil {
+		log.Warnf("Error getting timer properties for unit %s", unit.Name)
+		return
+	}
+	now := c.stats.UnixNow()
+
+	// a zero timestamp means the timer never triggered
+	lastTrigger, err := getPropertyUint64(timerProperties, "LastTriggerUSec")
+	if err != nil {
+		log.Debugf("Cannot send property 'LastTriggerUSec' for unit '%s': %v", unit.Name, err)
+	} else if lastTrigger > 0 {
+		sender.Gauge("systemd.timer.seconds_since_last_trigger", float64(maxInt64(now-int64(lastTrigger)/1000000, 0)), "", tags)
+	}
+
+	// timers only based on monotonic clocks (e.g. OnBootSec) have no realtime next elapse
+	nextElapse, err := getPropertyUint64(timerProperties, "NextElapseUSecRealtime")
+	if err != nil {
+		log.Debugf("Cannot send property 'NextElapseUSecRealtime' for unit '%s': %v", unit.Name, err)
+	} else if nextElapse > 0 {
+		sender.Gauge("systemd.timer.seconds_until_next_trigger", float64(maxInt64(int64(nextElapse)/1000000-now, 0)), "", tags)
+	}
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
 	}
+	return b
 }
 
 func sendServicePropertyAsGauge(sender aggregator.Sender, properties map[string]interface{}, service metricConfigItem, tags []string) error {
@@ -499,12 +607,21 @@ func getServiceCheckStatus(state string, mapping map[string]string) metrics.Serv
 	return metrics.ServiceCheckUnknown
 }
 
-// isMonitored verifies if a unit should be monitored.
+// isMonitored verifies if a unit should be monitored: its name is listed in
+// `unit_names`, matches one of its glob patterns, or matches one of `unit_regexes`.
 func (c *SystemdCheck) isMonitored(unitName string) bool {
 	for _, name := range c.config.instance.UnitNames {
 		if name == unitName {
 			return true
 		}
+		if matched, _ := path.Match(name, unitName); matched {
+			return true
+		}
+	}
+	for _, re := range c.unitRegexes {
+		if re.MatchString(unitName) {
+			return true
+		}
 	}
 	return false
 }
@@ -537,8 +654,23 @@ func (c *SystemdCheck) Configure(rawInstance integration.Data, rawInitConfig int
 		return err
 	}
 
-	if len(c.config.instance.UnitNames) == 0 {
-		return fmt.Errorf("instance config `unit_names` must not be empty")
+	if len(c.config.instance.UnitNames) == 0 && len(c.config.instance.UnitRegexes) == 0 {
+		return fmt.Errorf("instance config `unit_names` or `unit_regexes` must not be empty")
+	}
+
+	for _, name := range c.config.instance.UnitNames {
+		if _, err := path.Match(name, ""); err != nil {
+			return fmt.Errorf("instance config `unit_names` contains an invalid pattern '%s': %v", name, err)
+		}
+	}
+
+	c.unitRegexes = nil
+	for _, pattern := range c.config.instance.UnitRegexes {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("instance config `unit_regexes` contains an invalid regex '%s': %v", pattern, err)
+		}
+		c.unitRegexes = append(c.unitRegexes, re)
 	}
 
 	for unitNameInMapping := range c.config.instance.SubstateStatusMapping {
@@ -558,6 +690,17 @@ func (c *SystemdCheck) Configure(rawInstance integration.Data, rawInitConfig int
 	return nil
 }
 
+// Cancel stops the collection of the unit events, if any
+func (c *SystemdCheck) Cancel() {
+	c.subMu.Lock()
+	c.cancelled = true
+	if c.sub != nil {
+		c.sub.stop()
+	}
+	c.subMu.Unlock()
+	c.CommonCancel()
+}
+
 func systemdFactory() check.Check {
 	return &SystemdCheck{
 		stats:     &defaultSystemdStats{},
@@ -0,0 +1,210 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build systemd
+// +build systemd
+
+package systemd
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/coreos/go-systemd/dbus"
+)
+
+const (
+	// maxPendingUnitEvents bounds the number of unit state transitions kept between two check runs
+	maxPendingUnitEvents = 1000
+	// unitUpdatesBufferSize is the size of the channel the property changes are received on
+	unitUpdatesBufferSize = 100
+)
+
+type unitState struct {
+	activeState string
+	subState    string
+}
+
+// unitEvent is a state transition of a monitored unit
+type unitEvent struct {
+	unitName  string
+	timestamp int64
+	// previous is empty when the state of the unit wasn't known yet
+	previous unitState
+	current  unitState
+}
+
+// unitEventSubscriber collects the state transitions of the monitored units from the
+// PropertiesChanged D-Bus signals, so that the units flapping between two check runs
+// are reported
+type unitEventSubscriber struct {
+	sync.Mutex
+	isMonitored func(unitName string) bool
+	unixNow     func() int64
+	states      map[string]unitState
+	events      []unitEvent
+	dropped     int
+	stopCh      chan struct{}
+	stopOnce    sync.Once
+}
+
+func newUnitEventSubscriber(isMonitored func(string) bool, unixNow func() int64) *unitEventSubscriber {
+	return &unitEventSubscriber{
+		isMonitored: isMonitored,
+		unixNow:     unixNow,
+		states:      make(map[string]unitState),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// startEventSubscriber subscribes to the property changes of the units on a dedicated
+// connection, unless it's already done or the check was cancelled
+func (c *SystemdCheck) startEventSubscriber() {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	if c.sub != nil || c.cancelled {
+		return
+	}
+
+	conn, err := c.getDbusConnection()
+	if err != nil {
+		log.Warnf("Cannot collect the systemd unit events, error creating a connection: %v", err)
+		return
+	}
+	updateCh := make(chan *dbus.PropertiesUpdate, unitUpdatesBufferSize)
+	errCh := make(chan error, 1)
+	if err := c.stats.SubscribeUnitProperties(conn, updateCh, errCh); err != nil {
+		c.stats.CloseConn(conn)
+		log.Warnf("Cannot collect the systemd unit events, error subscribing to the unit changes: %v", err)
+		return
+	}
+
+	c.sub = newUnitEventSubscriber(c.isMonitored, c.stats.UnixNow)
+	log.Info("Starting routine to collect systemd unit events ...")
+	go c.sub.run(updateCh, errCh, func() { c.stats.CloseConn(conn) })
+}
+
+// eventSubscriber returns the subscriber collecting the unit events, nil until it's started
+func (c *SystemdCheck) eventSubscriber() *unitEventSubscriber {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	return c.sub
+}
+
+func (s *unitEventSubscriber) run(updateCh <-chan *dbus.PropertiesUpdate, errCh <-chan error, closeConn func()) {
+	defer closeConn()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case update := <-updateCh:
+			s.handleUpdate(update)
+		case err := <-errCh:
+			log.Debugf("Error collecting systemd unit events: %v", err)
+		}
+	}
+}
+
+func (s *unitEventSubscriber) stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// handleUpdate records a transition when the state or substate of a monitored unit changed
+func (s *unitEventSubscriber) handleUpdate(update *dbus.PropertiesUpdate) {
+	if update == nil || !s.isMonitored(update.UnitName) {
+		return
+	}
+	activeState, hasActiveState := update.Changed["ActiveState"].Value().(string)
+	subState, hasSubState := update.Changed["SubState"].Value().(string)
+	if !hasActiveState && !hasSubState {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	previous := s.states[update.UnitName]
+	current := previous
+	if hasActiveState {
+		current.activeState = activeState
+	}
+	if hasSubState {
+		current.subState = subState
+	}
+	if current == previous {
+		return
+	}
+	s.states[update.UnitName] = current
+
+	if len(s.events) >= maxPendingUnitEvents {
+		s.events = s.events[1:]
+		s.dropped++
+	}
+	s.events = append(s.events, unitEvent{
+		unitName:  update.UnitName,
+		timestamp: s.unixNow(),
+		previous:  previous,
+		current:   current,
+	})
+}
+
+// setInitialState records the state of a unit as listed by the check, unless a
+// transition was already received for it
+func (s *unitEventSubscriber) setInitialState(unitName, activeState, subState string) {
+	s.Lock()
+	defer s.Unlock()
+	if _, found := s.states[unitName]; !found {
+		s.states[unitName] = unitState{activeState: activeState, subState: subState}
+	}
+}
+
+// flush returns the transitions received since the previous flush
+func (s *unitEventSubscriber) flush() []unitEvent {
+	s.Lock()
+	defer s.Unlock()
+	if s.dropped > 0 {
+		log.Warnf("Dropped %d systemd unit events, more than %d were received between two check runs", s.dropped, maxPendingUnitEvents)
+		s.dropped = 0
+	}
+	events := s.events
+	s.events = nil
+	return events
+}
+
+// submitEvents converts the unit state transitions into Datadog events
+func (c *SystemdCheck) submitEvents(sender aggregator.Sender, events []unitEvent) {
+	for _, e := range events {
+		text := fmt.Sprintf("Unit %s is now %s (%s)", e.unitName, e.current.activeState, e.current.subState)
+		if e.previous != (unitState{}) {
+			text = fmt.Sprintf("Unit %s changed state from %s (%s) to %s (%s)", e.unitName, e.previous.activeState, e.previous.subState, e.current.activeState, e.current.subState)
+		}
+		sender.Event(metrics.Event{
+			Title:          fmt.Sprintf("Unit %s is %s", e.unitName, e.current.activeState),
+			Text:           text,
+			Ts:             e.timestamp,
+			Priority:       metrics.EventPriorityNormal,
+			Tags:           []string{"unit:" + e.unitName},
+			AlertType:      getEventAlertType(e.current.activeState),
+			AggregationKey: "systemd:" + e.unitName,
+			SourceTypeName: systemdCheckName,
+			EventType:      systemdCheckName,
+		})
+	}
+}
+
+func getEventAlertType(activeState string) metrics.EventAlertType {
+	switch activeState {
+	case "active":
+		return metrics.EventAlertTypeSuccess
+	case "failed":
+		return metrics.EventAlertTypeError
+	case "inactive", "deactivating":
+		return metrics.EventAlertTypeWarning
+	}
+	return metrics.EventAlertTypeInfo
+}
@@ -28,6 +28,7 @@ import (
 	godbus "github.com/godbus/dbus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 const systemdVersion = "241"
@@ -82,6 +83,11 @@ func (s *mockSystemdStats) GetUnitTypeProperties(conn *dbus.Conn, unitName strin
 	return args.Get(0).(map[string]interface{}), args.Error(1)
 }
 
+func (s *mockSystemdStats) SubscribeUnitProperties(conn *dbus.Conn, updateCh chan<- *dbus.PropertiesUpdate, errCh chan<- error) error {
+	args := s.Mock.Called(conn, updateCh, errCh)
+	return args.Error(0)
+}
+
 func getCreatePropertieWithDefaults(props map[string]interface{}) map[string]interface{} {
 	defaultProps := map[string]interface{}{
 		"CPUAccounting":    true,
@@ -111,7 +117,7 @@ func TestMissingUnitNamesShouldRaiseError(t *testing.T) {
 	check := SystemdCheck{}
 	err := check.Configure([]byte(``), []byte(``), "test")
 
-	expectedErrorMsg := "instance config `unit_names` must not be empty"
+	expectedErrorMsg := "instance config `unit_names` or `unit_regexes` must not be empty"
 	assert.EqualError(t, err, expectedErrorMsg)
 }
 
@@ -850,6 +856,52 @@ unit_names:
 	}
 }
 
+func TestIsMonitoredPatterns(t *testing.T) {
+	rawInstanceConfig := []byte(`
+unit_names:
+  - unit1.service
+  - docker-*.scope
+unit_regexes:
+  - ^cron-[0-9]+\.timer$
+`)
+
+	check := SystemdCheck{}
+	err := check.Configure(rawInstanceConfig, nil, "test")
+	assert.Nil(t, err)
+
+	data := []struct {
+		unitName              string
+		expectedToBeMonitored bool
+	}{
+		{"unit1.service", true},
+		{"docker-0123abcd.scope", true},
+		{"docker.socket", false},
+		{"cron-42.timer", true},
+		{"cron-daily.timer", false},
+	}
+	for _, d := range data {
+		t.Run(fmt.Sprintf("check.isMonitored('%s') expected to be %v", d.unitName, d.expectedToBeMonitored), func(t *testing.T) {
+			assert.Equal(t, d.expectedToBeMonitored, check.isMonitored(d.unitName))
+		})
+	}
+}
+
+func TestInvalidUnitPatterns(t *testing.T) {
+	check := SystemdCheck{}
+	err := check.Configure([]byte(`
+unit_names:
+  - "unit[.service"
+`), nil, "test")
+	assert.EqualError(t, err, "instance config `unit_names` contains an invalid pattern 'unit[.service': syntax error in pattern")
+
+	check = SystemdCheck{}
+	err = check.Configure([]byte(`
+unit_regexes:
+  - "unit(.service"
+`), nil, "test")
+	assert.Contains(t, err.Error(), "instance config `unit_regexes` contains an invalid regex 'unit(.service'")
+}
+
 func TestIsMonitoredEmptyConfigShouldNone(t *testing.T) {
 	rawInstanceConfig := []byte(``)
 	check := SystemdCheck{}
@@ -1041,3 +1093,198 @@ unit_names:
 	assert.Equal(t, check.ID("systemd:31a0335c91ba9ae6"), check2.ID())
 	assert.NotEqual(t, check1.ID(), check2.ID())
 }
+
+func TestTimerMetrics(t *testing.T) {
+	rawInstanceConfig := []byte(`
+unit_names:
+ - "*.timer"
+`)
+
+	stats := createDefaultMockSystemdStats()
+	stats.On("ListUnits", mock.Anything).Return([]dbus.UnitStatus{
+		{Name: "logrotate.timer", ActiveState: "active", LoadState: "loaded"},
+		{Name: "boot.timer", ActiveState: "active", LoadState: "loaded"},
+	}, nil)
+	stats.On("UnixNow").Return(int64(1000))
+	stats.On("GetUnitTypeProperties", mock.Anything, mock.Anything, dbusTypeMap[typeUnit]).Return(map[string]interface{}{
+		"ActiveEnterTimestamp": uint64(100 * 1000 * 1000),
+	}, nil)
+	stats.On("GetUnitTypeProperties", mock.Anything, "logrotate.timer", dbusTypeMap[typeTimer]).Return(map[string]interface{}{
+		"LastTriggerUSec":        uint64(400 * 1000 * 1000),
+		"NextElapseUSecRealtime": uint64(1300 * 1000 * 1000),
+	}, nil)
+	stats.On("GetUnitTypeProperties", mock.Anything, "boot.timer", dbusTypeMap[typeTimer]).Return(map[string]interface{}{
+		"LastTriggerUSec":        uint64(0),
+		"NextElapseUSecRealtime": uint64(0),
+	}, nil)
+	stats.On("GetVersion", mock.Anything).Return(systemdVersion)
+
+	check := SystemdCheck{stats: stats}
+	check.Configure(rawInstanceConfig, nil, "test")
+
+	mockSender := mocksender.NewMockSender(check.ID())
+	mockSender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockSender.On("ServiceCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockSender.On("Commit").Return()
+
+	check.Run()
+
+	tags := []string{"unit:logrotate.timer"}
+	mockSender.AssertCalled(t, "Gauge", "systemd.timer.seconds_since_last_trigger", float64(600), "", tags)
+	mockSender.AssertCalled(t, "Gauge", "systemd.timer.seconds_until_next_trigger", float64(300), "", tags)
+
+	// a timer which never triggered, and has no realtime next elapse
+	tags = []string{"unit:boot.timer"}
+	mockSender.AssertNotCalled(t, "Gauge", "systemd.timer.seconds_since_last_trigger", mock.Anything, "", tags)
+	mockSender.AssertNotCalled(t, "Gauge", "systemd.timer.seconds_until_next_trigger", mock.Anything, "", tags)
+}
+
+func TestRestartEvent(t *testing.T) {
+	rawInstanceConfig := []byte(`
+unit_names:
+ - unit1.service
+`)
+
+	serviceProperties := getCreatePropertieWithDefaults(map[string]interface{}{"NRestarts": uint64(3)})
+	stats := createDefaultMockSystemdStats()
+	stats.On("ListUnits", mock.Anything).Return([]dbus.UnitStatus{
+		{Name: "unit1.service", ActiveState: "active", LoadState: "loaded"},
+	}, nil)
+	stats.On("UnixNow").Return(int64(1000))
+	stats.On("GetUnitTypeProperties", mock.Anything, "unit1.service", dbusTypeMap[typeUnit]).Return(map[string]interface{}{
+		"ActiveEnterTimestamp": uint64(100 * 1000 * 1000),
+	}, nil)
+	stats.On("GetUnitTypeProperties", mock.Anything, "unit1.service", dbusTypeMap[typeService]).Return(serviceProperties, nil)
+	stats.On("GetVersion", mock.Anything).Return(systemdVersion)
+
+	check := SystemdCheck{stats: stats}
+	check.Configure(rawInstanceConfig, nil, "test")
+
+	mockSender := mocksender.NewMockSender(check.ID())
+	mockSender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockSender.On("ServiceCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockSender.On("Event", mock.Anything).Return()
+	mockSender.On("Commit").Return()
+
+	// the first run only records the restart count
+	check.Run()
+	mockSender.AssertNotCalled(t, "Event", mock.Anything)
+
+	check.Run()
+	mockSender.AssertNotCalled(t, "Event", mock.Anything)
+
+	serviceProperties["NRestarts"] = uint64(5)
+	check.Run()
+	mockSender.AssertNumberOfCalls(t, "Event", 1)
+	mockSender.AssertCalled(t, "Event", metrics.Event{
+		Title:          "Unit unit1.service restarted",
+		Text:           "Unit unit1.service was restarted 2 time(s) since the previous check run, 5 time(s) in total",
+		Ts:             1000,
+		Priority:       metrics.EventPriorityNormal,
+		Tags:           []string{"unit:unit1.service"},
+		AlertType:      metrics.EventAlertTypeWarning,
+		AggregationKey: "systemd:unit1.service",
+		SourceTypeName: systemdCheckName,
+		EventType:      systemdCheckName,
+	})
+}
+
+func TestUnitEvents(t *testing.T) {
+	rawInstanceConfig := []byte(`
+unit_names:
+ - unit1.service
+collect_events: true
+`)
+
+	var updateCh chan<- *dbus.PropertiesUpdate
+	stats := createDefaultMockSystemdStats()
+	stats.On("SubscribeUnitProperties", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateCh = args.Get(1).(chan<- *dbus.PropertiesUpdate)
+	}).Once()
+	stats.On("ListUnits", mock.Anything).Return([]dbus.UnitStatus{
+		{Name: "unit1.service", ActiveState: "active", SubState: "running", LoadState: "loaded"},
+	}, nil)
+	stats.On("UnixNow").Return(int64(1000))
+	stats.On("GetUnitTypeProperties", mock.Anything, mock.Anything, mock.Anything).Return(map[string]interface{}{}, nil)
+	stats.On("GetVersion", mock.Anything).Return(systemdVersion)
+
+	check := SystemdCheck{stats: stats}
+	check.Configure(rawInstanceConfig, nil, "test")
+	defer check.Cancel()
+
+	mockSender := mocksender.NewMockSender(check.ID())
+	mockSender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockSender.On("ServiceCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockSender.On("Event", mock.Anything).Return()
+	mockSender.On("Commit").Return()
+
+	check.Run()
+	mockSender.AssertNotCalled(t, "Event", mock.Anything)
+	if !assert.NotNil(t, updateCh) {
+		return
+	}
+
+	// the unit flaps between two runs, and another unit isn't monitored
+	updateCh <- &dbus.PropertiesUpdate{UnitName: "unit1.service", Changed: map[string]godbus.Variant{
+		"ActiveState": godbus.MakeVariant("failed"),
+		"SubState":    godbus.MakeVariant("failed"),
+	}}
+	updateCh <- &dbus.PropertiesUpdate{UnitName: "unit2.service", Changed: map[string]godbus.Variant{
+		"ActiveState": godbus.MakeVariant("failed"),
+	}}
+	updateCh <- &dbus.PropertiesUpdate{UnitName: "unit1.service", Changed: map[string]godbus.Variant{
+		"ActiveState": godbus.MakeVariant("active"),
+		"SubState":    godbus.MakeVariant("running"),
+	}}
+	// no state change
+	updateCh <- &dbus.PropertiesUpdate{UnitName: "unit1.service", Changed: map[string]godbus.Variant{
+		"ActiveEnterTimestamp": godbus.MakeVariant(uint64(1)),
+	}}
+	sub := check.eventSubscriber()
+	require.NotNil(t, sub)
+	assert.Eventually(t, func() bool {
+		sub.Lock()
+		defer sub.Unlock()
+		return len(sub.events) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	check.Run()
+	mockSender.AssertNumberOfCalls(t, "Event", 2)
+	mockSender.AssertCalled(t, "Event", metrics.Event{
+		Title:          "Unit unit1.service is failed",
+		Text:           "Unit unit1.service changed state from active (running) to failed (failed)",
+		Ts:             1000,
+		Priority:       metrics.EventPriorityNormal,
+		Tags:           []string{"unit:unit1.service"},
+		AlertType:      metrics.EventAlertTypeError,
+		AggregationKey: "systemd:unit1.service",
+		SourceTypeName: systemdCheckName,
+		EventType:      systemdCheckName,
+	})
+	mockSender.AssertCalled(t, "Event", metrics.Event{
+		Title:          "Unit unit1.service is active",
+		Text:           "Unit unit1.service changed state from failed (failed) to active (running)",
+		Ts:             1000,
+		Priority:       metrics.EventPriorityNormal,
+		Tags:           []string{"unit:unit1.service"},
+		AlertType:      metrics.EventAlertTypeSuccess,
+		AggregationKey: "systemd:unit1.service",
+		SourceTypeName: systemdCheckName,
+		EventType:      systemdCheckName,
+	})
+	stats.AssertNumberOfCalls(t, "SubscribeUnitProperties", 1)
+}
+
+func TestUnitEventSubscriberBound(t *testing.T) {
+	sub := newUnitEventSubscriber(func(string) bool { return true }, func() int64 { return 0 })
+	for i := 0; i < maxPendingUnitEvents+10; i++ {
+		sub.handleUpdate(&dbus.PropertiesUpdate{UnitName: "unit1.service", Changed: map[string]godbus.Variant{
+			"SubState": godbus.MakeVariant(fmt.Sprintf("state-%d", i)),
+		}})
+	}
+
+	events := sub.flush()
+	assert.Len(t, events, maxPendingUnitEvents)
+	assert.Equal(t, "state-10", events[0].current.subState)
+	assert.Empty(t, sub.flush())
+}
@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package exec
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	outputFormatNagios = "nagios"
+	outputFormatJSON   = "json"
+
+	defaultTimeout       = 10
+	defaultMaxOutputSize = 64 * 1024
+)
+
+// instanceConfig contains the options of an exec check instance
+type instanceConfig struct {
+	Command          string            `yaml:"command"`
+	Args             []string          `yaml:"args"`
+	Env              map[string]string `yaml:"env"`
+	OutputFormat     string            `yaml:"output_format"`
+	Timeout          int               `yaml:"timeout"`
+	MaxOutputSize    int               `yaml:"max_output_size"`
+	MetricPrefix     string            `yaml:"metric_prefix"`
+	ServiceCheckName string            `yaml:"service_check_name"`
+	// Tags are added to every metric, event and service check submitted by the check
+	Tags []string `yaml:"tags"`
+}
+
+// Check runs an external executable and submits the data from its output
+type Check struct {
+	core.CheckBase
+	cfg instanceConfig
+}
+
+func newCheck(name string) *Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(name),
+	}
+}
+
+// Configure parses the instance configuration and checks the rights of the command
+func (c *Check) Configure(data integration.Data, initConfig integration.Data, source string) error {
+	cfg := instanceConfig{
+		OutputFormat:     outputFormatNagios,
+		Timeout:          defaultTimeout,
+		MaxOutputSize:    defaultMaxOutputSize,
+		MetricPrefix:     c.String(),
+		ServiceCheckName: c.String(),
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+
+	if !filepath.IsAbs(cfg.Command) {
+		return fmt.Errorf("the command must be an absolute path, got '%s'", cfg.Command)
+	}
+	if cfg.OutputFormat != outputFormatNagios && cfg.OutputFormat != outputFormatJSON {
+		return fmt.Errorf("invalid output format '%s', it must be '%s' or '%s'", cfg.OutputFormat, outputFormatNagios, outputFormatJSON)
+	}
+	if cfg.Timeout <= 0 {
+		return fmt.Errorf("the timeout must be positive, got %d", cfg.Timeout)
+	}
+	if cfg.MaxOutputSize <= 0 {
+		return fmt.Errorf("the maximum output size must be positive, got %d", cfg.MaxOutputSize)
+	}
+	if err := checkRights(cfg.Command, allowGroupExec()); err != nil {
+		return err
+	}
+
+	c.BuildID(data, initConfig)
+	c.cfg = cfg
+
+	return c.CommonConfigure(data, source)
+}
+
+// Run runs the command and submits the data from its output
+func (c *Check) Run() error {
+	sender, err := aggregator.GetSender(c.ID())
+	if err != nil {
+		return err
+	}
+	defer sender.Commit()
+
+	result, err := runCommand(c.cfg.Command, c.cfg.Args, c.cfg.Env, time.Duration(c.cfg.Timeout)*time.Second, c.cfg.MaxOutputSize)
+
+	if c.cfg.OutputFormat == outputFormatJSON {
+		if err != nil {
+			return err
+		}
+		if result.exitCode != 0 {
+			return fmt.Errorf("command '%s' exited with code %d: %s", c.cfg.Command, result.exitCode, result.stderr)
+		}
+		return submitJSONOutput(sender, result.stdout, c.cfg.Tags)
+	}
+
+	if err != nil {
+		// Nagios reports plugins that can't be run as unknown
+		sender.ServiceCheck(c.cfg.ServiceCheckName, metrics.ServiceCheckUnknown, "", c.cfg.Tags, err.Error())
+		return err
+	}
+
+	output := parseNagiosOutput(string(result.stdout))
+	sender.ServiceCheck(c.cfg.ServiceCheckName, nagiosStatus(result.exitCode), "", c.cfg.Tags, output.message)
+	for _, p := range output.perfData {
+		metricName := normalizeMetricName(p.label)
+		if metricName == "" {
+			log.Debugf("Skipping performance data '%s' of check %s: it can't be used as a metric name", p.label, c)
+			continue
+		}
+		name := c.cfg.MetricPrefix + "." + metricName
+		if p.unit == "c" {
+			sender.MonotonicCount(name, p.value, "", c.cfg.Tags)
+		} else {
+			sender.Gauge(name, p.value, "", c.cfg.Tags)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !windows
+// +build !windows
+
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+func writeScript(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "plugin.sh")
+	require.NoError(t, ioutil.WriteFile(path, []byte("#!/bin/sh\n"+content), 0700))
+	return path
+}
+
+var secretsCheckRights = checkRights
+
+func allowAllRights(t *testing.T) {
+	checkRights = func(path string, allowGroupExec bool) error { return nil }
+	t.Cleanup(func() { checkRights = secretsCheckRights })
+}
+
+func loadCheck(t *testing.T, instance string) *Check {
+	// the demultiplexer must be initialized to configure the custom tags of the check
+	mocksender.NewMockSender("")
+
+	loader, err := NewCheckLoader()
+	require.NoError(t, err)
+	c, err := loader.Load(integration.Config{Name: "check_disk", Source: "test"}, integration.Data(instance))
+	require.NoError(t, err)
+	return c.(*Check)
+}
+
+func TestLoad(t *testing.T) {
+	allowAllRights(t)
+	loader, _ := NewCheckLoader()
+
+	_, err := loader.Load(integration.Config{Name: "foo"}, integration.Data("host: localhost"))
+	assert.Error(t, err)
+	_, err = loader.Load(integration.Config{Name: "foo"}, integration.Data("command: plugin.sh"))
+	assert.Error(t, err)
+	_, err = loader.Load(integration.Config{Name: "foo"}, integration.Data("command: /bin/true\noutput_format: xml"))
+	assert.Error(t, err)
+
+	checkRights = func(path string, allowGroupExec bool) error { return errors.New("others have rights on it") }
+	_, err = loader.Load(integration.Config{Name: "foo"}, integration.Data("command: /bin/true"))
+	assert.Error(t, err)
+}
+
+func TestRunNagios(t *testing.T) {
+	allowAllRights(t)
+	script := writeScript(t, `echo "DISK WARNING - free space: / 3326 MB (56%) | /var=2643MB;5948;5958 'read ops'=1024c"
+echo "$1 long text"
+exit 1
+`)
+	c := loadCheck(t, fmt.Sprintf("command: %s\nargs: [first]\ntags: [team:infra]", script))
+
+	sender := mocksender.NewMockSender(c.ID())
+	sender.SetupAcceptAll()
+	require.NoError(t, c.Run())
+
+	sender.AssertServiceCheck(t, "check_disk", metrics.ServiceCheckWarning, "", []string{"team:infra"}, "DISK WARNING - free space: / 3326 MB (56%)\nfirst long text")
+	sender.AssertMetric(t, "Gauge", "check_disk.var", 2643, "", []string{"team:infra"})
+	sender.AssertMetric(t, "MonotonicCount", "check_disk.read_ops", 1024, "", []string{"team:infra"})
+}
+
+func TestRunNagiosTimeout(t *testing.T) {
+	allowAllRights(t)
+	script := writeScript(t, "exec sleep 10\n")
+	c := loadCheck(t, fmt.Sprintf("command: %s\ntimeout: 1\nservice_check_name: disk.status", script))
+
+	sender := mocksender.NewMockSender(c.ID())
+	sender.SetupAcceptAll()
+	assert.Error(t, c.Run())
+	sender.AssertCalled(t, "ServiceCheck", "disk.status", metrics.ServiceCheckUnknown, "", []string(nil), mock.AnythingOfType("string"))
+}
+
+func TestRunOutputTooLong(t *testing.T) {
+	allowAllRights(t)
+	script := writeScript(t, "printf '%0200d' 0\n")
+	c := loadCheck(t, fmt.Sprintf("command: %s\nmax_output_size: 100", script))
+
+	sender := mocksender.NewMockSender(c.ID())
+	sender.SetupAcceptAll()
+	err := c.Run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too long")
+}
+
+func TestRunJSON(t *testing.T) {
+	allowAllRights(t)
+	script := writeScript(t, `cat <<EOF
+{
+  "metrics": [
+    {"name": "app.queue.size", "value": 12, "tags": ["queue:jobs"]},
+    {"name": "app.jobs.processed", "type": "monotonic_count", "value": 300},
+    {"name": "app.invalid", "type": "set", "value": 1}
+  ],
+  "events": [
+    {"title": "Deployment", "text": "v1.2.3 deployed", "alert_type": "success", "tags": ["version:1.2.3"]}
+  ],
+  "service_checks": [
+    {"name": "app.can_connect", "status": 2, "message": "connection refused"}
+  ]
+}
+EOF
+`)
+	c := loadCheck(t, fmt.Sprintf("command: %s\noutput_format: json\ntags: [team:infra]", script))
+
+	sender := mocksender.NewMockSender(c.ID())
+	sender.SetupAcceptAll()
+	require.NoError(t, c.Run())
+
+	sender.AssertMetric(t, "Gauge", "app.queue.size", 12, "", []string{"queue:jobs", "team:infra"})
+	sender.AssertMetric(t, "MonotonicCount", "app.jobs.processed", 300, "", []string{"team:infra"})
+	sender.AssertNotCalled(t, "Gauge", "app.invalid", mock.Anything, mock.Anything, mock.Anything)
+	sender.AssertEvent(t, metrics.Event{
+		Title:     "Deployment",
+		Text:      "v1.2.3 deployed",
+		AlertType: metrics.EventAlertTypeSuccess,
+		Tags:      []string{"version:1.2.3", "team:infra"},
+	}, 0)
+	sender.AssertServiceCheck(t, "app.can_connect", metrics.ServiceCheckCritical, "", []string{"team:infra"}, "connection refused")
+}
+
+func TestRunJSONFailure(t *testing.T) {
+	allowAllRights(t)
+	c := loadCheck(t, fmt.Sprintf("command: %s\noutput_format: json", writeScript(t, "echo 'not json'\n")))
+	sender := mocksender.NewMockSender(c.ID())
+	sender.SetupAcceptAll()
+	assert.Error(t, c.Run())
+
+	c = loadCheck(t, fmt.Sprintf("command: %s\noutput_format: json", writeScript(t, "echo 'failure' >&2\nexit 2\n")))
+	sender = mocksender.NewMockSender(c.ID())
+	sender.SetupAcceptAll()
+	err := c.Run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failure")
+}
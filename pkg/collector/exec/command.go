@@ -0,0 +1,112 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/secrets"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var (
+	// for testing purposes
+	checkRights = secrets.CheckRights
+)
+
+// allowGroupExec returns whether the executables can be owned by a group of the agent user
+func allowGroupExec() bool {
+	return config.Datadog.GetBool("exec_checks_allow_group_exec_perm")
+}
+
+// commandResult is the result of a command that ran to completion
+type commandResult struct {
+	stdout   []byte
+	stderr   string
+	exitCode int
+}
+
+type limitBuffer struct {
+	max      int
+	buf      *bytes.Buffer
+	exceeded bool
+}
+
+func (b *limitBuffer) Write(p []byte) (n int, err error) {
+	if len(p)+b.buf.Len() > b.max {
+		b.exceeded = true
+		return 0, fmt.Errorf("command output was too long: exceeded %d bytes", b.max)
+	}
+	return b.buf.Write(p)
+}
+
+// runCommand runs a command and returns its output. A non-zero exit code isn't an error,
+// errors are returned when the command can't be run, times out, or its output is too long.
+// The rights of the executable are checked before every run, as it can be changed at any time.
+func runCommand(command string, args []string, env map[string]string, timeout time.Duration, maxOutputSize int) (commandResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := osexec.CommandContext(ctx, command, args...)
+	if err := checkRights(cmd.Path, allowGroupExec()); err != nil {
+		return commandResult{}, err
+	}
+
+	if len(env) > 0 {
+		cmd.Env = os.Environ()
+		for k, v := range env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+	}
+
+	stdout := limitBuffer{
+		buf: &bytes.Buffer{},
+		max: maxOutputSize,
+	}
+	stderr := limitBuffer{
+		buf: &bytes.Buffer{},
+		max: maxOutputSize,
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	log.Debugf("command '%s' completed in %s", command, time.Since(start))
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return commandResult{}, fmt.Errorf("error while running '%s': command timeout after %s", command, timeout)
+	}
+	if stdout.exceeded || stderr.exceeded {
+		// The command may have been killed when its output stopped being read
+		return commandResult{}, fmt.Errorf("error while running '%s': command output was too long: exceeded %d bytes", command, maxOutputSize)
+	}
+
+	var exitErr *osexec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return commandResult{}, fmt.Errorf("error while running '%s': %s", command, err)
+	}
+
+	result := commandResult{
+		stdout: stdout.buf.Bytes(),
+		stderr: strings.TrimSpace(stderr.buf.String()),
+	}
+	if exitErr != nil {
+		result.exitCode = exitErr.ExitCode()
+	}
+	if result.stderr != "" {
+		log.Debugf("command '%s' stderr: %s", command, result.stderr)
+	}
+	return result, nil
+}
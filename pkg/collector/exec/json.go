@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package exec
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// jsonOutput is the output contract of the commands using the `json` output format
+type jsonOutput struct {
+	Metrics       []jsonMetric       `json:"metrics"`
+	Events        []jsonEvent        `json:"events"`
+	ServiceChecks []jsonServiceCheck `json:"service_checks"`
+}
+
+type jsonMetric struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Value    float64  `json:"value"`
+	Tags     []string `json:"tags"`
+	Hostname string   `json:"hostname"`
+}
+
+type jsonEvent struct {
+	Title          string   `json:"title"`
+	Text           string   `json:"text"`
+	Timestamp      int64    `json:"timestamp"`
+	Priority       string   `json:"priority"`
+	AlertType      string   `json:"alert_type"`
+	AggregationKey string   `json:"aggregation_key"`
+	SourceTypeName string   `json:"source_type_name"`
+	Tags           []string `json:"tags"`
+	Hostname       string   `json:"hostname"`
+}
+
+type jsonServiceCheck struct {
+	Name     string   `json:"name"`
+	Status   int      `json:"status"`
+	Message  string   `json:"message"`
+	Tags     []string `json:"tags"`
+	Hostname string   `json:"hostname"`
+}
+
+// submitJSONOutput submits the metrics, events and service checks of a JSON output,
+// adding the tags of the check instance to the tags of each entry.
+// Invalid entries are skipped, the output is rejected only if it isn't valid JSON.
+func submitJSONOutput(sender aggregator.Sender, stdout []byte, tags []string) error {
+	var output jsonOutput
+	if err := json.Unmarshal(stdout, &output); err != nil {
+		return fmt.Errorf("unable to parse the command output: %s", err)
+	}
+
+	for _, m := range output.Metrics {
+		if m.Name == "" {
+			log.Warnf("Skipping metric without a name in the command output")
+			continue
+		}
+		metricTags := appendTags(m.Tags, tags)
+		switch m.Type {
+		case "gauge", "":
+			sender.Gauge(m.Name, m.Value, m.Hostname, metricTags)
+		case "count":
+			sender.Count(m.Name, m.Value, m.Hostname, metricTags)
+		case "rate":
+			sender.Rate(m.Name, m.Value, m.Hostname, metricTags)
+		case "monotonic_count":
+			sender.MonotonicCount(m.Name, m.Value, m.Hostname, metricTags)
+		case "histogram":
+			sender.Histogram(m.Name, m.Value, m.Hostname, metricTags)
+		default:
+			log.Warnf("Skipping metric %s of unknown type '%s' in the command output", m.Name, m.Type)
+		}
+	}
+
+	for _, e := range output.Events {
+		event := metrics.Event{
+			Title:          e.Title,
+			Text:           e.Text,
+			Ts:             e.Timestamp,
+			Host:           e.Hostname,
+			Tags:           appendTags(e.Tags, tags),
+			AggregationKey: e.AggregationKey,
+			SourceTypeName: e.SourceTypeName,
+		}
+		if e.Priority != "" {
+			priority, err := metrics.GetEventPriorityFromString(e.Priority)
+			if err != nil {
+				log.Warnf("Skipping event '%s' in the command output: %s", e.Title, err)
+				continue
+			}
+			event.Priority = priority
+		}
+		if e.AlertType != "" {
+			alertType, err := metrics.GetAlertTypeFromString(e.AlertType)
+			if err != nil {
+				log.Warnf("Skipping event '%s' in the command output: %s", e.Title, err)
+				continue
+			}
+			event.AlertType = alertType
+		}
+		sender.Event(event)
+	}
+
+	for _, sc := range output.ServiceChecks {
+		status, err := metrics.GetServiceCheckStatus(sc.Status)
+		if sc.Name == "" || err != nil {
+			log.Warnf("Skipping invalid service check '%s' in the command output", sc.Name)
+			continue
+		}
+		sender.ServiceCheck(sc.Name, status, sc.Hostname, appendTags(sc.Tags, tags), sc.Message)
+	}
+
+	return nil
+}
+
+// appendTags returns the tags of an output entry followed by the tags of the check instance,
+// without modifying the entry tags.
+func appendTags(entryTags []string, tags []string) []string {
+	if len(tags) == 0 {
+		return entryTags
+	}
+	return append(append(make([]string, 0, len(entryTags)+len(tags)), entryTags...), tags...)
+}
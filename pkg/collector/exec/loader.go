@@ -0,0 +1,74 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package exec implements a check loader running external executables, such as
+// Nagios plugins, and converting their output into metrics, events and service checks.
+package exec
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/loaders"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// CheckLoader is a loader for the checks running an external executable
+type CheckLoader struct{}
+
+// NewCheckLoader creates a loader for exec checks
+func NewCheckLoader() (*CheckLoader, error) {
+	return &CheckLoader{}, nil
+}
+
+// Name returns the exec loader name
+func (l *CheckLoader) Name() string {
+	return "exec"
+}
+
+// Load returns an exec check, the instance must define the command to run
+func (l *CheckLoader) Load(config integration.Config, instance integration.Data) (check.Check, error) {
+	var c check.Check
+
+	if !isExecInstance(instance) {
+		return c, fmt.Errorf("check %s is not an exec check: no command is defined in the instance", config.Name)
+	}
+
+	execCheck := newCheck(config.Name)
+	if err := execCheck.Configure(instance, config.InitConfig, config.Source); err != nil {
+		log.Errorf("exec.loader: could not configure check %s: %s", execCheck, err)
+		return c, fmt.Errorf("Could not configure check %s: %s", execCheck, err)
+	}
+
+	return execCheck, nil
+}
+
+func (l *CheckLoader) String() string {
+	return "Exec Check Loader"
+}
+
+// isExecInstance returns whether an instance defines a command to run
+func isExecInstance(instance integration.Data) bool {
+	var rawInstance struct {
+		Command string `yaml:"command"`
+	}
+	if err := yaml.Unmarshal(instance, &rawInstance); err != nil {
+		return false
+	}
+	return rawInstance.Command != ""
+}
+
+func init() {
+	factory := func() (check.Loader, error) {
+		return NewCheckLoader()
+	}
+
+	// The exec loader comes after the other loaders: a Python or a Go check
+	// having a `command` option must not be run as an executable.
+	loaders.RegisterLoader(40, factory)
+}
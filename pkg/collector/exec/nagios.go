@@ -0,0 +1,172 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package exec
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/common"
+)
+
+// nagiosOutput is the parsed output of a Nagios plugin
+type nagiosOutput struct {
+	// message is the text output of the plugin, made of its first line and its long text
+	message  string
+	perfData []perfData
+}
+
+// perfData is a performance data point of a Nagios plugin
+type perfData struct {
+	label string
+	value float64
+	// unit is the unit of measurement of the value, `c` for a continuous counter
+	unit string
+}
+
+// nagiosStatus converts the exit code of a Nagios plugin into a service check status
+func nagiosStatus(exitCode int) metrics.ServiceCheckStatus {
+	switch exitCode {
+	case 0:
+		return metrics.ServiceCheckOK
+	case 1:
+		return metrics.ServiceCheckWarning
+	case 2:
+		return metrics.ServiceCheckCritical
+	default:
+		return metrics.ServiceCheckUnknown
+	}
+}
+
+// parseNagiosOutput parses the output of a Nagios plugin, following the plugin API:
+//
+//	TEXT OUTPUT | OPTIONAL PERFDATA
+//	LONG TEXT LINE 1
+//	LONG TEXT LINE 2 | PERFDATA LINE 2
+//	PERFDATA LINE 3
+//
+// All the lines after the first pipe of the long text contain performance data.
+func parseNagiosOutput(output string) nagiosOutput {
+	var result nagiosOutput
+	var text, rawPerfData []string
+
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) == 0 {
+		return result
+	}
+
+	first, firstPerfData, _ := common.Cut(lines[0], "|")
+	text = append(text, strings.TrimSpace(first))
+	rawPerfData = append(rawPerfData, firstPerfData)
+
+	inPerfData := false
+	for _, line := range lines[1:] {
+		if inPerfData {
+			rawPerfData = append(rawPerfData, line)
+			continue
+		}
+		longText, perf, found := common.Cut(line, "|")
+		text = append(text, longText)
+		if found {
+			rawPerfData = append(rawPerfData, perf)
+			inPerfData = true
+		}
+	}
+
+	result.message = strings.TrimSpace(strings.Join(text, "\n"))
+	for _, raw := range rawPerfData {
+		result.perfData = append(result.perfData, parsePerfData(raw)...)
+	}
+	return result
+}
+
+// parsePerfData parses performance data, made of space separated data points:
+//
+//	'label'=value[UOM];[warn];[crit];[min];[max]
+//
+// Labels containing spaces are quoted, a quote in a quoted label is escaped by doubling it.
+// Data points that can't be parsed, or whose value is unknown, are skipped.
+func parsePerfData(raw string) []perfData {
+	var points []perfData
+
+	for raw = strings.TrimSpace(raw); raw != ""; raw = strings.TrimSpace(raw) {
+		var label string
+		if raw[0] == '\'' {
+			label, raw = parseQuotedLabel(raw[1:])
+		} else {
+			end := strings.IndexAny(raw, "= ")
+			if end < 0 {
+				end = len(raw)
+			}
+			label, raw = raw[:end], raw[end:]
+		}
+
+		if !strings.HasPrefix(raw, "=") {
+			// not a data point, skip to the next one
+			raw = skipToken(raw)
+			continue
+		}
+
+		var token string
+		token, raw = nextToken(raw[1:])
+		value, _, _ := common.Cut(token, ";")
+		number := strings.TrimRightFunc(value, func(r rune) bool {
+			return unicode.IsLetter(r) || r == '%'
+		})
+		v, err := strconv.ParseFloat(strings.Replace(number, ",", ".", 1), 64)
+		if label == "" || err != nil {
+			continue
+		}
+		points = append(points, perfData{label: label, value: v, unit: value[len(number):]})
+	}
+
+	return points
+}
+
+// parseQuotedLabel returns a quoted label and the rest of the data, raw starts after the opening quote
+func parseQuotedLabel(raw string) (string, string) {
+	var label strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\'' {
+			label.WriteByte(raw[i])
+			continue
+		}
+		if i+1 < len(raw) && raw[i+1] == '\'' {
+			label.WriteByte('\'')
+			i++
+			continue
+		}
+		return label.String(), raw[i+1:]
+	}
+	return label.String(), ""
+}
+
+// nextToken returns the data until the next space, and the rest of the data
+func nextToken(raw string) (string, string) {
+	end := strings.IndexByte(raw, ' ')
+	if end < 0 {
+		return raw, ""
+	}
+	return raw[:end], raw[end:]
+}
+
+func skipToken(raw string) string {
+	_, rest := nextToken(raw)
+	return rest
+}
+
+// normalizeMetricName turns a performance data label into a valid metric name
+func normalizeMetricName(label string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.') {
+			return r
+		}
+		return '_'
+	}, label)
+	return strings.Trim(name, "_.")
+}
@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+func TestNagiosStatus(t *testing.T) {
+	assert.Equal(t, metrics.ServiceCheckOK, nagiosStatus(0))
+	assert.Equal(t, metrics.ServiceCheckWarning, nagiosStatus(1))
+	assert.Equal(t, metrics.ServiceCheckCritical, nagiosStatus(2))
+	assert.Equal(t, metrics.ServiceCheckUnknown, nagiosStatus(3))
+	assert.Equal(t, metrics.ServiceCheckUnknown, nagiosStatus(127))
+}
+
+func TestParseNagiosOutput(t *testing.T) {
+	output := parseNagiosOutput("DISK OK - free space: / 3326 MB (56%); | /=2643MB;5948;5958;0;5968\n" +
+		"/ 15272 MB (77%);\n" +
+		"/boot 68 MB (69%); | /boot=68MB;88;93;0;98\n" +
+		"/home=69357MB;253404;253409;0;253414\n")
+
+	assert.Equal(t, "DISK OK - free space: / 3326 MB (56%);\n/ 15272 MB (77%);\n/boot 68 MB (69%);", output.message)
+	assert.Equal(t, []perfData{
+		{label: "/", value: 2643, unit: "MB"},
+		{label: "/boot", value: 68, unit: "MB"},
+		{label: "/home", value: 69357, unit: "MB"},
+	}, output.perfData)
+
+	output = parseNagiosOutput("PING OK")
+	assert.Equal(t, "PING OK", output.message)
+	assert.Empty(t, output.perfData)
+}
+
+func TestParsePerfData(t *testing.T) {
+	assert.Equal(t, []perfData{
+		{label: "time", value: 0.0042, unit: "s"},
+		{label: "used space", value: 45.5, unit: "%"},
+		{label: "it's", value: 3},
+		{label: "requests", value: 1024, unit: "c"},
+		{label: "load", value: 1.5},
+	}, parsePerfData("time=0.0042s;;;0 'used space'=45.5%;80;90 'it''s'=3 invalid requests=1024c unknown=U load=1,5"))
+}
+
+func TestNormalizeMetricName(t *testing.T) {
+	assert.Equal(t, "time", normalizeMetricName("time"))
+	assert.Equal(t, "used_space", normalizeMetricName("used space"))
+	assert.Equal(t, "var_log", normalizeMetricName("/var/log"))
+	assert.Equal(t, "", normalizeMetricName("/"))
+}
@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const procModulesPath = "/proc/modules"
+
+var kernelModuleReportedFields = []string{
+	compliance.KernelModuleFieldName,
+	compliance.KernelModuleFieldLoaded,
+}
+
+func resolveKernelModule(_ context.Context, e env.Env, id string, res compliance.ResourceCommon, rego bool) (resolved, error) {
+	if res.KernelModule == nil {
+		return nil, fmt.Errorf("%s: expecting kernel module resource in kernel module check", id)
+	}
+
+	name := res.KernelModule.Name
+
+	log.Debugf("%s: running kernel module check: %s", id, name)
+
+	path := e.NormalizeToHostRoot(procModulesPath)
+	f, err := os.Open(path)
+	if err != nil {
+		log.Errorf("%s: failed to open %s: %v", id, path, err)
+		return nil, err
+	}
+	defer f.Close()
+
+	loaded, err := isKernelModuleLoaded(f, name)
+	if err != nil {
+		return nil, wrapErrorWithID(id, err)
+	}
+
+	instance := eval.NewInstance(
+		eval.VarMap{
+			compliance.KernelModuleFieldName:   name,
+			compliance.KernelModuleFieldLoaded: loaded,
+		},
+		nil,
+		eval.RegoInputMap{
+			"name":   name,
+			"loaded": loaded,
+		},
+	)
+
+	return newResolvedInstance(instance, name, "kernelModule"), nil
+}
+
+// isKernelModuleLoaded looks for a module in the /proc/modules format, where the
+// module name is the first field of each line. Dashes and underscores are
+// interchangeable in module names.
+func isKernelModuleLoaded(r io.Reader, name string) (bool, error) {
+	name = strings.ReplaceAll(name, "-", "_")
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && fields[0] == name {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,85 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestKernelModuleCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource compliance.Resource
+
+		expectReport *compliance.Report
+	}{
+		{
+			name: "module loaded",
+			resource: compliance.Resource{
+				ResourceCommon: compliance.ResourceCommon{
+					KernelModule: &compliance.KernelModule{
+						Name: "br-netfilter",
+					},
+				},
+				Condition: `kernelModule.loaded`,
+			},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"kernelModule.name":   "br-netfilter",
+					"kernelModule.loaded": true,
+				},
+				Resource: compliance.ReportResource{
+					ID:   "br-netfilter",
+					Type: "kernelModule",
+				},
+			},
+		},
+		{
+			name: "module not loaded",
+			resource: compliance.Resource{
+				ResourceCommon: compliance.ResourceCommon{
+					KernelModule: &compliance.KernelModule{
+						Name: "cramfs",
+					},
+				},
+				Condition: `!kernelModule.loaded`,
+			},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"kernelModule.name":   "cramfs",
+					"kernelModule.loaded": false,
+				},
+				Resource: compliance.ReportResource{
+					ID:   "cramfs",
+					Type: "kernelModule",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			env := &mocks.Env{}
+			env.On("NormalizeToHostRoot", procModulesPath).Return("./testdata/kernel_module/proc-modules")
+
+			kernelModuleCheck, err := newResourceCheck(env, "rule-id", test.resource)
+			assert.NoError(err)
+
+			reports := kernelModuleCheck.check(env)
+			assert.Equal(test.expectReport, reports[0])
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var packageReportedFields = []string{
+	compliance.PackageFieldName,
+	compliance.PackageFieldVersion,
+	compliance.PackageFieldInstalled,
+}
+
+func resolvePackage(ctx context.Context, e env.Env, id string, res compliance.ResourceCommon, rego bool) (resolved, error) {
+	if res.Package == nil {
+		return nil, fmt.Errorf("%s: expecting package resource in package check", id)
+	}
+
+	name := res.Package.Name
+
+	log.Debugf("%s: running package check: %s", id, name)
+
+	pkg, err := findInstalledPackage(e, name)
+	if err != nil {
+		return nil, wrapErrorWithID(id, err)
+	}
+
+	var version string
+	if pkg != nil {
+		version = pkg.version
+	}
+	installed := pkg != nil
+
+	instance := eval.NewInstance(
+		eval.VarMap{
+			compliance.PackageFieldName:      name,
+			compliance.PackageFieldVersion:   version,
+			compliance.PackageFieldInstalled: installed,
+		},
+		eval.FunctionMap{
+			compliance.PackageFuncVersionCompare: packageVersionCompare(name, version, installed),
+		},
+		eval.RegoInputMap{
+			"name":      name,
+			"version":   version,
+			"installed": installed,
+		},
+	)
+
+	return newResolvedInstance(instance, name, "package"), nil
+}
+
+func packageVersionCompare(name, version string, installed bool) eval.Function {
+	return func(_ eval.Instance, args ...interface{}) (interface{}, error) {
+		other, err := validateVersionArg(args...)
+		if err != nil {
+			return nil, err
+		}
+		if !installed {
+			return nil, fmt.Errorf("package %s is not installed", name)
+		}
+		return compareVersions(version, other), nil
+	}
+}
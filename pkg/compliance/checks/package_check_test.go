@@ -0,0 +1,162 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+	"github.com/DataDog/datadog-agent/pkg/util/packagedb"
+
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestPackageCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		hostPaths map[string]string
+		resource  compliance.Resource
+
+		expectReport *compliance.Report
+	}{
+		{
+			name:      "dpkg package with recent version",
+			hostPaths: map[string]string{packagedb.DpkgStatusPath: "./testdata/package/dpkg-status"},
+			resource: compliance.Resource{
+				ResourceCommon: compliance.ResourceCommon{
+					Package: &compliance.Package{
+						Name: "openssh-server",
+					},
+				},
+				Condition: `package.installed && package.versionCompare("1:8.4") >= 0`,
+			},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"package.name":      "openssh-server",
+					"package.version":   "1:8.4p1-5",
+					"package.installed": true,
+				},
+				Resource: compliance.ReportResource{
+					ID:   "openssh-server",
+					Type: "package",
+				},
+			},
+		},
+		{
+			name:      "dpkg package removed",
+			hostPaths: map[string]string{packagedb.DpkgStatusPath: "./testdata/package/dpkg-status"},
+			resource: compliance.Resource{
+				ResourceCommon: compliance.ResourceCommon{
+					Package: &compliance.Package{
+						Name: "telnetd",
+					},
+				},
+				Condition: `!package.installed`,
+			},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"package.name":      "telnetd",
+					"package.version":   "",
+					"package.installed": false,
+				},
+				Resource: compliance.ReportResource{
+					ID:   "telnetd",
+					Type: "package",
+				},
+			},
+		},
+		{
+			name:      "apk package with old version",
+			hostPaths: map[string]string{packagedb.ApkInstalledPath: "./testdata/package/apk-installed"},
+			resource: compliance.Resource{
+				ResourceCommon: compliance.ResourceCommon{
+					Package: &compliance.Package{
+						Name: "openssh-server",
+					},
+				},
+				Condition: `package.versionCompare("8.9") >= 0`,
+			},
+			expectReport: &compliance.Report{
+				Passed: false,
+				Data: event.Data{
+					"package.name":      "openssh-server",
+					"package.version":   "8.8_p1-r1",
+					"package.installed": true,
+				},
+				Resource: compliance.ReportResource{
+					ID:   "openssh-server",
+					Type: "package",
+				},
+			},
+		},
+		{
+			name:      "rpm database unsupported",
+			hostPaths: map[string]string{packagedb.RpmDatabasePath: "./testdata/package"},
+			resource: compliance.Resource{
+				ResourceCommon: compliance.ResourceCommon{
+					Package: &compliance.Package{
+						Name: "openssh-server",
+					},
+				},
+				Condition: `package.versionCompare("8.0p1-5.el8") == 1`,
+			},
+			expectReport: &compliance.Report{
+				Passed: false,
+				Error:  fmt.Errorf("rule-id: %w", packagedb.ErrRPMDatabaseUnsupported),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			env := &mocks.Env{}
+			env.On("NormalizeToHostRoot", mock.AnythingOfType("string")).Return(func(path string) string {
+				if hostPath, ok := test.hostPaths[path]; ok {
+					return hostPath
+				}
+				return filepath.Join("./testdata/package/missing", path)
+			})
+
+			packageCheck, err := newResourceCheck(env, "rule-id", test.resource)
+			assert.NoError(err)
+
+			reports := packageCheck.check(env)
+			assert.Equal(test.expectReport, reports[0])
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.1", -1},
+		{"1.10", "1.9", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0a", "1.0", 1},
+		{"1.0-1", "1.0-2", -1},
+		{"1:1.0", "2.0", 1},
+		{"1:8.4p1-5", "1:8.4", 1},
+		{"8.4p1", "8.10", -1},
+		{"2.31-13+deb11u2", "2.31-13", 1},
+		{"1.001", "1.1", 0},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, compareVersions(test.a, test.b), "%s <=> %s", test.a, test.b)
+	}
+}
@@ -0,0 +1,187 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/util/packagedb"
+)
+
+// ErrPackageDatabaseNotFound is returned when none of the supported package databases could be found
+var ErrPackageDatabaseNotFound = errors.New("package database not found")
+
+type installedPackage struct {
+	name    string
+	version string
+}
+
+// findInstalledPackage looks for a package in the dpkg and apk databases of the host,
+// in this order. It returns nil if the package is not installed.
+func findInstalledPackage(e env.Env, name string) (*installedPackage, error) {
+	var pkgs []packagedb.Package
+	var err error
+
+	if path := e.NormalizeToHostRoot(packagedb.DpkgStatusPath); fileExists(path) {
+		pkgs, err = packagedb.ReadFile(path, packagedb.ParseDpkgStatus)
+	} else if path := e.NormalizeToHostRoot(packagedb.ApkInstalledPath); fileExists(path) {
+		pkgs, err = packagedb.ReadFile(path, packagedb.ParseApkInstalled)
+	} else if path := e.NormalizeToHostRoot(packagedb.RpmDatabasePath); fileExists(path) {
+		return nil, packagedb.ErrRPMDatabaseUnsupported
+	} else {
+		return nil, ErrPackageDatabaseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range pkgs {
+		if pkg.Name == name {
+			return &installedPackage{name: pkg.Name, version: pkg.Version}, nil
+		}
+	}
+	return nil, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// compareVersions compares two package versions following the algorithm used by dpkg,
+// which is also a good approximation for rpm and apk versions. The versions are made
+// of an optional epoch, an upstream version and an optional revision ([epoch:]upstream[-revision]).
+// It returns -1, 0 or 1 when a is respectively lower, equal or greater than b.
+func compareVersions(a, b string) int {
+	epochA, upstreamA, revisionA := splitVersion(a)
+	epochB, upstreamB, revisionB := splitVersion(b)
+
+	if epochA != epochB {
+		if epochA < epochB {
+			return -1
+		}
+		return 1
+	}
+
+	if res := compareVersionPart(upstreamA, upstreamB); res != 0 {
+		return res
+	}
+	return compareVersionPart(revisionA, revisionB)
+}
+
+func splitVersion(version string) (int, string, string) {
+	var epoch int
+	if i := strings.IndexByte(version, ':'); i >= 0 {
+		if e, err := strconv.Atoi(version[:i]); err == nil {
+			epoch = e
+			version = version[i+1:]
+		}
+	}
+
+	var revision string
+	if i := strings.LastIndexByte(version, '-'); i >= 0 {
+		revision = version[i+1:]
+		version = version[:i]
+	}
+
+	return epoch, version, revision
+}
+
+func isVersionDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isVersionLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// versionCharOrder returns the weight of a character in a non digit part of a version,
+// '~' sorts before anything, even the end of the part, and letters before other characters
+func versionCharOrder(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+
+	c := s[i]
+	switch {
+	case isVersionDigit(c):
+		return 0
+	case isVersionLetter(c):
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+func compareVersionPart(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isVersionDigit(a[i])) || (j < len(b) && !isVersionDigit(b[j])) {
+			ac, bc := versionCharOrder(a, i), versionCharOrder(b, j)
+			if ac != bc {
+				return sign(ac - bc)
+			}
+			i++
+			j++
+		}
+
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+
+		firstDiff := 0
+		for i < len(a) && isVersionDigit(a[i]) && j < len(b) && isVersionDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+
+		if i < len(a) && isVersionDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isVersionDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return sign(firstDiff)
+		}
+	}
+	return 0
+}
+
+func sign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func validateVersionArg(args ...interface{}) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf(`invalid number of arguments, expecting 1 got %d`, len(args))
+	}
+	version, ok := args[0].(string)
+	if !ok {
+		return "", errors.New(`expecting string value for version argument`)
+	}
+	return version, nil
+}
@@ -21,6 +21,7 @@ var helpers string
 
 var regoBuiltins = []func(*rego.Rego){
 	octalLiteralFunc,
+	versionCompareFunc,
 }
 
 var octalLiteralFunc = rego.Function1(
@@ -42,3 +43,23 @@ var octalLiteralFunc = rego.Function1(
 		return ast.IntNumberTerm(int(value)), err
 	},
 )
+
+var versionCompareFunc = rego.Function2(
+	&rego.Function{
+		Name: "version_compare",
+		Decl: types.NewFunction(types.Args(types.S, types.S), types.N),
+	},
+	func(_ rego.BuiltinContext, a, b *ast.Term) (*ast.Term, error) {
+		versionA, ok := a.Value.(ast.String)
+		if !ok {
+			return nil, errors.New("failed to parse version")
+		}
+
+		versionB, ok := b.Value.(ast.String)
+		if !ok {
+			return nil, errors.New("failed to parse version")
+		}
+
+		return ast.IntNumberTerm(compareVersions(string(versionA), string(versionB))), nil
+	},
+)
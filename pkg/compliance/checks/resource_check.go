@@ -210,6 +210,12 @@ func resourceKindToResolverAndFields(env env.Env, ruleID string, kind compliance
 		return resolveKubeapiserver, kubeResourceReportedFields, nil
 	case compliance.KindConstants:
 		return resolveConstants, nil, nil
+	case compliance.KindPackage:
+		return resolvePackage, packageReportedFields, nil
+	case compliance.KindSysctl:
+		return resolveSysctl, sysctlReportedFields, nil
+	case compliance.KindKernelModule:
+		return resolveKernelModule, kernelModuleReportedFields, nil
 	default:
 		return nil, nil, ErrResourceKindNotSupported
 	}
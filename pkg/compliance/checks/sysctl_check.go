@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const procSysPath = "/proc/sys"
+
+var sysctlReportedFields = []string{
+	compliance.SysctlFieldKey,
+	compliance.SysctlFieldValue,
+}
+
+// ErrSysctlNotFound is returned when a kernel parameter cannot be found
+var ErrSysctlNotFound = errors.New("sysctl not found")
+
+func resolveSysctl(_ context.Context, e env.Env, id string, res compliance.ResourceCommon, rego bool) (resolved, error) {
+	if res.Sysctl == nil {
+		return nil, fmt.Errorf("%s: expecting sysctl resource in sysctl check", id)
+	}
+
+	key := res.Sysctl.Key
+
+	log.Debugf("%s: running sysctl check: %s", id, key)
+
+	path, err := sysctlPath(key)
+	if err != nil {
+		return nil, wrapErrorWithID(id, err)
+	}
+
+	content, err := ioutil.ReadFile(e.NormalizeToHostRoot(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			if rego {
+				return nil, nil
+			}
+			return nil, wrapErrorWithID(id, ErrSysctlNotFound)
+		}
+		return nil, wrapErrorWithID(id, err)
+	}
+
+	// multi-valued parameters are separated by tabs, as reported by the sysctl command
+	value := strings.Join(strings.Fields(string(content)), " ")
+
+	instance := eval.NewInstance(
+		eval.VarMap{
+			compliance.SysctlFieldKey:   key,
+			compliance.SysctlFieldValue: value,
+		},
+		nil,
+		eval.RegoInputMap{
+			"key":   key,
+			"value": value,
+		},
+	)
+
+	return newResolvedInstance(instance, key, "sysctl"), nil
+}
+
+// sysctlPath returns the path of a kernel parameter in /proc/sys, the key can either use
+// dots or slashes as separators
+func sysctlPath(key string) (string, error) {
+	if key == "" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid sysctl key `%s`", key)
+	}
+
+	return filepath.Join(procSysPath, strings.ReplaceAll(key, ".", "/")), nil
+}
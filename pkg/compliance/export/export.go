@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package export implements the local export of compliance results
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+// Format describes the format of an export file
+type Format string
+
+const (
+	// FormatJSON exports the results as a JSON array of events
+	FormatJSON Format = "json"
+	// FormatJUnit exports the results as a JUnit XML report
+	FormatJUnit Format = "junit"
+	// FormatSARIF exports the results as a SARIF 2.1.0 log
+	FormatSARIF Format = "sarif"
+)
+
+// Formats lists the supported export formats
+var Formats = []Format{FormatJSON, FormatJUnit, FormatSARIF}
+
+// ParseFormat returns the format matching the given name
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(name))
+	for _, f := range Formats {
+		if f == format {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported export format '%s'", name)
+}
+
+// Write writes the given events to w using the given format
+func Write(w io.Writer, format Format, events []*event.Event) error {
+	events = sortEvents(events)
+
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, events)
+	case FormatJUnit:
+		return writeJUnit(w, events)
+	case FormatSARIF:
+		return writeSARIF(w, events)
+	}
+	return fmt.Errorf("unsupported export format '%s'", format)
+}
+
+// HasFailures returns whether one of the events is not passed
+func HasFailures(events []*event.Event) bool {
+	for _, e := range events {
+		if e.Result != event.Passed {
+			return true
+		}
+	}
+	return false
+}
+
+// sortEvents returns a copy of events sorted by framework, rule and resource
+// so that the exported files are stable from one run to another
+func sortEvents(events []*event.Event) []*event.Event {
+	sorted := make([]*event.Event, len(events))
+	copy(sorted, events)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.AgentFrameworkID != b.AgentFrameworkID {
+			return a.AgentFrameworkID < b.AgentFrameworkID
+		}
+		if a.AgentRuleID != b.AgentRuleID {
+			return a.AgentRuleID < b.AgentRuleID
+		}
+		return a.ResourceID < b.ResourceID
+	})
+
+	return sorted
+}
+
+func resourceName(e *event.Event) string {
+	if e.ResourceType == "" {
+		return e.ResourceID
+	}
+	return fmt.Sprintf("%s:%s", e.ResourceType, e.ResourceID)
+}
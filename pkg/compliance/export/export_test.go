@@ -0,0 +1,173 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+var testEvents = []*event.Event{
+	{
+		AgentRuleID:      "cis-docker-1",
+		AgentFrameworkID: "cis-docker",
+		Result:           event.Failed,
+		ResourceType:     "docker_daemon",
+		ResourceID:       "host_daemon",
+		Data:             event.Data{"file.owner": "nobody"},
+	},
+	{
+		AgentRuleID:      "cis-docker-2",
+		AgentFrameworkID: "cis-docker",
+		Result:           event.Error,
+		ResourceType:     "docker_daemon",
+		ResourceID:       "host_daemon",
+	},
+	{
+		AgentRuleID:      "cis-docker-1",
+		AgentFrameworkID: "cis-docker",
+		Result:           event.Passed,
+		ResourceType:     "docker_daemon",
+		ResourceID:       "another_daemon",
+		Data:             event.Data{"file.owner": "root"},
+	},
+	{
+		AgentRuleID:      "cis-kubernetes-1",
+		AgentFrameworkID: "cis-kubernetes",
+		Result:           event.Passed,
+		ResourceType:     "kubernetes_worker_node",
+		ResourceID:       "node",
+	},
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("SARIF")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatSARIF, format)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, FormatJSON, testEvents))
+
+	var events []*event.Event
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &events))
+	assert.Len(t, events, 4)
+	assert.Equal(t, "cis-docker-1", events[0].AgentRuleID)
+	assert.Equal(t, "another_daemon", events[0].ResourceID)
+	assert.Equal(t, event.Passed, events[0].Result)
+	assert.Equal(t, map[string]interface{}{"file.owner": "root"}, events[0].Data)
+
+	buf.Reset()
+	assert.NoError(t, Write(&buf, FormatJSON, nil))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, FormatJUnit, testEvents))
+
+	var report junitTestSuites
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, 4, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Errors)
+	assert.Len(t, report.Suites, 2)
+
+	docker := report.Suites[0]
+	assert.Equal(t, "cis-docker", docker.Name)
+	assert.Equal(t, 3, docker.Tests)
+	assert.Len(t, docker.TestCases, 3)
+
+	assert.Equal(t, "cis-docker-1 docker_daemon:another_daemon", docker.TestCases[0].Name)
+	assert.Equal(t, "cis-docker.cis-docker-1", docker.TestCases[0].ClassName)
+	assert.Nil(t, docker.TestCases[0].Failure)
+	assert.Nil(t, docker.TestCases[0].Error)
+
+	assert.NotNil(t, docker.TestCases[1].Failure)
+	assert.Equal(t, `{"file.owner":"nobody"}`, docker.TestCases[1].SystemOut)
+	assert.NotNil(t, docker.TestCases[2].Error)
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, FormatSARIF, testEvents))
+
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, sarifVersion, log.Version)
+	assert.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Len(t, run.Tool.Driver.Rules, 3)
+	assert.Len(t, run.Results, 4)
+
+	passed := run.Results[0]
+	assert.Equal(t, "cis-docker-1", passed.RuleID)
+	assert.Equal(t, 0, passed.RuleIndex)
+	assert.Equal(t, "pass", passed.Kind)
+	assert.Equal(t, "none", passed.Level)
+	assert.Equal(t, "another_daemon", passed.Locations[0].LogicalLocations[0].Name)
+	assert.Equal(t, "docker_daemon", passed.Locations[0].LogicalLocations[0].Kind)
+
+	failed := run.Results[1]
+	assert.Equal(t, 0, failed.RuleIndex)
+	assert.Equal(t, "fail", failed.Kind)
+	assert.Equal(t, "error", failed.Level)
+	assert.Equal(t, map[string]interface{}{"file.owner": "nobody"}, failed.Properties["data"])
+
+	errored := run.Results[2]
+	assert.Equal(t, "cis-docker-2", errored.RuleID)
+	assert.Equal(t, 1, errored.RuleIndex)
+	assert.Equal(t, "warning", errored.Level)
+}
+
+func TestReporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "compliance.json")
+	reporter := NewReporter(nil, path, FormatJSON)
+
+	for _, e := range testEvents {
+		reporter.Report(e)
+	}
+
+	// a new result for the same rule and resource replaces the previous one
+	reporter.Report(&event.Event{
+		AgentRuleID:      "cis-docker-1",
+		AgentFrameworkID: "cis-docker",
+		Result:           event.Passed,
+		ResourceType:     "docker_daemon",
+		ResourceID:       "host_daemon",
+	})
+
+	assert.Len(t, reporter.Events(), 4)
+	assert.False(t, HasFailures([]*event.Event{testEvents[2], testEvents[3]}))
+	assert.True(t, HasFailures(reporter.Events()))
+
+	// the file is only written when flushed
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	assert.NoError(t, reporter.Flush())
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var events []*event.Event
+	assert.NoError(t, json.Unmarshal(content, &events))
+	assert.Len(t, events, 4)
+	assert.Equal(t, "host_daemon", events[1].ResourceID)
+	assert.Equal(t, event.Passed, events[1].Result)
+}
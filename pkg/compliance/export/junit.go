@@ -0,0 +1,115 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+func writeJUnit(w io.Writer, events []*event.Event) error {
+	report := junitTestSuites{
+		Name: "compliance",
+	}
+
+	suiteIndex := make(map[string]int)
+	for _, e := range events {
+		framework := e.AgentFrameworkID
+		if framework == "" {
+			framework = "default"
+		}
+
+		index, found := suiteIndex[framework]
+		if !found {
+			index = len(report.Suites)
+			suiteIndex[framework] = index
+			report.Suites = append(report.Suites, junitTestSuite{Name: framework})
+		}
+		suite := &report.Suites[index]
+
+		testCase := junitTestCase{
+			Name:      fmt.Sprintf("%s %s", e.AgentRuleID, resourceName(e)),
+			ClassName: fmt.Sprintf("%s.%s", framework, e.AgentRuleID),
+		}
+
+		if e.Data != nil {
+			data, err := json.Marshal(e.Data)
+			if err != nil {
+				return err
+			}
+			testCase.SystemOut = string(data)
+		}
+
+		switch e.Result {
+		case event.Passed:
+		case event.Failed:
+			testCase.Failure = &junitMessage{
+				Message: fmt.Sprintf("rule %s failed on %s", e.AgentRuleID, resourceName(e)),
+				Type:    e.Result,
+			}
+			suite.Failures++
+			report.Failures++
+		default:
+			testCase.Error = &junitMessage{
+				Message: fmt.Sprintf("rule %s could not be evaluated on %s", e.AgentRuleID, resourceName(e)),
+				Type:    e.Result,
+			}
+			suite.Errors++
+			report.Errors++
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+		report.Tests++
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
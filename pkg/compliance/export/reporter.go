@@ -0,0 +1,161 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// WriteFile atomically writes the given events to path using the given format
+func WriteFile(path string, format Format, events []*event.Event) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if err := Write(tmpFile, format, events); err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	if err := tmpFile.Chmod(0644); err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
+
+// flushInterval is how often the export file is rewritten when new results were reported
+const flushInterval = 10 * time.Second
+
+type resultKey struct {
+	ruleID       string
+	resourceType string
+	resourceID   string
+}
+
+// Reporter is an event.Reporter that forwards events to an underlying reporter
+// and maintains a local export file with the latest result of each rule and resource.
+// The file is rewritten periodically once Start is called, and when the reporter is stopped.
+type Reporter struct {
+	sync.Mutex
+	reporter event.Reporter
+	path     string
+	format   Format
+	results  map[resultKey]*event.Event
+	dirty    bool
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewReporter returns a new export reporter. The underlying reporter may be nil.
+func NewReporter(reporter event.Reporter, path string, format Format) *Reporter {
+	return &Reporter{
+		reporter: reporter,
+		path:     path,
+		format:   format,
+		results:  make(map[resultKey]*event.Event),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start rewrites the export file periodically with the new results
+func (r *Reporter) Start() {
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.flushOrLog()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic rewrite started by Start and writes the latest results
+func (r *Reporter) Stop() {
+	close(r.stop)
+	<-r.done
+	r.flushOrLog()
+}
+
+// Report stores the event and forwards it, the export file is written by Flush
+func (r *Reporter) Report(e *event.Event) {
+	r.Lock()
+	key := resultKey{
+		ruleID:       e.AgentRuleID,
+		resourceType: e.ResourceType,
+		resourceID:   e.ResourceID,
+	}
+	r.results[key] = e
+	r.dirty = true
+	r.Unlock()
+
+	if r.reporter != nil {
+		r.reporter.Report(e)
+	}
+}
+
+// ReportRaw forwards raw content to the underlying reporter
+func (r *Reporter) ReportRaw(content []byte, service string, tags ...string) {
+	if r.reporter != nil {
+		r.reporter.ReportRaw(content, service, tags...)
+	}
+}
+
+// Events returns the latest result of each rule and resource
+func (r *Reporter) Events() []*event.Event {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.events()
+}
+
+func (r *Reporter) events() []*event.Event {
+	events := make([]*event.Event, 0, len(r.results))
+	for _, e := range r.results {
+		events = append(events, e)
+	}
+	return events
+}
+
+// Flush writes the export file if results were reported since the previous write
+func (r *Reporter) Flush() error {
+	r.Lock()
+	defer r.Unlock()
+
+	if !r.dirty {
+		return nil
+	}
+	if err := WriteFile(r.path, r.format, r.events()); err != nil {
+		return err
+	}
+	r.dirty = false
+	return nil
+}
+
+func (r *Reporter) flushOrLog() {
+	if err := r.Flush(); err != nil {
+		log.Errorf("Failed to export compliance results to %s: %v", r.path, err)
+	}
+}
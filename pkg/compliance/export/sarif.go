@@ -0,0 +1,148 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/version"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID         string                 `json:"id"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Kind       string                 `json:"kind"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+func writeSARIF(w io.Writer, events []*event.Event) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "datadog-agent",
+				Version:        version.AgentVersion,
+				InformationURI: "https://docs.datadoghq.com/security_platform/cspm/",
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	ruleIndex := make(map[string]int)
+	for _, e := range events {
+		index, found := ruleIndex[e.AgentRuleID]
+		if !found {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndex[e.AgentRuleID] = index
+
+			rule := sarifRule{ID: e.AgentRuleID}
+			if e.AgentFrameworkID != "" {
+				rule.Properties = map[string]interface{}{
+					"framework": e.AgentFrameworkID,
+					"version":   e.AgentRuleVersion,
+				}
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+
+		result := sarifResult{
+			RuleID:    e.AgentRuleID,
+			RuleIndex: index,
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name: e.ResourceID,
+					Kind: e.ResourceType,
+				}},
+			}},
+			Properties: map[string]interface{}{
+				"result": e.Result,
+			},
+		}
+
+		switch e.Result {
+		case event.Passed:
+			result.Kind, result.Level = "pass", "none"
+			result.Message.Text = fmt.Sprintf("Rule %s passed on %s", e.AgentRuleID, resourceName(e))
+		case event.Failed:
+			result.Kind, result.Level = "fail", "error"
+			result.Message.Text = fmt.Sprintf("Rule %s failed on %s", e.AgentRuleID, resourceName(e))
+		default:
+			result.Kind, result.Level = "fail", "warning"
+			result.Message.Text = fmt.Sprintf("Rule %s could not be evaluated on %s", e.AgentRuleID, resourceName(e))
+		}
+
+		if e.Data != nil {
+			result.Properties["data"] = e.Data
+		}
+		if len(e.Tags) != 0 {
+			result.Properties["tags"] = e.Tags
+		}
+
+		run.Results = append(run.Results, result)
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(log)
+}
@@ -34,6 +34,12 @@ const (
 	KindConstants = ResourceKind("constants")
 	// KindCustom is used for a Custom check
 	KindCustom = ResourceKind("custom")
+	// KindPackage is used for an installed Package resource
+	KindPackage = ResourceKind("package")
+	// KindSysctl is used for a Sysctl kernel parameter resource
+	KindSysctl = ResourceKind("sysctl")
+	// KindKernelModule is used for a KernelModule resource
+	KindKernelModule = ResourceKind("kernelModule")
 )
 
 // ResourceCommon describes the base fields of resource types
@@ -47,6 +53,9 @@ type ResourceCommon struct {
 	KubeApiserver *KubernetesResource `yaml:"kubeApiserver,omitempty"`
 	Constants     *ConstantsResource  `yaml:"constants,omitempty"`
 	Custom        *Custom             `yaml:"custom,omitempty"`
+	Package       *Package            `yaml:"package,omitempty"`
+	Sysctl        *Sysctl             `yaml:"sysctl,omitempty"`
+	KernelModule  *KernelModule       `yaml:"kernelModule,omitempty"`
 }
 
 // Resource describes supported resource types observed by a Rule
@@ -96,6 +105,12 @@ func (r *ResourceCommon) Kind() ResourceKind {
 		return KindConstants
 	case r.Custom != nil:
 		return KindCustom
+	case r.Package != nil:
+		return KindPackage
+	case r.Sysctl != nil:
+		return KindSysctl
+	case r.KernelModule != nil:
+		return KindKernelModule
 	default:
 		return KindInvalid
 	}
@@ -301,3 +316,39 @@ type Custom struct {
 	Name      string            `yaml:"name"`
 	Variables map[string]string `yaml:"variables,omitempty"`
 }
+
+// Fields & functions available for Package
+const (
+	PackageFieldName      = "package.name"
+	PackageFieldVersion   = "package.version"
+	PackageFieldInstalled = "package.installed"
+
+	PackageFuncVersionCompare = "package.versionCompare"
+)
+
+// Package describes a package installed through the package manager of the host
+type Package struct {
+	Name string `yaml:"name"`
+}
+
+// Fields available for Sysctl
+const (
+	SysctlFieldKey   = "sysctl.key"
+	SysctlFieldValue = "sysctl.value"
+)
+
+// Sysctl describes a kernel parameter, as exposed in /proc/sys
+type Sysctl struct {
+	Key string `yaml:"key"`
+}
+
+// Fields available for KernelModule
+const (
+	KernelModuleFieldName   = "kernelModule.name"
+	KernelModuleFieldLoaded = "kernelModule.loaded"
+)
+
+// KernelModule describes a kernel module
+type KernelModule struct {
+	Name string `yaml:"name"`
+}
@@ -62,6 +62,24 @@ docker:
 condition: docker.template("{{ $.Config.Healthcheck }}") != ""
 `
 
+const testResourcePackage = `
+package:
+  name: openssh-server
+condition: package.installed && package.versionCompare("1:8.4") >= 0
+`
+
+const testResourceSysctl = `
+sysctl:
+  key: net.ipv4.ip_forward
+condition: sysctl.value == "0"
+`
+
+const testResourceKernelModule = `
+kernelModule:
+  name: cramfs
+condition: '!kernelModule.loaded'
+`
+
 func TestResources(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -166,6 +184,42 @@ func TestResources(t *testing.T) {
 				Condition: `docker.template("{{ $.Config.Healthcheck }}") != ""`,
 			},
 		},
+		{
+			name:  "package",
+			input: testResourcePackage,
+			expected: Resource{
+				ResourceCommon: ResourceCommon{
+					Package: &Package{
+						Name: "openssh-server",
+					},
+				},
+				Condition: `package.installed && package.versionCompare("1:8.4") >= 0`,
+			},
+		},
+		{
+			name:  "sysctl",
+			input: testResourceSysctl,
+			expected: Resource{
+				ResourceCommon: ResourceCommon{
+					Sysctl: &Sysctl{
+						Key: "net.ipv4.ip_forward",
+					},
+				},
+				Condition: `sysctl.value == "0"`,
+			},
+		},
+		{
+			name:  "kernel module",
+			input: testResourceKernelModule,
+			expected: Resource{
+				ResourceCommon: ResourceCommon{
+					KernelModule: &KernelModule{
+						Name: "cramfs",
+					},
+				},
+				Condition: `!kernelModule.loaded`,
+			},
+		},
 	}
 
 	for _, test := range tests {
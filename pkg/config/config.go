@@ -352,6 +352,9 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("secret_backend_command_allow_group_exec_perm", false)
 	config.BindEnvAndSetDefault("secret_backend_skip_checks", false)
 
+	// exec checks
+	config.BindEnvAndSetDefault("exec_checks_allow_group_exec_perm", false)
+
 	// Use to output logs in JSON format
 	config.BindEnvAndSetDefault("log_format_json", false)
 
@@ -603,6 +606,7 @@ func InitConfig(config Config) {
 	config.BindEnv("prometheus_scrape.checks")                                // Defines any extra prometheus/openmetrics check configurations to be handled by the prometheus config provider
 	config.SetEnvKeyTransformer("prometheus_scrape.checks", prometheusScrapeChecksTransformer)
 	config.BindEnvAndSetDefault("prometheus_scrape.version", 1) // Version of the openmetrics check to be scheduled by the Prometheus auto-discovery
+	config.BindEnvAndSetDefault("prometheus_scrape.loader", "") // Loader of the openmetrics checks scheduled by the Prometheus auto-discovery, "core" selects the Go check
 
 	// SNMP
 	config.SetKnown("snmp_listener.discovery_interval")
@@ -615,9 +619,13 @@ func InitConfig(config Config) {
 	config.SetKnown("snmp_listener.min_collection_interval")
 	config.SetKnown("snmp_listener.namespace")
 
+	// Process listener
+	config.BindEnvAndSetDefault("process_listener.discovery_interval", 30) // in seconds
+
 	config.BindEnvAndSetDefault("snmp_traps_enabled", false)
 	config.BindEnvAndSetDefault("snmp_traps_config.port", 162)
 	config.BindEnvAndSetDefault("snmp_traps_config.community_strings", []string{})
+	config.SetKnown("snmp_traps_config.users")
 	config.BindEnvAndSetDefault("snmp_traps_config.bind_host", "localhost")
 	config.BindEnvAndSetDefault("snmp_traps_config.stop_timeout", 5) // in seconds
 
@@ -747,6 +755,10 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("logs_config.open_files_limit", 100)
 	// add global processing rules that are applied on all logs
 	config.BindEnv("logs_config.processing_rules")
+	// send some of the logs to their own endpoints
+	config.BindEnv("logs_config.routes")
+	// limit the rate of the logs of some services
+	config.BindEnv("logs_config.rate_limits")
 	// enforce the agent to use files to collect container logs on kubernetes environment
 	config.BindEnvAndSetDefault("logs_config.k8s_container_use_file", false)
 	// Enable the agent to use files to collect container logs on standalone docker environment, containers
@@ -779,6 +791,15 @@ func InitConfig(config Config) {
 	bindEnvAndSetLogsConfigKeys(config, "network_devices.metadata.")
 	config.BindEnvAndSetDefault("network_devices.namespace", "default")
 
+	// NetFlow
+	config.BindEnvAndSetDefault("network_devices.netflow.enabled", false)
+	config.SetKnown("network_devices.netflow.listeners")
+	config.BindEnvAndSetDefault("network_devices.netflow.stop_timeout", 5) // in seconds
+	config.BindEnvAndSetDefault("network_devices.netflow.aggregator_buffer_size", 10000)
+	config.BindEnvAndSetDefault("network_devices.netflow.aggregator_flush_interval", 300) // in seconds
+	config.BindEnvAndSetDefault("network_devices.netflow.aggregator_max_flows", 100000)
+	bindEnvAndSetLogsConfigKeys(config, "network_devices.netflow.forwarder.")
+
 	config.BindEnvAndSetDefault("logs_config.dd_port", 10516)
 	config.BindEnvAndSetDefault("logs_config.dev_mode_use_proto", true)
 	config.BindEnvAndSetDefault("logs_config.dd_url_443", "agent-443-intake.logs.datadoghq.com")
@@ -926,6 +947,11 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("inventories_max_interval", DefaultInventoriesMaxInterval) // integer seconds
 	config.BindEnvAndSetDefault("inventories_min_interval", DefaultInventoriesMinInterval) // integer seconds
 
+	// software inventory
+	config.BindEnvAndSetDefault("software_inventory.enabled", false)
+	config.BindEnvAndSetDefault("software_inventory.interval", 3600) // integer seconds
+	config.BindEnvAndSetDefault("software_inventory.host_root", "/")
+
 	// Datadog security agent (common)
 	config.BindEnvAndSetDefault("security_agent.cmd_port", 5010)
 	config.BindEnvAndSetDefault("security_agent.expvar_port", 5011)
@@ -939,6 +965,8 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("compliance_config.dir", "/etc/datadog-agent/compliance.d")
 	config.BindEnvAndSetDefault("compliance_config.run_path", defaultRunPath)
 	config.BindEnv("compliance_config.run_commands_as")
+	config.BindEnvAndSetDefault("compliance_config.export.path", "")
+	config.BindEnvAndSetDefault("compliance_config.export.format", "json")
 	bindEnvAndSetLogsConfigKeys(config, "compliance_config.endpoints.")
 
 	// Datadog security agent (runtime)
@@ -972,6 +1000,8 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("runtime_security_config.self_test.enabled", true)
 	config.BindEnvAndSetDefault("runtime_security_config.enable_remote_configuration", false)
 	config.BindEnvAndSetDefault("runtime_security_config.enable_runtime_compiled_constants", false)
+	config.BindEnvAndSetDefault("runtime_security_config.enforcement.enabled", false)
+	config.BindEnvAndSetDefault("runtime_security_config.enforcement.kill_allowlist", []string{})
 
 	// Serverless Agent
 	config.BindEnvAndSetDefault("serverless.logs_enabled", true)
@@ -1061,15 +1091,15 @@ func loadProxyFromEnv(config Config) {
 	// We have to set each value individually so both config.Get("proxy")
 	// and config.Get("proxy.http") work
 	if isSet {
-		config.Set("proxy.http", p.HTTP)
-		config.Set("proxy.https", p.HTTPS)
+		config.SetWithSource("proxy.http", p.HTTP, SourceEnvVar)
+		config.SetWithSource("proxy.https", p.HTTPS, SourceEnvVar)
 		if len(p.NoProxy) > 0 {
-			config.Set("proxy.no_proxy", p.NoProxy)
+			config.SetWithSource("proxy.no_proxy", p.NoProxy, SourceEnvVar)
 		} else {
 			// If this is set to an empty []string, viper will have a type conflict when merging
 			// this config during secrets resolution. It unmarshals empty yaml lists to type
 			// []interface{}, which will then conflict with type []string and fail to merge.
-			config.Set("proxy.no_proxy", []interface{}{})
+			config.SetWithSource("proxy.no_proxy", []interface{}{}, SourceEnvVar)
 		}
 		proxies = p
 	}
@@ -1186,6 +1216,10 @@ func load(config Config, origin string, loadSecret bool) (*Warnings, error) {
 		log.Warnf("Unknown environment variable: %v", v)
 	}
 
+	for _, setting := range findDeprecatedKeys(config) {
+		log.Warnf("Deprecated key in config: %v, %v", setting.Key, setting.Message)
+	}
+
 	if loadSecret {
 		if err := ResolveSecrets(config, origin); err != nil {
 			return &warnings, err
@@ -1231,7 +1265,8 @@ func ResolveSecrets(config Config, origin string) error {
 		// loads. Since we are searching for 'datadog.yaml' in multiple
 		// locations we let viper determine the one to use before
 		// updating it.
-		yamlConf, err := yaml.Marshal(config.AllSettings())
+		allSettings := config.AllSettings()
+		yamlConf, err := yaml.Marshal(allSettings)
 		if err != nil {
 			return fmt.Errorf("unable to marshal configuration to YAML to decrypt secrets: %v", err)
 		}
@@ -1244,13 +1279,16 @@ func ResolveSecrets(config Config, origin string) error {
 		if err = config.MergeConfigOverride(r); err != nil {
 			return fmt.Errorf("could not update main configuration after decrypting secrets: %v", err)
 		}
+		for _, key := range findSecretKeys("", allSettings) {
+			config.SetSource(key, SourceSecret)
+		}
 	}
 	return nil
 }
 
 // SanitizeAPIKeyConfig strips newlines and other control characters from a given key.
 func SanitizeAPIKeyConfig(config Config, key string) {
-	config.Set(key, SanitizeAPIKey(config.GetString(key)))
+	config.SetWithSource(key, SanitizeAPIKey(config.GetString(key)), config.GetSource(key))
 }
 
 // SanitizeAPIKey strips newlines and other control characters from a given string.
@@ -1492,7 +1530,7 @@ func setTracemallocEnabled(config Config) bool {
 	}
 
 	// update config with the actual effective tracemalloc
-	config.Set("tracemalloc_debug", wTracemalloc)
+	config.SetWithSource("tracemalloc_debug", wTracemalloc, config.GetSource("tracemalloc_debug"))
 	return traceMallocEnabledWithPy2
 }
 
@@ -1501,13 +1539,15 @@ func setTracemallocEnabled(config Config) bool {
 func setNumWorkers(config Config) {
 	wTracemalloc := config.GetBool("tracemalloc_debug")
 	numWorkers := config.GetInt("check_runners")
+	source := config.GetSource("check_runners")
 	if wTracemalloc {
 		log.Infof("Tracemalloc enabled, only one check runner enabled to run checks serially")
 		numWorkers = 1
+		source = config.GetSource("tracemalloc_debug")
 	}
 
 	// update config with the actual effective number of workers
-	config.Set("check_runners", numWorkers)
+	config.SetWithSource("check_runners", numWorkers, source)
 }
 
 // GetDogstatsdMappingProfiles returns mapping profiles used in DogStatsD mapper
@@ -1556,6 +1596,26 @@ func IsCLCRunner() bool {
 	return true
 }
 
+// IsConfigProviderEnabled returns whether the config provider is listed in `config_providers`
+// or `extra_config_providers`
+func IsConfigProviderEnabled(name string) bool {
+	var cps []ConfigurationProviders
+	if err := Datadog.UnmarshalKey("config_providers", &cps); err != nil {
+		return false
+	}
+	for _, cp := range cps {
+		if cp.Name == name {
+			return true
+		}
+	}
+	for _, extra := range Datadog.GetStringSlice("extra_config_providers") {
+		if extra == name {
+			return true
+		}
+	}
+	return false
+}
+
 // GetBindHost returns `bind_host` variable or default value
 // Not using `config.BindEnvAndSetDefault` as some processes need to know
 // if value was default one or not (e.g. trace-agent)
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -1060,3 +1061,66 @@ func TestGetInventoriesMaxIntervalInvalid(t *testing.T) {
 	Mock().Set("inventories_max_interval", 0)
 	assert.EqualValues(t, DefaultInventoriesMaxInterval*time.Second, GetInventoriesMaxInterval())
 }
+
+func TestGetProvenance(t *testing.T) {
+	config := setupConfFromYAML(`
+api_key: 0123456789abcdef0123456789abcdef
+log_enabled: true
+unknown_key.unknown_subkey: true
+logs_config:
+  use_http: true
+`)
+	resetSite := setEnvForTest("DD_SITE", "datadoghq.eu")
+	defer resetSite()
+	resetUnknown := setEnvForTest("DD_UNKNOWN_VAR", "foo")
+	defer resetUnknown()
+	config.Set("hostname", "my-host")
+	config.SetWithSource("app_key", "ENC[app_key]", SourceSecret)
+
+	provenance := GetProvenance(config, "")
+
+	sources := map[string]Source{}
+	values := map[string]interface{}{}
+	for _, setting := range provenance.Settings {
+		sources[setting.Key] = setting.Source
+		values[setting.Key] = setting.Value
+	}
+	assert.Equal(t, SourceFile, sources["api_key"])
+	assert.Equal(t, SourceFile, sources["logs_config.use_http"])
+	assert.Equal(t, SourceEnvVar, sources["site"])
+	assert.Equal(t, SourceAgentRuntime, sources["hostname"])
+	assert.Equal(t, SourceDefault, sources["logs_enabled"])
+	assert.Equal(t, SourceSecret, sources["app_key"])
+	assert.Equal(t, secretPlaceholder, values["app_key"])
+
+	assert.Equal(t, []string{"unknown_key.unknown_subkey"}, provenance.UnknownKeys)
+	assert.Contains(t, provenance.UnknownEnvVars, "DD_UNKNOWN_VAR")
+	assert.Equal(t, []DeprecatedSetting{
+		{Key: "log_enabled", Source: SourceFile, Message: "use logs_enabled instead"},
+	}, provenance.DeprecatedKeys)
+
+	provenance = GetProvenance(config, "logs_config")
+	for _, setting := range provenance.Settings {
+		assert.True(t, strings.HasPrefix(setting.Key, "logs_config."), setting.Key)
+	}
+	assert.Empty(t, provenance.UnknownKeys)
+	assert.Empty(t, provenance.DeprecatedKeys)
+}
+
+func TestFindSecretKeys(t *testing.T) {
+	settings := map[string]interface{}{
+		"api_key": "ENC[api_key]",
+		"site":    "datadoghq.com",
+		"proxy": map[string]interface{}{
+			"http":  " ENC[proxy] ",
+			"https": "https://proxy",
+		},
+		"additional_endpoints": map[interface{}]interface{}{
+			"https://app.datadoghq.com": []interface{}{"ENC[key1]", "plainkey"},
+		},
+		"tags": []string{"env:prod"},
+	}
+	keys := findSecretKeys("", settings)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"additional_endpoints.https://app.datadoghq.com", "api_key", "proxy.http"}, keys)
+}
@@ -13,6 +13,7 @@ const (
 	ExperimentalOTLPTracePort         = ExperimentalOTLPSection + ".internal_traces_port"
 	ExperimentalOTLPMetricsEnabled    = ExperimentalOTLPSection + ".metrics_enabled"
 	ExperimentalOTLPTracesEnabled     = ExperimentalOTLPSection + ".traces_enabled"
+	ExperimentalOTLPLogsEnabled       = ExperimentalOTLPSection + ".logs_enabled"
 	ReceiverSubSectionKey             = "receiver"
 	ExperimentalOTLPReceiverSection   = ExperimentalOTLPSection + "." + ReceiverSubSectionKey
 	ExperimentalOTLPMetrics           = ExperimentalOTLPSection + ".metrics"
@@ -24,6 +25,7 @@ func SetupOTLP(config Config) {
 	config.BindEnvAndSetDefault(ExperimentalOTLPTracePort, 5003)
 	config.BindEnvAndSetDefault(ExperimentalOTLPMetricsEnabled, true)
 	config.BindEnvAndSetDefault(ExperimentalOTLPTracesEnabled, true)
+	config.BindEnvAndSetDefault(ExperimentalOTLPLogsEnabled, false)
 	config.BindEnv(ExperimentalOTLPHTTPPort, "DD_OTLP_HTTP_PORT")
 	config.BindEnv(ExperimentalOTLPgRPCPort, "DD_OTLP_GRPC_PORT")
 
@@ -125,15 +125,17 @@ func loadProcessTransforms(config Config) {
 			"and process_config.process_collection.enabled instead, " +
 			"see https://docs.datadoghq.com/infrastructure/process#installation for more information")
 		procConfigEnabled := strings.ToLower(config.GetString("process_config.enabled"))
+		// the translated keys come from wherever process_config.enabled was set
+		source := config.GetSource("process_config.enabled")
 		if procConfigEnabled == "disabled" {
-			config.Set("process_config.process_collection.enabled", false)
-			config.Set("process_config.container_collection.enabled", false)
+			config.SetWithSource("process_config.process_collection.enabled", false, source)
+			config.SetWithSource("process_config.container_collection.enabled", false, source)
 		} else if enabled, _ := strconv.ParseBool(procConfigEnabled); enabled { // "true"
-			config.Set("process_config.process_collection.enabled", true)
-			config.Set("process_config.container_collection.enabled", false)
+			config.SetWithSource("process_config.process_collection.enabled", true, source)
+			config.SetWithSource("process_config.container_collection.enabled", false, source)
 		} else { // "false"
-			config.Set("process_config.process_collection.enabled", false)
-			config.Set("process_config.container_collection.enabled", true)
+			config.SetWithSource("process_config.process_collection.enabled", false, source)
+			config.SetWithSource("process_config.container_collection.enabled", true, source)
 		}
 	}
 }
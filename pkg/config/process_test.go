@@ -306,11 +306,13 @@ func TestProcConfigEnabledTransform(t *testing.T) {
 	} {
 		t.Run("process_config.enabled="+tc.procConfigEnabled, func(t *testing.T) {
 			cfg := setupConf()
-			cfg.Set("process_config.enabled", tc.procConfigEnabled)
+			cfg.SetWithSource("process_config.enabled", tc.procConfigEnabled, SourceFile)
 			loadProcessTransforms(cfg)
 
 			assert.Equal(t, tc.expectedContainerCollection, cfg.GetBool("process_config.container_collection.enabled"))
 			assert.Equal(t, tc.expectedProcessCollection, cfg.GetBool("process_config.process_collection.enabled"))
+			assert.Equal(t, SourceFile, cfg.GetSource("process_config.container_collection.enabled"))
+			assert.Equal(t, SourceFile, cfg.GetSource("process_config.process_collection.enabled"))
 		})
 	}
 
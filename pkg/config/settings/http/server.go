@@ -6,9 +6,12 @@
 package http
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"html"
 	"net/http"
+	"strings"
 
 	ddconfig "github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/config/settings"
@@ -20,15 +23,17 @@ import (
 
 // Server offers functions that implement the standard runtime settings HTTP API
 var Server = struct {
-	GetFull          func(string) http.HandlerFunc
-	GetValue         http.HandlerFunc
-	SetValue         http.HandlerFunc
-	ListConfigurable http.HandlerFunc
+	GetFull            func(string) http.HandlerFunc
+	GetFullWithSources func(string) http.HandlerFunc
+	GetValue           http.HandlerFunc
+	SetValue           http.HandlerFunc
+	ListConfigurable   http.HandlerFunc
 }{
-	GetFull:          getFullConfig,
-	GetValue:         getConfigValue,
-	SetValue:         setConfigValue,
-	ListConfigurable: listConfigurableSettings,
+	GetFull:            getFullConfig,
+	GetFullWithSources: getFullConfigWithSources,
+	GetValue:           getConfigValue,
+	SetValue:           setConfigValue,
+	ListConfigurable:   listConfigurableSettings,
 }
 
 func getFullConfig(namespace string) http.HandlerFunc {
@@ -66,6 +71,72 @@ func getFullConfig(namespace string) http.HandlerFunc {
 	}
 }
 
+// getFullConfigWithSources renders the configuration as YAML, every key being
+// commented with the source of its value
+func getFullConfigWithSources(namespace string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		provenance := ddconfig.GetProvenance(ddconfig.Datadog, namespace)
+
+		var buf bytes.Buffer
+		buf.WriteString("settings:\n")
+		for _, setting := range provenance.Settings {
+			if err := writeSettingWithSource(&buf, setting); err != nil {
+				log.Errorf("Unable to render runtime config with sources: %s", err)
+				body, _ := json.Marshal(map[string]string{"error": err.Error()})
+				http.Error(w, string(body), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		buf.WriteString("unknown_keys:")
+		writeList(&buf, provenance.UnknownKeys)
+		buf.WriteString("unknown_env_vars:")
+		writeList(&buf, provenance.UnknownEnvVars)
+		buf.WriteString("deprecated_keys:")
+		if len(provenance.DeprecatedKeys) == 0 {
+			buf.WriteString(" {}\n")
+		} else {
+			buf.WriteString("\n")
+		}
+		for _, setting := range provenance.DeprecatedKeys {
+			fmt.Fprintf(&buf, "  %s: %q # %s\n", setting.Key, setting.Message, setting.Source)
+		}
+
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+// writeSettingWithSource writes the scrubbed YAML representation of setting, with
+// its source as a comment
+func writeSettingWithSource(buf *bytes.Buffer, setting ddconfig.SettingSource) error {
+	out, err := yaml.Marshal(map[string]interface{}{setting.Key: setting.Value})
+	if err != nil {
+		return err
+	}
+	// scrub every setting on its own, so that the source comment can't be scrubbed away
+	scrubbed, err := scrubber.ScrubBytes(out)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSuffix(string(scrubbed), "\n"), "\n")
+	lines[0] += " # " + string(setting.Source)
+	for _, line := range lines {
+		buf.WriteString("  " + line + "\n")
+	}
+	return nil
+}
+
+func writeList(buf *bytes.Buffer, items []string) {
+	if len(items) == 0 {
+		buf.WriteString(" []\n")
+		return
+	}
+	buf.WriteString("\n")
+	for _, item := range items {
+		fmt.Fprintf(buf, "  - %s\n", item)
+	}
+}
+
 func listConfigurableSettings(w http.ResponseWriter, _ *http.Request) {
 	configurableSettings := make(map[string]settings.RuntimeSettingResponse)
 	for name, setting := range settings.RuntimeSettings() {
@@ -117,7 +188,7 @@ func setConfigValue(w http.ResponseWriter, r *http.Request) {
 	_ = r.ParseForm()
 	value := html.UnescapeString(r.Form.Get("value"))
 
-	if err := settings.SetRuntimeSetting(setting, value); err != nil {
+	if err := settings.SetRuntimeSetting(setting, value, ddconfig.SourceCLI); err != nil {
 		body, _ := json.Marshal(map[string]string{"error": err.Error()})
 		switch err.(type) {
 		case *settings.SettingNotFoundError:
@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
 )
 
 var runtimeSettings = make(map[string]RuntimeSetting)
@@ -31,7 +33,7 @@ func (e *SettingNotFoundError) Error() string {
 // RuntimeSetting represents a setting that can be changed and read at runtime.
 type RuntimeSetting interface {
 	Get() (interface{}, error)
-	Set(v interface{}) error
+	Set(v interface{}, source config.Source) error
 	Name() string
 	Description() string
 	Hidden() bool
@@ -51,12 +53,13 @@ func RuntimeSettings() map[string]RuntimeSetting {
 	return runtimeSettings
 }
 
-// SetRuntimeSetting changes the value of a runtime configurable setting
-func SetRuntimeSetting(setting string, value interface{}) error {
+// SetRuntimeSetting changes the value of a runtime configurable setting, source
+// being the origin of the change
+func SetRuntimeSetting(setting string, value interface{}, source config.Source) error {
 	if _, ok := runtimeSettings[setting]; !ok {
 		return &SettingNotFoundError{name: setting}
 	}
-	return runtimeSettings[setting].Set(value)
+	return runtimeSettings[setting].Set(value, source)
 }
 
 // GetRuntimeSetting returns the value of a runtime configurable setting
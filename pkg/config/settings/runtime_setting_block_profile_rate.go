@@ -37,7 +37,7 @@ func (r RuntimeBlockProfileRate) Get() (interface{}, error) {
 }
 
 // Set changes the value of the runtime setting
-func (r RuntimeBlockProfileRate) Set(value interface{}) error {
+func (r RuntimeBlockProfileRate) Set(value interface{}, source config.Source) error {
 	rate, err := GetInt(value)
 	if err != nil {
 		return err
@@ -46,7 +46,7 @@ func (r RuntimeBlockProfileRate) Set(value interface{}) error {
 	err = checkProfilingNeedsRestart(profiling.GetBlockProfileRate(), rate)
 
 	profiling.SetBlockProfileRate(rate)
-	config.Datadog.Set("internal_profiling.block_profile_rate", rate)
+	config.Datadog.SetWithSource("internal_profiling.block_profile_rate", rate, source)
 
 	return err
 }
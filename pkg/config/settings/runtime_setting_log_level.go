@@ -40,7 +40,7 @@ func (l LogLevelRuntimeSetting) Get() (interface{}, error) {
 }
 
 // Set changes the value of the runtime setting
-func (l LogLevelRuntimeSetting) Set(v interface{}) error {
+func (l LogLevelRuntimeSetting) Set(v interface{}, source config.Source) error {
 	logLevel := v.(string)
 	err := config.ChangeLogLevel(logLevel)
 	if err != nil {
@@ -50,6 +50,6 @@ func (l LogLevelRuntimeSetting) Set(v interface{}) error {
 	if l.ConfigKey != "" {
 		key = l.ConfigKey
 	}
-	config.Datadog.Set(key, logLevel)
+	config.Datadog.SetWithSource(key, logLevel, source)
 	return nil
 }
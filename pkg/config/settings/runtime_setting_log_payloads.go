@@ -36,7 +36,7 @@ func (l LogPayloadsRuntimeSetting) Get() (interface{}, error) {
 }
 
 // Set changes the value of the runtime setting
-func (l LogPayloadsRuntimeSetting) Set(v interface{}) error {
+func (l LogPayloadsRuntimeSetting) Set(v interface{}, source config.Source) error {
 	var newValue bool
 	var err error
 
@@ -44,6 +44,6 @@ func (l LogPayloadsRuntimeSetting) Set(v interface{}) error {
 		return fmt.Errorf("LogPayloadsRuntimeSetting: %v", err)
 	}
 
-	config.Datadog.Set("log_payloads", newValue)
+	config.Datadog.SetWithSource("log_payloads", newValue, source)
 	return nil
 }
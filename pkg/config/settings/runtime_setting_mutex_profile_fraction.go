@@ -37,7 +37,7 @@ func (r RuntimeMutexProfileFraction) Get() (interface{}, error) {
 }
 
 // Set changes the value of the runtime setting
-func (r RuntimeMutexProfileFraction) Set(value interface{}) error {
+func (r RuntimeMutexProfileFraction) Set(value interface{}, source config.Source) error {
 	rate, err := GetInt(value)
 	if err != nil {
 		return err
@@ -46,7 +46,7 @@ func (r RuntimeMutexProfileFraction) Set(value interface{}) error {
 	err = checkProfilingNeedsRestart(profiling.GetMutexProfileFraction(), rate)
 
 	profiling.SetMutexProfileFraction(rate)
-	config.Datadog.Set("internal_profiling.mutex_profile_fraction", rate)
+	config.Datadog.SetWithSource("internal_profiling.mutex_profile_fraction", rate, source)
 
 	return err
 }
@@ -38,15 +38,15 @@ func (l ProfilingRuntimeSetting) Get() (interface{}, error) {
 }
 
 // Set changes the value of the runtime setting
-func (l ProfilingRuntimeSetting) Set(v interface{}) error {
+func (l ProfilingRuntimeSetting) Set(v interface{}, source config.Source) error {
 	var profile bool
 	var err error
 
 	if v, ok := v.(string); ok && strings.ToLower(v) == "restart" {
-		if err := l.Set(false); err != nil {
+		if err := l.Set(false, source); err != nil {
 			return err
 		}
-		return l.Set(true)
+		return l.Set(true, source)
 	}
 
 	profile, err = GetBool(v)
@@ -83,11 +83,11 @@ func (l ProfilingRuntimeSetting) Set(v interface{}) error {
 		}
 		err := profiling.Start(settings)
 		if err == nil {
-			config.Datadog.Set("internal_profiling.enabled", true)
+			config.Datadog.SetWithSource("internal_profiling.enabled", true, source)
 		}
 	} else {
 		profiling.Stop()
-		config.Datadog.Set("internal_profiling.enabled", false)
+		config.Datadog.SetWithSource("internal_profiling.enabled", false, source)
 	}
 
 	return nil
@@ -30,7 +30,7 @@ func (t *runtimeTestSetting) Get() (interface{}, error) {
 	return t.value, nil
 }
 
-func (t *runtimeTestSetting) Set(v interface{}) error {
+func (t *runtimeTestSetting) Set(v interface{}, source config.Source) error {
 	t.value = v.(int)
 	return nil
 }
@@ -61,7 +61,7 @@ func TestRuntimeSettings(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, runtimeSetting.value, v)
 
-	err = SetRuntimeSetting(runtimeSetting.Name(), 123)
+	err = SetRuntimeSetting(runtimeSetting.Name(), 123, config.SourceCLI)
 	assert.Nil(t, err)
 
 	v, err = GetRuntimeSetting(runtimeSetting.Name())
@@ -80,27 +80,29 @@ func TestLogLevel(t *testing.T) {
 	ll := LogLevelRuntimeSetting{}
 	assert.Equal(t, "log_level", ll.Name())
 
-	err := ll.Set("off")
+	err := ll.Set("off", config.SourceCLI)
 	assert.Nil(t, err)
 
 	v, err := ll.Get()
 	assert.Equal(t, "off", v)
 	assert.Nil(t, err)
 
-	err = ll.Set("WARNING")
+	err = ll.Set("WARNING", config.SourceCLI)
 	assert.Nil(t, err)
 
 	v, err = ll.Get()
 	assert.Equal(t, "warn", v)
 	assert.Nil(t, err)
+	assert.Equal(t, config.SourceCLI, config.Datadog.GetSource("log_level"))
 
-	err = ll.Set("invalid")
+	err = ll.Set("invalid", config.SourceCLI)
 	assert.NotNil(t, err)
 	assert.Equal(t, "unknown log level: invalid", err.Error())
 
 	v, err = ll.Get()
 	assert.Equal(t, "warn", v)
 	assert.Nil(t, err)
+	assert.Equal(t, config.SourceCLI, config.Datadog.GetSource("log_level"))
 }
 
 func TestProfiling(t *testing.T) {
@@ -110,14 +112,14 @@ func TestProfiling(t *testing.T) {
 	ll := ProfilingRuntimeSetting("internal_profiling")
 	assert.Equal(t, "internal_profiling", ll.Name())
 
-	err := ll.Set("false")
+	err := ll.Set("false", config.SourceCLI)
 	assert.Nil(t, err)
 
 	v, err := ll.Get()
 	assert.Equal(t, false, v)
 	assert.Nil(t, err)
 
-	err = ll.Set("on")
+	err = ll.Set("on", config.SourceCLI)
 	assert.NotNil(t, err)
 }
 
@@ -33,13 +33,13 @@ func (r ProfilingGoroutines) Get() (interface{}, error) {
 }
 
 // Set changes the value of the runtime setting
-func (r ProfilingGoroutines) Set(value interface{}) error {
+func (r ProfilingGoroutines) Set(value interface{}, source config.Source) error {
 	enabled, err := GetBool(value)
 	if err != nil {
 		return err
 	}
 
-	config.Datadog.Set("internal_profiling.enable_goroutine_stacktraces", enabled)
+	config.Datadog.SetWithSource("internal_profiling.enable_goroutine_stacktraces", enabled, source)
 
 	return nil
 }
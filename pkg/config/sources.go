@@ -0,0 +1,165 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Source is the origin of the value of a configuration key
+type Source string
+
+const (
+	// SourceDefault is used for the keys which have their default value
+	SourceDefault Source = "default"
+	// SourceFile is used for the keys set in a configuration file
+	SourceFile Source = "file"
+	// SourceEnvVar is used for the keys set through an environment variable
+	SourceEnvVar Source = "environment-variable"
+	// SourceSecret is used for the keys whose value was resolved by the secret backend
+	SourceSecret Source = "secret"
+	// SourceAgentRuntime is used for the keys set by the agent itself
+	SourceAgentRuntime Source = "agent-runtime"
+	// SourceCLI is used for the keys changed at runtime through the runtime settings API,
+	// e.g. with the `config set` command
+	SourceCLI Source = "cli"
+	// SourceUnset is used for the keys which have no value
+	SourceUnset Source = "unset"
+)
+
+// secretPlaceholder replaces the values resolved by the secret backend in the provenance report
+const secretPlaceholder = "********"
+
+// deprecatedKeys maps the deprecated configuration keys to a hint on what replaces them
+var deprecatedKeys = map[string]string{
+	"tracemalloc_whitelist":                            "use tracemalloc_include instead",
+	"tracemalloc_blacklist":                            "use tracemalloc_exclude instead",
+	"log_enabled":                                      "use logs_enabled instead",
+	"forwarder_retry_queue_max_size":                   "use forwarder_retry_queue_payloads_max_size instead",
+	"process_config.enabled":                           "use process_config.process_collection.enabled and process_config.container_collection.enabled instead",
+	"process_config.orchestrator_dd_url":               "use orchestrator_explorer.orchestrator_dd_url instead",
+	"process_config.orchestrator_additional_endpoints": "use orchestrator_explorer.orchestrator_additional_endpoints instead",
+}
+
+// SettingSource is the value of a configuration key and the source it comes from
+type SettingSource struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source Source      `json:"source"`
+}
+
+// DeprecatedSetting is a deprecated configuration key which is set
+type DeprecatedSetting struct {
+	Key     string `json:"key"`
+	Source  Source `json:"source"`
+	Message string `json:"message"`
+}
+
+// Provenance is the effective configuration, along with the source of every key
+// and the keys which need attention
+type Provenance struct {
+	Settings       []SettingSource     `json:"settings"`
+	UnknownKeys    []string            `json:"unknown_keys"`
+	UnknownEnvVars []string            `json:"unknown_env_vars"`
+	DeprecatedKeys []DeprecatedSetting `json:"deprecated_keys"`
+}
+
+// GetProvenance returns the effective configuration of the keys under namespace
+// (all of them if namespace is empty) along with their source. The values
+// resolved by the secret backend are masked.
+func GetProvenance(config Config, namespace string) Provenance {
+	inNamespace := func(key string) bool {
+		return namespace == "" || key == namespace || strings.HasPrefix(key, namespace+".")
+	}
+
+	provenance := Provenance{}
+
+	keys := config.AllKeys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !inNamespace(key) {
+			continue
+		}
+		setting := SettingSource{Key: key, Value: config.Get(key), Source: config.GetSource(key)}
+		if setting.Source == SourceSecret {
+			setting.Value = secretPlaceholder
+		}
+		provenance.Settings = append(provenance.Settings, setting)
+	}
+
+	for _, key := range findUnknownKeys(config) {
+		if inNamespace(key) {
+			provenance.UnknownKeys = append(provenance.UnknownKeys, key)
+		}
+	}
+	sort.Strings(provenance.UnknownKeys)
+
+	provenance.UnknownEnvVars = findUnknownEnvVars(config, os.Environ())
+	sort.Strings(provenance.UnknownEnvVars)
+
+	for _, setting := range findDeprecatedKeys(config) {
+		if inNamespace(setting.Key) {
+			provenance.DeprecatedKeys = append(provenance.DeprecatedKeys, setting)
+		}
+	}
+
+	return provenance
+}
+
+// findDeprecatedKeys returns the deprecated keys which are set in config, sorted by key
+func findDeprecatedKeys(config Config) []DeprecatedSetting {
+	var deprecated []DeprecatedSetting
+	for key, message := range deprecatedKeys {
+		source := config.GetSource(key)
+		if source == SourceDefault || source == SourceUnset {
+			continue
+		}
+		deprecated = append(deprecated, DeprecatedSetting{Key: key, Source: source, Message: message})
+	}
+	sort.Slice(deprecated, func(i, j int) bool { return deprecated[i].Key < deprecated[j].Key })
+	return deprecated
+}
+
+// findSecretKeys returns the keys of settings whose value contains a secret handle
+// (i.e. a string of the form "ENC[handle]")
+func findSecretKeys(prefix string, settings interface{}) []string {
+	var keys []string
+	switch v := settings.(type) {
+	case map[string]interface{}:
+		for k, sub := range v {
+			keys = append(keys, findSecretKeys(joinKey(prefix, k), sub)...)
+		}
+	case map[interface{}]interface{}:
+		for k, sub := range v {
+			keys = append(keys, findSecretKeys(joinKey(prefix, fmt.Sprint(k)), sub)...)
+		}
+	case []interface{}:
+		for _, sub := range v {
+			if len(findSecretKeys(prefix, sub)) > 0 {
+				return []string{prefix}
+			}
+		}
+	case []string:
+		for _, sub := range v {
+			if isSecretHandle(sub) {
+				return []string{prefix}
+			}
+		}
+	case string:
+		if isSecretHandle(v) {
+			return []string{prefix}
+		}
+	}
+	return keys
+}
+
+func isSecretHandle(value string) bool {
+	value = strings.Trim(value, " ")
+	return strings.HasPrefix(value, "ENC[") && strings.HasSuffix(value, "]")
+}
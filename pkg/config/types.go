@@ -89,4 +89,12 @@ type Config interface {
 	// GetEnvVars returns a list of the env vars that the config supports.
 	// These have had the EnvPrefix applied, as well as the EnvKeyReplacer.
 	GetEnvVars() []string
+
+	// SetWithSource sets the value of a key, like Set, and records the source it comes from
+	SetWithSource(key string, value interface{}, source Source)
+	// SetSource records the source of the current value of a key, when it was changed
+	// without Set, e.g. by MergeConfigOverride
+	SetSource(key string, source Source)
+	// GetSource returns the source the current value of a key comes from
+	GetSource(key string) Source
 }
@@ -6,8 +6,11 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,6 +21,7 @@ import (
 	"github.com/DataDog/viper"
 	"github.com/spf13/afero"
 	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
 )
 
 // safeConfig implements Config:
@@ -32,13 +36,108 @@ type safeConfig struct {
 	// configEnvVars is the set of env vars that are consulted for
 	// configuration values.
 	configEnvVars map[string]struct{}
+
+	// envBindings maps each key to the env vars bound to it
+	envBindings map[string][]string
+	// fileKeys is the set of keys read from configuration files
+	fileKeys map[string]struct{}
+	// sources holds the source of the keys set with Set, SetWithSource or
+	// SetSource, which take precedence over every other source
+	sources map[string]Source
+
+	fs afero.Fs
 }
 
-// Set wraps Viper for concurrent access
+// Set wraps Viper for concurrent access, the value is considered as set by the agent itself
 func (c *safeConfig) Set(key string, value interface{}) {
+	c.SetWithSource(key, value, SourceAgentRuntime)
+}
+
+// SetWithSource wraps Viper for concurrent access, and records the source of the value
+func (c *safeConfig) SetWithSource(key string, value interface{}, source Source) {
 	c.Lock()
 	defer c.Unlock()
 	c.Viper.Set(key, value)
+	c.sources[strings.ToLower(key)] = source
+}
+
+// SetSource records the source of the current value of key, without changing it
+func (c *safeConfig) SetSource(key string, source Source) {
+	c.Lock()
+	defer c.Unlock()
+	c.sources[strings.ToLower(key)] = source
+}
+
+// GetSource returns the source the current value of key comes from
+func (c *safeConfig) GetSource(key string) Source {
+	c.RLock()
+	defer c.RUnlock()
+
+	key = strings.ToLower(key)
+	// values set at runtime take precedence over env vars, which take
+	// precedence over files, which take precedence over defaults
+	for k := key; k != ""; k = parentKey(k) {
+		if source, found := c.sources[k]; found {
+			return source
+		}
+	}
+	for _, envVar := range c.envBindings[key] {
+		// viper ignores empty env vars
+		if value, found := os.LookupEnv(envVar); found && value != "" {
+			return SourceEnvVar
+		}
+	}
+	for k := key; k != ""; k = parentKey(k) {
+		if _, found := c.fileKeys[k]; found {
+			return SourceFile
+		}
+	}
+	if c.Viper.IsSet(key) {
+		return SourceDefault
+	}
+	return SourceUnset
+}
+
+// trackFileKeys records the keys defined in the given configuration file content
+func (c *safeConfig) trackFileKeys(content []byte) {
+	var fileConfig map[string]interface{}
+	if err := yaml.Unmarshal(content, &fileConfig); err != nil {
+		log.Debugf("Unable to track the keys of the configuration file: %v", err)
+		return
+	}
+	flattenKeys("", fileConfig, c.fileKeys)
+}
+
+// flattenKeys adds the dotted path of every leaf of value to keys
+func flattenKeys(prefix string, value interface{}, keys map[string]struct{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, sub := range v {
+			flattenKeys(joinKey(prefix, k), sub, keys)
+		}
+	case map[interface{}]interface{}:
+		for k, sub := range v {
+			flattenKeys(joinKey(prefix, fmt.Sprint(k)), sub, keys)
+		}
+	default:
+		if prefix != "" {
+			keys[prefix] = struct{}{}
+		}
+	}
+}
+
+func joinKey(prefix string, key string) string {
+	if prefix == "" {
+		return strings.ToLower(key)
+	}
+	return prefix + "." + strings.ToLower(key)
+}
+
+func parentKey(key string) string {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[:i]
+	}
+	return ""
 }
 
 // SetDefault wraps Viper for concurrent access
@@ -79,6 +178,7 @@ func (c *safeConfig) SetFs(fs afero.Fs) {
 	c.Lock()
 	defer c.Unlock()
 	c.Viper.SetFs(fs)
+	c.fs = fs
 }
 
 // IsSet wraps Viper for concurrent access
@@ -298,6 +398,7 @@ func (c *safeConfig) BindEnv(input ...string) {
 			key = c.envKeyReplacer.Replace(key)
 		}
 		c.configEnvVars[key] = struct{}{}
+		c.envBindings[strings.ToLower(input[0])] = append(c.envBindings[strings.ToLower(input[0])], key)
 	}
 
 	_ = c.Viper.BindEnv(input...)
@@ -336,21 +437,48 @@ func (c *safeConfig) UnmarshalExact(rawVal interface{}) error {
 func (c *safeConfig) ReadInConfig() error {
 	c.Lock()
 	defer c.Unlock()
-	return c.Viper.ReadInConfig()
+	if err := c.Viper.ReadInConfig(); err != nil {
+		return err
+	}
+	c.fileKeys = map[string]struct{}{}
+	content, err := afero.ReadFile(c.fs, c.Viper.ConfigFileUsed())
+	if err != nil {
+		log.Debugf("Unable to track the keys of the configuration file: %v", err)
+		return nil
+	}
+	c.trackFileKeys(content)
+	return nil
 }
 
 // ReadConfig wraps Viper for concurrent access
 func (c *safeConfig) ReadConfig(in io.Reader) error {
 	c.Lock()
 	defer c.Unlock()
-	return c.Viper.ReadConfig(in)
+	content, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	if err := c.Viper.ReadConfig(bytes.NewReader(content)); err != nil {
+		return err
+	}
+	c.fileKeys = map[string]struct{}{}
+	c.trackFileKeys(content)
+	return nil
 }
 
 // MergeConfig wraps Viper for concurrent access
 func (c *safeConfig) MergeConfig(in io.Reader) error {
 	c.Lock()
 	defer c.Unlock()
-	return c.Viper.MergeConfig(in)
+	content, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	if err := c.Viper.MergeConfig(bytes.NewReader(content)); err != nil {
+		return err
+	}
+	c.trackFileKeys(content)
+	return nil
 }
 
 // MergeConfigOverride wraps Viper for concurrent access
@@ -432,6 +560,10 @@ func NewConfig(name string, envPrefix string, envKeyReplacer *strings.Replacer)
 	config := safeConfig{
 		Viper:         viper.New(),
 		configEnvVars: map[string]struct{}{},
+		envBindings:   map[string][]string{},
+		fileKeys:      map[string]struct{}{},
+		sources:       map[string]Source{},
+		fs:            afero.NewOsFs(),
 	}
 	config.SetConfigName(name)
 	config.SetEnvPrefix(envPrefix)
@@ -161,3 +161,61 @@ float_list:
 	assert.Nil(t, err)
 	assert.Equal(t, []float64{1.1, 2.2, 3.3}, list)
 }
+
+func TestGetSource(t *testing.T) {
+	config := NewConfig("test", "DD", strings.NewReplacer(".", "_"))
+	config.SetConfigType("yaml")
+
+	config.BindEnvAndSetDefault("default_key", "default")
+	config.BindEnvAndSetDefault("file_key", "default")
+	config.BindEnvAndSetDefault("nested.file_key", "default")
+	config.BindEnvAndSetDefault("env_key", "default")
+	config.BindEnv("other_env_key", "DD_OTHER_ENV")
+	config.BindEnvAndSetDefault("runtime_key", "default")
+	config.BindEnvAndSetDefault("cli_key", "default")
+
+	yamlExample := []byte(`
+file_key: file
+nested:
+  file_key: file
+env_key: file
+runtime_key: file
+`)
+	assert.NoError(t, config.ReadConfig(bytes.NewBuffer(yamlExample)))
+
+	os.Setenv("DD_ENV_KEY", "env")
+	defer os.Unsetenv("DD_ENV_KEY")
+	os.Setenv("DD_OTHER_ENV", "env")
+	defer os.Unsetenv("DD_OTHER_ENV")
+	// empty env vars are ignored
+	os.Setenv("DD_DEFAULT_KEY", "")
+	defer os.Unsetenv("DD_DEFAULT_KEY")
+
+	config.Set("runtime_key", "runtime")
+	config.SetWithSource("cli_key", "cli", SourceCLI)
+
+	assert.Equal(t, SourceDefault, config.GetSource("default_key"))
+	assert.Equal(t, SourceFile, config.GetSource("file_key"))
+	assert.Equal(t, SourceFile, config.GetSource("nested.file_key"))
+	assert.Equal(t, SourceEnvVar, config.GetSource("env_key"))
+	assert.Equal(t, SourceEnvVar, config.GetSource("other_env_key"))
+	assert.Equal(t, SourceAgentRuntime, config.GetSource("runtime_key"))
+	assert.Equal(t, SourceCLI, config.GetSource("cli_key"))
+	assert.Equal(t, "cli", config.GetString("cli_key"))
+	assert.Equal(t, SourceUnset, config.GetSource("missing_key"))
+
+	// reading another file forgets the keys of the previous one
+	assert.NoError(t, config.ReadConfig(bytes.NewBuffer([]byte("default_key: file\n"))))
+	assert.Equal(t, SourceFile, config.GetSource("default_key"))
+	assert.Equal(t, SourceDefault, config.GetSource("file_key"))
+
+	assert.NoError(t, config.MergeConfig(bytes.NewBuffer([]byte("file_key: file\n"))))
+	assert.Equal(t, SourceFile, config.GetSource("default_key"))
+	assert.Equal(t, SourceFile, config.GetSource("file_key"))
+
+	// the source of a value changed without Set can be recorded
+	assert.NoError(t, config.MergeConfigOverride(bytes.NewBuffer([]byte("file_key: secret\n"))))
+	config.SetSource("file_key", SourceSecret)
+	assert.Equal(t, SourceSecret, config.GetSource("file_key"))
+	assert.Equal(t, "secret", config.GetString("file_key"))
+}
@@ -0,0 +1,367 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package connectivity
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/forwarder/endpoints"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/version"
+
+	"golang.org/x/net/proxy"
+)
+
+// Steps of the connection to an endpoint
+const (
+	StepDNS    = "dns_resolution"
+	StepTCP    = "tcp_connect"
+	StepProxy  = "proxy_connect"
+	StepTLS    = "tls_handshake"
+	StepAPIKey = "api_key_validation"
+)
+
+const (
+	defaultTimeout   = 5 * time.Second
+	apiKeyHTTPHeader = "DD-API-KEY"
+)
+
+// Endpoint is an intake the agent sends data to
+type Endpoint struct {
+	// Name describes what the agent sends to the endpoint
+	Name string
+	Host string
+	Port string
+	// UseTLS is whether the agent talks to the endpoint over TLS
+	UseTLS bool
+	// Proxy is the HTTP proxy the agent goes through to reach the endpoint, if any
+	Proxy *url.URL
+	// SOCKS5Proxy is the address of the SOCKS5 proxy the agent goes through to
+	// reach the endpoint, if any
+	SOCKS5Proxy string
+	// APIKey, when set, is validated against APIKeyDomain
+	APIKey       string
+	APIKeyDomain string
+}
+
+// Options tune how the endpoints are diagnosed
+type Options struct {
+	// Timeout of every step of the diagnosis
+	Timeout time.Duration
+	// InsecureSkipVerify disables the verification of the certificates
+	InsecureSkipVerify bool
+}
+
+// DefaultOptions returns the options matching the configuration of the agent
+func DefaultOptions() Options {
+	return Options{
+		Timeout:            defaultTimeout,
+		InsecureSkipVerify: config.Datadog.GetBool("skip_ssl_validation"),
+	}
+}
+
+// Certificate describes the certificate presented by an endpoint
+type Certificate struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	DNSNames  []string  `json:"dns_names,omitempty"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+}
+
+// StepResult is the outcome of a step of the connection to an endpoint
+type StepResult struct {
+	Name     string `json:"name"`
+	Success  bool   `json:"success"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+	// Addresses are the IPs the host resolved to
+	Addresses []string `json:"addresses,omitempty"`
+	// RemoteAddress is the address the TCP connection was established with
+	RemoteAddress string       `json:"remote_address,omitempty"`
+	TLSVersion    string       `json:"tls_version,omitempty"`
+	Certificate   *Certificate `json:"certificate,omitempty"`
+	StatusCode    int          `json:"status_code,omitempty"`
+}
+
+// Result is the outcome of the diagnosis of an endpoint
+type Result struct {
+	Name     string       `json:"name"`
+	Endpoint string       `json:"endpoint"`
+	Proxy    string       `json:"proxy,omitempty"`
+	APIKey   string       `json:"api_key,omitempty"`
+	Success  bool         `json:"success"`
+	Steps    []StepResult `json:"steps"`
+}
+
+// Diagnose goes through every step of the connection of the agent to the endpoint,
+// stopping at the first one which fails
+func Diagnose(ctx context.Context, e Endpoint, opts Options) Result {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+
+	target := net.JoinHostPort(e.Host, e.Port)
+	result := Result{
+		Name:     e.Name,
+		Endpoint: target,
+		Success:  true,
+	}
+	if e.APIKey != "" {
+		result.APIKey = maskAPIKey(e.APIKey)
+	}
+
+	// the host the agent connects to, which is the proxy when there is one
+	host, port := e.Host, e.Port
+	if e.Proxy != nil {
+		result.Proxy = safeURL(e.Proxy)
+		host, port = e.Proxy.Hostname(), e.Proxy.Port()
+		if port == "" {
+			port = defaultPort(e.Proxy.Scheme)
+		}
+	} else if e.SOCKS5Proxy != "" {
+		result.Proxy = "socks5://" + e.SOCKS5Proxy
+		var err error
+		if host, port, err = net.SplitHostPort(e.SOCKS5Proxy); err != nil {
+			host, port = e.SOCKS5Proxy, "1080"
+		}
+	}
+
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	run := func(name string, step func(*StepResult) error) {
+		if !result.Success {
+			return
+		}
+		s := StepResult{Name: name}
+		start := time.Now()
+		err := step(&s)
+		s.Duration = time.Since(start).Round(time.Millisecond).String()
+		s.Success = err == nil
+		if err != nil {
+			s.Error = err.Error()
+			result.Success = false
+		}
+		result.Steps = append(result.Steps, s)
+	}
+
+	run(StepDNS, func(s *StepResult) error {
+		ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+		addresses, err := net.DefaultResolver.LookupHost(ctx, host)
+		s.Addresses = addresses
+		return err
+	})
+
+	run(StepTCP, func(s *StepResult) error {
+		dialer := &net.Dialer{Timeout: opts.Timeout}
+		var err error
+		if conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port)); err != nil {
+			return err
+		}
+		s.RemoteAddress = conn.RemoteAddr().String()
+		return nil
+	})
+
+	if e.Proxy != nil {
+		run(StepProxy, func(s *StepResult) error {
+			conn.SetDeadline(time.Now().Add(opts.Timeout)) //nolint:errcheck
+			var err error
+			conn, s.StatusCode, err = proxyConnect(conn, e.Proxy, target, opts)
+			return err
+		})
+	} else if e.SOCKS5Proxy != "" {
+		run(StepProxy, func(s *StepResult) error {
+			conn.SetDeadline(time.Now().Add(opts.Timeout)) //nolint:errcheck
+			dialer, err := proxy.SOCKS5("tcp", e.SOCKS5Proxy, nil, connDialer{conn})
+			if err != nil {
+				return err
+			}
+			_, err = dialer.Dial("tcp", target)
+			return err
+		})
+	}
+
+	if e.UseTLS {
+		run(StepTLS, func(s *StepResult) error {
+			conn.SetDeadline(time.Now().Add(opts.Timeout)) //nolint:errcheck
+			tlsConn := tls.Client(conn, &tls.Config{
+				ServerName:         e.Host,
+				InsecureSkipVerify: opts.InsecureSkipVerify,
+			})
+			conn = tlsConn
+			err := tlsConn.Handshake()
+			describeTLS(s, tlsConn.ConnectionState())
+			return err
+		})
+	}
+
+	if e.APIKey != "" {
+		run(StepAPIKey, func(s *StepResult) error {
+			var err error
+			s.StatusCode, err = validateAPIKey(ctx, e.APIKey, e.APIKeyDomain, opts.Timeout)
+			return err
+		})
+	}
+
+	return result
+}
+
+// proxyConnect opens a tunnel to target through the HTTP proxy the agent is connected to
+func proxyConnect(conn net.Conn, proxyURL *url.URL, target string, opts Options) (net.Conn, int, error) {
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{
+			ServerName:         proxyURL.Hostname(),
+			InsecureSkipVerify: opts.InsecureSkipVerify,
+		})
+		if err := tlsConn.Handshake(); err != nil {
+			return tlsConn, 0, fmt.Errorf("TLS handshake with the proxy failed: %v", err)
+		}
+		conn = tlsConn
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: target},
+		Host:   target,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		return conn, 0, err
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return conn, 0, err
+	}
+	// on success, the body of the response is the tunnel
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return conn, resp.StatusCode, fmt.Errorf("the proxy refused to connect to %s: %s", target, resp.Status)
+	}
+	return conn, resp.StatusCode, nil
+}
+
+// validateAPIKey checks the API key against the validation endpoint of domain, the
+// same way the forwarder does
+func validateAPIKey(ctx context.Context, apiKey, domain string, timeout time.Duration) (int, error) {
+	client := &http.Client{
+		Transport: httputils.CreateHTTPTransport(),
+		Timeout:   timeout,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, domain+endpoints.V1ValidateEndpoint.Route, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set(apiKeyHTTPHeader, apiKey)
+	req.Header.Set("User-Agent", fmt.Sprintf("datadog-agent/%s", version.AgentVersion))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// the endpoint responds 200 if the key is valid or 403 if invalid
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.StatusCode, nil
+	case http.StatusForbidden:
+		return resp.StatusCode, errors.New("the API key is invalid")
+	default:
+		return resp.StatusCode, fmt.Errorf("unexpected response code from the API key validation endpoint: %v", resp.StatusCode)
+	}
+}
+
+func describeTLS(s *StepResult, state tls.ConnectionState) {
+	s.TLSVersion = tlsVersionName(state.Version)
+	if len(state.PeerCertificates) == 0 {
+		return
+	}
+	cert := state.PeerCertificates[0]
+	s.Certificate = &Certificate{
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		DNSNames:  cert.DNSNames,
+		NotBefore: cert.NotBefore,
+		NotAfter:  cert.NotAfter,
+	}
+}
+
+func tlsVersionName(v uint16) string {
+	switch v {
+	case 0:
+		return ""
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return fmt.Sprintf("0x%04X", v)
+	}
+}
+
+// connDialer hands an already established connection to the SOCKS5 client
+type connDialer struct {
+	conn net.Conn
+}
+
+func (d connDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.conn, nil
+}
+
+func defaultPort(scheme string) string {
+	if scheme == "http" {
+		return "80"
+	}
+	return "443"
+}
+
+// safeURL hides the credentials of u
+func safeURL(u *url.URL) string {
+	userInfo := ""
+	if u.User != nil {
+		if _, isSet := u.User.Password(); isSet {
+			userInfo = "*****:*****@"
+		} else {
+			userInfo = "*****@"
+		}
+	}
+	return u.Scheme + "://" + userInfo + u.Host
+}
+
+// maskAPIKey only keeps the last five characters of an API key
+func maskAPIKey(apiKey string) string {
+	if len(apiKey) <= 5 {
+		return "*****"
+	}
+	return fmt.Sprintf("***************************%s", apiKey[len(apiKey)-5:])
+}
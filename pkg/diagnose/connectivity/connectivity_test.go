@@ -0,0 +1,227 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package connectivity
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOptions = Options{Timeout: 2 * time.Second, InsecureSkipVerify: true}
+
+func endpointOf(t *testing.T, server *httptest.Server) Endpoint {
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return Endpoint{
+		Name:   "test",
+		Host:   u.Hostname(),
+		Port:   u.Port(),
+		UseTLS: u.Scheme == "https",
+	}
+}
+
+func stepNames(result Result) []string {
+	var names []string
+	for _, step := range result.Steps {
+		names = append(names, step.Name)
+	}
+	return names
+}
+
+// connectProxy is an HTTP proxy only accepting CONNECT requests authenticated as user:pass
+func connectProxy(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Proxy-Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte("user:pass")) {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		go func() {
+			io.Copy(upstream, conn) //nolint:errcheck
+			upstream.Close()
+		}()
+		go func() {
+			io.Copy(conn, upstream) //nolint:errcheck
+			conn.Close()
+		}()
+	}))
+}
+
+func TestDiagnoseTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	result := Diagnose(context.Background(), endpointOf(t, server), testOptions)
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{StepDNS, StepTCP, StepTLS}, stepNames(result))
+	assert.Equal(t, []string{"127.0.0.1"}, result.Steps[0].Addresses)
+	assert.Equal(t, server.Listener.Addr().String(), result.Steps[1].RemoteAddress)
+	require.NotNil(t, result.Steps[2].Certificate)
+	assert.Contains(t, result.Steps[2].Certificate.Subject, "Acme Co")
+	assert.NotEmpty(t, result.Steps[2].TLSVersion)
+
+	// the certificate of the test server isn't trusted
+	result = Diagnose(context.Background(), endpointOf(t, server), Options{Timeout: 2 * time.Second})
+	assert.False(t, result.Success)
+	require.Len(t, result.Steps, 3)
+	assert.False(t, result.Steps[2].Success)
+	assert.Contains(t, result.Steps[2].Error, "certificate")
+}
+
+func TestDiagnoseStopsAtFirstFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	result := Diagnose(context.Background(), Endpoint{Host: "127.0.0.1", Port: port, UseTLS: true, APIKey: "0123456789"}, testOptions)
+	assert.False(t, result.Success)
+	assert.Equal(t, []string{StepDNS, StepTCP}, stepNames(result))
+	assert.True(t, result.Steps[0].Success)
+	assert.False(t, result.Steps[1].Success)
+	assert.NotEmpty(t, result.Steps[1].Error)
+}
+
+func TestDiagnoseProxy(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	proxy := connectProxy(t)
+	defer proxy.Close()
+
+	e := endpointOf(t, server)
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+	proxyURL.User = url.UserPassword("user", "pass")
+	e.Proxy = proxyURL
+
+	result := Diagnose(context.Background(), e, testOptions)
+	assert.True(t, result.Success, "%+v", result)
+	assert.Equal(t, []string{StepDNS, StepTCP, StepProxy, StepTLS}, stepNames(result))
+	assert.Equal(t, proxy.Listener.Addr().String(), result.Steps[1].RemoteAddress)
+	assert.Equal(t, http.StatusOK, result.Steps[2].StatusCode)
+	assert.Equal(t, "http://*****:*****@"+proxyURL.Host, result.Proxy)
+
+	proxyURL.User = url.UserPassword("user", "wrong")
+	result = Diagnose(context.Background(), e, testOptions)
+	assert.False(t, result.Success)
+	assert.Equal(t, []string{StepDNS, StepTCP, StepProxy}, stepNames(result))
+	assert.Equal(t, http.StatusProxyAuthRequired, result.Steps[2].StatusCode)
+}
+
+func TestDiagnoseAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/validate" {
+			w.WriteHeader(http.StatusNotFound)
+		} else if r.Header.Get("DD-API-KEY") != "abcdefghijklmnop" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	e := endpointOf(t, server)
+	e.APIKey = "abcdefghijklmnop"
+	e.APIKeyDomain = server.URL
+
+	result := Diagnose(context.Background(), e, testOptions)
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{StepDNS, StepTCP, StepAPIKey}, stepNames(result))
+	assert.Equal(t, http.StatusOK, result.Steps[2].StatusCode)
+	assert.Equal(t, "***************************lmnop", result.APIKey)
+
+	e.APIKey = "invalid"
+	result = Diagnose(context.Background(), e, testOptions)
+	assert.False(t, result.Success)
+	assert.Equal(t, http.StatusForbidden, result.Steps[2].StatusCode)
+	assert.Equal(t, "the API key is invalid", result.Steps[2].Error)
+
+	var b bytes.Buffer
+	result.WriteText(&b)
+	assert.Contains(t, b.String(), "api_key_validation")
+	assert.Contains(t, b.String(), "error: the API key is invalid")
+	assert.NotContains(t, b.String(), "invalid)")
+}
+
+func TestHTTPEndpoint(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy:3128")
+	proxyFunc := func(r *http.Request) (*url.URL, error) {
+		if r.URL.Hostname() == "trace.agent.datadoghq.com" {
+			return proxyURL, nil
+		}
+		return nil, nil
+	}
+
+	e, err := httpEndpoint("APM", "https://trace.agent.datadoghq.com", proxyFunc)
+	require.NoError(t, err)
+	assert.Equal(t, Endpoint{Name: "APM", Host: "trace.agent.datadoghq.com", Port: "443", UseTLS: true, Proxy: proxyURL}, e)
+
+	e, err = httpEndpoint("Logs (HTTP)", "http://localhost:8080", proxyFunc)
+	require.NoError(t, err)
+	assert.Equal(t, Endpoint{Name: "Logs (HTTP)", Host: "localhost", Port: "8080"}, e)
+}
+
+func TestGetEndpoints(t *testing.T) {
+	mockConfig := config.Mock()
+	mockConfig.Set("api_key", "abcdefghijklmnop")
+	mockConfig.Set("site", "datadoghq.eu")
+	mockConfig.Set("logs_enabled", true)
+	mockConfig.Set("apm_config.enabled", true)
+	mockConfig.Set("orchestrator_explorer.enabled", false)
+
+	endpoints, err := GetEndpoints()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Forwarder", "Logs (HTTP)", "APM", "Process"}, endpointNames(endpoints))
+
+	assert.Equal(t, "abcdefghijklmnop", endpoints[0].APIKey)
+	assert.Equal(t, "https://api.datadoghq.eu", endpoints[0].APIKeyDomain)
+	assert.True(t, strings.HasSuffix(endpoints[0].Host, "-app.agent.datadoghq.eu"), endpoints[0].Host)
+	assert.Equal(t, "443", endpoints[0].Port)
+	assert.Equal(t, "agent-http-intake.logs.datadoghq.eu", endpoints[1].Host)
+	assert.Equal(t, "trace.agent.datadoghq.eu", endpoints[2].Host)
+	assert.Equal(t, "process.datadoghq.eu", endpoints[3].Host)
+
+	// only the transport used by the logs agent is diagnosed
+	mockConfig.Set("logs_config.use_tcp", true)
+	mockConfig.Set("process_config.process_collection.enabled", false)
+	mockConfig.Set("process_config.container_collection.enabled", false)
+	mockConfig.Set("process_config.process_discovery.enabled", false)
+	endpoints, err = GetEndpoints()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Forwarder", "Logs (TCP)", "APM"}, endpointNames(endpoints))
+	assert.Equal(t, "agent-intake.logs.datadoghq.eu", endpoints[1].Host)
+}
+
+func endpointNames(endpoints []Endpoint) []string {
+	var names []string
+	for _, e := range endpoints {
+		names = append(names, e.Name)
+	}
+	return names
+}
@@ -0,0 +1,197 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package connectivity
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/forwarder"
+	logsconfig "github.com/DataDog/datadog-agent/pkg/logs/config"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+)
+
+// ProxySettings are the proxy settings of the agent, with the credentials hidden
+type ProxySettings struct {
+	HTTP                 string   `json:"http,omitempty"`
+	HTTPS                string   `json:"https,omitempty"`
+	NoProxy              []string `json:"no_proxy,omitempty"`
+	NoProxyNonexactMatch bool     `json:"no_proxy_nonexact_match"`
+	LogsSOCKS5           string   `json:"logs_socks5,omitempty"`
+}
+
+// GetProxySettings returns the proxy settings of the agent
+func GetProxySettings() ProxySettings {
+	settings := ProxySettings{
+		NoProxyNonexactMatch: config.Datadog.GetBool("no_proxy_nonexact_match"),
+		LogsSOCKS5:           config.Datadog.GetString("logs_config.socks5_proxy_address"),
+	}
+	if p := config.GetProxies(); p != nil {
+		settings.HTTP = safeRawURL(p.HTTP)
+		settings.HTTPS = safeRawURL(p.HTTPS)
+		settings.NoProxy = p.NoProxy
+	}
+	return settings
+}
+
+// GetEndpoints returns the intakes the agent is configured to send data to: every
+// forwarder domain along with each of its API keys, the logs endpoints of the transport
+// in use, and the APM, process and orchestrator intakes of the enabled products
+func GetEndpoints() ([]Endpoint, error) {
+	proxyFunc := func(*http.Request) (*url.URL, error) { return nil, nil }
+	if p := config.GetProxies(); p != nil {
+		proxyFunc = httputils.GetProxyTransportFunc(p)
+	}
+	var result []Endpoint
+
+	keysPerDomain, err := config.GetMultipleEndpoints()
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve the forwarder endpoints: %v", err)
+	}
+	domains := make([]string, 0, len(keysPerDomain))
+	for domain := range keysPerDomain {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	for _, domain := range domains {
+		versionedDomain, err := config.AddAgentVersionToDomain(domain, "app")
+		if err != nil {
+			return nil, err
+		}
+		for _, apiKey := range keysPerDomain[domain] {
+			e, err := httpEndpoint("Forwarder", versionedDomain, proxyFunc)
+			if err != nil {
+				return nil, err
+			}
+			e.APIKey = apiKey
+			e.APIKeyDomain = forwarder.APIKeyValidationDomain(versionedDomain)
+			result = append(result, e)
+		}
+	}
+
+	if config.Datadog.GetBool("logs_enabled") || config.Datadog.GetBool("log_enabled") {
+		logsEndpoints, err := getLogsEndpoints(proxyFunc)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, logsEndpoints...)
+	}
+
+	// the process agent runs as long as one of its checks is enabled
+	processEnabled := config.Datadog.GetBool("process_config.process_collection.enabled") ||
+		config.Datadog.GetBool("process_config.container_collection.enabled") ||
+		config.Datadog.GetBool("process_config.process_discovery.enabled")
+
+	intakes := []struct {
+		name    string
+		enabled bool
+		url     string
+	}{
+		{
+			name:    "APM",
+			enabled: config.Datadog.GetBool("apm_config.enabled"),
+			url:     config.GetMainEndpoint("https://trace.agent.", "apm_config.apm_dd_url"),
+		},
+		{
+			name:    "Process",
+			enabled: processEnabled,
+			url:     config.GetMainEndpoint("https://process.", "process_config.process_dd_url"),
+		},
+		{
+			name:    "Orchestrator",
+			enabled: config.Datadog.GetBool("orchestrator_explorer.enabled"),
+			url:     config.GetMainEndpointWithConfigBackwardCompatible(config.Datadog, "https://orchestrator.", "orchestrator_explorer.orchestrator_dd_url", "process_config.orchestrator_dd_url"),
+		},
+	}
+	for _, intake := range intakes {
+		if !intake.enabled {
+			continue
+		}
+		e, err := httpEndpoint(intake.name, intake.url, proxyFunc)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+
+	return result, nil
+}
+
+// getLogsEndpoints returns the endpoints of the transport the logs agent uses, HTTP or TCP, chosen the same
+// way as the logs agent does when the HTTP intake is reachable: the diagnosis tells whether it actually is.
+func getLogsEndpoints(proxyFunc func(*http.Request) (*url.URL, error)) ([]Endpoint, error) {
+	logsEndpoints, err := logsconfig.BuildEndpoints(logsconfig.HTTPConnectivitySuccess, "logs", logsconfig.DefaultIntakeProtocol, logsconfig.DefaultIntakeOrigin)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve the logs endpoints: %v", err)
+	}
+
+	var result []Endpoint
+	for _, le := range logsEndpoints.Endpoints {
+		if !logsEndpoints.UseHTTP {
+			result = append(result, Endpoint{
+				Name:        "Logs (TCP)",
+				Host:        le.Host,
+				Port:        strconv.Itoa(le.Port),
+				UseTLS:      le.UseSSL,
+				SOCKS5Proxy: le.ProxyAddress,
+			})
+			continue
+		}
+		scheme := "https"
+		if !le.UseSSL {
+			scheme = "http"
+		}
+		rawURL := scheme + "://" + le.Host
+		if le.Port != 0 {
+			rawURL += ":" + strconv.Itoa(le.Port)
+		}
+		e, err := httpEndpoint("Logs (HTTP)", rawURL, proxyFunc)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+// httpEndpoint returns the endpoint of an HTTP intake, along with the proxy the agent
+// uses to reach it
+func httpEndpoint(name, rawURL string, proxyFunc func(*http.Request) (*url.URL, error)) (Endpoint, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Endpoint{}, fmt.Errorf("could not parse the %s endpoint %q: %v", name, rawURL, err)
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPort(u.Scheme)
+	}
+	proxyURL, err := proxyFunc(&http.Request{URL: u})
+	if err != nil {
+		return Endpoint{}, err
+	}
+	return Endpoint{
+		Name:   name,
+		Host:   u.Hostname(),
+		Port:   port,
+		UseTLS: u.Scheme != "http",
+		Proxy:  proxyURL,
+	}, nil
+}
+
+func safeRawURL(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid URL>"
+	}
+	return safeURL(u)
+}
@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package connectivity
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Report is the outcome of the diagnosis of every endpoint of the agent
+type Report struct {
+	Proxy   ProxySettings `json:"proxy"`
+	Results []Result      `json:"results"`
+}
+
+// Run diagnoses every endpoint the agent is configured to send data to
+func Run(ctx context.Context, opts Options) (Report, error) {
+	endpoints, err := GetEndpoints()
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{Proxy: GetProxySettings()}
+	for _, e := range endpoints {
+		report.Results = append(report.Results, Diagnose(ctx, e, opts))
+	}
+	return report, nil
+}
+
+// Failures returns the number of endpoints which could not be reached
+func (r Report) Failures() int {
+	failures := 0
+	for _, result := range r.Results {
+		if !result.Success {
+			failures++
+		}
+	}
+	return failures
+}
+
+// WriteText writes the report in a human readable format
+func (r Report) WriteText(w io.Writer) {
+	fmt.Fprintln(w, "Proxy settings:")
+	fmt.Fprintf(w, "  http: %s\n", valueOrNone(r.Proxy.HTTP))
+	fmt.Fprintf(w, "  https: %s\n", valueOrNone(r.Proxy.HTTPS))
+	fmt.Fprintf(w, "  no_proxy: %s\n", valueOrNone(strings.Join(r.Proxy.NoProxy, ", ")))
+	fmt.Fprintf(w, "  no_proxy_nonexact_match: %v\n", r.Proxy.NoProxyNonexactMatch)
+	fmt.Fprintf(w, "  logs socks5: %s\n", valueOrNone(r.Proxy.LogsSOCKS5))
+	fmt.Fprintln(w)
+
+	for _, result := range r.Results {
+		result.WriteText(w)
+		fmt.Fprintln(w)
+	}
+}
+
+// WriteText writes the result in a human readable format
+func (r Result) WriteText(w io.Writer) {
+	title := fmt.Sprintf("%s - %s", r.Name, r.Endpoint)
+	if r.Proxy != "" {
+		title += " through " + r.Proxy
+	}
+	if r.APIKey != "" {
+		title += fmt.Sprintf(" (API key %s)", r.APIKey)
+	}
+	fmt.Fprintln(w, color.BlueString(title))
+
+	for _, step := range r.Steps {
+		status := color.GreenString("PASS")
+		if !step.Success {
+			status = color.RedString("FAIL")
+		}
+		fmt.Fprintf(w, "  [%s] %s (%s)", status, step.Name, step.Duration)
+		if details := step.details(); details != "" {
+			fmt.Fprintf(w, ": %s", details)
+		}
+		fmt.Fprintln(w)
+		if step.Error != "" {
+			fmt.Fprintf(w, "      error: %s\n", step.Error)
+		}
+	}
+}
+
+func (s StepResult) details() string {
+	var details []string
+	if len(s.Addresses) > 0 {
+		details = append(details, strings.Join(s.Addresses, ", "))
+	}
+	if s.RemoteAddress != "" {
+		details = append(details, "connected to "+s.RemoteAddress)
+	}
+	if s.TLSVersion != "" {
+		details = append(details, s.TLSVersion)
+	}
+	if c := s.Certificate; c != nil {
+		details = append(details, fmt.Sprintf("subject %q, issuer %q, valid until %s", c.Subject, c.Issuer, c.NotAfter.Format(time.RFC3339)))
+		if len(c.DNSNames) > 0 {
+			details = append(details, "DNS names "+strings.Join(c.DNSNames, ", "))
+		}
+	}
+	if s.StatusCode != 0 {
+		details = append(details, fmt.Sprintf("status code %d", s.StatusCode))
+	}
+	return strings.Join(details, "; ")
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
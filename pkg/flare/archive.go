@@ -12,6 +12,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"expvar"
 	"fmt"
 	"io"
@@ -20,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strings"
 	"time"
@@ -28,6 +30,7 @@ import (
 	apiutil "github.com/DataDog/datadog-agent/pkg/api/util"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/diagnose"
+	"github.com/DataDog/datadog-agent/pkg/flare/provider"
 	"github.com/DataDog/datadog-agent/pkg/secrets"
 	"github.com/DataDog/datadog-agent/pkg/status"
 	"github.com/DataDog/datadog-agent/pkg/status/health"
@@ -71,6 +74,13 @@ type SearchPaths map[string]string
 // The key is the filepath of the file.
 type permissionsInfos map[string]filePermsInfo
 
+// merge adds the files of other to the map
+func (p permissionsInfos) merge(other permissionsInfos) {
+	for filePath, info := range other {
+		p[filePath] = info
+	}
+}
+
 type filePermsInfo struct {
 	mode  os.FileMode
 	owner string
@@ -146,18 +156,105 @@ func CreatePerformanceProfile(prefix, debugURL string, cpusec int, target *Profi
 	return nil
 }
 
-// CreateArchive packages up the files
-func CreateArchive(local bool, distPath, pyChecksPath string, logFilePaths []string, pdata ProfileData, ipcError error) (string, error) {
+// CreateArchive packages up the files. If only isn't empty, the flare is restricted to the
+// providers it names (see ProviderNames).
+func CreateArchive(local bool, distPath, pyChecksPath string, logFilePaths []string, pdata ProfileData, ipcError error, only []string) (string, error) {
 	zipFilePath := getArchivePath()
 	confSearchPaths := SearchPaths{
 		"":        config.Datadog.GetString("confd_path"),
 		"dist":    filepath.Join(distPath, "conf.d"),
 		"checksd": pyChecksPath,
 	}
-	return createArchive(confSearchPaths, local, zipFilePath, logFilePaths, pdata, ipcError)
+	return createArchive(confSearchPaths, local, zipFilePath, logFilePaths, pdata, ipcError, only)
+}
+
+// builtinProvider is a part of the flare collected by the flare package itself
+type builtinProvider struct {
+	name    string
+	enabled bool
+	// timeout and maxSize default to the ones of registered providers when zero
+	timeout time.Duration
+	maxSize int
+	zip     func(tempDir, hostname string) error
+	// collect is used instead of zip by the providers which walk directories: they stop
+	// once ctx is done, and add the files they copy to permsInfos
+	collect func(ctx context.Context, tempDir, hostname string, permsInfos permissionsInfos) error
+}
+
+// builtinProviders returns the parts of the flare collected by the flare package, in the
+// order they are collected
+func builtinProviders(confSearchPaths SearchPaths, local bool, logFilePaths []string, pdata ProfileData) []builtinProvider {
+	windows := runtime.GOOS == "windows"
+	return []builtinProvider{
+		// Status informations are only available when the agent is running
+		{name: "status", enabled: !local, zip: zipStatusFile},
+		{name: "config-check", enabled: !local, zip: zipConfigCheck},
+		{name: "tagger-list", enabled: !local, zip: zipTaggerList},
+		{name: "workload-list", enabled: !local, zip: zipWorkloadList},
+		{name: "config-files", enabled: true, maxSize: 50 * 1024 * 1024, collect: func(ctx context.Context, tempDir, hostname string, permsInfos permissionsInfos) error {
+			return zipConfigFiles(ctx, tempDir, hostname, confSearchPaths, permsInfos)
+		}},
+		{name: "expvar", enabled: true, zip: zipExpVar},
+		{name: "system-probe-stats", enabled: config.Datadog.GetBool("system_probe_config.enabled"), zip: zipSystemProbeStats},
+		{name: "diagnose", enabled: true, timeout: 30 * time.Second, zip: zipDiagnose},
+		{name: "version-history", enabled: true, zip: zipVersionHistory},
+		{name: "secrets", enabled: true, zip: zipSecrets},
+		{name: "envvars", enabled: true, zip: zipEnvvars},
+		{name: "inventories", enabled: true, zip: zipMetadataInventories},
+		{name: "software-inventory", enabled: true, zip: zipMetadataSoftwareInventory},
+		{name: "metadata-v5", enabled: true, zip: zipMetadataV5},
+		{name: "health", enabled: true, zip: zipHealth},
+		{name: "telemetry", enabled: config.Datadog.GetBool("telemetry.enabled"), zip: zipTelemetry},
+		{name: "stack-traces", enabled: true, zip: zipStackTraces},
+		{name: "docker-inspect", enabled: config.IsContainerized(), zip: zipDockerSelfInspect},
+		{name: "docker-ps", enabled: true, zip: zipDockerPs},
+		{name: "typeperf", enabled: windows, timeout: 30 * time.Second, zip: zipTypeperfData},
+		{name: "lodctr", enabled: windows, timeout: 30 * time.Second, zip: zipLodctrOutput},
+		{name: "counter-strings", enabled: windows, timeout: 30 * time.Second, zip: zipCounterStrings},
+		{name: "windows-event-logs", enabled: windows, timeout: time.Minute, maxSize: 100 * 1024 * 1024, zip: zipWindowsEventLogs},
+		{name: "windows-service-status", enabled: windows, zip: zipServiceStatus},
+		// every log file of the agents, including the rotated ones
+		{name: "logs", enabled: true, timeout: 2 * time.Minute, maxSize: 512 * 1024 * 1024, collect: func(ctx context.Context, tempDir, hostname string, permsInfos permissionsInfos) error {
+			// force a log flush before zipping them
+			log.Flush()
+			var errs []string
+			for _, logFilePath := range logFilePaths {
+				if err := zipLogFiles(ctx, tempDir, hostname, logFilePath, permsInfos); err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+			if len(errs) > 0 {
+				return errors.New(strings.Join(errs, ", "))
+			}
+			return nil
+		}},
+		{name: "install-info", enabled: true, zip: zipInstallInfo},
+		{name: "profiles", enabled: pdata != nil, maxSize: 100 * 1024 * 1024, zip: func(tempDir, hostname string) error {
+			return zipPerformanceProfile(tempDir, hostname, pdata)
+		}},
+	}
+}
+
+// ProviderNames returns the names of every part of the flare which can be selected
+// with the only parameter of CreateArchive
+func ProviderNames() []string {
+	var names []string
+	for _, p := range builtinProviders(nil, false, nil, nil) {
+		names = append(names, p.name)
+	}
+	for name := range provider.DefaultCatalog {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
-func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string, logFilePaths []string, pdata ProfileData, ipcError error) (string, error) {
+func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string, logFilePaths []string, pdata ProfileData, ipcError error, only []string) (string, error) {
+	selected, err := selectProviders(only)
+	if err != nil {
+		return "", err
+	}
+
 	tempDir, err := createTempDir()
 	if err != nil {
 		return "", err
@@ -203,27 +300,6 @@ func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string,
 				return "", err
 			}
 		}
-	} else {
-		// Status informations are available, zip them up as the agent is running.
-		err = zipStatusFile(tempDir, hostname)
-		if err != nil {
-			log.Errorf("Could not zip status: %s", err)
-		}
-
-		err = zipConfigCheck(tempDir, hostname)
-		if err != nil {
-			log.Errorf("Could not zip config check: %s", err)
-		}
-
-		err = zipTaggerList(tempDir, hostname)
-		if err != nil {
-			log.Errorf("Could not zip tagger list: %s", err)
-		}
-
-		err = zipWorkloadList(tempDir, hostname)
-		if err != nil {
-			log.Errorf("Could not zip workload list: %s", err)
-		}
 	}
 
 	// auth token permissions info (only if existing)
@@ -231,129 +307,44 @@ func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string,
 		permsInfos.add(security.GetAuthTokenFilepath())
 	}
 
-	err = zipConfigFiles(tempDir, hostname, confSearchPaths, permsInfos)
-	if err != nil {
-		log.Errorf("Could not zip config: %s", err)
-	}
-
-	err = zipExpVar(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not zip exp var: %s", err)
-	}
-
-	if config.Datadog.GetBool("system_probe_config.enabled") {
-		err = zipSystemProbeStats(tempDir, hostname)
-		if err != nil {
-			log.Errorf("Could not zip system probe exp var stats: %s", err)
+	var index providersIndex
+	for _, p := range builtinProviders(confSearchPaths, local, logFilePaths, pdata) {
+		if !p.enabled {
+			index.disable(p.name)
+			continue
 		}
-	}
-
-	err = zipDiagnose(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not zip diagnose: %s", err)
-	}
-
-	err = zipRegistryJSON(tempDir, hostname)
-	if err != nil {
-		log.Warnf("Could not zip registry.json: %s", err)
-	}
-
-	err = zipVersionHistory(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not zip version-history.json: %s", err)
-	}
-
-	err = zipSecrets(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not zip secrets: %s", err)
-	}
-
-	err = zipEnvvars(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not zip env vars: %s", err)
-	}
-
-	err = zipMetadataInventories(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not zip inventories metadata payload: %s", err)
-	}
-
-	err = zipMetadataV5(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not zip v5 metadata payload: %s", err)
-	}
-
-	err = zipHealth(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not zip health check: %s", err)
-	}
-
-	if config.Datadog.GetBool("telemetry.enabled") {
-		err = zipTelemetry(tempDir, hostname)
-		if err != nil {
-			log.Errorf("Could not collect telemetry metrics: %s", err)
+		if !selected(p.name) {
+			index.exclude(p.name)
+			continue
 		}
-	}
-
-	err = zipStackTraces(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not collect go routine stack traces: %s", err)
-	}
-
-	if config.IsContainerized() {
-		err = zipDockerSelfInspect(tempDir, hostname)
+		start := time.Now()
+		err = runBuiltinProvider(tempDir, hostname, p, permsInfos)
 		if err != nil {
-			log.Errorf("Could not zip docker inspect: %s", err)
+			log.Errorf("Could not collect %s for the flare: %s", p.name, err)
 		}
+		index.add(p.name, start, err)
 	}
 
-	err = zipDockerPs(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not zip docker ps: %s", err)
-	}
-
-	err = zipTypeperfData(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not write typeperf data: %s", err)
-	}
-	err = zipLodctrOutput(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not write lodctr data: %s", err)
-	}
-
-	err = zipCounterStrings(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not write counter strings: %s", err)
+	names := make([]string, 0, len(provider.DefaultCatalog))
+	for name := range provider.DefaultCatalog {
+		names = append(names, name)
 	}
-
-	err = zipWindowsEventLogs(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not export Windows event logs: %s", err)
-	}
-	err = zipServiceStatus(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not export Windows driver status: %s", err)
-	}
-
-	// force a log flush before zipping them
-	log.Flush()
-	for _, logFilePath := range logFilePaths {
-		err = zipLogFiles(tempDir, hostname, logFilePath, permsInfos)
+	sort.Strings(names)
+	for _, name := range names {
+		if !selected(name) {
+			index.exclude(name)
+			continue
+		}
+		start := time.Now()
+		err = runProvider(tempDir, hostname, provider.DefaultCatalog[name])
 		if err != nil {
-			log.Errorf("Could not zip logs: %s", err)
+			log.Errorf("Could not collect %s for the flare: %s", name, err)
 		}
+		index.add(name, start, err)
 	}
 
-	err = zipInstallInfo(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not zip install_info: %s", err)
-	}
-
-	if pdata != nil {
-		err = zipPerformanceProfile(tempDir, hostname, pdata)
-		if err != nil {
-			log.Errorf("Could not zip performance profile: %s", err)
-		}
+	if err := index.commit(tempDir, hostname); err != nil {
+		log.Errorf("Could not write the flare providers index: %s", err)
 	}
 
 	// gets files infos and write the permissions.log file
@@ -414,7 +405,7 @@ func addParentPerms(dirPath string, permsInfos permissionsInfos) {
 	}
 }
 
-func zipLogFiles(tempDir, hostname, logFilePath string, permsInfos permissionsInfos) error {
+func zipLogFiles(ctx context.Context, tempDir, hostname, logFilePath string, permsInfos permissionsInfos) error {
 	// Force dir path to be absolute first
 	logFileDir, err := filepath.Abs(filepath.Dir(logFilePath))
 	if err != nil {
@@ -424,6 +415,9 @@ func zipLogFiles(tempDir, hostname, logFilePath string, permsInfos permissionsIn
 	permsInfos.add(logFileDir)
 
 	err = filepath.Walk(logFileDir, func(src string, f os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if f == nil {
 			return nil
 		}
@@ -525,7 +519,7 @@ func zipSystemProbeStats(tempDir, hostname string) error {
 	return writeScrubbedFile(sysProbeFile, sysProbeBuf)
 }
 
-func zipConfigFiles(tempDir, hostname string, confSearchPaths SearchPaths, permsInfos permissionsInfos) error {
+func zipConfigFiles(ctx context.Context, tempDir, hostname string, confSearchPaths SearchPaths, permsInfos permissionsInfos) error {
 	c, err := yaml.Marshal(config.Datadog.AllSettings())
 	if err != nil {
 		return err
@@ -542,7 +536,7 @@ func zipConfigFiles(tempDir, hostname string, confSearchPaths SearchPaths, perms
 		return err
 	}
 
-	err = walkConfigFilePaths(tempDir, hostname, confSearchPaths, permsInfos)
+	err = walkConfigFilePaths(ctx, tempDir, hostname, confSearchPaths, permsInfos)
 	if err != nil {
 		return err
 	}
@@ -644,12 +638,6 @@ func zipFile(originalPath, zippedPath string) error {
 	return err
 }
 
-func zipRegistryJSON(tempDir, hostname string) error {
-	originalPath := filepath.Join(config.Datadog.GetString("logs_config.run_path"), "registry.json")
-	zippedPath := filepath.Join(tempDir, hostname, "registry.json")
-	return zipFile(originalPath, zippedPath)
-}
-
 func zipVersionHistory(tempDir, hostname string) error {
 	originalPath := filepath.Join(config.Datadog.GetString("run_path"), "version-history.json")
 	zippedPath := filepath.Join(tempDir, hostname, "version-history.json")
@@ -834,10 +822,13 @@ func zipPerformanceProfile(tempDir, hostname string, pdata ProfileData) error {
 	return nil
 }
 
-func walkConfigFilePaths(tempDir, hostname string, confSearchPaths SearchPaths, permsInfos permissionsInfos) error {
+func walkConfigFilePaths(ctx context.Context, tempDir, hostname string, confSearchPaths SearchPaths, permsInfos permissionsInfos) error {
 	for prefix, filePath := range confSearchPaths {
 
 		err := filepath.Walk(filePath, func(src string, f os.FileInfo, err error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
 			if f == nil {
 				return nil
 			}
@@ -68,12 +68,12 @@ func createDCAArchive(zipFilePath string, local bool, confSearchPaths SearchPath
 
 	permsInfos := make(permissionsInfos)
 
-	err = zipLogFiles(tempDir, hostname, logFilePath, permsInfos)
+	err = zipLogFiles(context.TODO(), tempDir, hostname, logFilePath, permsInfos)
 	if err != nil {
 		return "", err
 	}
 
-	err = zipConfigFiles(tempDir, hostname, confSearchPaths, permsInfos)
+	err = zipConfigFiles(context.TODO(), tempDir, hostname, confSearchPaths, permsInfos)
 	if err != nil {
 		return "", err
 	}
@@ -54,12 +54,12 @@ func CreateSecurityAgentArchive(local bool, logFilePath string, runtimeStatus, c
 
 	permsInfos := make(permissionsInfos)
 
-	err = zipLogFiles(tempDir, hostname, logFilePath, permsInfos)
+	err = zipLogFiles(context.TODO(), tempDir, hostname, logFilePath, permsInfos)
 	if err != nil {
 		return "", err
 	}
 
-	err = zipConfigFiles(tempDir, hostname, SearchPaths{}, permsInfos)
+	err = zipConfigFiles(context.TODO(), tempDir, hostname, SearchPaths{}, permsInfos)
 	if err != nil {
 		return "", err
 	}
@@ -7,6 +7,7 @@ package flare
 
 import (
 	"archive/zip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -35,7 +36,7 @@ func TestCreateArchive(t *testing.T) {
 	mockConfig.Set("confd_path", "./test/confd")
 	mockConfig.Set("log_file", "./test/logs/agent.log")
 	zipFilePath := getArchivePath()
-	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil)
+	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil, nil)
 
 	assert.Nil(t, err)
 	assert.Equal(t, zipFilePath, filePath)
@@ -59,7 +60,7 @@ func TestCreateArchiveAndGoRoutines(t *testing.T) {
 	pprofURL = ts.URL
 
 	zipFilePath := getArchivePath()
-	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil)
+	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil, nil)
 
 	assert.Nil(t, err)
 	assert.Equal(t, zipFilePath, filePath)
@@ -103,7 +104,7 @@ func TestCreateArchiveAndGoRoutines(t *testing.T) {
 func TestCreateArchiveBadConfig(t *testing.T) {
 	common.SetupConfig("")
 	zipFilePath := getArchivePath()
-	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil)
+	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil, nil)
 
 	assert.Nil(t, err)
 	assert.Equal(t, zipFilePath, filePath)
@@ -157,7 +158,7 @@ func TestIncludeSystemProbeConfig(t *testing.T) {
 	defer os.Remove("./test/system-probe.yaml")
 
 	zipFilePath := getArchivePath()
-	filePath, err := createArchive(SearchPaths{"": "./test/confd"}, true, zipFilePath, []string{""}, nil, nil)
+	filePath, err := createArchive(SearchPaths{"": "./test/confd"}, true, zipFilePath, []string{""}, nil, nil, nil)
 	assert.NoError(err)
 	assert.Equal(zipFilePath, filePath)
 
@@ -185,7 +186,7 @@ func TestIncludeConfigFiles(t *testing.T) {
 
 	common.SetupConfig("./test")
 	zipFilePath := getArchivePath()
-	filePath, err := createArchive(SearchPaths{"": "./test/confd"}, true, zipFilePath, []string{""}, nil, nil)
+	filePath, err := createArchive(SearchPaths{"": "./test/confd"}, true, zipFilePath, []string{""}, nil, nil, nil)
 
 	assert.NoError(err)
 	assert.Equal(zipFilePath, filePath)
@@ -269,7 +270,7 @@ func TestZipLogFiles(t *testing.T) {
 
 	permsInfos := make(permissionsInfos)
 
-	err = zipLogFiles(dstDir, "test", filepath.Join(srcDir, "agent.log"), permsInfos)
+	err = zipLogFiles(context.Background(), dstDir, "test", filepath.Join(srcDir, "agent.log"), permsInfos)
 	assert.NoError(t, err)
 
 	// Check all the log files are in the destination path, at the right subdirectories
@@ -281,35 +282,6 @@ func TestZipLogFiles(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestZipRegistryJSON(t *testing.T) {
-	srcDir, err := ioutil.TempDir("", "run")
-	require.NoError(t, err)
-	defer os.RemoveAll(srcDir)
-	dstDir, err := ioutil.TempDir("", "TestZipRegistryJSON")
-	require.NoError(t, err)
-	defer os.RemoveAll(dstDir)
-
-	// create non-empty registry.json file
-	file, err := os.Create(filepath.Join(srcDir, "registry.json"))
-	require.NoError(t, err)
-	_, err = file.WriteString("{\"key\":\"value\"}")
-	require.NoError(t, err)
-	err = file.Close()
-	require.NoError(t, err)
-
-	tempRunPath := config.Datadog.GetString("logs_config.run_path")
-	config.Datadog.Set("logs_config.run_path", srcDir)
-	defer config.Datadog.Set("logs_config.run_path", tempRunPath)
-
-	err = zipRegistryJSON(dstDir, "test")
-	assert.NoError(t, err)
-
-	// Check all the log files are in the destination path, at the right subdirectories
-	stat, err := os.Stat(filepath.Join(dstDir, "test", "registry.json"))
-	assert.NoError(t, err)
-	assert.Greater(t, stat.Size(), int64(0))
-}
-
 func TestZipTaggerList(t *testing.T) {
 	tagMap := make(map[string]response.TaggerListEntity)
 	tagMap["random_entity_name"] = response.TaggerListEntity{
@@ -391,7 +363,7 @@ func TestPerformanceProfile(t *testing.T) {
 		"third":  []byte{},
 	}
 	zipFilePath := getArchivePath()
-	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, testProfile, nil)
+	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, testProfile, nil, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, zipFilePath, filePath)
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 
 	"github.com/DataDog/datadog-agent/pkg/metadata/inventories"
+	"github.com/DataDog/datadog-agent/pkg/metadata/packages"
 	v5 "github.com/DataDog/datadog-agent/pkg/metadata/v5"
 	"github.com/DataDog/datadog-agent/pkg/util"
 )
@@ -29,6 +30,15 @@ func zipMetadataInventories(tempDir, hostname string) error {
 	return addMetadata(tempDir, hostname, "metadata_inventories.json", payload)
 }
 
+func zipMetadataSoftwareInventory(tempDir, hostname string) error {
+	payload, err := packages.GetLastInventory()
+	if err != nil {
+		return err
+	}
+
+	return addMetadata(tempDir, hostname, "metadata_software_inventory.json", payload)
+}
+
 func zipMetadataV5(tempDir, hostname string) error {
 	ctx := context.Background()
 	hostnameData, _ := util.GetHostnameData(ctx)
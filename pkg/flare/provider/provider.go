@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// DefaultTimeout is the time given to a provider when it doesn't set a Timeout
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxSize is the size limit of a provider when it doesn't set a MaxSize
+	DefaultMaxSize = 10 * 1024 * 1024
+)
+
+// Callback returns the files to add to the flare, keyed by their path relative
+// to the root of the flare. The context is cancelled once the provider timed out.
+type Callback func(ctx context.Context) (map[string][]byte, error)
+
+// Provider contributes files to the flare. The flare scrubs the content of the
+// files before writing them.
+type Provider struct {
+	Callback Callback
+	// Timeout is the maximum time given to Callback, DefaultTimeout if zero
+	Timeout time.Duration
+	// MaxSize is the maximum total size, in bytes, of the files returned by
+	// Callback, DefaultMaxSize if zero
+	MaxSize int
+}
+
+// Catalog holds the flare providers by name
+type Catalog map[string]Provider
+
+// DefaultCatalog holds every compiled-in flare provider
+var DefaultCatalog = make(Catalog)
+
+// Register a provider that will be called on flare
+func Register(name string, p Provider) {
+	if _, ok := DefaultCatalog[name]; ok {
+		log.Warnf("Flare provider %s already registered, overriding it", name)
+	}
+	DefaultCatalog[name] = p
+}
@@ -0,0 +1,279 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/flare/provider"
+
+	"gopkg.in/yaml.v2"
+)
+
+const providersIndexFilename = "providers.yaml"
+
+// Status of a provider in the flare providers index
+const (
+	providerSuccess = "success"
+	providerFailure = "failure"
+	providerTimeout = "timeout"
+	// providerDisabled is the status of a provider which doesn't apply to this agent
+	providerDisabled = "disabled"
+	// providerExcluded is the status of a provider left out by the only parameter
+	providerExcluded = "excluded"
+)
+
+var errProviderTimeout = errors.New("timed out")
+
+// providerResult is the outcome of a flare provider
+type providerResult struct {
+	Name     string `yaml:"name"`
+	Status   string `yaml:"status"`
+	Duration string `yaml:"duration,omitempty"`
+	Error    string `yaml:"error,omitempty"`
+}
+
+// providersIndex lists which providers contributed to the flare, and how they fared
+type providersIndex []providerResult
+
+func (i *providersIndex) disable(name string) {
+	*i = append(*i, providerResult{Name: name, Status: providerDisabled})
+}
+
+func (i *providersIndex) exclude(name string) {
+	*i = append(*i, providerResult{Name: name, Status: providerExcluded})
+}
+
+func (i *providersIndex) add(name string, start time.Time, err error) {
+	result := providerResult{
+		Name:     name,
+		Status:   providerSuccess,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+	if errors.Is(err, errProviderTimeout) {
+		result.Status = providerTimeout
+		result.Error = err.Error()
+	} else if err != nil {
+		result.Status = providerFailure
+		result.Error = err.Error()
+	}
+	*i = append(*i, result)
+}
+
+func (i providersIndex) commit(tempDir, hostname string) error {
+	data, err := yaml.Marshal(i)
+	if err != nil {
+		return err
+	}
+	f := filepath.Join(tempDir, hostname, providersIndexFilename)
+	if err := ensureParentDirsExist(f); err != nil {
+		return err
+	}
+	return writeScrubbedFile(f, data)
+}
+
+// selectProviders returns whether a provider is part of the flare, given the names
+// of the providers the flare is restricted to
+func selectProviders(only []string) (func(name string) bool, error) {
+	if len(only) == 0 {
+		return func(string) bool { return true }, nil
+	}
+
+	known := make(map[string]struct{})
+	for _, name := range ProviderNames() {
+		known[name] = struct{}{}
+	}
+	selected := make(map[string]struct{}, len(only))
+	for _, name := range only {
+		if _, found := known[name]; !found {
+			return nil, fmt.Errorf("unknown flare provider %q, available providers are: %s", name, strings.Join(ProviderNames(), ", "))
+		}
+		selected[name] = struct{}{}
+	}
+	return func(name string) bool {
+		_, found := selected[name]
+		return found
+	}, nil
+}
+
+// runProvider calls a registered provider within its timeout, and writes the files it
+// returned, once scrubbed, in the flare
+func runProvider(tempDir, hostname string, p provider.Provider) error {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = provider.DefaultTimeout
+	}
+	maxSize := p.MaxSize
+	if maxSize <= 0 {
+		maxSize = provider.DefaultMaxSize
+	}
+
+	files, err := callProvider(p.Callback, timeout)
+	if err != nil {
+		return err
+	}
+
+	size := 0
+	for name, content := range files {
+		if filepath.IsAbs(name) || strings.HasPrefix(filepath.Clean(name), "..") {
+			return fmt.Errorf("invalid file path %q, it must be relative to the flare root", name)
+		}
+		if _, err := os.Stat(filepath.Join(tempDir, hostname, filepath.Clean(name))); err == nil {
+			return fmt.Errorf("file %s is already part of the flare", name)
+		}
+		size += len(content)
+	}
+	if size > maxSize {
+		return fmt.Errorf("the files weigh %d bytes, which is over the limit of %d bytes", size, maxSize)
+	}
+
+	for name, content := range files {
+		f := filepath.Join(tempDir, hostname, filepath.Clean(name))
+		if err := ensureParentDirsExist(f); err != nil {
+			return err
+		}
+		if err := writeScrubbedFile(f, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runBuiltinProvider calls a built-in provider within its timeout. The provider writes
+// its files in a staging directory, from which they're moved in the flare once known to
+// fit in its size limit. They're already scrubbed by the provider. The files it adds to
+// its own permissions infos are merged in permsInfos once it returned in time.
+func runBuiltinProvider(tempDir, hostname string, p builtinProvider, permsInfos permissionsInfos) error {
+	timeout := p.timeout
+	if timeout <= 0 {
+		timeout = provider.DefaultTimeout
+	}
+	maxSize := p.maxSize
+	if maxSize <= 0 {
+		maxSize = provider.DefaultMaxSize
+	}
+
+	// the staging directory is out of tempDir/hostname, so that the files of a provider
+	// which timed out never end up in the flare
+	stagingDir, err := ioutil.TempDir(tempDir, "provider-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stagingDir)
+	// some providers expect the directory of the host to exist
+	if err := os.MkdirAll(filepath.Join(stagingDir, hostname), os.ModePerm); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	providerPermsInfos := make(permissionsInfos)
+	// unlike the callbacks of the registered providers, the built-in providers write in the
+	// staging directory and the permissions infos, so they're waited for even once timed out
+	err = callBuiltinProvider(ctx, stagingDir, hostname, p, providerPermsInfos)
+	if ctx.Err() != nil {
+		return fmt.Errorf("%w after %s", errProviderTimeout, timeout)
+	}
+	permsInfos.merge(providerPermsInfos)
+
+	// a provider failing to collect some of its files still adds the others
+	if moveErr := moveStagedFiles(filepath.Join(stagingDir, hostname), filepath.Join(tempDir, hostname), maxSize); err == nil {
+		err = moveErr
+	}
+	return err
+}
+
+// callBuiltinProvider calls the zip or collect function of a built-in provider, turning its
+// panics into errors
+func callBuiltinProvider(ctx context.Context, stagingDir, hostname string, p builtinProvider, permsInfos permissionsInfos) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	if p.collect != nil {
+		return p.collect(ctx, stagingDir, hostname, permsInfos)
+	}
+	return p.zip(stagingDir, hostname)
+}
+
+// moveStagedFiles moves the files of srcDir in dstDir, unless they weigh more than maxSize
+// bytes or would override a file already in dstDir
+func moveStagedFiles(srcDir, dstDir string, maxSize int) error {
+	var files []string
+	var size int64
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		name, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(dstDir, name)); err == nil {
+			return fmt.Errorf("file %s is already part of the flare", name)
+		}
+		files = append(files, name)
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if size > int64(maxSize) {
+		return fmt.Errorf("the files weigh %d bytes, which is over the limit of %d bytes", size, maxSize)
+	}
+
+	for _, name := range files {
+		f := filepath.Join(dstDir, name)
+		if err := ensureParentDirsExist(f); err != nil {
+			return err
+		}
+		if err := os.Rename(filepath.Join(srcDir, name), f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// callProvider calls callback, giving up on it once the timeout expired
+func callProvider(callback provider.Callback, timeout time.Duration) (map[string][]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	type result struct {
+		files map[string][]byte
+		err   error
+	}
+	// buffered, so that a callback returning after the timeout doesn't leak
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{err: fmt.Errorf("panic: %v", r)}
+			}
+		}()
+		files, err := callback(ctx)
+		done <- result{files, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.files, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w after %s", errProviderTimeout, timeout)
+	}
+}
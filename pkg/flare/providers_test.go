@@ -0,0 +1,229 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flare
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/flare/provider"
+)
+
+func TestRunProvider(t *testing.T) {
+	tempDir := t.TempDir()
+
+	err := runProvider(tempDir, "host", provider.Provider{
+		Callback: func(ctx context.Context) (map[string][]byte, error) {
+			return map[string][]byte{
+				"component/state.yaml": []byte("password: secret\n"),
+			}, nil
+		},
+	})
+	require.NoError(t, err)
+	content, err := ioutil.ReadFile(filepath.Join(tempDir, "host", "component", "state.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "password: ********", string(content))
+
+	// files can't be overwritten
+	err = runProvider(tempDir, "host", provider.Provider{
+		Callback: func(ctx context.Context) (map[string][]byte, error) {
+			return map[string][]byte{"component/state.yaml": []byte("")}, nil
+		},
+	})
+	assert.EqualError(t, err, "file component/state.yaml is already part of the flare")
+
+	for _, name := range []string{"/etc/passwd", "../outside", "component/../../outside"} {
+		err = runProvider(tempDir, "host", provider.Provider{
+			Callback: func(ctx context.Context) (map[string][]byte, error) {
+				return map[string][]byte{name: []byte("")}, nil
+			},
+		})
+		assert.Error(t, err, name)
+	}
+
+	err = runProvider(tempDir, "host", provider.Provider{
+		Callback: func(ctx context.Context) (map[string][]byte, error) {
+			return map[string][]byte{"a": []byte("12345"), "b": []byte("67890")}, nil
+		},
+		MaxSize: 8,
+	})
+	assert.EqualError(t, err, "the files weigh 10 bytes, which is over the limit of 8 bytes")
+	_, err = os.Stat(filepath.Join(tempDir, "host", "a"))
+	assert.True(t, os.IsNotExist(err))
+
+	err = runProvider(tempDir, "host", provider.Provider{
+		Callback: func(ctx context.Context) (map[string][]byte, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+		Timeout: 10 * time.Millisecond,
+	})
+	assert.True(t, errors.Is(err, errProviderTimeout))
+
+	err = runProvider(tempDir, "host", provider.Provider{
+		Callback: func(ctx context.Context) (map[string][]byte, error) {
+			panic("oops")
+		},
+	})
+	assert.EqualError(t, err, "panic: oops")
+}
+
+func TestRunBuiltinProvider(t *testing.T) {
+	tempDir := t.TempDir()
+	permsInfos := make(permissionsInfos)
+	write := func(tempDir, hostname, name, content string) error {
+		f := filepath.Join(tempDir, hostname, name)
+		if err := ensureParentDirsExist(f); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(f, []byte(content), os.ModePerm)
+	}
+
+	err := runBuiltinProvider(tempDir, "host", builtinProvider{name: "ok", zip: func(tempDir, hostname string) error {
+		return write(tempDir, hostname, "component/state.log", "ok")
+	}}, permsInfos)
+	require.NoError(t, err)
+	content, err := ioutil.ReadFile(filepath.Join(tempDir, "host", "component", "state.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(content))
+
+	// files can't be overwritten
+	err = runBuiltinProvider(tempDir, "host", builtinProvider{name: "conflict", zip: func(tempDir, hostname string) error {
+		return write(tempDir, hostname, "component/state.log", "")
+	}}, permsInfos)
+	assert.EqualError(t, err, "file component/state.log is already part of the flare")
+
+	// the files collected before a failure are kept
+	err = runBuiltinProvider(tempDir, "host", builtinProvider{name: "failure", zip: func(tempDir, hostname string) error {
+		if err := write(tempDir, hostname, "partial.log", "partial"); err != nil {
+			return err
+		}
+		return errors.New("broken")
+	}}, permsInfos)
+	assert.EqualError(t, err, "broken")
+	_, err = os.Stat(filepath.Join(tempDir, "host", "partial.log"))
+	assert.NoError(t, err)
+
+	err = runBuiltinProvider(tempDir, "host", builtinProvider{name: "too-big", maxSize: 8, zip: func(tempDir, hostname string) error {
+		if err := write(tempDir, hostname, "a", "12345"); err != nil {
+			return err
+		}
+		return write(tempDir, hostname, "b", "67890")
+	}}, permsInfos)
+	assert.EqualError(t, err, "the files weigh 10 bytes, which is over the limit of 8 bytes")
+	_, err = os.Stat(filepath.Join(tempDir, "host", "a"))
+	assert.True(t, os.IsNotExist(err))
+
+	// the collected files are added to the permissions infos
+	err = runBuiltinProvider(tempDir, "host", builtinProvider{name: "collect", collect: func(ctx context.Context, tempDir, hostname string, permsInfos permissionsInfos) error {
+		permsInfos["/etc/datadog-agent/datadog.yaml"] = filePermsInfo{}
+		return write(tempDir, hostname, "collected.log", "collected")
+	}}, permsInfos)
+	require.NoError(t, err)
+	assert.Contains(t, permsInfos, "/etc/datadog-agent/datadog.yaml")
+
+	// a provider which timed out is waited for, and neither its files nor its permissions infos are kept
+	err = runBuiltinProvider(tempDir, "host", builtinProvider{name: "slow", timeout: 10 * time.Millisecond, collect: func(ctx context.Context, tempDir, hostname string, permsInfos permissionsInfos) error {
+		<-ctx.Done()
+		permsInfos["/var/log/datadog/agent.log"] = filePermsInfo{}
+		return write(tempDir, hostname, "slow.log", "slow")
+	}}, permsInfos)
+	assert.True(t, errors.Is(err, errProviderTimeout))
+	assert.NotContains(t, permsInfos, "/var/log/datadog/agent.log")
+	_, err = os.Stat(filepath.Join(tempDir, "host", "slow.log"))
+	assert.True(t, os.IsNotExist(err))
+
+	err = runBuiltinProvider(tempDir, "host", builtinProvider{name: "panic", zip: func(tempDir, hostname string) error {
+		panic("oops")
+	}}, permsInfos)
+	assert.EqualError(t, err, "panic: oops")
+
+	// the staging directories are removed
+	entries, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "host", entries[0].Name())
+}
+
+func TestSelectProviders(t *testing.T) {
+	selected, err := selectProviders(nil)
+	require.NoError(t, err)
+	assert.True(t, selected("status"))
+
+	selected, err = selectProviders([]string{"status", "logs"})
+	require.NoError(t, err)
+	assert.True(t, selected("logs"))
+	assert.False(t, selected("expvar"))
+
+	_, err = selectProviders([]string{"unknown"})
+	assert.Error(t, err)
+}
+
+func TestCreateArchiveWithProviders(t *testing.T) {
+	defer func(catalog provider.Catalog) { provider.DefaultCatalog = catalog }(provider.DefaultCatalog)
+	provider.DefaultCatalog = make(provider.Catalog)
+	provider.Register("test-ok", provider.Provider{
+		Callback: func(ctx context.Context) (map[string][]byte, error) {
+			return map[string][]byte{"test/ok.log": []byte("ok")}, nil
+		},
+	})
+	provider.Register("test-failure", provider.Provider{
+		Callback: func(ctx context.Context) (map[string][]byte, error) {
+			return nil, errors.New("broken")
+		},
+	})
+	assert.Contains(t, ProviderNames(), "test-ok")
+	assert.Contains(t, ProviderNames(), "envvars")
+
+	zipFilePath := getArchivePath()
+	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil, []string{"envvars", "test-ok", "test-failure"})
+	require.NoError(t, err)
+	defer os.Remove(filePath)
+
+	z, err := zip.OpenReader(filePath)
+	require.NoError(t, err)
+	defer z.Close()
+
+	files := map[string]*zip.File{}
+	for _, f := range z.File {
+		files[path.Base(f.Name)] = f
+	}
+	assert.Contains(t, files, "ok.log")
+	assert.Contains(t, files, "envvars.log")
+	assert.NotContains(t, files, "health.yaml")
+	require.Contains(t, files, providersIndexFilename)
+
+	r, err := files[providersIndexFilename].Open()
+	require.NoError(t, err)
+	defer r.Close()
+	content, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	var index providersIndex
+	require.NoError(t, yaml.Unmarshal(content, &index))
+
+	statuses := map[string]providerResult{}
+	for _, result := range index {
+		statuses[result.Name] = result
+	}
+	assert.Equal(t, providerSuccess, statuses["envvars"].Status)
+	// the agent isn't running in local mode
+	assert.Equal(t, providerDisabled, statuses["status"].Status)
+	assert.Equal(t, providerExcluded, statuses["health"].Status)
+	assert.Equal(t, providerSuccess, statuses["test-ok"].Status)
+	assert.Equal(t, providerFailure, statuses["test-failure"].Status)
+	assert.Equal(t, "broken", statuses["test-failure"].Error)
+}
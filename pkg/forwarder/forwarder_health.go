@@ -34,6 +34,8 @@ var (
 	validateAPIKeyTimeout = 10 * time.Second
 
 	apiKeyStatus = expvar.Map{}
+
+	apiDomainRegexp = regexp.MustCompile(`((us|eu)\d\.)?(datadoghq.[a-z]+|ddog-gov.com)$`)
 )
 
 func init() {
@@ -133,17 +135,20 @@ func (fh *forwarderHealth) healthCheckLoop() {
 // computeDomainsURL populates a map containing API Endpoints per API keys that belongs to the forwarderHealth struct
 func (fh *forwarderHealth) computeDomainsURL() {
 	for domain, dr := range fh.domainResolvers {
-		apiDomain := ""
-		re := regexp.MustCompile(`((us|eu)\d\.)?(datadoghq.[a-z]+|ddog-gov.com)$`)
-		if re.MatchString(domain) {
-			apiDomain = "https://api." + re.FindString(domain)
-		} else {
-			apiDomain = domain
-		}
+		apiDomain := APIKeyValidationDomain(domain)
 		fh.keysPerAPIEndpoint[apiDomain] = append(fh.keysPerAPIEndpoint[apiDomain], dr.GetAPIKeys()...)
 	}
 }
 
+// APIKeyValidationDomain returns the domain of the API validating the keys used
+// to send data to domain
+func APIKeyValidationDomain(domain string) string {
+	if apiDomainRegexp.MatchString(domain) {
+		return "https://api." + apiDomainRegexp.FindString(domain)
+	}
+	return domain
+}
+
 func (fh *forwarderHealth) setAPIKeyStatus(apiKey string, domain string, status expvar.Var) {
 	if len(apiKey) > 5 {
 		apiKey = apiKey[len(apiKey)-5:]
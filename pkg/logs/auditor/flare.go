@@ -0,0 +1,32 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package auditor
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	coreConfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/flare/provider"
+)
+
+func init() {
+	provider.Register("registry", provider.Provider{Callback: flareRegistry})
+}
+
+// flareRegistry adds the registry of the logs agent to the flare, when there's one
+func flareRegistry(ctx context.Context) (map[string][]byte, error) {
+	content, err := ioutil.ReadFile(filepath.Join(coreConfig.Datadog.GetString("logs_config.run_path"), DefaultRegistryFilename))
+	if os.IsNotExist(err) {
+		// the logs agent is not enabled, or didn't tail anything yet
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{DefaultRegistryFilename: content}, nil
+}
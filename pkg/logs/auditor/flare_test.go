@@ -0,0 +1,42 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package auditor
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	coreConfig "github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func TestFlareRegistry(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "run")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcDir)
+
+	tempRunPath := coreConfig.Datadog.GetString("logs_config.run_path")
+	coreConfig.Datadog.Set("logs_config.run_path", srcDir)
+	defer coreConfig.Datadog.Set("logs_config.run_path", tempRunPath)
+
+	// no registry.json file yet
+	files, err := flareRegistry(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	// create non-empty registry.json file
+	err = ioutil.WriteFile(filepath.Join(srcDir, "registry.json"), []byte("{\"key\":\"value\"}"), 0644)
+	require.NoError(t, err)
+
+	files, err = flareRegistry(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"registry.json": []byte("{\"key\":\"value\"}")}, files)
+}
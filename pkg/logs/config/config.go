@@ -23,6 +23,9 @@ const ContainerCollectAll = "container_collect_all"
 // SnmpTraps is the name of the integration that collects logs from SNMP traps received by the Agent
 const SnmpTraps = "snmp_traps"
 
+// OTLP is the name of the integration that collects logs received by the OTLP pipeline of the Agent
+const OTLP = "otlp"
+
 // logs-intake endpoint prefix.
 const (
 	tcpEndpointPrefix            = "agent-intake.logs."
@@ -83,6 +86,18 @@ func SNMPTrapsSource() *LogSource {
 	return nil
 }
 
+// OTLPSource returns a source to forward the logs received by the OTLP pipeline.
+func OTLPSource() *LogSource {
+	if coreConfig.Datadog.GetBool(coreConfig.ExperimentalOTLPLogsEnabled) {
+		// source to forward OTLP logs, their service and tags are set from their resource attributes.
+		return NewLogSource(OTLP, &LogsConfig{
+			Type:   OTLPType,
+			Source: "otlp",
+		})
+	}
+	return nil
+}
+
 // GlobalProcessingRules returns the global processing rules to apply to all logs.
 func GlobalProcessingRules() ([]*ProcessingRule, error) {
 	var rules []*ProcessingRule
@@ -110,6 +125,33 @@ func GlobalProcessingRules() ([]*ProcessingRule, error) {
 	return rules, nil
 }
 
+// GlobalRateLimits returns the rate limits to apply to the logs of some services.
+func GlobalRateLimits() ([]*RateLimit, error) {
+	var rateLimits []*RateLimit
+	var err error
+	raw := coreConfig.Datadog.Get("logs_config.rate_limits")
+	if raw == nil {
+		return rateLimits, nil
+	}
+	if s, ok := raw.(string); ok && s != "" {
+		err = json.Unmarshal([]byte(s), &rateLimits)
+	} else {
+		err = coreConfig.Datadog.UnmarshalKey("logs_config.rate_limits", &rateLimits)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, rateLimit := range rateLimits {
+		if rateLimit.Service == "" {
+			return nil, fmt.Errorf("all rate limits must have a service")
+		}
+		if err := ValidateRateLimit(rateLimit); err != nil {
+			return nil, fmt.Errorf("invalid rate limit for service %s: %v", rateLimit.Service, err)
+		}
+	}
+	return rateLimits, nil
+}
+
 // BuildEndpoints returns the endpoints to send logs.
 func BuildEndpoints(httpConnectivity HTTPConnectivity, intakeTrackType IntakeTrackType, intakeProtocol IntakeProtocol, intakeOrigin IntakeOrigin) (*Endpoints, error) {
 	coreConfig.SanitizeAPIKeyConfig(coreConfig.Datadog, "logs_config.api_key")
@@ -185,13 +227,19 @@ func buildTCPEndpoints(logsConfig *LogsConfigKeys) (*Endpoints, error) {
 		main.UseSSL = !logsConfig.devModeNoSSL()
 	}
 
+	completeEndpoint := func(endpoint *Endpoint) {
+		endpoint.UseSSL = main.UseSSL
+		endpoint.ProxyAddress = proxyAddress
+		endpoint.APIKey = coreConfig.SanitizeAPIKey(endpoint.APIKey)
+	}
+
 	additionals := logsConfig.getAdditionalEndpoints()
 	for i := 0; i < len(additionals); i++ {
-		additionals[i].UseSSL = main.UseSSL
-		additionals[i].ProxyAddress = proxyAddress
-		additionals[i].APIKey = coreConfig.SanitizeAPIKey(additionals[i].APIKey)
+		completeEndpoint(&additionals[i])
 	}
-	return NewEndpoints(main, additionals, useProto, false), nil
+	endpoints := NewEndpoints(main, additionals, useProto, false)
+	endpoints.Routes = buildRoutes(logsConfig, completeEndpoint)
+	return endpoints, nil
 }
 
 // BuildHTTPEndpoints returns the HTTP endpoints to send logs to.
@@ -239,34 +287,53 @@ func BuildHTTPEndpointsWithConfig(logsConfig *LogsConfigKeys, endpointPrefix str
 		main.UseSSL = !logsConfig.devModeNoSSL()
 	}
 
-	additionals := logsConfig.getAdditionalEndpoints()
-	for i := 0; i < len(additionals); i++ {
-		additionals[i].UseSSL = main.UseSSL
-		additionals[i].APIKey = coreConfig.SanitizeAPIKey(additionals[i].APIKey)
-		additionals[i].UseCompression = main.UseCompression
-		additionals[i].CompressionLevel = main.CompressionLevel
-		additionals[i].BackoffBase = main.BackoffBase
-		additionals[i].BackoffMax = main.BackoffMax
-		additionals[i].BackoffFactor = main.BackoffFactor
-		additionals[i].RecoveryInterval = main.RecoveryInterval
-		additionals[i].RecoveryReset = main.RecoveryReset
-
-		if additionals[i].Version == 0 {
-			additionals[i].Version = main.Version
+	completeEndpoint := func(endpoint *Endpoint) {
+		endpoint.UseSSL = main.UseSSL
+		endpoint.APIKey = coreConfig.SanitizeAPIKey(endpoint.APIKey)
+		endpoint.UseCompression = main.UseCompression
+		endpoint.CompressionLevel = main.CompressionLevel
+		endpoint.BackoffBase = main.BackoffBase
+		endpoint.BackoffMax = main.BackoffMax
+		endpoint.BackoffFactor = main.BackoffFactor
+		endpoint.RecoveryInterval = main.RecoveryInterval
+		endpoint.RecoveryReset = main.RecoveryReset
+
+		if endpoint.Version == 0 {
+			endpoint.Version = main.Version
 		}
-		if additionals[i].Version == EPIntakeVersion2 {
-			additionals[i].TrackType = intakeTrackType
-			additionals[i].Protocol = intakeProtocol
-			additionals[i].Origin = intakeOrigin
+		if endpoint.Version == EPIntakeVersion2 {
+			endpoint.TrackType = intakeTrackType
+			endpoint.Protocol = intakeProtocol
+			endpoint.Origin = intakeOrigin
 		}
 	}
 
+	additionals := logsConfig.getAdditionalEndpoints()
+	for i := 0; i < len(additionals); i++ {
+		completeEndpoint(&additionals[i])
+	}
+
 	batchWait := logsConfig.batchWait()
 	batchMaxConcurrentSend := logsConfig.batchMaxConcurrentSend()
 	batchMaxSize := logsConfig.batchMaxSize()
 	batchMaxContentSize := logsConfig.batchMaxContentSize()
 
-	return NewEndpointsWithBatchSettings(main, additionals, false, true, batchWait, batchMaxConcurrentSend, batchMaxSize, batchMaxContentSize), nil
+	endpoints := NewEndpointsWithBatchSettings(main, additionals, false, true, batchWait, batchMaxConcurrentSend, batchMaxSize, batchMaxContentSize)
+	endpoints.Routes = buildRoutes(logsConfig, completeEndpoint)
+	return endpoints, nil
+}
+
+// buildRoutes returns the routes with their endpoints completed like the additional endpoints.
+// The endpoints of the routes are reliable, the offsets of the logs are only committed once they are sent to all their routes.
+func buildRoutes(logsConfig *LogsConfigKeys, completeEndpoint func(*Endpoint)) []Route {
+	routes := logsConfig.getRoutes()
+	for i := range routes {
+		for j := range routes[i].Endpoints {
+			completeEndpoint(&routes[i].Endpoints[j])
+			routes[i].Endpoints[j].IsReliable = true
+		}
+	}
+	return routes
 }
 
 // parseAddress returns the host and the port of the address.
@@ -137,6 +137,29 @@ func (l *LogsConfigKeys) getAdditionalEndpoints() []Endpoint {
 	return endpoints
 }
 
+func (l *LogsConfigKeys) getRoutes() []Route {
+	var routes []Route
+	var err error
+	configKey := l.getConfigKey("routes")
+	raw := l.getConfig().Get(configKey)
+	if raw == nil {
+		return routes
+	}
+	if s, ok := raw.(string); ok && s != "" {
+		err = json.Unmarshal([]byte(s), &routes)
+	} else {
+		err = l.getConfig().UnmarshalKey(configKey, &routes)
+	}
+	if err == nil {
+		err = ValidateRoutes(routes)
+	}
+	if err != nil {
+		log.Warnf("Could not parse routes for logs, all logs are sent to the main endpoints: %v", err)
+		return nil
+	}
+	return routes
+}
+
 func (l *LogsConfigKeys) expectedTagsDuration() time.Duration {
 	return l.getConfig().GetDuration(l.getConfigKey("expected_tags_duration"))
 }
@@ -128,6 +128,29 @@ func (suite *ConfigTestSuite) TestGlobalProcessingRulesShouldReturnRulesWithVali
 	suite.NotNil(rule.Regex)
 }
 
+func (suite *ConfigTestSuite) TestGlobalRateLimits() {
+	rateLimits, err := GlobalRateLimits()
+	suite.Nil(err)
+	suite.Empty(rateLimits)
+
+	suite.config.Set("logs_config.rate_limits", `[{"service": "api", "messages_per_second": 100, "overflow": "errors_only"}]`)
+	rateLimits, err = GlobalRateLimits()
+	suite.Nil(err)
+	suite.Require().Len(rateLimits, 1)
+	suite.Equal("api", rateLimits[0].Service)
+	suite.Equal(float64(100), rateLimits[0].MessagesPerSecond)
+	suite.Equal(float64(100), rateLimits[0].MessagesBurst)
+	suite.Equal(OverflowErrorsOnly, rateLimits[0].Overflow)
+
+	suite.config.Set("logs_config.rate_limits", []map[string]interface{}{{"messages_per_second": 100}})
+	_, err = GlobalRateLimits()
+	suite.NotNil(err)
+
+	suite.config.Set("logs_config.rate_limits", []map[string]interface{}{{"service": "api", "overflow": "drop"}})
+	_, err = GlobalRateLimits()
+	suite.NotNil(err)
+}
+
 func (suite *ConfigTestSuite) TestTaggerWarmupDuration() {
 	// assert TaggerWarmupDuration is disabled by default
 	taggerWarmupDuration := TaggerWarmupDuration()
@@ -444,6 +467,68 @@ func (suite *ConfigTestSuite) TestMultipleTCPEndpointsInConf() {
 	suite.Equal(expectedEndpoints, endpoints)
 }
 
+func (suite *ConfigTestSuite) TestRoutesInConfig() {
+	suite.config.Set("api_key", "123")
+	suite.config.Set("logs_config.use_compression", true)
+	suite.config.Set("logs_config.compression_level", 4)
+	suite.config.Set("logs_config.routes", []map[string]interface{}{
+		{
+			"name":      "security",
+			"exclusive": true,
+			"match": map[string]interface{}{
+				"sources": []string{"auth"},
+			},
+			"endpoints": []map[string]interface{}{
+				{"api_key": "456\n", "host": "security.intake"},
+			},
+		},
+	})
+
+	endpoints, err := BuildHTTPEndpoints("test-track", "test-proto", "test-source")
+	suite.Nil(err)
+	suite.Require().Len(endpoints.Routes, 1)
+
+	route := endpoints.Routes[0]
+	suite.Equal("security", route.Name)
+	suite.True(route.Exclusive)
+	suite.Equal([]string{"auth"}, route.Match.Sources)
+	suite.Require().Len(route.Endpoints, 1)
+	suite.Equal("456", route.Endpoints[0].APIKey)
+	suite.Equal("security.intake", route.Endpoints[0].Host)
+	suite.True(route.Endpoints[0].IsReliable)
+	suite.True(route.Endpoints[0].UseSSL)
+	suite.True(route.Endpoints[0].UseCompression)
+	suite.Equal(4, route.Endpoints[0].CompressionLevel)
+	suite.Equal(endpoints.Main.Version, route.Endpoints[0].Version)
+}
+
+func (suite *ConfigTestSuite) TestRoutesJSONString() {
+	suite.config.Set("logs_config.logs_dd_url", "agent-intake.logs.datadoghq.com:10516")
+	suite.config.Set("logs_config.socks5_proxy_address", "proxy.test:3128")
+	suite.config.Set("logs_config.routes", `[{"name": "archive", "endpoints": [{"host": "archive.intake", "port": 10516}]}]`)
+
+	endpoints, err := buildTCPEndpoints(defaultLogsConfigKeys())
+	suite.Nil(err)
+	suite.Equal([]Route{{
+		Name: "archive",
+		Endpoints: []Endpoint{{
+			Host:         "archive.intake",
+			Port:         10516,
+			UseSSL:       true,
+			ProxyAddress: "proxy.test:3128",
+			IsReliable:   true,
+		}},
+	}}, endpoints.Routes)
+}
+
+func (suite *ConfigTestSuite) TestInvalidRoutes() {
+	suite.config.Set("logs_config.routes", `[{"name": "archive"}]`)
+
+	endpoints, err := BuildHTTPEndpoints("test-track", "test-proto", "test-source")
+	suite.Nil(err)
+	suite.Empty(endpoints.Routes)
+}
+
 func (suite *ConfigTestSuite) TestEndpointsSetLogsDDUrl() {
 	suite.config.Set("api_key", "123")
 	suite.config.Set("compliance_config.endpoints.logs_dd_url", "my-proxy:443")
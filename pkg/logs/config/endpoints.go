@@ -103,6 +103,8 @@ type Endpoints struct {
 	BatchMaxConcurrentSend int
 	BatchMaxSize           int
 	BatchMaxContentSize    int
+	// Routes send some of the logs to their own endpoints
+	Routes []Route
 }
 
 // GetStatus returns the endpoints status, one line per endpoint
@@ -114,6 +116,11 @@ func (e *Endpoints) GetStatus() []string {
 	for _, endpoint := range e.GetUnReliableEndpoints() {
 		result = append(result, endpoint.GetStatus("Unreliable: ", e.UseHTTP))
 	}
+	for _, route := range e.Routes {
+		for _, endpoint := range route.Endpoints {
+			result = append(result, endpoint.GetStatus(fmt.Sprintf("Route %s: ", route.Name), e.UseHTTP))
+		}
+	}
 	return result
 }
 
@@ -165,6 +172,15 @@ func (e *Endpoints) GetReliableEndpoints() []Endpoint {
 	return endpoints
 }
 
+// ForRoute returns the endpoints of a route, with the same settings as the main endpoints.
+func (e *Endpoints) ForRoute(route Route) *Endpoints {
+	endpoints := *e
+	endpoints.Main = route.Endpoints[0]
+	endpoints.Endpoints = route.Endpoints
+	endpoints.Routes = nil
+	return &endpoints
+}
+
 // GetUnReliableEndpoints returns additional endpoints that do not guarantee logs are received in the event of an error.
 func (e *Endpoints) GetUnReliableEndpoints() []Endpoint {
 	endpoints := []Endpoint{}
@@ -22,6 +22,7 @@ const (
 	WindowsEventType  = "windows_event"
 	SnmpTrapsType     = "snmp_traps"
 	StringChannelType = "string_channel"
+	OTLPType          = "otlp"
 
 	// UTF16BE for UTF-16 Big endian encoding
 	UTF16BE string = "utf-16-be"
@@ -47,6 +48,14 @@ type LogsConfig struct {
 	IncludeUnits  []string `mapstructure:"include_units" json:"include_units"`   // Journald
 	ExcludeUnits  []string `mapstructure:"exclude_units" json:"exclude_units"`   // Journald
 	ContainerMode bool     `mapstructure:"container_mode" json:"container_mode"` // Journald
+	// IncludeMatches and ExcludeMatches filter the journal entries on their fields: an entry matches
+	// when it matches any of the matches, and a match is a set of field values that must all match.
+	IncludeMatches []map[string]string `mapstructure:"include_matches" json:"include_matches"` // Journald
+	ExcludeMatches []map[string]string `mapstructure:"exclude_matches" json:"exclude_matches"` // Journald
+	// FieldsToTags maps the journal fields promoted to tags to the names of the tags
+	FieldsToTags map[string]string `mapstructure:"fields_to_tags" json:"fields_to_tags"` // Journald
+	ServiceField string            `mapstructure:"service_field" json:"service_field"`   // Journald
+	SourceField  string            `mapstructure:"source_field" json:"source_field"`     // Journald
 
 	Image string // Docker
 	Label string // Docker
@@ -67,6 +76,12 @@ type LogsConfig struct {
 	SourceCategory  string
 	Tags            []string
 	ProcessingRules []*ProcessingRule `mapstructure:"log_processing_rules" json:"log_processing_rules"`
+	// Routes are the names of the routes the logs of the source are sent to, see `logs_config.routes`
+	Routes []string
+	// RateLimit limits the logs of the source, the logs above the limit are handled according to its overflow policy
+	RateLimit *RateLimit `mapstructure:"rate_limit" json:"rate_limit"`
+	// DeduplicateLines replaces the consecutive identical lines of the source with a summary of their repeat count
+	DeduplicateLines bool `mapstructure:"deduplicate_lines" json:"deduplicate_lines"`
 
 	AutoMultiLine               *bool   `mapstructure:"auto_multi_line_detection" json:"auto_multi_line_detection"`
 	AutoMultiLineSampleSize     int     `mapstructure:"auto_multi_line_sample_size" json:"auto_multi_line_sample_size"`
@@ -139,6 +154,11 @@ func (c *LogsConfig) Validate() error {
 	if err != nil {
 		return err
 	}
+	if c.RateLimit != nil {
+		if err := ValidateRateLimit(c.RateLimit); err != nil {
+			return err
+		}
+	}
 	return CompileProcessingRules(c.ProcessingRules)
 }
 
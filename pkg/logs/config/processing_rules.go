@@ -16,6 +16,7 @@ const (
 	IncludeAtMatch = "include_at_match"
 	MaskSequences  = "mask_sequences"
 	MultiLine      = "multi_line"
+	RouteAtMatch   = "route_at_match"
 )
 
 // ProcessingRule defines an exclusion or a masking rule to
@@ -25,6 +26,8 @@ type ProcessingRule struct {
 	Name               string
 	ReplacePlaceholder string `mapstructure:"replace_placeholder" json:"replace_placeholder"`
 	Pattern            string
+	// Route is the name of the route of the logs matching a `route_at_match` rule
+	Route string
 	// TODO: should be moved out
 	Regex       *regexp.Regexp
 	Placeholder []byte
@@ -44,6 +47,10 @@ func ValidateProcessingRules(rules []*ProcessingRule) error {
 		switch rule.Type {
 		case ExcludeAtMatch, IncludeAtMatch, MaskSequences, MultiLine:
 			break
+		case RouteAtMatch:
+			if rule.Route == "" {
+				return fmt.Errorf("no route provided for processing rule: %s", rule.Name)
+			}
 		case "":
 			return fmt.Errorf("type must be set for processing rule `%s`", rule.Name)
 		default:
@@ -69,7 +76,7 @@ func CompileProcessingRules(rules []*ProcessingRule) error {
 			return err
 		}
 		switch rule.Type {
-		case ExcludeAtMatch, IncludeAtMatch:
+		case ExcludeAtMatch, IncludeAtMatch, RouteAtMatch:
 			rule.Regex = re
 		case MaskSequences:
 			rule.Regex = re
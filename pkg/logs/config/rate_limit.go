@@ -0,0 +1,140 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Overflow policies, applied to the logs exceeding a rate limit
+const (
+	OverflowDrop       = "drop"
+	OverflowSample     = "sample"
+	OverflowErrorsOnly = "errors_only"
+)
+
+const defaultSampleRate = 0.1
+
+// RateLimit limits the number of logs, or of bytes, of a source.
+// When it is defined in `logs_config.rate_limits`, it limits all the logs of a service.
+type RateLimit struct {
+	Service           string
+	MessagesPerSecond float64 `mapstructure:"messages_per_second" json:"messages_per_second"`
+	MessagesBurst     float64 `mapstructure:"messages_burst" json:"messages_burst"`
+	BytesPerSecond    float64 `mapstructure:"bytes_per_second" json:"bytes_per_second"`
+	BytesBurst        float64 `mapstructure:"bytes_burst" json:"bytes_burst"`
+	Overflow          string
+	// SampleRate is the ratio of the logs kept above the limit with the `sample` overflow policy
+	SampleRate float64 `mapstructure:"sample_rate" json:"sample_rate"`
+
+	// TODO: should be moved out
+	lock       sync.Mutex
+	messages   tokenBucket
+	bytes      tokenBucket
+	overflowed uint64
+}
+
+// tokenBucket refills at a constant rate up to its capacity, a zero rate means no limit
+type tokenBucket struct {
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate, capacity float64) tokenBucket {
+	return tokenBucket{rate: rate, capacity: capacity, tokens: capacity}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+}
+
+func (b *tokenBucket) has(n float64) bool {
+	return b.rate == 0 || b.tokens >= n
+}
+
+func (b *tokenBucket) take(n float64) {
+	if b.rate != 0 {
+		b.tokens -= n
+	}
+}
+
+// ValidateRateLimit validates the rate limit, raises an error if it is misconfigured and sets the default values.
+// A rate limit must have:
+// - a positive rate of messages or bytes
+// - a valid overflow policy
+// - a sample rate between 0 and 1
+func ValidateRateLimit(r *RateLimit) error {
+	if r.MessagesPerSecond < 0 || r.BytesPerSecond < 0 || r.MessagesBurst < 0 || r.BytesBurst < 0 {
+		return fmt.Errorf("rate limits can't be negative")
+	}
+	if r.MessagesPerSecond == 0 && r.BytesPerSecond == 0 {
+		return fmt.Errorf("a rate limit must have messages_per_second or bytes_per_second")
+	}
+
+	switch r.Overflow {
+	case "":
+		r.Overflow = OverflowDrop
+	case OverflowDrop, OverflowSample, OverflowErrorsOnly:
+		break
+	default:
+		return fmt.Errorf("overflow policy %s is not supported", r.Overflow)
+	}
+
+	if r.SampleRate == 0 {
+		r.SampleRate = defaultSampleRate
+	}
+	if r.SampleRate < 0 || r.SampleRate > 1 {
+		return fmt.Errorf("sample_rate must be between 0 and 1, got %v", r.SampleRate)
+	}
+
+	// bursts default to one second of logs
+	if r.MessagesBurst == 0 {
+		r.MessagesBurst = r.MessagesPerSecond
+	}
+	if r.BytesBurst == 0 {
+		r.BytesBurst = r.BytesPerSecond
+	}
+	r.messages = newTokenBucket(r.MessagesPerSecond, r.MessagesBurst)
+	r.bytes = newTokenBucket(r.BytesPerSecond, r.BytesBurst)
+	return nil
+}
+
+// Allow returns true if a log of the given size is within the rate limit.
+func (r *RateLimit) Allow(size int) bool {
+	return r.allowAt(size, time.Now())
+}
+
+func (r *RateLimit) allowAt(size int, now time.Time) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.messages.refill(now)
+	r.bytes.refill(now)
+	if !r.messages.has(1) || !r.bytes.has(float64(size)) {
+		return false
+	}
+	r.messages.take(1)
+	r.bytes.take(float64(size))
+	return true
+}
+
+// Sample returns true for the ratio of the logs exceeding the limit that must be kept with the `sample` overflow policy.
+func (r *RateLimit) Sample() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.overflowed++
+	every := uint64(math.Round(1 / r.SampleRate))
+	return every <= 1 || r.overflowed%every == 1
+}
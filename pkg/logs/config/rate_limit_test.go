@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRateLimit(t *testing.T) {
+	rateLimit := &RateLimit{BytesPerSecond: 1024}
+	require.NoError(t, ValidateRateLimit(rateLimit))
+	assert.Equal(t, OverflowDrop, rateLimit.Overflow)
+	assert.Equal(t, float64(1024), rateLimit.BytesBurst)
+	assert.Equal(t, defaultSampleRate, rateLimit.SampleRate)
+
+	assert.Error(t, ValidateRateLimit(&RateLimit{}))
+	assert.Error(t, ValidateRateLimit(&RateLimit{MessagesPerSecond: -1}))
+	assert.Error(t, ValidateRateLimit(&RateLimit{MessagesPerSecond: 1, Overflow: "keep"}))
+	assert.Error(t, ValidateRateLimit(&RateLimit{MessagesPerSecond: 1, SampleRate: 2}))
+}
+
+func TestRateLimitMessages(t *testing.T) {
+	rateLimit := &RateLimit{MessagesPerSecond: 2, MessagesBurst: 3}
+	require.NoError(t, ValidateRateLimit(rateLimit))
+
+	now := time.Now()
+	assert.True(t, rateLimit.allowAt(10, now))
+	assert.True(t, rateLimit.allowAt(10, now))
+	assert.True(t, rateLimit.allowAt(10, now))
+	assert.False(t, rateLimit.allowAt(10, now))
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, rateLimit.allowAt(10, now))
+	assert.False(t, rateLimit.allowAt(10, now))
+
+	// the bucket can't hold more than the burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.True(t, rateLimit.allowAt(10, now))
+	}
+	assert.False(t, rateLimit.allowAt(10, now))
+}
+
+func TestRateLimitBytes(t *testing.T) {
+	rateLimit := &RateLimit{BytesPerSecond: 100}
+	require.NoError(t, ValidateRateLimit(rateLimit))
+
+	now := time.Now()
+	assert.True(t, rateLimit.allowAt(60, now))
+	assert.False(t, rateLimit.allowAt(60, now))
+	assert.True(t, rateLimit.allowAt(40, now))
+
+	now = now.Add(time.Second)
+	assert.True(t, rateLimit.allowAt(100, now))
+}
+
+func TestRateLimitSample(t *testing.T) {
+	rateLimit := &RateLimit{MessagesPerSecond: 1, Overflow: OverflowSample, SampleRate: 0.25}
+	require.NoError(t, ValidateRateLimit(rateLimit))
+
+	var kept int
+	for i := 0; i < 100; i++ {
+		if rateLimit.Sample() {
+			kept++
+		}
+	}
+	assert.Equal(t, 25, kept)
+}
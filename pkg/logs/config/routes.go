@@ -0,0 +1,91 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"fmt"
+)
+
+// Route sends the logs matching its criteria to its own endpoints.
+// A log can also be sent to a route explicitly, from the `routes` of its source
+// or from a processing rule of type `route_at_match`.
+type Route struct {
+	Name string
+	// Exclusive routes take the logs they match away from the main endpoints
+	Exclusive bool
+	Match     RouteMatch
+	// Endpoints have the same format as the additional endpoints, they are always reliable
+	Endpoints []Endpoint
+}
+
+// RouteMatch holds the criteria of a route. A log matches when it matches
+// all the criteria that are set, and a criterion matches when any of its values matches.
+type RouteMatch struct {
+	Sources  []string
+	Services []string
+	Tags     []string
+}
+
+// IsEmpty returns true if no criteria is set, such routes only receive the logs sent to them explicitly.
+func (m *RouteMatch) IsEmpty() bool {
+	return len(m.Sources) == 0 && len(m.Services) == 0 && len(m.Tags) == 0
+}
+
+// Matches returns true if a log with the given source, service and tags matches the criteria of the route.
+func (r *Route) Matches(source string, service string, tags []string) bool {
+	if r.Match.IsEmpty() {
+		return false
+	}
+	if len(r.Match.Sources) > 0 && !contains(r.Match.Sources, source) {
+		return false
+	}
+	if len(r.Match.Services) > 0 && !contains(r.Match.Services, service) {
+		return false
+	}
+	if len(r.Match.Tags) > 0 {
+		for _, tag := range tags {
+			if contains(r.Match.Tags, tag) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// ValidateRoutes validates the routes and raises an error if one is misconfigured.
+// Each route must have a unique name and at least one endpoint with a host.
+func ValidateRoutes(routes []Route) error {
+	names := make(map[string]struct{}, len(routes))
+	for _, route := range routes {
+		if route.Name == "" {
+			return fmt.Errorf("all routes must have a name")
+		}
+		if _, found := names[route.Name]; found {
+			return fmt.Errorf("route `%s` is defined more than once", route.Name)
+		}
+		names[route.Name] = struct{}{}
+
+		if len(route.Endpoints) == 0 {
+			return fmt.Errorf("no endpoint provided for route: %s", route.Name)
+		}
+		for _, endpoint := range route.Endpoints {
+			if endpoint.Host == "" {
+				return fmt.Errorf("all the endpoints of route `%s` must have a host", route.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteMatches(t *testing.T) {
+	route := Route{Name: "audit"}
+	assert.False(t, route.Matches("auth", "sshd", nil))
+
+	route.Match = RouteMatch{Sources: []string{"auth", "kern"}}
+	assert.True(t, route.Matches("auth", "sshd", nil))
+	assert.False(t, route.Matches("nginx", "sshd", nil))
+
+	route.Match.Services = []string{"sshd"}
+	assert.True(t, route.Matches("kern", "sshd", nil))
+	assert.False(t, route.Matches("kern", "cron", nil))
+
+	route.Match.Tags = []string{"env:prod", "team:security"}
+	assert.True(t, route.Matches("auth", "sshd", []string{"env:staging", "team:security"}))
+	assert.False(t, route.Matches("auth", "sshd", []string{"env:staging"}))
+}
+
+func TestValidateRoutes(t *testing.T) {
+	endpoints := []Endpoint{{Host: "archive.example.com"}}
+
+	assert.NoError(t, ValidateRoutes(nil))
+	assert.NoError(t, ValidateRoutes([]Route{{Name: "archive", Endpoints: endpoints}, {Name: "audit", Endpoints: endpoints}}))
+
+	assert.Error(t, ValidateRoutes([]Route{{Endpoints: endpoints}}))
+	assert.Error(t, ValidateRoutes([]Route{{Name: "archive", Endpoints: endpoints}, {Name: "archive", Endpoints: endpoints}}))
+	assert.Error(t, ValidateRoutes([]Route{{Name: "archive"}}))
+	assert.Error(t, ValidateRoutes([]Route{{Name: "archive", Endpoints: []Endpoint{{Port: 443}}}}))
+}
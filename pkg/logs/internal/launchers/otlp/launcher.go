@@ -0,0 +1,66 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package otlp
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	tailer "github.com/DataDog/datadog-agent/pkg/logs/internal/tailers/otlp"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
+)
+
+// Launcher forwards the logs received by the OTLP pipeline to the logs pipelines.
+type Launcher struct {
+	pipelineProvider pipeline.Provider
+	sources          chan *config.LogSource
+	inputChan        chan *message.Message
+	tailer           *tailer.Tailer
+	stop             chan interface{}
+}
+
+// NewLauncher returns an initialized Launcher reading the messages of the given channel
+func NewLauncher(sources *config.LogSources, pipelineProvider pipeline.Provider, inputChan chan *message.Message) *Launcher {
+	return &Launcher{
+		pipelineProvider: pipelineProvider,
+		sources:          sources.GetAddedForType(config.OTLPType),
+		inputChan:        inputChan,
+		stop:             make(chan interface{}, 1),
+	}
+}
+
+// Start starts the launcher.
+func (l *Launcher) Start() {
+	go l.run()
+}
+
+func (l *Launcher) startNewTailer(source *config.LogSource) {
+	outputChan := l.pipelineProvider.NextPipelineChan()
+	l.tailer = tailer.NewTailer(source, l.inputChan, outputChan)
+	l.tailer.Start()
+}
+
+func (l *Launcher) run() {
+	for {
+		select {
+		case source := <-l.sources:
+			if l.tailer == nil {
+				l.startNewTailer(source)
+				source.Status.Success()
+			}
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// Stop stops the running tailer.
+func (l *Launcher) Stop() {
+	if l.tailer != nil {
+		l.tailer.Stop()
+		l.tailer = nil
+	}
+	l.stop <- true
+}
@@ -36,7 +36,7 @@ func (l *Launcher) Start() {
 
 func (l *Launcher) startNewTailer(source *config.LogSource, inputChan chan *traps.SnmpPacket) {
 	outputChan := l.pipelineProvider.NextPipelineChan()
-	l.tailer = tailer.NewTailer(source, inputChan, outputChan)
+	l.tailer = tailer.NewTailer(source, inputChan, outputChan, traps.GetOIDResolver())
 	l.tailer.Start()
 }
 
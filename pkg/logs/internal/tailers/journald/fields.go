@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package journald
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/util/common"
+)
+
+// priorityField is the journal field holding the syslog priority of an entry, it can be matched with a range
+const priorityField = "PRIORITY"
+
+// fieldMatch matches the journal entries whose fields have all the expected values
+type fieldMatch map[string]valueMatcher
+
+// valueMatcher matches the value of a journal field
+type valueMatcher struct {
+	value string
+	// min and max are the bounds of a priority range, set when isRange is true
+	isRange  bool
+	min, max int
+}
+
+func (m valueMatcher) matches(value string) bool {
+	if !m.isRange {
+		return m.value == value
+	}
+	priority, err := strconv.Atoi(value)
+	return err == nil && priority >= m.min && priority <= m.max
+}
+
+// compileMatches compiles the field matches of a journald config.
+// The names of the journal fields are case insensitive and the priority can be matched
+// with an inclusive range `<min>..<max>`, e.g. `0..3` for the error logs.
+func compileMatches(matches []map[string]string) ([]fieldMatch, error) {
+	var compiled []fieldMatch
+	for _, match := range matches {
+		if len(match) == 0 {
+			return nil, fmt.Errorf("journal field matches can't be empty")
+		}
+		fm := make(fieldMatch, len(match))
+		for field, value := range match {
+			field = strings.ToUpper(field)
+			matcher := valueMatcher{value: value}
+			if field == priorityField {
+				if min, max, isRange := common.Cut(value, ".."); isRange {
+					var err error
+					if matcher.min, err = strconv.Atoi(min); err != nil {
+						return nil, fmt.Errorf("invalid priority range %s: %s", value, err)
+					}
+					if matcher.max, err = strconv.Atoi(max); err != nil {
+						return nil, fmt.Errorf("invalid priority range %s: %s", value, err)
+					}
+					matcher.isRange = true
+				}
+			}
+			fm[field] = matcher
+		}
+		compiled = append(compiled, fm)
+	}
+	return compiled, nil
+}
+
+// matches returns true if all the fields of the match have the expected values
+func (m fieldMatch) matches(fields map[string]string) bool {
+	for field, matcher := range m {
+		value, exists := fields[field]
+		if !exists || !matcher.matches(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAny returns true if the fields match any of the matches
+func matchesAny(matches []fieldMatch, fields map[string]string) bool {
+	for _, match := range matches {
+		if match.matches(fields) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldMapping promotes journal fields to tags, service and source
+type fieldMapping struct {
+	tags         []fieldTag
+	serviceField string
+	sourceField  string
+}
+
+// fieldTag is a journal field promoted to a tag
+type fieldTag struct {
+	field string
+	tag   string
+}
+
+func newFieldMapping(logsConfig *config.LogsConfig) fieldMapping {
+	mapping := fieldMapping{
+		serviceField: strings.ToUpper(logsConfig.ServiceField),
+		sourceField:  strings.ToUpper(logsConfig.SourceField),
+	}
+	for field, tag := range logsConfig.FieldsToTags {
+		mapping.tags = append(mapping.tags, fieldTag{field: strings.ToUpper(field), tag: tag})
+	}
+	// keep the order of the tags stable
+	sort.Slice(mapping.tags, func(i, j int) bool { return mapping.tags[i].field < mapping.tags[j].field })
+	return mapping
+}
+
+// getTags returns the tags of the promoted fields of an entry
+func (m fieldMapping) getTags(fields map[string]string) []string {
+	var tags []string
+	for _, ft := range m.tags {
+		if value, exists := fields[ft.field]; exists && value != "" {
+			tags = append(tags, ft.tag+":"+value)
+		}
+	}
+	return tags
+}
+
+// getField returns the value of a promoted field of an entry
+func (m fieldMapping) getField(field string, fields map[string]string) (string, bool) {
+	if field == "" {
+		return "", false
+	}
+	value, exists := fields[field]
+	return value, exists && value != ""
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package journald
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+)
+
+func TestMatches(t *testing.T) {
+	matches, err := compileMatches([]map[string]string{
+		{"syslog_identifier": "sshd", "_TRANSPORT": "syslog"},
+		{"_COMM": "sudo"},
+		{"PRIORITY": "0..3"},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, matchesAny(matches, map[string]string{"SYSLOG_IDENTIFIER": "sshd", "_TRANSPORT": "syslog", "PRIORITY": "6"}))
+	assert.False(t, matchesAny(matches, map[string]string{"SYSLOG_IDENTIFIER": "sshd", "_TRANSPORT": "journal", "PRIORITY": "6"}))
+	assert.True(t, matchesAny(matches, map[string]string{"_COMM": "sudo"}))
+	assert.True(t, matchesAny(matches, map[string]string{"PRIORITY": "3"}))
+	assert.False(t, matchesAny(matches, map[string]string{"PRIORITY": "4"}))
+	assert.False(t, matchesAny(matches, map[string]string{"PRIORITY": "err"}))
+	assert.False(t, matchesAny(nil, map[string]string{"_COMM": "sudo"}))
+}
+
+func TestCompileInvalidMatches(t *testing.T) {
+	_, err := compileMatches([]map[string]string{{}})
+	assert.Error(t, err)
+	_, err = compileMatches([]map[string]string{{"PRIORITY": "0..err"}})
+	assert.Error(t, err)
+
+	// ranges are only supported on the priority
+	matches, err := compileMatches([]map[string]string{{"_PID": "1..3"}})
+	require.NoError(t, err)
+	assert.True(t, matchesAny(matches, map[string]string{"_PID": "1..3"}))
+	assert.False(t, matchesAny(matches, map[string]string{"_PID": "2"}))
+}
+
+func TestFieldMapping(t *testing.T) {
+	mapping := newFieldMapping(&config.LogsConfig{
+		FieldsToTags: map[string]string{"_systemd_unit": "unit", "_HOSTNAME": "journal_host"},
+		ServiceField: "syslog_identifier",
+		SourceField:  "_COMM",
+	})
+	fields := map[string]string{
+		"_SYSTEMD_UNIT":     "sshd.service",
+		"_HOSTNAME":         "web-1",
+		"SYSLOG_IDENTIFIER": "sshd",
+	}
+
+	assert.Equal(t, []string{"journal_host:web-1", "unit:sshd.service"}, mapping.getTags(fields))
+
+	service, found := mapping.getField(mapping.serviceField, fields)
+	assert.True(t, found)
+	assert.Equal(t, "sshd", service)
+	_, found = mapping.getField(mapping.sourceField, fields)
+	assert.False(t, found)
+	_, found = newFieldMapping(&config.LogsConfig{}).getField("", fields)
+	assert.False(t, found)
+}
@@ -33,8 +33,12 @@ type Tailer struct {
 	outputChan chan *message.Message
 	journal    *sdjournal.Journal
 	blacklist  map[string]bool
-	stop       chan struct{}
-	done       chan struct{}
+	// includeMatches and excludeMatches filter the entries on their fields
+	includeMatches []fieldMatch
+	excludeMatches []fieldMatch
+	fieldMapping   fieldMapping
+	stop           chan struct{}
+	done           chan struct{}
 }
 
 // NewTailer returns a new tailer.
@@ -79,6 +83,15 @@ func (t *Tailer) setup() error {
 
 	t.initializeTagger()
 
+	// compile the matches before opening the journal so that an invalid configuration doesn't leak it
+	if t.includeMatches, err = compileMatches(config.IncludeMatches); err != nil {
+		return fmt.Errorf("invalid include_matches: %s", err)
+	}
+	if t.excludeMatches, err = compileMatches(config.ExcludeMatches); err != nil {
+		return fmt.Errorf("invalid exclude_matches: %s", err)
+	}
+	t.fieldMapping = newFieldMapping(config)
+
 	if config.Path == "" {
 		// open the default journal
 		t.journal, err = sdjournal.NewJournal()
@@ -164,15 +177,16 @@ func (t *Tailer) tail() {
 // shouldDrop returns true if the entry should be dropped,
 // returns false otherwise.
 func (t *Tailer) shouldDrop(entry *sdjournal.JournalEntry) bool {
-	unit, exists := entry.Fields[sdjournal.SD_JOURNAL_FIELD_SYSTEMD_UNIT]
-	if !exists {
-		return false
+	if unit, exists := entry.Fields[sdjournal.SD_JOURNAL_FIELD_SYSTEMD_UNIT]; exists {
+		if _, blacklisted := t.blacklist[unit]; blacklisted {
+			// drop the entry
+			return true
+		}
 	}
-	if _, blacklisted := t.blacklist[unit]; blacklisted {
-		// drop the entry
+	if len(t.includeMatches) > 0 && !matchesAny(t.includeMatches, entry.Fields) {
 		return true
 	}
-	return false
+	return matchesAny(t.excludeMatches, entry.Fields)
 }
 
 // toMessage transforms a journal entry into a message.
@@ -229,6 +243,12 @@ func (t *Tailer) getOrigin(entry *sdjournal.JournalEntry) *message.Origin {
 	applicationName := t.getApplicationName(entry, tags)
 	origin.SetSource(applicationName)
 	origin.SetService(applicationName)
+	if source, found := t.fieldMapping.getField(t.fieldMapping.sourceField, entry.Fields); found {
+		origin.SetSource(source)
+	}
+	if service, found := t.fieldMapping.getField(t.fieldMapping.serviceField, entry.Fields); found {
+		origin.SetService(service)
+	}
 	origin.SetTags(tags)
 	return origin
 }
@@ -266,7 +286,7 @@ func (t *Tailer) getTags(entry *sdjournal.JournalEntry) []string {
 	if t.isContainerEntry(entry) {
 		tags = t.getContainerTags(t.getContainerID(entry))
 	}
-	return tags
+	return append(tags, t.fieldMapping.getTags(entry.Fields)...)
 }
 
 // priorityStatusMapping represents the 1:1 mapping between journal entry priorities and statuses.
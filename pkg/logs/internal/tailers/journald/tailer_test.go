@@ -63,6 +63,50 @@ func TestShouldDropEntry(t *testing.T) {
 		}))
 }
 
+func TestSetupInvalidMatches(t *testing.T) {
+	source := config.NewLogSource("", &config.LogsConfig{
+		IncludeMatches: []map[string]string{{sdjournal.SD_JOURNAL_FIELD_PRIORITY: "0..high"}},
+	})
+	tailer := NewTailer(source, nil)
+	assert.Error(t, tailer.setup())
+	// the journal isn't opened for an invalid configuration
+	assert.Nil(t, tailer.journal)
+}
+
+func TestShouldDropEntryWithMatches(t *testing.T) {
+	source := config.NewLogSource("", &config.LogsConfig{
+		IncludeMatches: []map[string]string{{sdjournal.SD_JOURNAL_FIELD_TRANSPORT: "syslog"}},
+		ExcludeMatches: []map[string]string{{sdjournal.SD_JOURNAL_FIELD_PRIORITY: "7..7"}},
+	})
+	tailer := NewTailer(source, nil)
+	err := tailer.setup()
+	assert.Nil(t, err)
+
+	assert.False(t, tailer.shouldDrop(
+		&sdjournal.JournalEntry{
+			Fields: map[string]string{
+				sdjournal.SD_JOURNAL_FIELD_TRANSPORT: "syslog",
+				sdjournal.SD_JOURNAL_FIELD_PRIORITY:  "6",
+			},
+		}))
+
+	assert.True(t, tailer.shouldDrop(
+		&sdjournal.JournalEntry{
+			Fields: map[string]string{
+				sdjournal.SD_JOURNAL_FIELD_TRANSPORT: "syslog",
+				sdjournal.SD_JOURNAL_FIELD_PRIORITY:  "7",
+			},
+		}))
+
+	assert.True(t, tailer.shouldDrop(
+		&sdjournal.JournalEntry{
+			Fields: map[string]string{
+				sdjournal.SD_JOURNAL_FIELD_TRANSPORT: "journal",
+				sdjournal.SD_JOURNAL_FIELD_PRIORITY:  "6",
+			},
+		}))
+}
+
 func TestApplicationName(t *testing.T) {
 	source := config.NewLogSource("", &config.LogsConfig{})
 	tailer := NewTailer(source, nil)
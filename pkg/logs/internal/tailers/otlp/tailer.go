@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package otlp
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+// Tailer forwards the log messages built by the OTLP pipeline to a stream of log messages.
+type Tailer struct {
+	source     *config.LogSource
+	inputChan  chan *message.Message
+	outputChan chan *message.Message
+	stop       chan interface{}
+	done       chan interface{}
+}
+
+// NewTailer returns a new Tailer
+func NewTailer(source *config.LogSource, inputChan chan *message.Message, outputChan chan *message.Message) *Tailer {
+	return &Tailer{
+		source:     source,
+		inputChan:  inputChan,
+		outputChan: outputChan,
+		stop:       make(chan interface{}),
+		done:       make(chan interface{}, 1),
+	}
+}
+
+// Start starts the tailer.
+func (t *Tailer) Start() {
+	go t.run()
+}
+
+// Stop stops the tailer and waits for the message being forwarded, if any.
+// The input channel is owned by the OTLP pipeline, so it is left open and
+// the pending messages are processed by the next tailer.
+func (t *Tailer) Stop() {
+	close(t.stop)
+	<-t.done
+}
+
+func (t *Tailer) run() {
+	defer func() {
+		t.done <- true
+	}()
+
+	for {
+		select {
+		case msg := <-t.inputChan:
+			// Messages are built without a log source by the OTLP pipeline.
+			msg.Origin.LogSource = t.source
+			t.source.BytesRead.Add(int64(len(msg.Content)))
+			t.outputChan <- msg
+		case <-t.stop:
+			return
+		}
+	}
+}
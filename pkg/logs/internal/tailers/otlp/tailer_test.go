@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package otlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+func TestTailerForwardsMessages(t *testing.T) {
+	source := config.NewLogSource(config.OTLP, &config.LogsConfig{Type: config.OTLPType, Source: "otlp", Tags: []string{"foo:bar"}})
+	inputChan := make(chan *message.Message, 1)
+	outputChan := make(chan *message.Message, 1)
+
+	tailer := NewTailer(source, inputChan, outputChan)
+	tailer.Start()
+
+	origin := message.NewOrigin(nil)
+	origin.SetService("my-service")
+	origin.SetTags([]string{"env:prod"})
+	msg := message.NewMessage([]byte(`{"message":"hello"}`), origin, message.StatusError, 0)
+	msg.Hostname = "my-host"
+	inputChan <- msg
+
+	out := <-outputChan
+	tailer.Stop()
+
+	assert.Equal(t, `{"message":"hello"}`, string(out.Content))
+	assert.Equal(t, message.StatusError, out.GetStatus())
+	assert.Equal(t, "my-host", out.GetHostname())
+	assert.Equal(t, "my-service", out.Origin.Service())
+	assert.Equal(t, "otlp", out.Origin.Source())
+	assert.Equal(t, "env:prod,foo:bar", out.Origin.TagsToString())
+	assert.Equal(t, int64(len(msg.Content)), source.BytesRead.Value())
+
+	// the input channel is left open for the next tailer
+	inputChan <- msg
+	assert.Len(t, inputChan, 1)
+}
@@ -20,15 +20,17 @@ type Tailer struct {
 	source     *config.LogSource
 	inputChan  traps.PacketsChannel
 	outputChan chan *message.Message
+	resolver   traps.OIDResolver
 	done       chan interface{}
 }
 
-// NewTailer returns a new Tailer
-func NewTailer(source *config.LogSource, inputChan traps.PacketsChannel, outputChan chan *message.Message) *Tailer {
+// NewTailer returns a new Tailer. The resolver is used to resolve trap OIDs, it may be nil.
+func NewTailer(source *config.LogSource, inputChan traps.PacketsChannel, outputChan chan *message.Message, resolver traps.OIDResolver) *Tailer {
 	return &Tailer{
 		source:     source,
 		inputChan:  inputChan,
 		outputChan: outputChan,
+		resolver:   resolver,
 		done:       make(chan interface{}, 1),
 	}
 }
@@ -50,7 +52,7 @@ func (t *Tailer) run() {
 
 	// Loop terminates when the channel is closed.
 	for packet := range t.inputChan {
-		data, err := traps.FormatPacketToJSON(packet)
+		data, err := traps.FormatPacketToJSON(packet, t.resolver)
 		if err != nil {
 			log.Errorf("failed to format packet: %s", err)
 			continue
@@ -22,6 +22,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/logs/config"
 	"github.com/DataDog/datadog-agent/pkg/logs/diagnostic"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
 	"github.com/DataDog/datadog-agent/pkg/logs/scheduler"
 	"github.com/DataDog/datadog-agent/pkg/logs/service"
 	"github.com/DataDog/datadog-agent/pkg/logs/status"
@@ -30,11 +31,14 @@ import (
 const (
 	// key used to display a warning message on the agent status
 	invalidProcessingRules = "invalid_global_processing_rules"
+	invalidRateLimits      = "invalid_global_rate_limits"
 	invalidEndpoints       = "invalid_endpoints"
 	intakeTrackType        = "logs"
 
 	// AgentJSONIntakeProtocol agent json protocol
 	AgentJSONIntakeProtocol = "agent-json"
+
+	otlpChanSize = 100
 )
 
 var (
@@ -42,8 +46,16 @@ var (
 	isRunning int32
 	// logs-agent
 	agent *Agent
+	// otlpChan receives the logs of the OTLP pipeline, it is created once as
+	// the OTLP pipeline can start before the logs-agent and outlive its restarts.
+	otlpChan = make(chan *message.Message, otlpChanSize)
 )
 
+// GetOTLPChannel returns the channel to send the logs received by the OTLP pipeline to.
+func GetOTLPChannel() chan *message.Message {
+	return otlpChan
+}
+
 // Start starts logs-agent
 // getAC is a func returning the prepared AutoConfig. It is nil until
 // the AutoConfig is ready, please consider using BlockUntilAutoConfigRanOnce
@@ -108,15 +120,23 @@ func start(getAC func() *autodiscovery.AutoConfig, serverless bool, logsChan cha
 		return errors.New(message)
 	}
 
+	// setup the rate limits of the services
+	rateLimits, err := config.GlobalRateLimits()
+	if err != nil {
+		message := fmt.Sprintf("Invalid rate limits: %v", err)
+		status.AddGlobalError(invalidRateLimits, message)
+		return errors.New(message)
+	}
+
 	// setup and start the logs agent
 	if !serverless {
 		// regular logs agent
 		log.Info("Starting logs-agent...")
-		agent = NewAgent(sources, services, processingRules, endpoints)
+		agent = NewAgent(sources, services, processingRules, rateLimits, endpoints)
 	} else {
 		// serverless logs agent
 		log.Info("Starting a serverless logs-agent...")
-		agent = NewServerless(sources, services, processingRules, endpoints)
+		agent = NewServerless(sources, services, processingRules, rateLimits, endpoints)
 	}
 
 	agent.Start()
@@ -141,6 +161,12 @@ func start(getAC func() *autodiscovery.AutoConfig, serverless bool, logsChan cha
 		sources.AddSource(source)
 	}
 
+	// add OTLP source forwarding the logs received by the OTLP pipeline if enabled.
+	if source := config.OTLPSource(); source != nil {
+		log.Debug("Adding OTLP source to the Logs Agent")
+		sources.AddSource(source)
+	}
+
 	// adds the source collecting logs from all containers if enabled,
 	// but ensure that it is enabled after the AutoConfig initialization
 	if source := config.ContainerCollectAllSource(); source != nil {
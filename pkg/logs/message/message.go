@@ -7,6 +7,7 @@ package message
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/logs/config"
@@ -23,6 +24,14 @@ type Payload struct {
 	Encoding string
 	// The size of the unencoded payload
 	UnencodedSize int
+	// acked is set once the payload has been sent to one of its reliable destinations
+	acked int32
+}
+
+// MarkAcked marks the payload as sent and returns true the first time it is called.
+// All the reliable destinations output the same payload once they have sent it.
+func (p *Payload) MarkAcked() bool {
+	return atomic.CompareAndSwapInt32(&p.acked, 0, 1)
 }
 
 // Message represents a log line sent to datadog, with its metadata
@@ -37,6 +46,13 @@ type Message struct {
 	// Optional.
 	// Used in the Serverless Agent
 	Lambda *Lambda
+	// Optional. Overrides the hostname of the Agent
+	// Used for the logs received by the OTLP pipeline
+	Hostname string
+	// Optional. Names of the routes matched by the processing rules
+	Routes []string
+	// pendingAcks is the number of routes the message still has to be sent to
+	pendingAcks int32
 }
 
 // Lambda is a struct storing information about the Lambda function and function execution.
@@ -84,6 +100,22 @@ func (m *Message) GetStatus() string {
 	return m.status
 }
 
+// SetPendingAcks sets the number of routes the message has to be sent to before its offset can be committed.
+func (m *Message) SetPendingAcks(count int) {
+	atomic.StoreInt32(&m.pendingAcks, int32(count))
+}
+
+// Ack acknowledges that the message has been sent to one of its routes,
+// it returns true once the message has been sent to all of them.
+func (m *Message) Ack() bool {
+	return atomic.AddInt32(&m.pendingAcks, -1) <= 0
+}
+
+// IsAcked returns true once the message has been sent to all its routes.
+func (m *Message) IsAcked() bool {
+	return atomic.LoadInt32(&m.pendingAcks) <= 0
+}
+
 // GetLatency returns the latency delta from ingestion time until now
 func (m *Message) GetLatency() int64 {
 	return time.Now().UnixNano() - m.IngestionTimestamp
@@ -94,6 +126,9 @@ func (m *Message) GetHostname() string {
 	if m.Lambda != nil {
 		return m.Lambda.ARN
 	}
+	if m.Hostname != "" {
+		return m.Hostname
+	}
 	hostname, err := util.GetHostname(context.TODO())
 	if err != nil {
 		// this scenario is not likely to happen since
@@ -25,6 +25,17 @@ var (
 	TlmLogsProcessed = telemetry.NewCounter("logs", "processed",
 		nil, "Total number of processed logs")
 
+	// LogsRateLimited is the total number of logs dropped by the rate limits.
+	LogsRateLimited = expvar.Int{}
+	// TlmLogsRateLimited is the total number of logs dropped by the rate limits.
+	TlmLogsRateLimited = telemetry.NewCounter("logs", "rate_limited",
+		nil, "Total number of logs dropped by the rate limits")
+	// LogsDeduplicated is the total number of consecutive identical logs dropped by the deduplication.
+	LogsDeduplicated = expvar.Int{}
+	// TlmLogsDeduplicated is the total number of consecutive identical logs dropped by the deduplication.
+	TlmLogsDeduplicated = telemetry.NewCounter("logs", "deduplicated",
+		nil, "Total number of consecutive identical logs dropped by the deduplication")
+
 	// LogsSent is the total number of sent logs.
 	LogsSent = expvar.Int{}
 	// TlmLogsSent is the total number of sent logs.
@@ -66,6 +77,8 @@ func init() {
 	LogsExpvars = expvar.NewMap("logs-agent")
 	LogsExpvars.Set("LogsDecoded", &LogsDecoded)
 	LogsExpvars.Set("LogsProcessed", &LogsProcessed)
+	LogsExpvars.Set("LogsRateLimited", &LogsRateLimited)
+	LogsExpvars.Set("LogsDeduplicated", &LogsDeduplicated)
 	LogsExpvars.Set("LogsSent", &LogsSent)
 	LogsExpvars.Set("DestinationErrors", &DestinationErrors)
 	LogsExpvars.Set("DestinationLogsDropped", &DestinationLogsDropped)
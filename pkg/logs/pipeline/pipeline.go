@@ -23,29 +23,22 @@ import (
 type Pipeline struct {
 	InputChan chan *message.Message
 	processor *processor.Processor
-	strategy  sender.Strategy
-	sender    *sender.Sender
+	lanes     []*lane
+	// router and acker are only set when routes are configured
+	router *router
+	acker  *acker
 }
 
 // NewPipeline returns a new Pipeline
 func NewPipeline(outputChan chan *message.Payload,
 	processingRules []*config.ProcessingRule,
+	rateLimits []*config.RateLimit,
 	endpoints *config.Endpoints,
 	destinationsContext *client.DestinationsContext,
 	diagnosticMessageReceiver diagnostic.MessageReceiver,
 	serverless bool,
 	pipelineID int) *Pipeline {
 
-	mainDestinations := getDestinations(endpoints, destinationsContext, pipelineID)
-
-	strategyInput := make(chan *message.Message, config.ChanSize)
-	senderInput := make(chan *message.Payload, 1) // Only buffer 1 message since payloads can be large
-
-	var logsSender *sender.Sender
-
-	strategy := getStrategy(strategyInput, senderInput, endpoints, serverless, pipelineID)
-	logsSender = sender.NewSender(senderInput, outputChan, mainDestinations, config.DestinationPayloadChanSize)
-
 	var encoder processor.Encoder
 	if serverless {
 		encoder = processor.JSONServerlessEncoder
@@ -58,28 +51,81 @@ func NewPipeline(outputChan chan *message.Payload,
 	}
 
 	inputChan := make(chan *message.Message, config.ChanSize)
-	processor := processor.New(inputChan, strategyInput, processingRules, encoder, diagnosticMessageReceiver)
+	mainDestinations := getDestinations(endpoints, destinationsContext, fmt.Sprintf("logs_%d", pipelineID))
+
+	if len(endpoints.Routes) == 0 {
+		mainLane := newLane(outputChan, mainDestinations, endpoints, serverless, pipelineID)
+		return &Pipeline{
+			InputChan: inputChan,
+			processor: processor.New(inputChan, mainLane.inputChan, processingRules, rateLimits, encoder, diagnosticMessageReceiver),
+			lanes:     []*lane{mainLane},
+		}
+	}
+
+	// The messages are sent to several lanes, the acker only forwards them to the auditor
+	// once all their lanes have sent them, in the order they were received for each origin.
+	ackChan := make(chan *message.Payload, config.ChanSize)
+	mainLane := newLane(ackChan, mainDestinations, endpoints, serverless, pipelineID)
+	lanes := []*lane{mainLane}
+	routeLanes := make(map[string]*lane, len(endpoints.Routes))
+	for _, route := range endpoints.Routes {
+		routeEndpoints := endpoints.ForRoute(route)
+		routeDestinations := getDestinations(routeEndpoints, destinationsContext, fmt.Sprintf("logs_%d_route_%s", pipelineID, route.Name))
+		routeLanes[route.Name] = newLane(ackChan, routeDestinations, routeEndpoints, serverless, pipelineID)
+		lanes = append(lanes, routeLanes[route.Name])
+	}
 
+	routerInput := make(chan *message.Message, config.ChanSize)
+	tracker := newAckTracker()
 	return &Pipeline{
 		InputChan: inputChan,
-		processor: processor,
-		strategy:  strategy,
-		sender:    logsSender,
+		processor: processor.New(inputChan, routerInput, processingRules, rateLimits, encoder, diagnosticMessageReceiver),
+		lanes:     lanes,
+		router:    newRouter(routerInput, mainLane, endpoints.Routes, routeLanes, tracker),
+		acker:     newAcker(ackChan, outputChan, tracker),
+	}
+}
+
+func newLane(outputChan chan *message.Payload, destinations *client.Destinations, endpoints *config.Endpoints, serverless bool, pipelineID int) *lane {
+	strategyInput := make(chan *message.Message, config.ChanSize)
+	senderInput := make(chan *message.Payload, 1) // Only buffer 1 message since payloads can be large
+
+	return &lane{
+		inputChan: strategyInput,
+		strategy:  getStrategy(strategyInput, senderInput, endpoints, serverless, pipelineID),
+		sender:    sender.NewSender(senderInput, outputChan, destinations, config.DestinationPayloadChanSize),
 	}
 }
 
 // Start launches the pipeline
 func (p *Pipeline) Start() {
-	p.sender.Start()
-	p.strategy.Start()
+	if p.acker != nil {
+		p.acker.start()
+	}
+	for _, l := range p.lanes {
+		l.start()
+	}
+	if p.router != nil {
+		p.router.start()
+	}
 	p.processor.Start()
 }
 
 // Stop stops the pipeline
 func (p *Pipeline) Stop() {
 	p.processor.Stop()
-	p.strategy.Stop()
-	p.sender.Stop()
+	if p.router != nil {
+		p.router.stop()
+	}
+	for _, l := range p.lanes {
+		l.strategy.Stop()
+	}
+	for _, l := range p.lanes {
+		l.sender.Stop()
+	}
+	if p.acker != nil {
+		p.acker.stop()
+	}
 }
 
 // Flush flushes synchronously the processor and sender managed by this pipeline.
@@ -87,17 +133,17 @@ func (p *Pipeline) Flush(ctx context.Context) {
 	p.processor.Flush(ctx) // flush messages in the processor into the sender
 }
 
-func getDestinations(endpoints *config.Endpoints, destinationsContext *client.DestinationsContext, pipelineID int) *client.Destinations {
+func getDestinations(endpoints *config.Endpoints, destinationsContext *client.DestinationsContext, telemetryPrefix string) *client.Destinations {
 	reliable := []client.Destination{}
 	additionals := []client.Destination{}
 
 	if endpoints.UseHTTP {
 		for i, endpoint := range endpoints.GetReliableEndpoints() {
-			telemetryName := fmt.Sprintf("logs_%d_reliable_%d", pipelineID, i)
+			telemetryName := fmt.Sprintf("%s_reliable_%d", telemetryPrefix, i)
 			reliable = append(reliable, http.NewDestination(endpoint, http.JSONContentType, destinationsContext, endpoints.BatchMaxConcurrentSend, true, telemetryName))
 		}
 		for i, endpoint := range endpoints.GetUnReliableEndpoints() {
-			telemetryName := fmt.Sprintf("logs_%d_unreliable_%d", pipelineID, i)
+			telemetryName := fmt.Sprintf("%s_unreliable_%d", telemetryPrefix, i)
 			additionals = append(additionals, http.NewDestination(endpoint, http.JSONContentType, destinationsContext, endpoints.BatchMaxConcurrentSend, false, telemetryName))
 		}
 		return client.NewDestinations(reliable, additionals)
@@ -0,0 +1,219 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package pipeline
+
+import (
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/sender"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// lane batches and sends the messages to a set of destinations
+type lane struct {
+	inputChan chan *message.Message
+	strategy  sender.Strategy
+	sender    *sender.Sender
+}
+
+func (l *lane) start() {
+	l.sender.Start()
+	l.strategy.Start()
+}
+
+// router dispatches the processed messages to the lane of the main endpoints
+// and to the lanes of the routes they match.
+type router struct {
+	inputChan chan *message.Message
+	main      *lane
+	routes    []config.Route
+	byName    map[string]config.Route
+	lanes     map[string]*lane
+	tracker   *ackTracker
+	done      chan struct{}
+}
+
+func newRouter(inputChan chan *message.Message, main *lane, routes []config.Route, lanes map[string]*lane, tracker *ackTracker) *router {
+	byName := make(map[string]config.Route, len(routes))
+	for _, route := range routes {
+		byName[route.Name] = route
+	}
+	return &router{
+		inputChan: inputChan,
+		main:      main,
+		routes:    routes,
+		byName:    byName,
+		lanes:     lanes,
+		tracker:   tracker,
+		done:      make(chan struct{}),
+	}
+}
+
+func (r *router) start() {
+	go r.run()
+}
+
+// stop stops the router, this call blocks until inputChan is flushed
+func (r *router) stop() {
+	close(r.inputChan)
+	<-r.done
+}
+
+func (r *router) run() {
+	defer close(r.done)
+	for msg := range r.inputChan {
+		lanes := r.lanesFor(msg)
+		// the offset of the message is committed once it has been sent by all its lanes
+		msg.SetPendingAcks(len(lanes))
+		r.tracker.add(msg)
+		for _, l := range lanes {
+			l.inputChan <- msg
+		}
+	}
+}
+
+// lanesFor returns the lanes a message must be sent to: the lanes of the routes set on its source,
+// of the routes set by the processing rules, and of the routes matching its origin.
+// The main lane is used unless one of these routes is exclusive.
+func (r *router) lanesFor(msg *message.Message) []*lane {
+	var names []string
+	names = append(names, msg.Origin.LogSource.Config.Routes...)
+	names = append(names, msg.Routes...)
+
+	source, service, tags := msg.Origin.Source(), msg.Origin.Service(), msg.Origin.Tags()
+	for _, route := range r.routes {
+		if route.Matches(source, service, tags) {
+			names = append(names, route.Name)
+		}
+	}
+
+	var lanes []*lane
+	exclusive := false
+	selected := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if _, found := selected[name]; found {
+			continue
+		}
+		route, found := r.byName[name]
+		if !found {
+			log.Debugf("Unknown route %s for a log of source %s", name, source)
+			continue
+		}
+		selected[name] = struct{}{}
+		exclusive = exclusive || route.Exclusive
+		lanes = append(lanes, r.lanes[name])
+	}
+
+	if !exclusive {
+		lanes = append([]*lane{r.main}, lanes...)
+	}
+	return lanes
+}
+
+// ackTracker keeps the order in which the messages of each origin were dispatched, so that
+// their offsets are committed in order even when their lanes don't send them at the same pace.
+type ackTracker struct {
+	mu      sync.Mutex
+	pending map[string][]*message.Message
+}
+
+func newAckTracker() *ackTracker {
+	return &ackTracker{
+		pending: make(map[string][]*message.Message),
+	}
+}
+
+// add records that a message of its origin was dispatched to its lanes
+func (t *ackTracker) add(msg *message.Message) {
+	if msg.Origin.Identifier == "" {
+		// the offsets of the origin aren't committed
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[msg.Origin.Identifier] = append(t.pending[msg.Origin.Identifier], msg)
+}
+
+// release returns the messages of the origin of msg, which has just been sent by all its lanes,
+// whose offsets can now be committed: the ones sent by all their lanes and not preceded by a message still in flight.
+func (t *ackTracker) release(msg *message.Message) []*message.Message {
+	if msg.Origin.Identifier == "" {
+		return []*message.Message{msg}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	pending := t.pending[msg.Origin.Identifier]
+	i := 0
+	for i < len(pending) && pending[i].IsAcked() {
+		i++
+	}
+	if i == 0 {
+		return nil
+	}
+	released := make([]*message.Message, i)
+	copy(released, pending[:i])
+	if i == len(pending) {
+		delete(t.pending, msg.Origin.Identifier)
+	} else {
+		t.pending[msg.Origin.Identifier] = pending[i:]
+	}
+	return released
+}
+
+// acker forwards the messages to the auditor once they, and all the messages dispatched before
+// them from the same origin, have been sent by all their lanes
+type acker struct {
+	inputChan  chan *message.Payload
+	outputChan chan *message.Payload
+	tracker    *ackTracker
+	done       chan struct{}
+}
+
+func newAcker(inputChan chan *message.Payload, outputChan chan *message.Payload, tracker *ackTracker) *acker {
+	return &acker{
+		inputChan:  inputChan,
+		outputChan: outputChan,
+		tracker:    tracker,
+		done:       make(chan struct{}),
+	}
+}
+
+func (a *acker) start() {
+	go a.run()
+}
+
+// stop stops the acker, this call blocks until inputChan is flushed
+func (a *acker) stop() {
+	close(a.inputChan)
+	<-a.done
+}
+
+func (a *acker) run() {
+	defer close(a.done)
+	for payload := range a.inputChan {
+		if !payload.MarkAcked() {
+			// already sent by another reliable destination of the lane
+			continue
+		}
+		var acked []*message.Message
+		for _, msg := range payload.Messages {
+			if msg.Ack() {
+				acked = append(acked, a.tracker.release(msg)...)
+			}
+		}
+		if len(acked) == 0 {
+			continue
+		}
+		a.outputChan <- &message.Payload{
+			Messages:      acked,
+			Encoded:       payload.Encoded,
+			Encoding:      payload.Encoding,
+			UnencodedSize: payload.UnencodedSize,
+		}
+	}
+}
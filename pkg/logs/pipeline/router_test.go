@@ -0,0 +1,113 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+func newTestRouter() (*router, *lane, map[string]*lane) {
+	routes := []config.Route{
+		{Name: "security", Exclusive: true, Match: config.RouteMatch{Sources: []string{"auth"}}},
+		{Name: "archive", Match: config.RouteMatch{Tags: []string{"env:dev"}}},
+		{Name: "debug"},
+	}
+	main := &lane{inputChan: make(chan *message.Message, 10)}
+	lanes := map[string]*lane{}
+	for _, route := range routes {
+		lanes[route.Name] = &lane{inputChan: make(chan *message.Message, 10)}
+	}
+	return newRouter(make(chan *message.Message, 10), main, routes, lanes, newAckTracker()), main, lanes
+}
+
+func newRoutedMessage(logsConfig *config.LogsConfig, routes ...string) *message.Message {
+	msg := message.NewMessageWithSource([]byte("hello"), message.StatusInfo, config.NewLogSource("", logsConfig), 0)
+	msg.Routes = routes
+	return msg
+}
+
+func TestRouterLanes(t *testing.T) {
+	r, main, lanes := newTestRouter()
+
+	assert.Equal(t, []*lane{main}, r.lanesFor(newRoutedMessage(&config.LogsConfig{Source: "nginx"})))
+	assert.Equal(t, []*lane{main}, r.lanesFor(newRoutedMessage(&config.LogsConfig{Source: "nginx"}, "unknown")))
+	assert.Equal(t, []*lane{lanes["security"]}, r.lanesFor(newRoutedMessage(&config.LogsConfig{Source: "auth"})))
+	assert.Equal(t, []*lane{main, lanes["archive"]}, r.lanesFor(newRoutedMessage(&config.LogsConfig{Tags: []string{"env:dev"}})))
+	assert.Equal(t, []*lane{main, lanes["debug"]}, r.lanesFor(newRoutedMessage(&config.LogsConfig{}, "debug", "debug")))
+	assert.Equal(t, []*lane{lanes["debug"], lanes["security"]}, r.lanesFor(newRoutedMessage(&config.LogsConfig{Routes: []string{"debug", "security"}})))
+}
+
+func TestRouterDispatchesAndAcks(t *testing.T) {
+	r, main, lanes := newTestRouter()
+	ackChan := make(chan *message.Payload, 10)
+	outputChan := make(chan *message.Payload, 10)
+	a := newAcker(ackChan, outputChan, r.tracker)
+	r.start()
+	a.start()
+
+	routed := newRoutedMessage(&config.LogsConfig{}, "debug")
+	notRouted := newRoutedMessage(&config.LogsConfig{})
+	r.inputChan <- routed
+	r.inputChan <- notRouted
+	r.stop()
+
+	assert.Equal(t, routed, <-main.inputChan)
+	assert.Equal(t, notRouted, <-main.inputChan)
+	assert.Equal(t, routed, <-lanes["debug"].inputChan)
+
+	// the payload of the main lane is output by two reliable destinations
+	mainPayload := &message.Payload{Messages: []*message.Message{routed, notRouted}}
+	ackChan <- mainPayload
+	ackChan <- mainPayload
+	ackChan <- &message.Payload{Messages: []*message.Message{routed}}
+	a.stop()
+
+	assert.Equal(t, []*message.Message{notRouted}, (<-outputChan).Messages)
+	assert.Equal(t, []*message.Message{routed}, (<-outputChan).Messages)
+	assert.Empty(t, outputChan)
+}
+
+func TestAckerReleasesInOrderPerOrigin(t *testing.T) {
+	r, main, lanes := newTestRouter()
+	ackChan := make(chan *message.Payload, 10)
+	outputChan := make(chan *message.Payload, 10)
+	a := newAcker(ackChan, outputChan, r.tracker)
+	r.start()
+	a.start()
+	defer a.stop()
+
+	newMessage := func(identifier string, routes ...string) *message.Message {
+		msg := newRoutedMessage(&config.LogsConfig{}, routes...)
+		msg.Origin.Identifier = identifier
+		return msg
+	}
+	first := newMessage("file:/var/log/a.log", "debug")
+	second := newMessage("file:/var/log/a.log")
+	third := newMessage("file:/var/log/a.log")
+	other := newMessage("file:/var/log/b.log")
+	for _, msg := range []*message.Message{first, second, third, other} {
+		r.inputChan <- msg
+	}
+	r.stop()
+	assert.Len(t, main.inputChan, 4)
+	assert.Len(t, lanes["debug"].inputChan, 1)
+
+	// the main lane is faster than the debug lane: the messages following the first one
+	// are held until the debug lane has sent it, other origins aren't held
+	ackChan <- &message.Payload{Messages: []*message.Message{first, second}}
+	ackChan <- &message.Payload{Messages: []*message.Message{third, other}}
+	assert.Equal(t, []*message.Message{other}, (<-outputChan).Messages)
+	assert.Empty(t, outputChan)
+
+	ackChan <- &message.Payload{Messages: []*message.Message{first}}
+	assert.Equal(t, []*message.Message{first, second, third}, (<-outputChan).Messages)
+	assert.Empty(t, r.tracker.pending)
+}
@@ -8,6 +8,7 @@ package processor
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
@@ -23,21 +24,31 @@ type Processor struct {
 	inputChan                 chan *message.Message
 	outputChan                chan *message.Message
 	processingRules           []*config.ProcessingRule
+	serviceRateLimits         map[string]*config.RateLimit
 	encoder                   Encoder
 	done                      chan struct{}
 	diagnosticMessageReceiver diagnostic.MessageReceiver
 	mu                        sync.Mutex
+	// repeats are the last lines of the origins whose consecutive identical lines are deduplicated
+	repeats   map[repeatsKey]*lineRepeats
+	repeatsMu sync.Mutex
 }
 
 // New returns an initialized Processor.
-func New(inputChan, outputChan chan *message.Message, processingRules []*config.ProcessingRule, encoder Encoder, diagnosticMessageReceiver diagnostic.MessageReceiver) *Processor {
+func New(inputChan, outputChan chan *message.Message, processingRules []*config.ProcessingRule, rateLimits []*config.RateLimit, encoder Encoder, diagnosticMessageReceiver diagnostic.MessageReceiver) *Processor {
+	serviceRateLimits := make(map[string]*config.RateLimit, len(rateLimits))
+	for _, rateLimit := range rateLimits {
+		serviceRateLimits[rateLimit.Service] = rateLimit
+	}
 	return &Processor{
 		inputChan:                 inputChan,
 		outputChan:                outputChan,
 		processingRules:           processingRules,
+		serviceRateLimits:         serviceRateLimits,
 		encoder:                   encoder,
 		done:                      make(chan struct{}),
 		diagnosticMessageReceiver: diagnosticMessageReceiver,
+		repeats:                   make(map[repeatsKey]*lineRepeats),
 	}
 }
 
@@ -63,6 +74,7 @@ func (p *Processor) Flush(ctx context.Context) {
 			return
 		default:
 			if len(p.inputChan) == 0 {
+				p.flushRepeats(time.Now(), 0)
 				return
 			}
 			msg := <-p.inputChan
@@ -76,16 +88,40 @@ func (p *Processor) run() {
 	defer func() {
 		p.done <- struct{}{}
 	}()
-	for msg := range p.inputChan {
-		p.processMessage(msg)
-		p.mu.Lock() // block here if we're trying to flush synchronously
-		p.mu.Unlock()
+	ticker := time.NewTicker(repeatsFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg, ok := <-p.inputChan:
+			if !ok {
+				// the repetitions still pending are summarized before stopping
+				p.flushRepeats(time.Now(), 0)
+				return
+			}
+			p.processMessage(msg)
+			p.mu.Lock() // block here if we're trying to flush synchronously
+			p.mu.Unlock()
+		case now := <-ticker.C:
+			p.mu.Lock()
+			p.flushRepeats(now, repeatsTimeout)
+			p.mu.Unlock()
+		}
 	}
 }
 
 func (p *Processor) processMessage(msg *message.Message) {
 	metrics.LogsDecoded.Add(1)
 	metrics.TlmLogsDecoded.Inc()
+	shouldProcess, summary := p.throttle(msg)
+	if summary != nil {
+		p.processDecodedMessage(summary)
+	}
+	if shouldProcess {
+		p.processDecodedMessage(msg)
+	}
+}
+
+func (p *Processor) processDecodedMessage(msg *message.Message) {
 	if shouldProcess, redactedMsg := p.applyRedactingRules(msg); shouldProcess {
 		metrics.LogsProcessed.Add(1)
 		metrics.TlmLogsProcessed.Inc()
@@ -120,6 +156,10 @@ func (p *Processor) applyRedactingRules(msg *message.Message) (bool, []byte) {
 			}
 		case config.MaskSequences:
 			content = rule.Regex.ReplaceAll(content, rule.Placeholder)
+		case config.RouteAtMatch:
+			if rule.Regex.Match(content) {
+				msg.Routes = append(msg.Routes, rule.Route)
+			}
 		}
 	}
 	return true, content
@@ -128,6 +128,24 @@ func TestMask(t *testing.T) {
 	assert.Equal(t, []byte("New data added to data_values= on prod"), redactedMessage)
 }
 
+func TestRouting(t *testing.T) {
+	p := &Processor{}
+
+	rule := newProcessingRule(config.RouteAtMatch, "", "audit")
+	rule.Route = "security"
+	source := config.LogSource{Config: &config.LogsConfig{ProcessingRules: []*config.ProcessingRule{rule}}}
+
+	msg := newMessage([]byte("user logged in"), &source, "")
+	shouldProcess, _ := p.applyRedactingRules(msg)
+	assert.Equal(t, true, shouldProcess)
+	assert.Empty(t, msg.Routes)
+
+	msg = newMessage([]byte("audit: user logged in"), &source, "")
+	shouldProcess, _ = p.applyRedactingRules(msg)
+	assert.Equal(t, true, shouldProcess)
+	assert.Equal(t, []string{"security"}, msg.Routes)
+}
+
 func TestTruncate(t *testing.T) {
 	p := &Processor{}
 
@@ -0,0 +1,171 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/metrics"
+)
+
+const (
+	rateLimitedInfoKey  = "Rate limited logs"
+	deduplicatedInfoKey = "Deduplicated logs"
+
+	// repeatsTimeout is how long after its last repetition a line is summarized when no other line follows it
+	repeatsTimeout = 5 * time.Second
+	// repeatsFlushInterval is how often the lines repeated for longer than repeatsTimeout are looked for
+	repeatsFlushInterval = time.Second
+)
+
+// repeatsKey identifies the origin whose consecutive identical lines are deduplicated:
+// the tailers of a source, e.g. one per file matching a wildcard, each have their own.
+type repeatsKey struct {
+	source     *config.LogSource
+	identifier string
+}
+
+// lineRepeats tracks the repetitions of the last line of an origin
+type lineRepeats struct {
+	content []byte
+	status  string
+	count   int
+	// origin and ingestionTimestamp are the ones of the last repetition of the line
+	origin             message.Origin
+	ingestionTimestamp int64
+	updated            time.Time
+}
+
+// throttle deduplicates the consecutive identical lines of the source of the message,
+// and applies the rate limits of its source and of its service.
+// It returns false if the message must be dropped, and the summary of the repetitions
+// of the previous line, if any, to send before the message.
+func (p *Processor) throttle(msg *message.Message) (bool, *message.Message) {
+	source := msg.Origin.LogSource
+
+	var summary *message.Message
+	if source.Config.DeduplicateLines {
+		var duplicate bool
+		if duplicate, summary = p.deduplicate(msg, time.Now()); duplicate {
+			metrics.LogsDeduplicated.Add(1)
+			metrics.TlmLogsDeduplicated.Inc()
+			sourceCountInfo(source, deduplicatedInfoKey).Add(1)
+			return false, nil
+		}
+	}
+
+	if !allow(msg, source.Config.RateLimit) || !allow(msg, p.serviceRateLimits[msg.Origin.Service()]) {
+		metrics.LogsRateLimited.Add(1)
+		metrics.TlmLogsRateLimited.Inc()
+		sourceCountInfo(source, rateLimitedInfoKey).Add(1)
+		return false, summary
+	}
+	return true, summary
+}
+
+// allow returns true if the message is within the rate limit, or must be kept according to its overflow policy
+func allow(msg *message.Message, rateLimit *config.RateLimit) bool {
+	if rateLimit == nil || rateLimit.Allow(len(msg.Content)) {
+		return true
+	}
+	switch rateLimit.Overflow {
+	case config.OverflowSample:
+		return rateLimit.Sample()
+	case config.OverflowErrorsOnly:
+		return isError(msg.GetStatus())
+	default:
+		return false
+	}
+}
+
+func isError(status string) bool {
+	switch status {
+	case message.StatusEmergency, message.StatusAlert, message.StatusCritical, message.StatusError:
+		return true
+	default:
+		return false
+	}
+}
+
+// deduplicate records a line of the origin of msg and returns true if it is identical to the previous line
+// of this origin. When it differs, it returns the summary of the repetitions of the previous line, if any.
+func (p *Processor) deduplicate(msg *message.Message, now time.Time) (bool, *message.Message) {
+	p.repeatsMu.Lock()
+	defer p.repeatsMu.Unlock()
+	if p.repeats == nil {
+		p.repeats = make(map[repeatsKey]*lineRepeats)
+	}
+
+	key := repeatsKey{source: msg.Origin.LogSource, identifier: msg.Origin.Identifier}
+	last, found := p.repeats[key]
+	if found && bytes.Equal(last.content, msg.Content) {
+		last.count++
+		last.origin = *msg.Origin
+		last.ingestionTimestamp = msg.IngestionTimestamp
+		last.updated = now
+		return true, nil
+	}
+
+	var summary *message.Message
+	if found && last.count > 0 {
+		summary = newRepeatsSummary(last)
+	}
+	p.repeats[key] = &lineRepeats{
+		content: append([]byte(nil), msg.Content...),
+		status:  msg.GetStatus(),
+		updated: now,
+	}
+	return false, summary
+}
+
+// expireRepeats forgets the lines which were last received more than timeout before now, e.g. because
+// their tailer stopped, and returns the summaries of those which were repeated.
+func (p *Processor) expireRepeats(now time.Time, timeout time.Duration) []*message.Message {
+	p.repeatsMu.Lock()
+	defer p.repeatsMu.Unlock()
+
+	var summaries []*message.Message
+	for key, last := range p.repeats {
+		if now.Sub(last.updated) < timeout {
+			continue
+		}
+		if last.count > 0 {
+			summaries = append(summaries, newRepeatsSummary(last))
+		}
+		delete(p.repeats, key)
+	}
+	return summaries
+}
+
+// flushRepeats sends the summaries of the lines which were last received more than timeout before now.
+func (p *Processor) flushRepeats(now time.Time, timeout time.Duration) {
+	for _, summary := range p.expireRepeats(now, timeout) {
+		p.processDecodedMessage(summary)
+	}
+}
+
+// newRepeatsSummary returns a message summarizing the repetitions of a line.
+// Its origin is the one of the last repetition so that the registry skips all of them.
+func newRepeatsSummary(last *lineRepeats) *message.Message {
+	origin := last.origin
+	content := []byte(fmt.Sprintf("Last message repeated %d times", last.count))
+	return message.NewMessage(content, &origin, last.status, last.ingestionTimestamp)
+}
+
+// sourceCountInfo returns the count displayed on the status page for the given key,
+// it is shared by all the pipelines processing the logs of the source.
+func sourceCountInfo(source *config.LogSource, key string) *config.CountInfo {
+	if info, ok := source.GetInfo(key).(*config.CountInfo); ok {
+		return info
+	}
+	info := config.NewCountInfo(key)
+	source.RegisterInfo(info)
+	return info
+}
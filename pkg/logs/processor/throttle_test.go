@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+func newOffsetMessage(content string, source *config.LogSource, status string, offset string) *message.Message {
+	msg := newMessage([]byte(content), source, status)
+	msg.Origin.Offset = offset
+	return msg
+}
+
+func newFileMessage(content string, source *config.LogSource, identifier string, offset string) *message.Message {
+	msg := newOffsetMessage(content, source, message.StatusInfo, offset)
+	msg.Origin.Identifier = identifier
+	return msg
+}
+
+func TestThrottleDeduplicatesLines(t *testing.T) {
+	p := &Processor{}
+	source := config.NewLogSource("", &config.LogsConfig{DeduplicateLines: true})
+
+	keep, summary := p.throttle(newOffsetMessage("panic", source, message.StatusError, "1"))
+	assert.True(t, keep)
+	assert.Nil(t, summary)
+	for _, offset := range []string{"2", "3", "4"} {
+		keep, summary = p.throttle(newOffsetMessage("panic", source, message.StatusError, offset))
+		assert.False(t, keep)
+		assert.Nil(t, summary)
+	}
+
+	keep, summary = p.throttle(newOffsetMessage("restarting", source, message.StatusInfo, "5"))
+	assert.True(t, keep)
+	require.NotNil(t, summary)
+	assert.Equal(t, "Last message repeated 3 times", string(summary.Content))
+	assert.Equal(t, message.StatusError, summary.GetStatus())
+	assert.Equal(t, "4", summary.Origin.Offset)
+	assert.Equal(t, []string{"3"}, source.GetInfo(deduplicatedInfoKey).Info())
+}
+
+func TestThrottleDeduplicatesLinesPerOrigin(t *testing.T) {
+	p := New(nil, nil, nil, nil, nil, nil)
+	source := config.NewLogSource("", &config.LogsConfig{DeduplicateLines: true})
+	now := time.Now()
+
+	// the files matching the wildcard of the source are deduplicated separately
+	for _, identifier := range []string{"file:/var/log/a.log", "file:/var/log/b.log"} {
+		duplicate, summary := p.deduplicate(newFileMessage("retrying", source, identifier, "1"), now)
+		assert.False(t, duplicate)
+		assert.Nil(t, summary)
+	}
+	duplicate, _ := p.deduplicate(newFileMessage("retrying", source, "file:/var/log/a.log", "2"), now)
+	assert.True(t, duplicate)
+	duplicate, _ = p.deduplicate(newFileMessage("retrying", source, "file:/var/log/b.log", "7"), now)
+	assert.True(t, duplicate)
+	duplicate, _ = p.deduplicate(newFileMessage("retrying", source, "file:/var/log/a.log", "3"), now.Add(2*time.Second))
+	assert.True(t, duplicate)
+
+	duplicate, summary := p.deduplicate(newFileMessage("connected", source, "file:/var/log/b.log", "8"), now)
+	assert.False(t, duplicate)
+	require.NotNil(t, summary)
+	assert.Equal(t, "Last message repeated 1 times", string(summary.Content))
+	assert.Equal(t, "file:/var/log/b.log", summary.Origin.Identifier)
+	assert.Equal(t, "7", summary.Origin.Offset)
+
+	// the trailing repetitions are summarized once the line isn't received anymore
+	assert.Empty(t, p.expireRepeats(now.Add(repeatsTimeout), repeatsTimeout))
+	summaries := p.expireRepeats(now.Add(2*time.Second+repeatsTimeout), repeatsTimeout)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "Last message repeated 2 times", string(summaries[0].Content))
+	assert.Equal(t, "file:/var/log/a.log", summaries[0].Origin.Identifier)
+	assert.Equal(t, "3", summaries[0].Origin.Offset)
+	assert.Empty(t, p.repeats)
+}
+
+func TestThrottleRateLimits(t *testing.T) {
+	sourceRateLimit := &config.RateLimit{MessagesPerSecond: 1, Overflow: config.OverflowErrorsOnly}
+	require.NoError(t, config.ValidateRateLimit(sourceRateLimit))
+	source := config.NewLogSource("", &config.LogsConfig{RateLimit: sourceRateLimit})
+
+	p := New(nil, nil, nil, nil, nil, nil)
+	keep, _ := p.throttle(newMessage([]byte("first"), source, message.StatusInfo))
+	assert.True(t, keep)
+	keep, _ = p.throttle(newMessage([]byte("second"), source, message.StatusInfo))
+	assert.False(t, keep)
+	keep, _ = p.throttle(newMessage([]byte("failure"), source, message.StatusError))
+	assert.True(t, keep)
+	assert.Equal(t, []string{"1"}, source.GetInfo(rateLimitedInfoKey).Info())
+
+	serviceRateLimit := &config.RateLimit{Service: "api", BytesPerSecond: 10}
+	require.NoError(t, config.ValidateRateLimit(serviceRateLimit))
+	source = config.NewLogSource("", &config.LogsConfig{Service: "api"})
+	other := config.NewLogSource("", &config.LogsConfig{Service: "web"})
+
+	p = New(nil, nil, nil, []*config.RateLimit{serviceRateLimit}, nil, nil)
+	keep, _ = p.throttle(newMessage([]byte("0123456789"), source, message.StatusInfo))
+	assert.True(t, keep)
+	keep, _ = p.throttle(newMessage([]byte("0123456789"), source, message.StatusInfo))
+	assert.False(t, keep)
+	keep, _ = p.throttle(newMessage([]byte("0123456789"), other, message.StatusInfo))
+	assert.True(t, keep)
+}
@@ -0,0 +1,179 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package packages
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/packagedb"
+)
+
+const (
+	dpkgManager = "dpkg"
+	apkManager  = "apk"
+)
+
+// fullPayloadInterval is the interval at which the full inventory is sent, even if it
+// didn't change, so that a lost payload doesn't leave the inventory out of sync for long.
+const fullPayloadInterval = 24 * time.Hour
+
+var (
+	// sentPackages are the packages of the last payload that was sent, by key
+	sentPackages map[string]Package
+	// lastFullPayload is the time the last full payload was sent
+	lastFullPayload time.Time
+	// lastInventory is the last collected list of packages
+	lastInventory  []Package
+	inventoryMutex = &sync.Mutex{}
+)
+
+var (
+	// For testing purposes
+	timeNow = time.Now
+)
+
+// CollectPackages returns the packages installed on the host whose filesystem is
+// mounted at hostRoot, from the dpkg and apk databases found on the host.
+// Hosts with only an rpm database are reported as unsupported.
+func CollectPackages(hostRoot string) ([]Package, error) {
+	var pkgs []Package
+	found := false
+
+	databases := []struct {
+		path    string
+		manager string
+		parser  func(io.Reader) ([]packagedb.Package, error)
+	}{
+		{packagedb.DpkgStatusPath, dpkgManager, packagedb.ParseDpkgStatus},
+		{packagedb.ApkInstalledPath, apkManager, packagedb.ParseApkInstalled},
+	}
+	for _, db := range databases {
+		path := filepath.Join(hostRoot, db.path)
+		if !fileExists(path) {
+			continue
+		}
+		found = true
+
+		dbPkgs, err := packagedb.ReadFile(path, db.parser)
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range dbPkgs {
+			pkgs = append(pkgs, Package{
+				Name:    pkg.Name,
+				Version: pkg.Version,
+				Arch:    pkg.Arch,
+				Source:  pkg.Source,
+				Manager: db.manager,
+			})
+		}
+	}
+
+	if fileExists(filepath.Join(hostRoot, packagedb.RpmDatabasePath)) {
+		if !found {
+			return nil, packagedb.ErrRPMDatabaseUnsupported
+		}
+		log.Debugf("Ignoring the rpm database of the host: %s", packagedb.ErrRPMDatabaseUnsupported)
+	}
+
+	sortPackages(pkgs)
+	return pkgs, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func sortPackages(pkgs []Package) {
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].key() < pkgs[j].key()
+	})
+}
+
+// GetPayload collects the packages installed on the host and returns a payload with the
+// changes since the last payload that was sent, or with the full inventory if none was sent
+// or if the last full one is too old. It returns nil when there is no change to send.
+func GetPayload(hostname string, hostRoot string) (*Payload, error) {
+	pkgs, err := CollectPackages(hostRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	inventoryMutex.Lock()
+	defer inventoryMutex.Unlock()
+
+	lastInventory = append([]Package{}, pkgs...)
+
+	payload := &Payload{
+		Hostname:  hostname,
+		Timestamp: timeNow().UnixNano(),
+		Added:     []Package{},
+		Removed:   []Package{},
+	}
+
+	if sentPackages == nil || timeNow().Sub(lastFullPayload) >= fullPayloadInterval {
+		payload.Full = true
+		payload.Added = append(payload.Added, pkgs...)
+		return payload, nil
+	}
+
+	current := make(map[string]struct{}, len(pkgs))
+	for _, pkg := range pkgs {
+		current[pkg.key()] = struct{}{}
+		if _, found := sentPackages[pkg.key()]; !found {
+			payload.Added = append(payload.Added, pkg)
+		}
+	}
+	for key, pkg := range sentPackages {
+		if _, found := current[key]; !found {
+			payload.Removed = append(payload.Removed, pkg)
+		}
+	}
+
+	if len(payload.Added) == 0 && len(payload.Removed) == 0 {
+		return nil, nil
+	}
+
+	sortPackages(payload.Removed)
+	return payload, nil
+}
+
+// MarkSent records a payload returned by GetPayload as successfully sent, the next
+// payloads only contain the changes since this one.
+func MarkSent(payload *Payload) {
+	inventoryMutex.Lock()
+	defer inventoryMutex.Unlock()
+
+	if payload.Full {
+		sentPackages = make(map[string]Package, len(payload.Added))
+		lastFullPayload = timeNow()
+	}
+	for _, pkg := range payload.Removed {
+		delete(sentPackages, pkg.key())
+	}
+	for _, pkg := range payload.Added {
+		sentPackages[pkg.key()] = pkg
+	}
+}
+
+// GetLastInventory returns the last list of packages collected as JSON.
+func GetLastInventory() ([]byte, error) {
+	inventoryMutex.Lock()
+	defer inventoryMutex.Unlock()
+
+	if lastInventory == nil {
+		return []byte("no software inventory was collected yet"), nil
+	}
+	return json.MarshalIndent(lastInventory, "", "    ")
+}
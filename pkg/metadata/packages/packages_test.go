@@ -0,0 +1,148 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package packages
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/util/packagedb"
+)
+
+var (
+	libssl     = Package{Name: "libssl1.1", Version: "1.1.1n-0+deb11u3", Arch: "amd64", Source: "openssl", Manager: "dpkg"}
+	bash       = Package{Name: "bash", Version: "5.1-2+deb11u1", Arch: "amd64", Source: "bash", Manager: "dpkg"}
+	libsystemd = Package{Name: "libsystemd0", Version: "247.3-7+deb11u1", Arch: "amd64", Source: "systemd", Manager: "dpkg"}
+)
+
+// writeDpkgStatus writes the test dpkg status database under root and returns its path and content
+func writeDpkgStatus(t *testing.T, root string) (string, []byte) {
+	statusPath := filepath.Join(root, packagedb.DpkgStatusPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(statusPath), 0755))
+	status, err := os.ReadFile("../../util/packagedb/testdata/dpkg/var/lib/dpkg/status")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(statusPath, status, 0644))
+	return statusPath, status
+}
+
+func resetState() {
+	inventoryMutex.Lock()
+	defer inventoryMutex.Unlock()
+
+	sentPackages = nil
+	lastFullPayload = time.Time{}
+	lastInventory = nil
+}
+
+func TestCollectPackages(t *testing.T) {
+	pkgs, err := CollectPackages("../../util/packagedb/testdata/apk")
+	require.NoError(t, err)
+	assert.Equal(t, []Package{
+		{Name: "libcrypto1.1", Version: "1.1.1q-r0", Arch: "x86_64", Source: "openssl", Manager: "apk"},
+		{Name: "musl", Version: "1.2.3-r0", Arch: "x86_64", Source: "musl", Manager: "apk"},
+	}, pkgs)
+}
+
+func TestCollectPackagesRpm(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, packagedb.RpmDatabasePath), 0755))
+
+	_, err := CollectPackages(root)
+	assert.Equal(t, packagedb.ErrRPMDatabaseUnsupported, err)
+
+	// the rpm database is ignored when another database is found
+	writeDpkgStatus(t, root)
+	pkgs, err := CollectPackages(root)
+	require.NoError(t, err)
+	assert.Equal(t, []Package{bash, libssl, libsystemd}, pkgs)
+}
+
+func TestCollectPackagesNoDatabase(t *testing.T) {
+	pkgs, err := CollectPackages(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, pkgs)
+}
+
+func TestGetPayload(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	now := time.Now()
+	defer func() { timeNow = time.Now }()
+	timeNow = func() time.Time { return now }
+
+	root := t.TempDir()
+	statusPath, status := writeDpkgStatus(t, root)
+
+	// first payload contains the full inventory
+	payload, err := GetPayload("my-host", root)
+	require.NoError(t, err)
+	require.NotNil(t, payload)
+	assert.Equal(t, "my-host", payload.Hostname)
+	assert.True(t, payload.Full)
+	assert.Equal(t, []Package{bash, libssl, libsystemd}, payload.Added)
+	assert.Empty(t, payload.Removed)
+
+	// the full inventory is sent again until a payload is marked as sent
+	payload, err = GetPayload("my-host", root)
+	require.NoError(t, err)
+	assert.True(t, payload.Full)
+	MarkSent(payload)
+
+	// no change
+	payload, err = GetPayload("my-host", root)
+	require.NoError(t, err)
+	assert.Nil(t, payload)
+
+	// libssl is upgraded and bash is removed
+	upgraded := strings.Replace(string(status), "1.1.1n-0+deb11u3", "1.1.1n-0+deb11u4", 1)
+	upgraded = strings.Replace(upgraded, "Package: bash\nEssential: yes\nStatus: install ok installed", "Package: bash\nEssential: yes\nStatus: deinstall ok config-files", 1)
+	require.NoError(t, os.WriteFile(statusPath, []byte(upgraded), 0644))
+
+	newLibssl := libssl
+	newLibssl.Version = "1.1.1n-0+deb11u4"
+
+	payload, err = GetPayload("my-host", root)
+	require.NoError(t, err)
+	require.NotNil(t, payload)
+	assert.False(t, payload.Full)
+	assert.Equal(t, []Package{newLibssl}, payload.Added)
+	assert.Equal(t, []Package{bash, libssl}, payload.Removed)
+	MarkSent(payload)
+
+	payload, err = GetPayload("my-host", root)
+	require.NoError(t, err)
+	assert.Nil(t, payload)
+
+	// the full inventory is sent again once a day
+	now = now.Add(fullPayloadInterval)
+	payload, err = GetPayload("my-host", root)
+	require.NoError(t, err)
+	require.NotNil(t, payload)
+	assert.True(t, payload.Full)
+	assert.Equal(t, []Package{newLibssl, libsystemd}, payload.Added)
+
+	var inventory []Package
+	content, err := GetLastInventory()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &inventory))
+	assert.Equal(t, []Package{newLibssl, libsystemd}, inventory)
+}
+
+func TestGetLastInventoryEmpty(t *testing.T) {
+	resetState()
+
+	content, err := GetLastInventory()
+	require.NoError(t, err)
+	assert.Equal(t, "no software inventory was collected yet", string(content))
+}
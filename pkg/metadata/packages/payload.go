@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package packages
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/serializer/marshaler"
+)
+
+// Package is a package installed on the host
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Arch    string `json:"arch,omitempty"`
+	// Source is the source package the package was built from
+	Source string `json:"source,omitempty"`
+	// Manager is the package manager the package was installed with: dpkg, rpm or apk
+	Manager string `json:"manager"`
+}
+
+// key returns a key identifying an installed version of a package
+func (p Package) key() string {
+	return p.Manager + "/" + p.Name + "/" + p.Arch + "/" + p.Version
+}
+
+// Payload handles the JSON unmarshalling of the software inventory metadata payload.
+// When Full is true, Added contains all the packages installed on the host and replaces
+// any previous inventory. Otherwise, Added and Removed contain the changes since the
+// last payload. An upgraded package is removed in its previous version and added in
+// its new version.
+type Payload struct {
+	Hostname  string    `json:"hostname"`
+	Timestamp int64     `json:"timestamp"`
+	Full      bool      `json:"full"`
+	Added     []Package `json:"added"`
+	Removed   []Package `json:"removed"`
+}
+
+// MarshalJSON serialization a Payload to JSON
+func (p *Payload) MarshalJSON() ([]byte, error) {
+	type PayloadAlias Payload
+	return json.Marshal((*PayloadAlias)(p))
+}
+
+// SplitPayload breaks the payload into times number of pieces
+func (p *Payload) SplitPayload(times int) ([]marshaler.AbstractMarshaler, error) {
+	return nil, fmt.Errorf("Software inventory Payload splitting is not implemented")
+}
+
+// MarshalSplitCompress not implemented
+func (p *Payload) MarshalSplitCompress(bufferContext *marshaler.BufferContext) ([]*[]byte, error) {
+	return nil, fmt.Errorf("Software inventory MarshalSplitCompress is not implemented")
+}
@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metadata/packages"
+	"github.com/DataDog/datadog-agent/pkg/serializer"
+	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	softwareInventoryCollectorName = "software_inventory"
+	// the software inventory can't be collected more often than every 600 seconds (10 minutes)
+	softwareInventoryCollectorMinInterval = 600 * time.Second
+)
+
+type softwareInventoryCollector struct {
+	hostRoot string
+}
+
+// Send collects the packages installed on the host and submits the changes since the last payload
+func (c softwareInventoryCollector) Send(ctx context.Context, s serializer.MetricSerializer) error {
+	if s == nil {
+		return nil
+	}
+
+	hostname, err := util.GetHostname(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to submit software inventory metadata payload, no hostname: %s", err)
+	}
+
+	payload, err := packages.GetPayload(hostname, c.hostRoot)
+	if err != nil {
+		return fmt.Errorf("unable to collect software inventory: %s", err)
+	}
+	if payload == nil {
+		log.Debugf("No change in the software inventory, skipping payload")
+		return nil
+	}
+
+	if err := s.SendMetadata(payload); err != nil {
+		return fmt.Errorf("unable to submit software inventory payload, %s", err)
+	}
+	packages.MarkSent(payload)
+	return nil
+}
+
+// SetupSoftwareInventory registers the software inventory collector into the Scheduler and schedules it
+func SetupSoftwareInventory(sc *Scheduler) error {
+	if runtime.GOOS != "linux" {
+		log.Infof("Software inventory is only supported on Linux, not scheduling it")
+		return nil
+	}
+
+	interval := time.Duration(config.Datadog.GetInt("software_inventory.interval")) * time.Second
+	if interval < softwareInventoryCollectorMinInterval {
+		log.Warnf("software_inventory.interval is lower than the minimum of %v, using the minimum instead", softwareInventoryCollectorMinInterval)
+		interval = softwareInventoryCollectorMinInterval
+	}
+
+	RegisterCollector(softwareInventoryCollectorName, softwareInventoryCollector{
+		hostRoot: config.Datadog.GetString("software_inventory.host_root"),
+	})
+
+	if err := sc.AddCollector(softwareInventoryCollectorName, interval); err != nil {
+		return err
+	}
+	log.Infof("Scheduled metadata provider '%v' to run every %v", softwareInventoryCollectorName, interval)
+	return nil
+}
@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package common
+
+import (
+	"net"
+)
+
+// FlowType represents the type of a flow export protocol
+type FlowType string
+
+const (
+	// TypeNetFlow5 represents NetFlow v5
+	TypeNetFlow5 FlowType = "netflow5"
+	// TypeNetFlow9 represents NetFlow v9
+	TypeNetFlow9 FlowType = "netflow9"
+	// TypeIPFIX represents IPFIX (NetFlow v10)
+	TypeIPFIX FlowType = "ipfix"
+	// TypeSFlow5 represents sFlow v5
+	TypeSFlow5 FlowType = "sflow5"
+)
+
+// FlowTypes lists the supported flow types
+var FlowTypes = []FlowType{TypeNetFlow5, TypeNetFlow9, TypeIPFIX, TypeSFlow5}
+
+// DefaultPort returns the standard port of a flow type
+func (t FlowType) DefaultPort() uint16 {
+	switch t {
+	case TypeNetFlow5, TypeNetFlow9:
+		return 2055
+	case TypeIPFIX:
+		return 4739
+	case TypeSFlow5:
+		return 6343
+	}
+	return 0
+}
+
+// Direction of a flow relative to the interface it was observed on
+const (
+	DirectionIngress uint32 = 0
+	DirectionEgress  uint32 = 1
+)
+
+// EtherType values
+const (
+	EtherTypeIPv4 uint32 = 0x0800
+	EtherTypeIPv6 uint32 = 0x86DD
+)
+
+// Flow contains the fields of a decoded flow record
+type Flow struct {
+	FlowType     FlowType
+	ExporterAddr net.IP
+	// Namespace of the listener which received the flow, used to build the exporter device ID
+	Namespace string
+
+	SamplingRate uint64
+	Direction    uint32
+
+	// Unix timestamps in seconds
+	StartTimestamp uint64
+	EndTimestamp   uint64
+
+	Bytes   uint64
+	Packets uint64
+
+	EtherType  uint32
+	IPProtocol uint32
+
+	SrcAddr net.IP
+	DstAddr net.IP
+	SrcPort uint32
+	DstPort uint32
+	SrcMac  uint64
+	DstMac  uint64
+	SrcMask uint32
+	DstMask uint32
+
+	NextHop net.IP
+
+	InputInterface  uint32
+	OutputInterface uint32
+
+	Tos      uint32
+	TCPFlags uint32
+}
+
+// AggregationKey contains the fields used to aggregate flows: the 5-tuple, the exporter and
+// the input and output interfaces. IP addresses are stored in their 16-byte form so that the
+// key is comparable and can be used as a map key.
+type AggregationKey struct {
+	FlowType        FlowType
+	Namespace       string
+	ExporterAddr    string
+	SrcAddr         string
+	DstAddr         string
+	SrcPort         uint32
+	DstPort         uint32
+	IPProtocol      uint32
+	EtherType       uint32
+	InputInterface  uint32
+	OutputInterface uint32
+}
+
+// AggregationKey returns the key used to aggregate the flow
+func (f *Flow) AggregationKey() AggregationKey {
+	return AggregationKey{
+		FlowType:        f.FlowType,
+		Namespace:       f.Namespace,
+		ExporterAddr:    string(f.ExporterAddr.To16()),
+		SrcAddr:         string(f.SrcAddr.To16()),
+		DstAddr:         string(f.DstAddr.To16()),
+		SrcPort:         f.SrcPort,
+		DstPort:         f.DstPort,
+		IPProtocol:      f.IPProtocol,
+		EtherType:       f.EtherType,
+		InputInterface:  f.InputInterface,
+		OutputInterface: f.OutputInterface,
+	}
+}
@@ -0,0 +1,109 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"fmt"
+
+	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+const (
+	defaultStopTimeout             = 5
+	defaultAggregatorBufferSize    = 10000
+	defaultAggregatorFlushInterval = 300
+	defaultAggregatorMaxFlows      = 100000
+	defaultWorkers                 = 1
+)
+
+// NetflowConfig contains configuration for NetFlow collector.
+// YAML field tags provided for test marshalling purposes.
+type NetflowConfig struct {
+	Listeners   []ListenerConfig `mapstructure:"listeners" yaml:"listeners"`
+	StopTimeout int              `mapstructure:"stop_timeout" yaml:"stop_timeout"`
+	// AggregatorBufferSize is the size of the channel between the listeners and the aggregator
+	AggregatorBufferSize int `mapstructure:"aggregator_buffer_size" yaml:"aggregator_buffer_size"`
+	// AggregatorFlushInterval is the aggregation window in seconds
+	AggregatorFlushInterval int `mapstructure:"aggregator_flush_interval" yaml:"aggregator_flush_interval"`
+	// AggregatorMaxFlows is the maximum number of aggregated flows kept between two flushes
+	AggregatorMaxFlows int `mapstructure:"aggregator_max_flows" yaml:"aggregator_max_flows"`
+}
+
+// ListenerConfig contains configuration for a single flow listener
+type ListenerConfig struct {
+	FlowType  common.FlowType `mapstructure:"flow_type" yaml:"flow_type"`
+	Port      uint16          `mapstructure:"port" yaml:"port"`
+	BindHost  string          `mapstructure:"bind_host" yaml:"bind_host"`
+	Workers   int             `mapstructure:"workers" yaml:"workers"`
+	Namespace string          `mapstructure:"namespace" yaml:"namespace"`
+}
+
+// ReadConfig builds and returns configuration from Agent configuration.
+func ReadConfig() (*NetflowConfig, error) {
+	var mainConfig NetflowConfig
+
+	err := coreconfig.Datadog.UnmarshalKey("network_devices.netflow", &mainConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(mainConfig.Listeners) == 0 {
+		return nil, fmt.Errorf("`listeners` is required and must be non-empty")
+	}
+
+	for i := range mainConfig.Listeners {
+		listenerConfig := &mainConfig.Listeners[i]
+
+		if !isValidFlowType(listenerConfig.FlowType) {
+			return nil, fmt.Errorf("invalid flow type `%s`, valid flow types are: %v", listenerConfig.FlowType, common.FlowTypes)
+		}
+
+		// Set defaults.
+		if listenerConfig.Port == 0 {
+			listenerConfig.Port = listenerConfig.FlowType.DefaultPort()
+		}
+		if listenerConfig.BindHost == "" {
+			// Default to global bind_host option.
+			listenerConfig.BindHost = coreconfig.GetBindHost()
+		}
+		if listenerConfig.Workers == 0 {
+			listenerConfig.Workers = defaultWorkers
+		}
+		if listenerConfig.Namespace == "" {
+			listenerConfig.Namespace = coreconfig.Datadog.GetString("network_devices.namespace")
+		}
+	}
+
+	if mainConfig.StopTimeout == 0 {
+		mainConfig.StopTimeout = defaultStopTimeout
+	}
+	if mainConfig.AggregatorBufferSize == 0 {
+		mainConfig.AggregatorBufferSize = defaultAggregatorBufferSize
+	}
+	if mainConfig.AggregatorFlushInterval == 0 {
+		mainConfig.AggregatorFlushInterval = defaultAggregatorFlushInterval
+	}
+	if mainConfig.AggregatorMaxFlows == 0 {
+		mainConfig.AggregatorMaxFlows = defaultAggregatorMaxFlows
+	}
+
+	return &mainConfig, nil
+}
+
+// Addr returns the host:port address to listen on.
+func (c *ListenerConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", c.BindHost, c.Port)
+}
+
+func isValidFlowType(flowType common.FlowType) bool {
+	for _, t := range common.FlowTypes {
+		if t == flowType {
+			return true
+		}
+	}
+	return false
+}
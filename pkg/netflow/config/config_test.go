@@ -0,0 +1,71 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+func loadConfig(t *testing.T, content string) {
+	coreconfig.Datadog.SetConfigType("yaml")
+	require.NoError(t, coreconfig.Datadog.ReadConfig(strings.NewReader(content)))
+}
+
+func TestReadConfig(t *testing.T) {
+	loadConfig(t, `
+network_devices:
+  namespace: my-ns
+  netflow:
+    enabled: true
+    aggregator_flush_interval: 60
+    listeners:
+      - flow_type: netflow9
+        bind_host: 127.0.0.1
+        workers: 4
+      - flow_type: sflow5
+        port: 1234
+        namespace: other-ns
+`)
+
+	config, err := ReadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 5, config.StopTimeout)
+	assert.Equal(t, 10000, config.AggregatorBufferSize)
+	assert.Equal(t, 60, config.AggregatorFlushInterval)
+	assert.Equal(t, 100000, config.AggregatorMaxFlows)
+	assert.Equal(t, []ListenerConfig{
+		{FlowType: common.TypeNetFlow9, Port: 2055, BindHost: "127.0.0.1", Workers: 4, Namespace: "my-ns"},
+		{FlowType: common.TypeSFlow5, Port: 1234, BindHost: "localhost", Workers: 1, Namespace: "other-ns"},
+	}, config.Listeners)
+	assert.Equal(t, "127.0.0.1:2055", config.Listeners[0].Addr())
+}
+
+func TestReadConfigInvalid(t *testing.T) {
+	loadConfig(t, `
+network_devices:
+  netflow:
+    enabled: true
+`)
+	_, err := ReadConfig()
+	assert.Error(t, err)
+
+	loadConfig(t, `
+network_devices:
+  netflow:
+    enabled: true
+    listeners:
+      - flow_type: netflow1
+`)
+	_, err = ReadConfig()
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package decoder
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+// ErrMissingTemplate is returned when a NetFlow v9 or IPFIX packet contains data
+// records whose template has not been received yet. The flows decoded from the
+// other records of the packet are still returned along with this error.
+var ErrMissingTemplate = errors.New("missing template")
+
+// timeNow is used to timestamp flows from protocols without absolute timestamps, overridden in tests
+var timeNow = time.Now
+
+// Decoder decodes the payload of a flow export packet
+type Decoder interface {
+	// Decode returns the flows contained in the payload sent by the given exporter
+	Decode(payload []byte, exporter net.IP) ([]*common.Flow, error)
+}
+
+// NewDecoder returns a decoder for the given flow type. NetFlow v9 and IPFIX decoders
+// store the templates they receive in the given cache.
+func NewDecoder(flowType common.FlowType, templates *TemplateCache) (Decoder, error) {
+	switch flowType {
+	case common.TypeNetFlow5:
+		return &netflow5Decoder{}, nil
+	case common.TypeNetFlow9:
+		return &netflow9Decoder{templates: templates}, nil
+	case common.TypeIPFIX:
+		return &ipfixDecoder{templates: templates}, nil
+	case common.TypeSFlow5:
+		return &sflow5Decoder{}, nil
+	}
+	return nil, fmt.Errorf("unsupported flow type `%s`", flowType)
+}
+
+// reader reads big-endian values from a packet, failing on truncated input
+type reader struct {
+	buf []byte
+	err error
+}
+
+func newReader(buf []byte) *reader {
+	return &reader{buf: buf}
+}
+
+func (r *reader) len() int {
+	return len(r.buf)
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.buf) < n {
+		r.err = errors.New("truncated packet")
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *reader) skip(n int) {
+	r.next(n)
+}
+
+func (r *reader) uint8() uint8 {
+	b := r.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *reader) uint16() uint16 {
+	b := r.next(2)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+func (r *reader) uint32() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (r *reader) ip(n int) net.IP {
+	b := r.next(n)
+	if b == nil {
+		return nil
+	}
+	ip := make(net.IP, n)
+	copy(ip, b)
+	return ip
+}
+
+// decodeUint decodes a big-endian unsigned integer of up to 8 bytes
+func decodeUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// decodeIP copies an IPv4 or IPv6 address, returns nil for other lengths
+func decodeIP(b []byte) net.IP {
+	if len(b) != net.IPv4len && len(b) != net.IPv6len {
+		return nil
+	}
+	ip := make(net.IP, len(b))
+	copy(ip, b)
+	return ip
+}
+
+// decodeMac decodes a MAC address as an integer
+func decodeMac(b []byte) uint64 {
+	if len(b) != 6 {
+		return 0
+	}
+	return decodeUint(b)
+}
@@ -0,0 +1,293 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package decoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+var exporter = net.ParseIP("10.0.0.1")
+
+// packet builds packets in network byte order
+type packet struct {
+	bytes.Buffer
+}
+
+func (p *packet) put(values ...interface{}) *packet {
+	for _, v := range values {
+		binary.Write(&p.Buffer, binary.BigEndian, v) //nolint:errcheck
+	}
+	return p
+}
+
+func TestNetflow5(t *testing.T) {
+	p := &packet{}
+	// header: version, count, sys uptime, unix secs, unix nsecs, sequence, engine type, engine id, sampling
+	p.put(uint16(5), uint16(1), uint32(100000), uint32(1600000000), uint32(0), uint32(1), uint8(0), uint8(0), uint16(0x4000|10))
+	// record
+	p.put(net.ParseIP("192.168.1.1").To4(), net.ParseIP("192.168.1.2").To4(), net.ParseIP("192.168.1.254").To4())
+	p.put(uint16(1), uint16(2), uint32(10), uint32(1500), uint32(40000), uint32(90000))
+	p.put(uint16(12345), uint16(443), uint8(0), uint8(0x12), uint8(6), uint8(0), uint16(0), uint16(0), uint8(24), uint8(16), uint16(0))
+
+	d, err := NewDecoder(common.TypeNetFlow5, nil)
+	require.NoError(t, err)
+
+	flows, err := d.Decode(p.Bytes(), exporter)
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+	assert.Equal(t, &common.Flow{
+		FlowType:        common.TypeNetFlow5,
+		ExporterAddr:    exporter,
+		SamplingRate:    10,
+		StartTimestamp:  1600000000 - 60,
+		EndTimestamp:    1600000000 - 10,
+		Bytes:           1500,
+		Packets:         10,
+		EtherType:       common.EtherTypeIPv4,
+		IPProtocol:      6,
+		SrcAddr:         net.ParseIP("192.168.1.1").To4(),
+		DstAddr:         net.ParseIP("192.168.1.2").To4(),
+		SrcPort:         12345,
+		DstPort:         443,
+		SrcMask:         24,
+		DstMask:         16,
+		NextHop:         net.ParseIP("192.168.1.254").To4(),
+		InputInterface:  1,
+		OutputInterface: 2,
+		TCPFlags:        0x12,
+	}, flows[0])
+
+	_, err = d.Decode(p.Bytes()[:60], exporter)
+	assert.Error(t, err)
+}
+
+func netflow9Packet(flowSets ...*packet) []byte {
+	p := &packet{}
+	p.put(uint16(9), uint16(len(flowSets)), uint32(100000), uint32(1600000000), uint32(1), uint32(7))
+	for _, flowSet := range flowSets {
+		p.Write(flowSet.Bytes())
+	}
+	return p.Bytes()
+}
+
+func TestNetflow9(t *testing.T) {
+	templateSet := (&packet{}).put(uint16(0), uint16(4+4+8*4))
+	templateSet.put(uint16(256), uint16(8))
+	templateSet.put(uint16(fieldSourceIPv4Address), uint16(4), uint16(fieldDestinationIPv4Address), uint16(4))
+	templateSet.put(uint16(fieldInBytes), uint16(4), uint16(fieldInPkts), uint16(4))
+	templateSet.put(uint16(fieldProtocolIdentifier), uint16(1), uint16(fieldIngressInterface), uint16(2))
+	templateSet.put(uint16(fieldFlowStartSysUpTime), uint16(4), uint16(fieldFlowEndSysUpTime), uint16(4))
+
+	dataSet := (&packet{}).put(uint16(256), uint16(4+27+1))
+	dataSet.put(net.ParseIP("10.1.1.1").To4(), net.ParseIP("10.1.1.2").To4(), uint32(2000), uint32(4), uint8(17), uint16(5), uint32(40000), uint32(90000))
+	dataSet.put(uint8(0)) // padding
+
+	templates := NewTemplateCache()
+	d, err := NewDecoder(common.TypeNetFlow9, templates)
+	require.NoError(t, err)
+
+	// data received before its template
+	flows, err := d.Decode(netflow9Packet(dataSet), exporter)
+	assert.ErrorIs(t, err, ErrMissingTemplate)
+	assert.Empty(t, flows)
+
+	flows, err = d.Decode(netflow9Packet(templateSet, dataSet), exporter)
+	require.NoError(t, err)
+	assert.Equal(t, 1, templates.Len())
+	require.Len(t, flows, 1)
+	assert.Equal(t, &common.Flow{
+		FlowType:       common.TypeNetFlow9,
+		ExporterAddr:   exporter,
+		StartTimestamp: 1600000000 - 60,
+		EndTimestamp:   1600000000 - 10,
+		Bytes:          2000,
+		Packets:        4,
+		EtherType:      common.EtherTypeIPv4,
+		IPProtocol:     17,
+		SrcAddr:        net.ParseIP("10.1.1.1").To4(),
+		DstAddr:        net.ParseIP("10.1.1.2").To4(),
+		InputInterface: 5,
+	}, flows[0])
+
+	// templates are cached per exporter
+	_, err = d.Decode(netflow9Packet(dataSet), net.ParseIP("10.0.0.2"))
+	assert.ErrorIs(t, err, ErrMissingTemplate)
+
+	// options records announce the sampling rate
+	optionsTemplateSet := (&packet{}).put(uint16(1), uint16(4+6+8+2))
+	optionsTemplateSet.put(uint16(257), uint16(4), uint16(4), uint16(1), uint16(4), uint16(fieldSamplingInterval), uint16(4), uint16(0))
+	optionsSet := (&packet{}).put(uint16(257), uint16(4+8), uint32(0), uint32(100))
+
+	flows, err = d.Decode(netflow9Packet(optionsTemplateSet, optionsSet, dataSet), exporter)
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+	assert.Equal(t, uint64(100), flows[0].SamplingRate)
+}
+
+func ipfixPacket(sets ...*packet) []byte {
+	length := 16
+	for _, set := range sets {
+		length += set.Len()
+	}
+	p := &packet{}
+	p.put(uint16(10), uint16(length), uint32(1600000000), uint32(1), uint32(3))
+	for _, set := range sets {
+		p.Write(set.Bytes())
+	}
+	return p.Bytes()
+}
+
+func TestIPFIX(t *testing.T) {
+	templateSet := (&packet{}).put(uint16(2), uint16(4+4+4*5+4))
+	templateSet.put(uint16(300), uint16(5))
+	templateSet.put(uint16(fieldSourceIPv6Address), uint16(16), uint16(fieldDestinationIPv6Address), uint16(16))
+	// enterprise specific variable-length field
+	templateSet.put(uint16(ipfixEnterpriseBit|1), uint16(variableLength), uint32(12345))
+	templateSet.put(uint16(fieldInBytes), uint16(8), uint16(fieldFlowEndMilliseconds), uint16(8))
+
+	dataSet := (&packet{}).put(uint16(300), uint16(4+32+4+16))
+	dataSet.put(net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2"))
+	dataSet.put(uint8(3), []byte("abc"))
+	dataSet.put(uint64(123456), uint64(1600000005123))
+
+	templates := NewTemplateCache()
+	d, err := NewDecoder(common.TypeIPFIX, templates)
+	require.NoError(t, err)
+
+	flows, err := d.Decode(ipfixPacket(templateSet, dataSet), exporter)
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+	assert.Equal(t, &common.Flow{
+		FlowType:       common.TypeIPFIX,
+		ExporterAddr:   exporter,
+		StartTimestamp: 1600000000,
+		EndTimestamp:   1600000005,
+		Bytes:          123456,
+		EtherType:      common.EtherTypeIPv6,
+		SrcAddr:        net.ParseIP("2001:db8::1"),
+		DstAddr:        net.ParseIP("2001:db8::2"),
+	}, flows[0])
+
+	// template withdrawal
+	withdrawalSet := (&packet{}).put(uint16(2), uint16(8), uint16(300), uint16(0))
+	_, err = d.Decode(ipfixPacket(withdrawalSet), exporter)
+	require.NoError(t, err)
+	assert.Equal(t, 0, templates.Len())
+
+	_, err = d.Decode(ipfixPacket(dataSet), exporter)
+	assert.ErrorIs(t, err, ErrMissingTemplate)
+
+	_, err = d.Decode(ipfixPacket(templateSet)[:20], exporter)
+	assert.Error(t, err)
+}
+
+func TestTemplateCacheBoundedPerExporter(t *testing.T) {
+	templates := NewTemplateCache()
+	templates.maxPerExporter = 2
+	domain := observationDomain{exporter: "10.0.0.1", version: 10, domainID: 1}
+	otherDomain := observationDomain{exporter: "10.0.0.1", version: 10, domainID: 2}
+	otherExporter := observationDomain{exporter: "10.0.0.2", version: 10, domainID: 1}
+
+	templates.add(domain, 256, &template{})
+	templates.add(otherDomain, 256, &template{})
+	templates.setSamplingRate(otherDomain, 100)
+	templates.add(otherExporter, 256, &template{})
+	// refreshing a template doesn't count against the limit
+	templates.add(domain, 256, &template{})
+	assert.Equal(t, 3, templates.Len())
+
+	// the template refreshed the longest time ago is evicted, with the sampling rate of its domain
+	templates.add(domain, 257, &template{})
+	assert.Equal(t, 3, templates.Len())
+	assert.Nil(t, templates.get(otherDomain, 256))
+	assert.Zero(t, templates.samplingRate(otherDomain))
+	assert.NotNil(t, templates.get(domain, 256))
+	assert.NotNil(t, templates.get(domain, 257))
+	assert.NotNil(t, templates.get(otherExporter, 256))
+
+	// sampling rates are only kept for the domains which have templates
+	templates.setSamplingRate(otherDomain, 100)
+	assert.Zero(t, templates.samplingRate(otherDomain))
+
+	templates.removeAll(domain)
+	assert.Equal(t, 1, templates.Len())
+}
+
+func TestSFlow5(t *testing.T) {
+	timeNow = func() time.Time { return time.Unix(1600000000, 0) }
+	defer func() { timeNow = time.Now }()
+
+	header := &packet{}
+	header.put([]byte{0, 1, 2, 3, 4, 5}, []byte{6, 7, 8, 9, 10, 11}, uint16(0x8100), uint16(10), uint16(0x0800))
+	// IPv4 header
+	header.put(uint8(0x45), uint8(0), uint16(60), uint16(0), uint16(0), uint8(64), uint8(6), uint16(0))
+	header.put(net.ParseIP("172.16.0.1").To4(), net.ParseIP("172.16.0.2").To4())
+	// TCP header
+	header.put(uint16(51000), uint16(22), uint32(0), uint32(0), uint8(0x50), uint8(0x02))
+	for header.Len()%4 != 0 {
+		header.put(uint8(0))
+	}
+
+	record := (&packet{}).put(uint32(sflowHeaderEthernet), uint32(1514), uint32(4), uint32(header.Len()))
+	record.Write(header.Bytes())
+
+	sample := (&packet{}).put(uint32(1), uint32(3), uint32(1000), uint32(5000), uint32(0), uint32(4), uint32(8), uint32(2))
+	// counters record, ignored
+	sample.put(uint32(1001), uint32(4), uint32(0))
+	sample.put(uint32(sflowRawPacketHeader), uint32(record.Len()))
+	sample.Write(record.Bytes())
+
+	p := &packet{}
+	p.put(uint32(5), uint32(sflowAddressIPv4), net.ParseIP("10.0.0.9").To4(), uint32(0), uint32(1), uint32(1000), uint32(2))
+	// counter sample, ignored
+	p.put(uint32(2), uint32(4), uint32(0))
+	p.put(uint32(sflowFlowSample), uint32(sample.Len()))
+	p.Write(sample.Bytes())
+
+	d, err := NewDecoder(common.TypeSFlow5, nil)
+	require.NoError(t, err)
+
+	flows, err := d.Decode(p.Bytes(), exporter)
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+	assert.Equal(t, &common.Flow{
+		FlowType:        common.TypeSFlow5,
+		ExporterAddr:    net.ParseIP("10.0.0.9").To4(),
+		SamplingRate:    1000,
+		StartTimestamp:  1600000000,
+		EndTimestamp:    1600000000,
+		Bytes:           1514,
+		Packets:         1,
+		EtherType:       common.EtherTypeIPv4,
+		IPProtocol:      6,
+		SrcAddr:         net.ParseIP("172.16.0.1").To4(),
+		DstAddr:         net.ParseIP("172.16.0.2").To4(),
+		SrcPort:         51000,
+		DstPort:         22,
+		SrcMac:          0x060708090a0b,
+		DstMac:          0x000102030405,
+		InputInterface:  4,
+		OutputInterface: 8,
+		TCPFlags:        0x02,
+	}, flows[0])
+
+	_, err = d.Decode(p.Bytes()[:40], exporter)
+	assert.Error(t, err)
+}
+
+func TestNewDecoderUnsupportedFlowType(t *testing.T) {
+	_, err := NewDecoder("netflow1", nil)
+	assert.Error(t, err)
+}
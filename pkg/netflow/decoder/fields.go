@@ -0,0 +1,145 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package decoder
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+// Information elements shared by NetFlow v9 and IPFIX.
+// See: https://www.iana.org/assignments/ipfix/ipfix.xhtml
+const (
+	fieldInBytes                  = 1
+	fieldInPkts                   = 2
+	fieldProtocolIdentifier       = 4
+	fieldIPClassOfService         = 5
+	fieldTCPControlBits           = 6
+	fieldSourceTransportPort      = 7
+	fieldSourceIPv4Address        = 8
+	fieldSourceIPv4PrefixLength   = 9
+	fieldIngressInterface         = 10
+	fieldDestinationTransportPort = 11
+	fieldDestinationIPv4Address   = 12
+	fieldDestinationIPv4PrefixLen = 13
+	fieldEgressInterface          = 14
+	fieldIPNextHopIPv4Address     = 15
+	fieldFlowEndSysUpTime         = 21
+	fieldFlowStartSysUpTime       = 22
+	fieldSourceIPv6Address        = 27
+	fieldDestinationIPv6Address   = 28
+	fieldSourceIPv6PrefixLength   = 29
+	fieldDestinationIPv6PrefixLen = 30
+	fieldSamplingInterval         = 34
+	fieldSourceMacAddress         = 56
+	fieldPostDestinationMacAddr   = 57
+	fieldFlowDirection            = 61
+	fieldIPNextHopIPv6Address     = 62
+	fieldDestinationMacAddress    = 80
+	fieldFlowStartSeconds         = 150
+	fieldFlowEndSeconds           = 151
+	fieldFlowStartMilliseconds    = 152
+	fieldFlowEndMilliseconds      = 153
+	fieldEthernetType             = 256
+	fieldSamplingPacketInterval   = 305
+)
+
+// recordTimes collects the timestamps of a record, which can be exported
+// either relative to the exporter uptime or as absolute times
+type recordTimes struct {
+	hasUptime bool
+	firstUp   uint32
+	lastUp    uint32
+	startSecs uint64
+	endSecs   uint64
+	hasStart  bool
+	hasEnd    bool
+}
+
+// decodeRecord reads a data record described by the given template into the flow.
+// It returns the sampling interval of the record, if any.
+func decodeRecord(r *reader, t *template, flow *common.Flow, times *recordTimes) uint64 {
+	var samplingRate uint64
+	for _, field := range t.fields {
+		length := int(field.length)
+		if field.length == variableLength {
+			length = int(r.uint8())
+			if length == 255 {
+				length = int(r.uint16())
+			}
+		}
+		value := r.next(length)
+		if r.err != nil {
+			return 0
+		}
+		if field.enterprise {
+			continue
+		}
+
+		switch field.id {
+		case fieldInBytes:
+			flow.Bytes = decodeUint(value)
+		case fieldInPkts:
+			flow.Packets = decodeUint(value)
+		case fieldProtocolIdentifier:
+			flow.IPProtocol = uint32(decodeUint(value))
+		case fieldIPClassOfService:
+			flow.Tos = uint32(decodeUint(value))
+		case fieldTCPControlBits:
+			flow.TCPFlags = uint32(decodeUint(value))
+		case fieldSourceTransportPort:
+			flow.SrcPort = uint32(decodeUint(value))
+		case fieldDestinationTransportPort:
+			flow.DstPort = uint32(decodeUint(value))
+		case fieldSourceIPv4Address:
+			flow.SrcAddr = decodeIP(value)
+			flow.EtherType = common.EtherTypeIPv4
+		case fieldDestinationIPv4Address:
+			flow.DstAddr = decodeIP(value)
+			flow.EtherType = common.EtherTypeIPv4
+		case fieldSourceIPv6Address:
+			flow.SrcAddr = decodeIP(value)
+			flow.EtherType = common.EtherTypeIPv6
+		case fieldDestinationIPv6Address:
+			flow.DstAddr = decodeIP(value)
+			flow.EtherType = common.EtherTypeIPv6
+		case fieldSourceIPv4PrefixLength, fieldSourceIPv6PrefixLength:
+			flow.SrcMask = uint32(decodeUint(value))
+		case fieldDestinationIPv4PrefixLen, fieldDestinationIPv6PrefixLen:
+			flow.DstMask = uint32(decodeUint(value))
+		case fieldIngressInterface:
+			flow.InputInterface = uint32(decodeUint(value))
+		case fieldEgressInterface:
+			flow.OutputInterface = uint32(decodeUint(value))
+		case fieldIPNextHopIPv4Address, fieldIPNextHopIPv6Address:
+			flow.NextHop = decodeIP(value)
+		case fieldSourceMacAddress:
+			flow.SrcMac = decodeMac(value)
+		case fieldDestinationMacAddress, fieldPostDestinationMacAddr:
+			flow.DstMac = decodeMac(value)
+		case fieldFlowDirection:
+			flow.Direction = uint32(decodeUint(value))
+		case fieldEthernetType:
+			flow.EtherType = uint32(decodeUint(value))
+		case fieldSamplingInterval, fieldSamplingPacketInterval:
+			samplingRate = decodeUint(value)
+		case fieldFlowStartSysUpTime:
+			times.firstUp = uint32(decodeUint(value))
+			times.hasUptime = true
+		case fieldFlowEndSysUpTime:
+			times.lastUp = uint32(decodeUint(value))
+			times.hasUptime = true
+		case fieldFlowStartSeconds:
+			times.startSecs, times.hasStart = decodeUint(value), true
+		case fieldFlowEndSeconds:
+			times.endSecs, times.hasEnd = decodeUint(value), true
+		case fieldFlowStartMilliseconds:
+			times.startSecs, times.hasStart = decodeUint(value)/1000, true
+		case fieldFlowEndMilliseconds:
+			times.endSecs, times.hasEnd = decodeUint(value)/1000, true
+		}
+	}
+	return samplingRate
+}
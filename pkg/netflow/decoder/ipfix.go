@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package decoder
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+const (
+	ipfixHeaderSize       = 16
+	ipfixTemplateSetID    = 2
+	ipfixOptionsSetID     = 3
+	ipfixEnterpriseBit    = 0x8000
+	ipfixAllTemplatesID   = 2
+	ipfixAllOptionsSetsID = 3
+)
+
+// ipfixDecoder decodes IPFIX packets.
+// See: https://www.rfc-editor.org/rfc/rfc7011.html
+type ipfixDecoder struct {
+	templates *TemplateCache
+}
+
+func (d *ipfixDecoder) Decode(payload []byte, exporter net.IP) ([]*common.Flow, error) {
+	r := newReader(payload)
+
+	version := r.uint16()
+	length := int(r.uint16())
+	exportTime := r.uint32()
+	r.skip(4) // sequence number
+	domainID := r.uint32()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if version != 10 {
+		return nil, fmt.Errorf("unexpected IPFIX version %d, expected 10", version)
+	}
+	if length < ipfixHeaderSize || length > len(payload) {
+		return nil, fmt.Errorf("invalid message length %d", length)
+	}
+	r = newReader(payload[ipfixHeaderSize:length])
+
+	header := exportHeader{
+		flowType:   common.TypeIPFIX,
+		exporter:   exporter,
+		domain:     observationDomain{exporter: exporter.String(), version: version, domainID: domainID},
+		exportSecs: exportTime,
+	}
+
+	var flows []*common.Flow
+	var missingTemplate bool
+	for r.len() >= flowSetHeaderSize {
+		setID := r.uint16()
+		setLength := int(r.uint16())
+		if setLength < flowSetHeaderSize {
+			return flows, fmt.Errorf("invalid set length %d", setLength)
+		}
+		set := newReader(r.next(setLength - flowSetHeaderSize))
+		if r.err != nil {
+			return flows, r.err
+		}
+
+		switch {
+		case setID == ipfixTemplateSetID:
+			if err := d.decodeTemplates(set, header.domain, false); err != nil {
+				return flows, err
+			}
+		case setID == ipfixOptionsSetID:
+			if err := d.decodeTemplates(set, header.domain, true); err != nil {
+				return flows, err
+			}
+		case setID >= minDataSetID:
+			t := d.templates.get(header.domain, setID)
+			if t == nil {
+				missingTemplate = true
+				continue
+			}
+			decoded, err := decodeDataSet(set, t, d.templates, header)
+			flows = append(flows, decoded...)
+			if err != nil {
+				return flows, err
+			}
+		}
+	}
+
+	if missingTemplate {
+		return flows, ErrMissingTemplate
+	}
+	return flows, nil
+}
+
+// decodeTemplates decodes the records of a template or options template set.
+// A record without fields withdraws a template, or all of them for the set ID itself.
+func (d *ipfixDecoder) decodeTemplates(r *reader, domain observationDomain, options bool) error {
+	headerLength := 4
+	if options {
+		headerLength = 6
+	}
+
+	for r.len() >= headerLength {
+		templateID := r.uint16()
+		fieldCount := int(r.uint16())
+		if fieldCount == 0 {
+			if templateID == ipfixAllTemplatesID || templateID == ipfixAllOptionsSetsID {
+				d.templates.removeAll(domain)
+			} else {
+				d.templates.remove(domain, templateID)
+			}
+			continue
+		}
+		if options {
+			r.skip(2) // scope field count
+		}
+
+		t := &template{options: options}
+		for i := 0; i < fieldCount; i++ {
+			field := templateField{id: r.uint16(), length: r.uint16()}
+			if field.id&ipfixEnterpriseBit != 0 {
+				field.id &^= ipfixEnterpriseBit
+				field.enterprise = true
+				r.skip(4) // enterprise number
+			}
+			t.fields = append(t.fields, field)
+		}
+		if r.err != nil {
+			return r.err
+		}
+		d.templates.add(domain, templateID, t)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package decoder
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+const (
+	netflow5RecordSize = 48
+)
+
+// netflow5Decoder decodes NetFlow v5 packets.
+// See: https://www.cisco.com/c/en/us/td/docs/net_mgmt/netflow_collection_engine/3-6/user/guide/format.html
+type netflow5Decoder struct{}
+
+func (d *netflow5Decoder) Decode(payload []byte, exporter net.IP) ([]*common.Flow, error) {
+	r := newReader(payload)
+
+	version := r.uint16()
+	count := r.uint16()
+	sysUptime := r.uint32()
+	unixSecs := r.uint32()
+	r.skip(4 + 4 + 1 + 1) // unix_nsecs, flow_sequence, engine_type, engine_id
+	samplingInterval := r.uint16() & 0x3fff
+	if r.err != nil {
+		return nil, r.err
+	}
+	if version != 5 {
+		return nil, fmt.Errorf("unexpected NetFlow version %d, expected 5", version)
+	}
+	if r.len() < int(count)*netflow5RecordSize {
+		return nil, fmt.Errorf("packet announces %d records but only contains %d bytes of records", count, r.len())
+	}
+
+	flows := make([]*common.Flow, 0, count)
+	for i := 0; i < int(count); i++ {
+		flow := &common.Flow{
+			FlowType:     common.TypeNetFlow5,
+			ExporterAddr: exporter,
+			SamplingRate: uint64(samplingInterval),
+			Direction:    common.DirectionIngress,
+			EtherType:    common.EtherTypeIPv4,
+		}
+		flow.SrcAddr = r.ip(4)
+		flow.DstAddr = r.ip(4)
+		flow.NextHop = r.ip(4)
+		flow.InputInterface = uint32(r.uint16())
+		flow.OutputInterface = uint32(r.uint16())
+		flow.Packets = uint64(r.uint32())
+		flow.Bytes = uint64(r.uint32())
+		first := r.uint32()
+		last := r.uint32()
+		flow.SrcPort = uint32(r.uint16())
+		flow.DstPort = uint32(r.uint16())
+		r.skip(1) // pad1
+		flow.TCPFlags = uint32(r.uint8())
+		flow.IPProtocol = uint32(r.uint8())
+		flow.Tos = uint32(r.uint8())
+		r.skip(2 + 2) // src_as, dst_as
+		flow.SrcMask = uint32(r.uint8())
+		flow.DstMask = uint32(r.uint8())
+		r.skip(2) // pad2
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		flow.StartTimestamp = uptimeToUnix(unixSecs, sysUptime, first)
+		flow.EndTimestamp = uptimeToUnix(unixSecs, sysUptime, last)
+		flows = append(flows, flow)
+	}
+
+	return flows, nil
+}
+
+// uptimeToUnix converts a timestamp in milliseconds since the exporter boot to a unix
+// timestamp in seconds, using the export time and the uptime of the exporter at export.
+func uptimeToUnix(exportSecs uint32, sysUptime uint32, uptime uint32) uint64 {
+	// uptime values are allowed to wrap around
+	elapsed := uint64((sysUptime - uptime) / 1000)
+	if elapsed > uint64(exportSecs) {
+		return 0
+	}
+	return uint64(exportSecs) - elapsed
+}
@@ -0,0 +1,190 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package decoder
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+const (
+	netflow9TemplateFlowSetID = 0
+	netflow9OptionsFlowSetID  = 1
+	minDataSetID              = 256
+	flowSetHeaderSize         = 4
+)
+
+// exportHeader holds the information of a packet header needed to decode its records
+type exportHeader struct {
+	flowType     common.FlowType
+	exporter     net.IP
+	domain       observationDomain
+	exportSecs   uint32
+	sysUptime    uint32
+	hasSysUptime bool
+}
+
+// netflow9Decoder decodes NetFlow v9 packets.
+// See: https://www.ietf.org/rfc/rfc3954.txt
+type netflow9Decoder struct {
+	templates *TemplateCache
+}
+
+func (d *netflow9Decoder) Decode(payload []byte, exporter net.IP) ([]*common.Flow, error) {
+	r := newReader(payload)
+
+	version := r.uint16()
+	r.skip(2) // count
+	sysUptime := r.uint32()
+	unixSecs := r.uint32()
+	r.skip(4) // sequence number
+	sourceID := r.uint32()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if version != 9 {
+		return nil, fmt.Errorf("unexpected NetFlow version %d, expected 9", version)
+	}
+
+	header := exportHeader{
+		flowType:     common.TypeNetFlow9,
+		exporter:     exporter,
+		domain:       observationDomain{exporter: exporter.String(), version: version, domainID: sourceID},
+		exportSecs:   unixSecs,
+		sysUptime:    sysUptime,
+		hasSysUptime: true,
+	}
+
+	var flows []*common.Flow
+	var missingTemplate bool
+	for r.len() >= flowSetHeaderSize {
+		flowSetID := r.uint16()
+		length := int(r.uint16())
+		if length < flowSetHeaderSize {
+			return flows, fmt.Errorf("invalid flowset length %d", length)
+		}
+		flowSet := newReader(r.next(length - flowSetHeaderSize))
+		if r.err != nil {
+			return flows, r.err
+		}
+
+		switch {
+		case flowSetID == netflow9TemplateFlowSetID:
+			if err := d.decodeTemplates(flowSet, header.domain); err != nil {
+				return flows, err
+			}
+		case flowSetID == netflow9OptionsFlowSetID:
+			if err := d.decodeOptionsTemplates(flowSet, header.domain); err != nil {
+				return flows, err
+			}
+		case flowSetID >= minDataSetID:
+			t := d.templates.get(header.domain, flowSetID)
+			if t == nil {
+				missingTemplate = true
+				continue
+			}
+			decoded, err := decodeDataSet(flowSet, t, d.templates, header)
+			flows = append(flows, decoded...)
+			if err != nil {
+				return flows, err
+			}
+		}
+	}
+
+	if missingTemplate {
+		return flows, ErrMissingTemplate
+	}
+	return flows, nil
+}
+
+func (d *netflow9Decoder) decodeTemplates(r *reader, domain observationDomain) error {
+	for r.len() >= 4 {
+		templateID := r.uint16()
+		fieldCount := int(r.uint16())
+		t := &template{}
+		for i := 0; i < fieldCount; i++ {
+			t.fields = append(t.fields, templateField{id: r.uint16(), length: r.uint16()})
+		}
+		if r.err != nil {
+			return r.err
+		}
+		d.templates.add(domain, templateID, t)
+	}
+	return nil
+}
+
+func (d *netflow9Decoder) decodeOptionsTemplates(r *reader, domain observationDomain) error {
+	// options templates may be followed by padding
+	for r.len() >= 6 {
+		templateID := r.uint16()
+		scopeLength := int(r.uint16())
+		optionLength := int(r.uint16())
+		t := &template{options: true}
+		for i := 0; i < (scopeLength+optionLength)/4; i++ {
+			t.fields = append(t.fields, templateField{id: r.uint16(), length: r.uint16()})
+		}
+		if r.err != nil {
+			return r.err
+		}
+		if len(t.fields) == 0 {
+			break
+		}
+		d.templates.add(domain, templateID, t)
+	}
+	return nil
+}
+
+// decodeDataSet decodes the data records of a NetFlow v9 flowset or an IPFIX set.
+// Options records update the sampling rate of the observation domain.
+func decodeDataSet(r *reader, t *template, templates *TemplateCache, header exportHeader) ([]*common.Flow, error) {
+	minLength := t.minRecordLength()
+	if minLength == 0 {
+		return nil, nil
+	}
+
+	var flows []*common.Flow
+	for r.len() >= minLength {
+		flow := &common.Flow{
+			FlowType:     header.flowType,
+			ExporterAddr: header.exporter,
+		}
+		var times recordTimes
+		samplingRate := decodeRecord(r, t, flow, &times)
+		if r.err != nil {
+			return flows, r.err
+		}
+
+		if t.options {
+			if samplingRate > 0 {
+				templates.setSamplingRate(header.domain, samplingRate)
+			}
+			continue
+		}
+
+		if samplingRate == 0 {
+			samplingRate = templates.samplingRate(header.domain)
+		}
+		flow.SamplingRate = samplingRate
+
+		flow.StartTimestamp = uint64(header.exportSecs)
+		flow.EndTimestamp = uint64(header.exportSecs)
+		if times.hasUptime && header.hasSysUptime {
+			flow.StartTimestamp = uptimeToUnix(header.exportSecs, header.sysUptime, times.firstUp)
+			flow.EndTimestamp = uptimeToUnix(header.exportSecs, header.sysUptime, times.lastUp)
+		}
+		if times.hasStart {
+			flow.StartTimestamp = times.startSecs
+		}
+		if times.hasEnd {
+			flow.EndTimestamp = times.endSecs
+		}
+
+		flows = append(flows, flow)
+	}
+	return flows, nil
+}
@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package decoder
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+const (
+	etherTypeVLAN = 0x8100
+	etherTypeQinQ = 0x88a8
+
+	protocolTCP = 6
+	protocolUDP = 17
+)
+
+// decodeEthernetHeader fills the flow with the fields of a sampled packet header.
+// Headers are truncated by the exporter, so decoding stops silently at the first missing layer.
+func decodeEthernetHeader(header []byte, flow *common.Flow) {
+	r := newReader(header)
+
+	flow.DstMac = decodeMac(r.next(6))
+	flow.SrcMac = decodeMac(r.next(6))
+	etherType := r.uint16()
+	for etherType == etherTypeVLAN || etherType == etherTypeQinQ {
+		r.skip(2) // tag control information
+		etherType = r.uint16()
+	}
+	if r.err != nil {
+		return
+	}
+	flow.EtherType = uint32(etherType)
+
+	var nextHeader uint8
+	switch uint32(etherType) {
+	case common.EtherTypeIPv4:
+		versionIHL := r.uint8()
+		flow.Tos = uint32(r.uint8())
+		r.skip(2 + 2) // total length, identification
+		fragmentOffset := r.uint16() & 0x1fff
+		r.skip(1) // ttl
+		nextHeader = r.uint8()
+		r.skip(2) // checksum
+		flow.SrcAddr = r.ip(4)
+		flow.DstAddr = r.ip(4)
+		r.skip(int(versionIHL&0x0f)*4 - 20) // options
+		if r.err != nil || fragmentOffset != 0 {
+			flow.IPProtocol = uint32(nextHeader)
+			return
+		}
+	case common.EtherTypeIPv6:
+		versionClass := r.uint16()
+		flow.Tos = uint32(versionClass>>4) & 0xff
+		r.skip(2 + 2) // flow label, payload length
+		nextHeader = r.uint8()
+		r.skip(1) // hop limit
+		flow.SrcAddr = r.ip(16)
+		flow.DstAddr = r.ip(16)
+	default:
+		return
+	}
+	if r.err != nil {
+		return
+	}
+	flow.IPProtocol = uint32(nextHeader)
+
+	switch nextHeader {
+	case protocolTCP:
+		flow.SrcPort = uint32(r.uint16())
+		flow.DstPort = uint32(r.uint16())
+		r.skip(4 + 4) // sequence, acknowledgment
+		r.skip(1)     // data offset
+		flow.TCPFlags = uint32(r.uint8())
+	case protocolUDP:
+		flow.SrcPort = uint32(r.uint16())
+		flow.DstPort = uint32(r.uint16())
+	}
+}
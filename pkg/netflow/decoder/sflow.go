@@ -0,0 +1,159 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package decoder
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+const (
+	sflowAddressIPv4 = 1
+	sflowAddressIPv6 = 2
+
+	sflowFlowSample         = 1
+	sflowExpandedFlowSample = 3
+
+	sflowRawPacketHeader = 1
+	sflowHeaderEthernet  = 1
+
+	// sflowInterfaceFormatMask is the mask of the format bits of a compact interface value
+	sflowInterfaceFormatMask = 0xc0000000
+	sflowInterfaceValueMask  = 0x3fffffff
+)
+
+// sflow5Decoder decodes sFlow v5 datagrams. Only flow samples containing a raw
+// packet header are decoded, counter samples are ignored.
+// See: https://sflow.org/sflow_version_5.txt
+type sflow5Decoder struct{}
+
+func (d *sflow5Decoder) Decode(payload []byte, exporter net.IP) ([]*common.Flow, error) {
+	r := newReader(payload)
+
+	version := r.uint32()
+	if r.err == nil && version != 5 {
+		return nil, fmt.Errorf("unexpected sFlow version %d, expected 5", version)
+	}
+	switch addressType := r.uint32(); addressType {
+	case sflowAddressIPv4:
+		exporter = r.ip(net.IPv4len)
+	case sflowAddressIPv6:
+		exporter = r.ip(net.IPv6len)
+	default:
+		if r.err == nil {
+			return nil, fmt.Errorf("unknown agent address type %d", addressType)
+		}
+	}
+	r.skip(4 + 4 + 4) // sub agent id, sequence number, uptime
+	numSamples := int(r.uint32())
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	now := uint64(timeNow().Unix())
+
+	var flows []*common.Flow
+	for i := 0; i < numSamples; i++ {
+		format := r.uint32()
+		sample := newReader(r.next(int(r.uint32())))
+		if r.err != nil {
+			return flows, r.err
+		}
+
+		var expanded bool
+		switch format {
+		case sflowFlowSample:
+		case sflowExpandedFlowSample:
+			expanded = true
+		default:
+			// counter samples and enterprise specific formats
+			continue
+		}
+
+		flow, err := decodeFlowSample(sample, expanded)
+		if err != nil {
+			return flows, err
+		}
+		if flow == nil {
+			continue
+		}
+		flow.ExporterAddr = exporter
+		flow.StartTimestamp = now
+		flow.EndTimestamp = now
+		flows = append(flows, flow)
+	}
+
+	return flows, nil
+}
+
+// decodeFlowSample decodes a flow sample, it returns nil if the sample does not contain a raw packet header
+func decodeFlowSample(r *reader, expanded bool) (*common.Flow, error) {
+	flow := &common.Flow{
+		FlowType: common.TypeSFlow5,
+		Packets:  1,
+	}
+
+	r.skip(4) // sequence number
+	if expanded {
+		r.skip(4 + 4) // source id type, source id index
+	} else {
+		r.skip(4) // source id
+	}
+	flow.SamplingRate = uint64(r.uint32())
+	r.skip(4 + 4) // sample pool, drops
+	if expanded {
+		r.skip(4) // input format
+		flow.InputInterface = r.uint32()
+		r.skip(4) // output format
+		flow.OutputInterface = r.uint32()
+	} else {
+		flow.InputInterface = r.uint32() & sflowInterfaceValueMask
+		output := r.uint32()
+		if output&sflowInterfaceFormatMask != 0 {
+			// discarded packet or multiple output interfaces
+			output = 0
+		}
+		flow.OutputInterface = output & sflowInterfaceValueMask
+	}
+	numRecords := int(r.uint32())
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var hasHeader bool
+	for i := 0; i < numRecords; i++ {
+		format := r.uint32()
+		record := newReader(r.next(int(r.uint32())))
+		if r.err != nil {
+			return nil, r.err
+		}
+		if format != sflowRawPacketHeader {
+			continue
+		}
+
+		protocol := record.uint32()
+		frameLength := record.uint32()
+		record.skip(4) // stripped
+		header := record.next(int(record.uint32()))
+		if record.err != nil {
+			return nil, record.err
+		}
+		if protocol != sflowHeaderEthernet {
+			continue
+		}
+
+		flow.Bytes = uint64(frameLength)
+		decodeEthernetHeader(header, flow)
+		hasHeader = true
+	}
+
+	if !hasHeader {
+		return nil, nil
+	}
+	return flow, nil
+}
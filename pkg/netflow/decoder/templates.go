@@ -0,0 +1,184 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package decoder
+
+import (
+	"sync"
+)
+
+// variableLength is the field length announcing a variable-length IPFIX field
+const variableLength = 65535
+
+// templateField is a field of a NetFlow v9 or IPFIX template
+type templateField struct {
+	id     uint16
+	length uint16
+	// enterprise is set for IPFIX enterprise-specific fields, which are skipped
+	enterprise bool
+}
+
+// template describes the layout of the data records referencing it
+type template struct {
+	fields []templateField
+	// options is set for options templates, whose records describe the exporter itself
+	options bool
+}
+
+// minRecordLength returns the smallest size of a record using this template,
+// used to tell records apart from the padding at the end of a set
+func (t *template) minRecordLength() int {
+	length := 0
+	for _, f := range t.fields {
+		if f.length == variableLength {
+			length++
+		} else {
+			length += int(f.length)
+		}
+	}
+	return length
+}
+
+// observationDomain identifies the templates namespace of an exporter. Templates are only
+// unique per exporter, protocol version and source ID (NetFlow v9) or observation domain (IPFIX).
+type observationDomain struct {
+	exporter string
+	version  uint16
+	domainID uint32
+}
+
+type templateKey struct {
+	domain     observationDomain
+	templateID uint16
+}
+
+// maxTemplatesPerExporter bounds the templates kept for a single exporter. Above it, the
+// template of the exporter which was refreshed the longest time ago is evicted.
+const maxTemplatesPerExporter = 1024
+
+// cachedTemplate is a template along with the order in which it was last refreshed
+type cachedTemplate struct {
+	template *template
+	refresh  uint64
+}
+
+// TemplateCache stores the templates received from each exporter, it is safe for concurrent use
+type TemplateCache struct {
+	mu            sync.RWMutex
+	templates     map[templateKey]cachedTemplate
+	samplingRates map[observationDomain]uint64
+	// domainTemplates and exporterTemplates count the templates of each observation
+	// domain and of each exporter
+	domainTemplates   map[observationDomain]int
+	exporterTemplates map[string]int
+	maxPerExporter    int
+	refreshes         uint64
+}
+
+// NewTemplateCache returns an empty template cache
+func NewTemplateCache() *TemplateCache {
+	return &TemplateCache{
+		templates:         make(map[templateKey]cachedTemplate),
+		samplingRates:     make(map[observationDomain]uint64),
+		domainTemplates:   make(map[observationDomain]int),
+		exporterTemplates: make(map[string]int),
+		maxPerExporter:    maxTemplatesPerExporter,
+	}
+}
+
+// Len returns the number of templates in the cache
+func (c *TemplateCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.templates)
+}
+
+func (c *TemplateCache) get(domain observationDomain, templateID uint16) *template {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.templates[templateKey{domain: domain, templateID: templateID}].template
+}
+
+func (c *TemplateCache) add(domain observationDomain, templateID uint16, t *template) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := templateKey{domain: domain, templateID: templateID}
+	if _, found := c.templates[key]; !found {
+		if c.exporterTemplates[domain.exporter] >= c.maxPerExporter {
+			c.evictStalest(domain.exporter)
+		}
+		c.domainTemplates[domain]++
+		c.exporterTemplates[domain.exporter]++
+	}
+	c.refreshes++
+	c.templates[key] = cachedTemplate{template: t, refresh: c.refreshes}
+}
+
+func (c *TemplateCache) remove(domain observationDomain, templateID uint16) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.delete(templateKey{domain: domain, templateID: templateID})
+}
+
+// removeAll withdraws all the templates of an observation domain
+func (c *TemplateCache) removeAll(domain observationDomain) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.templates {
+		if key.domain == domain {
+			c.delete(key)
+		}
+	}
+}
+
+// evictStalest removes the template of exporter which was refreshed the longest time ago,
+// the caller must hold the lock
+func (c *TemplateCache) evictStalest(exporter string) {
+	var stalest templateKey
+	var stalestRefresh uint64
+	found := false
+	for key, cached := range c.templates {
+		if key.domain.exporter == exporter && (!found || cached.refresh < stalestRefresh) {
+			stalest, stalestRefresh, found = key, cached.refresh, true
+		}
+	}
+	if found {
+		c.delete(stalest)
+	}
+}
+
+// delete removes a template, along with the sampling rate of its observation domain
+// once it has no template left, the caller must hold the lock
+func (c *TemplateCache) delete(key templateKey) {
+	if _, found := c.templates[key]; !found {
+		return
+	}
+	delete(c.templates, key)
+	if c.exporterTemplates[key.domain.exporter]--; c.exporterTemplates[key.domain.exporter] <= 0 {
+		delete(c.exporterTemplates, key.domain.exporter)
+	}
+	if c.domainTemplates[key.domain]--; c.domainTemplates[key.domain] <= 0 {
+		delete(c.domainTemplates, key.domain)
+		delete(c.samplingRates, key.domain)
+	}
+}
+
+// samplingRate returns the sampling rate announced in the options records of an observation domain
+func (c *TemplateCache) samplingRate(domain observationDomain) uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.samplingRates[domain]
+}
+
+// setSamplingRate records the sampling rate of an observation domain, as long as it has
+// templates, so that the sampling rates are bounded like the templates
+func (c *TemplateCache) setSamplingRate(domain observationDomain, rate uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.domainTemplates[domain] == 0 {
+		return
+	}
+	c.samplingRates[domain] = rate
+}
@@ -0,0 +1,170 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flowaggregator
+
+import (
+	"encoding/json"
+	"expvar"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	snmpmetadata "github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/metadata"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+	"github.com/DataDog/datadog-agent/pkg/netflow/config"
+	"github.com/DataDog/datadog-agent/pkg/netflow/payload"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var (
+	aggregatorExpvars      = expvar.NewMap("netflow_aggregator")
+	aggregatorFlowsFlushed = expvar.Int{}
+	aggregatorFlowsIn      = expvar.Int{}
+	aggregatorFlowsDropped = expvar.Int{}
+)
+
+func init() {
+	aggregatorExpvars.Set("FlowsIn", &aggregatorFlowsIn)
+	aggregatorExpvars.Set("FlowsFlushed", &aggregatorFlowsFlushed)
+	aggregatorExpvars.Set("FlowsDropped", &aggregatorFlowsDropped)
+}
+
+// FlowAggregator aggregates the flows received by the listeners by 5-tuple, exporter and
+// interfaces, and periodically sends the aggregated flows to the event platform.
+// At most maxFlows aggregated flows are kept between two flushes, flows with a new
+// aggregation key are dropped past this limit.
+type FlowAggregator struct {
+	flowIn        chan *common.Flow
+	flushInterval time.Duration
+	maxFlows      int
+	sender        aggregator.Sender
+	hostname      string
+	flows         map[common.AggregationKey]*common.Flow
+	dropped       int64
+	stopChan      chan struct{}
+	doneChan      chan struct{}
+}
+
+// NewFlowAggregator returns a new FlowAggregator
+func NewFlowAggregator(sender aggregator.Sender, config *config.NetflowConfig, hostname string) *FlowAggregator {
+	return &FlowAggregator{
+		flowIn:        make(chan *common.Flow, config.AggregatorBufferSize),
+		flushInterval: time.Duration(config.AggregatorFlushInterval) * time.Second,
+		maxFlows:      config.AggregatorMaxFlows,
+		sender:        sender,
+		hostname:      hostname,
+		flows:         make(map[common.AggregationKey]*common.Flow),
+		stopChan:      make(chan struct{}),
+		doneChan:      make(chan struct{}),
+	}
+}
+
+// GetFlowInChan returns the channel the listeners send their flows to
+func (agg *FlowAggregator) GetFlowInChan() chan *common.Flow {
+	return agg.flowIn
+}
+
+// Start starts aggregating flows in the background
+func (agg *FlowAggregator) Start() {
+	log.Info("Flow Aggregator started")
+	go agg.run()
+}
+
+// Stop stops the aggregator, the pending flows are flushed before it returns
+func (agg *FlowAggregator) Stop() {
+	close(agg.stopChan)
+	<-agg.doneChan
+}
+
+func (agg *FlowAggregator) run() {
+	defer close(agg.doneChan)
+
+	ticker := time.NewTicker(agg.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case flow := <-agg.flowIn:
+			aggregatorFlowsIn.Add(1)
+			agg.add(flow)
+		case <-ticker.C:
+			agg.flush()
+		case <-agg.stopChan:
+			agg.drain()
+			agg.flush()
+			log.Info("Flow Aggregator stopped")
+			return
+		}
+	}
+}
+
+// drain aggregates the flows still buffered in the input channel
+func (agg *FlowAggregator) drain() {
+	for {
+		select {
+		case flow := <-agg.flowIn:
+			aggregatorFlowsIn.Add(1)
+			agg.add(flow)
+		default:
+			return
+		}
+	}
+}
+
+func (agg *FlowAggregator) add(flow *common.Flow) {
+	key := flow.AggregationKey()
+	aggFlow, ok := agg.flows[key]
+	if !ok {
+		if agg.maxFlows > 0 && len(agg.flows) >= agg.maxFlows {
+			agg.dropped++
+			aggregatorFlowsDropped.Add(1)
+			return
+		}
+		agg.flows[key] = flow
+		return
+	}
+
+	aggFlow.Bytes += flow.Bytes
+	aggFlow.Packets += flow.Packets
+	aggFlow.TCPFlags |= flow.TCPFlags
+	if flow.StartTimestamp < aggFlow.StartTimestamp {
+		aggFlow.StartTimestamp = flow.StartTimestamp
+	}
+	if flow.EndTimestamp > aggFlow.EndTimestamp {
+		aggFlow.EndTimestamp = flow.EndTimestamp
+	}
+}
+
+func (agg *FlowAggregator) flush() {
+	if agg.dropped > 0 {
+		log.Warnf("Dropped %d flows, the maximum number of aggregated flows (%d) was reached", agg.dropped, agg.maxFlows)
+		agg.dropped = 0
+	}
+
+	if len(agg.flows) == 0 {
+		return
+	}
+	log.Debugf("Flushing %d flows", len(agg.flows))
+
+	for _, flow := range agg.flows {
+		deviceID := flow.Namespace + ":" + flow.ExporterAddr.String()
+		interfaceName := func(index uint32) string {
+			name, _ := snmpmetadata.GetInterfaceName(deviceID, int32(index))
+			return name
+		}
+
+		flowPayload := payload.FormatFlow(flow, flow.Namespace, agg.hostname, interfaceName)
+		payloadBytes, err := json.Marshal(flowPayload)
+		if err != nil {
+			log.Errorf("Error marshalling flow: %s", err)
+			continue
+		}
+		agg.sender.EventPlatformEvent(string(payloadBytes), epforwarder.EventTypeNetworkDevicesNetFlow)
+	}
+
+	aggregatorFlowsFlushed.Add(int64(len(agg.flows)))
+	agg.flows = make(map[common.AggregationKey]*common.Flow)
+}
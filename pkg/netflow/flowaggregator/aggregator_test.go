@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flowaggregator
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	snmpmetadata "github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/metadata"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+	"github.com/DataDog/datadog-agent/pkg/netflow/config"
+	"github.com/DataDog/datadog-agent/pkg/netflow/payload"
+)
+
+func newFlow(srcPort uint32, bytes uint64, start uint64, end uint64) *common.Flow {
+	return &common.Flow{
+		FlowType:        common.TypeNetFlow9,
+		ExporterAddr:    net.ParseIP("127.0.0.1"),
+		Namespace:       "my-ns",
+		StartTimestamp:  start,
+		EndTimestamp:    end,
+		Bytes:           bytes,
+		Packets:         1,
+		EtherType:       common.EtherTypeIPv4,
+		IPProtocol:      6,
+		SrcAddr:         net.ParseIP("10.10.10.10"),
+		DstAddr:         net.ParseIP("10.10.10.20"),
+		SrcPort:         srcPort,
+		DstPort:         80,
+		InputInterface:  1,
+		OutputInterface: 2,
+		TCPFlags:        0x02,
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	sender := mocksender.NewMockSender("")
+	sender.On("EventPlatformEvent", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return()
+
+	snmpmetadata.SetDeviceInterfaces("my-ns:127.0.0.1", []snmpmetadata.InterfaceMetadata{
+		{DeviceID: "my-ns:127.0.0.1", Index: 1, Name: "eth0"},
+	})
+
+	agg := NewFlowAggregator(sender, &config.NetflowConfig{
+		AggregatorBufferSize:    10,
+		AggregatorFlushInterval: 3600,
+	}, "my-hostname")
+	agg.Start()
+
+	flow := newFlow(2000, 100, 1600000010, 1600000020)
+	flow.TCPFlags = 0x10
+	agg.GetFlowInChan() <- newFlow(2000, 200, 1600000000, 1600000015)
+	agg.GetFlowInChan() <- flow
+	agg.GetFlowInChan() <- newFlow(3000, 50, 1600000000, 1600000000)
+
+	// pending flows are flushed on stop
+	agg.Stop()
+
+	sender.AssertNumberOfCalls(t, "EventPlatformEvent", 2)
+
+	var flows []payload.FlowPayload
+	for _, call := range sender.Calls {
+		if call.Method != "EventPlatformEvent" {
+			continue
+		}
+		assert.Equal(t, epforwarder.EventTypeNetworkDevicesNetFlow, call.Arguments.String(1))
+
+		var flowPayload payload.FlowPayload
+		require.NoError(t, json.Unmarshal([]byte(call.Arguments.String(0)), &flowPayload))
+		flows = append(flows, flowPayload)
+	}
+	if flows[0].Source.Port != 2000 {
+		flows[0], flows[1] = flows[1], flows[0]
+	}
+
+	assert.Equal(t, payload.FlowPayload{
+		FlowType:    "netflow9",
+		Direction:   "ingress",
+		Start:       1600000000,
+		End:         1600000020,
+		Bytes:       300,
+		Packets:     2,
+		EtherType:   "IPv4",
+		IPProtocol:  "TCP",
+		Device:      payload.Device{Namespace: "my-ns"},
+		Exporter:    payload.Exporter{IP: "127.0.0.1"},
+		Source:      payload.Endpoint{IP: "10.10.10.10", Port: 2000, Mac: "00:00:00:00:00:00", Mask: "0.0.0.0/0"},
+		Destination: payload.Endpoint{IP: "10.10.10.20", Port: 80, Mac: "00:00:00:00:00:00", Mask: "0.0.0.0/0"},
+		Ingress:     payload.ObservationPoint{Interface: payload.Interface{Index: 1, Name: "eth0"}},
+		Egress:      payload.ObservationPoint{Interface: payload.Interface{Index: 2}},
+		Host:        "my-hostname",
+		TCPFlags:    []string{"SYN", "ACK"},
+	}, flows[0])
+	assert.Equal(t, uint64(50), flows[1].Bytes)
+	assert.Equal(t, uint32(3000), flows[1].Source.Port)
+}
+
+func TestAggregatorMaxFlows(t *testing.T) {
+	sender := mocksender.NewMockSender("")
+	sender.On("EventPlatformEvent", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return()
+
+	agg := NewFlowAggregator(sender, &config.NetflowConfig{
+		AggregatorBufferSize:    10,
+		AggregatorFlushInterval: 3600,
+		AggregatorMaxFlows:      2,
+	}, "my-hostname")
+
+	agg.add(newFlow(2000, 100, 1600000000, 1600000010))
+	agg.add(newFlow(3000, 100, 1600000000, 1600000010))
+	// new aggregation key past the limit
+	agg.add(newFlow(4000, 100, 1600000000, 1600000010))
+	// existing aggregation keys are still aggregated
+	agg.add(newFlow(2000, 50, 1600000000, 1600000010))
+
+	require.Len(t, agg.flows, 2)
+	assert.Equal(t, int64(1), agg.dropped)
+	assert.Equal(t, uint64(150), agg.flows[newFlow(2000, 0, 0, 0).AggregationKey()].Bytes)
+
+	agg.flush()
+	sender.AssertNumberOfCalls(t, "EventPlatformEvent", 2)
+	assert.Equal(t, int64(0), agg.dropped)
+	assert.Empty(t, agg.flows)
+}
@@ -0,0 +1,110 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+	"github.com/DataDog/datadog-agent/pkg/netflow/config"
+	"github.com/DataDog/datadog-agent/pkg/netflow/decoder"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// maxPacketSize is the maximum size of a UDP datagram
+const maxPacketSize = 65535
+
+// flowListener receives flow packets of a single flow type on a UDP socket
+type flowListener struct {
+	config    config.ListenerConfig
+	flowOut   chan *common.Flow
+	templates *decoder.TemplateCache
+	conn      *net.UDPConn
+	workers   sync.WaitGroup
+	closing   int32
+}
+
+func newFlowListener(c config.ListenerConfig, flowOut chan *common.Flow) *flowListener {
+	return &flowListener{
+		config:    c,
+		flowOut:   flowOut,
+		templates: decoder.NewTemplateCache(),
+	}
+}
+
+// Listen binds the listener socket and starts the decoding workers in the background
+func (l *flowListener) Listen() error {
+	addr, err := net.ResolveUDPAddr("udp", l.config.Addr())
+	if err != nil {
+		return err
+	}
+
+	l.conn, err = net.ListenUDP("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < l.config.Workers; i++ {
+		// each worker has its own decoder, templates are shared by the listener workers
+		d, err := decoder.NewDecoder(l.config.FlowType, l.templates)
+		if err != nil {
+			l.conn.Close()
+			return err
+		}
+		l.workers.Add(1)
+		go l.run(d)
+	}
+
+	log.Infof("Start listening for %s flows on %s", l.config.FlowType, l.config.Addr())
+	return nil
+}
+
+func (l *flowListener) run(d decoder.Decoder) {
+	defer l.workers.Done()
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, remote, err := l.conn.ReadFromUDP(buf)
+		if err != nil {
+			if atomic.LoadInt32(&l.closing) == 1 {
+				return
+			}
+			log.Debugf("Error reading packet on listener %s: %v", l.config.Addr(), err)
+			continue
+		}
+		l.handlePacket(d, buf[:n], remote)
+	}
+}
+
+func (l *flowListener) handlePacket(d decoder.Decoder, payload []byte, remote *net.UDPAddr) {
+	flowType := string(l.config.FlowType)
+	netflowPackets.Add(flowType, 1)
+
+	flows, err := d.Decode(payload, remote.IP)
+	if errors.Is(err, decoder.ErrMissingTemplate) {
+		log.Debugf("Missing template for %s packet from %s on listener %s", flowType, remote.String(), l.config.Addr())
+		netflowMissingTemplates.Add(flowType, 1)
+	} else if err != nil {
+		log.Debugf("Failed to decode %s packet from %s on listener %s: %v", flowType, remote.String(), l.config.Addr(), err)
+		netflowDecodeErrors.Add(flowType, 1)
+	}
+
+	netflowFlows.Add(flowType, int64(len(flows)))
+	for _, flow := range flows {
+		flow.Namespace = l.config.Namespace
+		l.flowOut <- flow
+	}
+}
+
+// Close stops the listener and waits for the workers to return
+func (l *flowListener) Close() {
+	atomic.StoreInt32(&l.closing, 1)
+	l.conn.Close()
+	l.workers.Wait()
+}
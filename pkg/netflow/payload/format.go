@@ -0,0 +1,128 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package payload
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+var etherTypeNames = map[uint32]string{
+	common.EtherTypeIPv4: "IPv4",
+	common.EtherTypeIPv6: "IPv6",
+}
+
+var ipProtocolNames = map[uint32]string{
+	1:   "ICMP",
+	2:   "IGMP",
+	6:   "TCP",
+	17:  "UDP",
+	47:  "GRE",
+	50:  "ESP",
+	51:  "AH",
+	58:  "IPv6-ICMP",
+	89:  "OSPF",
+	132: "SCTP",
+}
+
+// tcpFlagNames are the names of the TCP flags, by bit position
+var tcpFlagNames = []string{"FIN", "SYN", "RST", "PSH", "ACK", "URG", "ECE", "CWR"}
+
+// FormatFlow builds the payload of a flow. The device namespace and interface names
+// are used to correlate the flow with the network devices monitored by the snmp check.
+func FormatFlow(flow *common.Flow, namespace string, hostname string, interfaceName func(index uint32) string) FlowPayload {
+	return FlowPayload{
+		FlowType:     string(flow.FlowType),
+		SamplingRate: flow.SamplingRate,
+		Direction:    formatDirection(flow.Direction),
+		Start:        flow.StartTimestamp,
+		End:          flow.EndTimestamp,
+		Bytes:        flow.Bytes,
+		Packets:      flow.Packets,
+		EtherType:    etherTypeNames[flow.EtherType],
+		IPProtocol:   formatIPProtocol(flow.IPProtocol),
+		Device:       Device{Namespace: namespace},
+		Exporter:     Exporter{IP: formatIP(flow.ExporterAddr)},
+		Source: Endpoint{
+			IP:   formatIP(flow.SrcAddr),
+			Port: flow.SrcPort,
+			Mac:  formatMac(flow.SrcMac),
+			Mask: formatMask(flow.SrcAddr, flow.SrcMask),
+		},
+		Destination: Endpoint{
+			IP:   formatIP(flow.DstAddr),
+			Port: flow.DstPort,
+			Mac:  formatMac(flow.DstMac),
+			Mask: formatMask(flow.DstAddr, flow.DstMask),
+		},
+		Ingress: ObservationPoint{
+			Interface: Interface{Index: flow.InputInterface, Name: interfaceName(flow.InputInterface)},
+		},
+		Egress: ObservationPoint{
+			Interface: Interface{Index: flow.OutputInterface, Name: interfaceName(flow.OutputInterface)},
+		},
+		Host:     hostname,
+		TCPFlags: formatTCPFlags(flow.TCPFlags),
+		NextHop:  NextHop{IP: formatIP(flow.NextHop)},
+	}
+}
+
+func formatDirection(direction uint32) string {
+	if direction == common.DirectionEgress {
+		return "egress"
+	}
+	return "ingress"
+}
+
+func formatIPProtocol(protocol uint32) string {
+	if name, ok := ipProtocolNames[protocol]; ok {
+		return name
+	}
+	return strconv.Itoa(int(protocol))
+}
+
+func formatIP(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+func formatMac(mac uint64) string {
+	return fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x",
+		byte(mac>>40), byte(mac>>32), byte(mac>>24), byte(mac>>16), byte(mac>>8), byte(mac))
+}
+
+// formatMask returns the network of an address with the given prefix length, in CIDR notation
+func formatMask(ip net.IP, prefixLength uint32) string {
+	if ip == nil {
+		return ""
+	}
+	bits := net.IPv6len * 8
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+		bits = net.IPv4len * 8
+	}
+	if int(prefixLength) > bits {
+		return ""
+	}
+	mask := net.CIDRMask(int(prefixLength), bits)
+	network := net.IPNet{IP: ip.Mask(mask), Mask: mask}
+	return network.String()
+}
+
+func formatTCPFlags(flags uint32) []string {
+	var names []string
+	for i, name := range tcpFlagNames {
+		if flags&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
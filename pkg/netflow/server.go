@@ -0,0 +1,115 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/netflow/config"
+	"github.com/DataDog/datadog-agent/pkg/netflow/flowaggregator"
+	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Server manages the NetFlow listeners and the flow aggregator.
+type Server struct {
+	config    *config.NetflowConfig
+	listeners []*flowListener
+	flowAgg   *flowaggregator.FlowAggregator
+}
+
+var (
+	serverInstance *Server
+	startError     error
+)
+
+// IsEnabled returns whether the NetFlow collector is enabled in the Agent configuration.
+func IsEnabled() bool {
+	return coreconfig.Datadog.GetBool("network_devices.netflow.enabled")
+}
+
+// StartServer starts the global NetFlow server.
+func StartServer(demux aggregator.Demultiplexer) error {
+	server, err := NewNetflowServer(demux)
+	serverInstance = server
+	startError = err
+	return err
+}
+
+// StopServer stops the global NetFlow server, if it is running.
+func StopServer() {
+	if serverInstance != nil {
+		serverInstance.Stop()
+		serverInstance = nil
+		startError = nil
+	}
+}
+
+// IsRunning returns whether the NetFlow server is currently running.
+func IsRunning() bool {
+	return serverInstance != nil
+}
+
+// NewNetflowServer configures and returns a running NetFlow server.
+func NewNetflowServer(demux aggregator.Demultiplexer) (*Server, error) {
+	mainConfig, err := config.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	sender, err := demux.GetDefaultSender()
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, err := util.GetHostname(context.TODO())
+	if err != nil {
+		log.Warnf("Error getting the hostname: %v", err)
+		hostname = ""
+	}
+
+	flowAgg := flowaggregator.NewFlowAggregator(sender, mainConfig, hostname)
+	flowAgg.Start()
+
+	server := &Server{
+		config:  mainConfig,
+		flowAgg: flowAgg,
+	}
+
+	for _, listenerConfig := range mainConfig.Listeners {
+		listener := newFlowListener(listenerConfig, flowAgg.GetFlowInChan())
+		if err := listener.Listen(); err != nil {
+			server.Stop()
+			return nil, err
+		}
+		server.listeners = append(server.listeners, listener)
+	}
+
+	return server, nil
+}
+
+// Stop stops the listeners, then flushes and stops the flow aggregator.
+func (s *Server) Stop() {
+	stopped := make(chan interface{})
+
+	go func() {
+		for _, listener := range s.listeners {
+			log.Infof("Stop listening on %s", listener.config.Addr())
+			listener.Close()
+		}
+		s.flowAgg.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Duration(s.config.StopTimeout) * time.Second):
+		log.Errorf("Stopping server. Timeout after %d seconds", s.config.StopTimeout)
+	}
+}
@@ -0,0 +1,43 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"encoding/json"
+	"expvar"
+)
+
+var (
+	netflowExpvars          = expvar.NewMap("netflow")
+	netflowPackets          = expvar.Map{}
+	netflowFlows            = expvar.Map{}
+	netflowDecodeErrors     = expvar.Map{}
+	netflowMissingTemplates = expvar.Map{}
+)
+
+func init() {
+	netflowExpvars.Set("Packets", &netflowPackets)
+	netflowExpvars.Set("Flows", &netflowFlows)
+	netflowExpvars.Set("DecodeErrors", &netflowDecodeErrors)
+	netflowExpvars.Set("MissingTemplates", &netflowMissingTemplates)
+}
+
+// GetStatus returns key-value data for use in status reporting of the NetFlow server.
+func GetStatus() map[string]interface{} {
+	status := make(map[string]interface{})
+
+	metricsJSON := []byte(expvar.Get("netflow").String())
+	metrics := make(map[string]interface{})
+	json.Unmarshal(metricsJSON, &metrics) //nolint:errcheck
+	status["metrics"] = metrics
+
+	if startError != nil {
+		status["error"] = startError.Error()
+	}
+	status["running"] = IsRunning()
+
+	return status
+}
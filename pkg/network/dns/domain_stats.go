@@ -0,0 +1,246 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package dns
+
+import (
+	"sort"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"go4.org/intern"
+)
+
+const (
+	// rcodeNXDomain is the response code returned for non-existent domains
+	rcodeNXDomain = 3
+	// rcodeServFail is the response code returned when the server failed to complete the request
+	rcodeServFail = 2
+)
+
+// LatencyBucketsMicros holds the upper bounds (inclusive, in microseconds) of
+// the buckets used by LatencyHistogram. Latencies above the last bound are
+// counted in an extra overflow bucket.
+var LatencyBucketsMicros = []uint64{
+	500,
+	1000,
+	2500,
+	5000,
+	10000,
+	25000,
+	50000,
+	100000,
+	250000,
+	500000,
+	1000000,
+}
+
+// LatencyHistogram is a fixed-bucket distribution of DNS response latencies
+type LatencyHistogram struct {
+	// Buckets holds one count per entry of LatencyBucketsMicros plus the overflow bucket
+	Buckets []uint32 `json:"buckets"`
+	Count   uint32   `json:"count"`
+	SumUs   uint64   `json:"sum_us"`
+	MinUs   uint64   `json:"min_us"`
+	MaxUs   uint64   `json:"max_us"`
+}
+
+// Add records a latency sample expressed in microseconds
+func (h *LatencyHistogram) Add(latency uint64) {
+	if h.Buckets == nil {
+		h.Buckets = make([]uint32, len(LatencyBucketsMicros)+1)
+	}
+
+	i := sort.Search(len(LatencyBucketsMicros), func(i int) bool {
+		return latency <= LatencyBucketsMicros[i]
+	})
+	h.Buckets[i]++
+
+	if h.Count == 0 || latency < h.MinUs {
+		h.MinUs = latency
+	}
+	if latency > h.MaxUs {
+		h.MaxUs = latency
+	}
+	h.Count++
+	h.SumUs += latency
+}
+
+// merge adds the samples of other to the histogram
+func (h *LatencyHistogram) merge(other LatencyHistogram) {
+	if other.Count == 0 {
+		return
+	}
+	if h.Buckets == nil {
+		h.Buckets = make([]uint32, len(LatencyBucketsMicros)+1)
+	}
+	for i, count := range other.Buckets {
+		h.Buckets[i] += count
+	}
+
+	if h.Count == 0 || other.MinUs < h.MinUs {
+		h.MinUs = other.MinUs
+	}
+	if other.MaxUs > h.MaxUs {
+		h.MaxUs = other.MaxUs
+	}
+	h.Count += other.Count
+	h.SumUs += other.SumUs
+}
+
+// DomainStats holds DNS statistics aggregated by queried domain and DNS server,
+// regardless of the client connection that issued the queries
+type DomainStats struct {
+	Domain       string            `json:"domain"`
+	Server       string            `json:"server"`
+	Queries      uint32            `json:"queries"`
+	Timeouts     uint32            `json:"timeouts"`
+	CountByRcode map[uint32]uint32 `json:"count_by_rcode"`
+	NXDomainRate float64           `json:"nxdomain_rate"`
+	ServFailRate float64           `json:"servfail_rate"`
+	Latency      LatencyHistogram  `json:"latency"`
+}
+
+// TopDomainStats sorts the given stats by descending number of queries and
+// returns at most n of them. A non-positive n returns all the stats.
+func TopDomainStats(stats []DomainStats, n int) []DomainStats {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Queries != stats[j].Queries {
+			return stats[i].Queries > stats[j].Queries
+		}
+		if stats[i].Domain != stats[j].Domain {
+			return stats[i].Domain < stats[j].Domain
+		}
+		return stats[i].Server < stats[j].Server
+	})
+
+	if n > 0 && n < len(stats) {
+		return stats[:n]
+	}
+	return stats
+}
+
+type domainKey struct {
+	server   util.Address
+	question *intern.Value
+}
+
+type domainStats struct {
+	timeouts     uint32
+	countByRcode map[uint32]uint32
+	latency      LatencyHistogram
+}
+
+func (s *domainStats) merge(other *domainStats) {
+	s.timeouts += other.timeouts
+	for rcode, count := range other.countByRcode {
+		s.countByRcode[rcode] += count
+	}
+	s.latency.merge(other.latency)
+}
+
+// domainStatsClient holds the stats of a client since its last request
+type domainStatsClient struct {
+	lastFetch time.Time
+	stats     map[domainKey]*domainStats
+}
+
+// domainStatsAggregator aggregates DNS responses by domain and server. Like the connections
+// state, the aggregated stats are handed to every client, so that a client fetching them
+// doesn't reset the stats of the others. It is not safe for concurrent use.
+type domainStatsAggregator struct {
+	stats        map[domainKey]*domainStats
+	clients      map[string]*domainStatsClient
+	clientExpiry time.Duration
+	maxStats     int
+	dropped      int
+}
+
+func newDomainStatsAggregator(maxStats int, clientExpiry time.Duration) *domainStatsAggregator {
+	return &domainStatsAggregator{
+		stats:        make(map[domainKey]*domainStats),
+		clients:      make(map[string]*domainStatsClient),
+		clientExpiry: clientExpiry,
+		maxStats:     maxStats,
+	}
+}
+
+func (a *domainStatsAggregator) get(stats map[domainKey]*domainStats, k domainKey) *domainStats {
+	s, ok := stats[k]
+	if !ok {
+		if len(stats) >= a.maxStats {
+			a.dropped++
+			return nil
+		}
+		s = &domainStats{countByRcode: make(map[uint32]uint32)}
+		stats[k] = s
+	}
+	return s
+}
+
+func (a *domainStatsAggregator) addResponse(server util.Address, question *intern.Value, rcode uint8, latency uint64) {
+	if s := a.get(a.stats, domainKey{server: server, question: question}); s != nil {
+		s.countByRcode[uint32(rcode)]++
+		s.latency.Add(latency)
+	}
+}
+
+func (a *domainStatsAggregator) addTimeout(server util.Address, question *intern.Value) {
+	if s := a.get(a.stats, domainKey{server: server, question: question}); s != nil {
+		s.timeouts++
+	}
+}
+
+// flush hands the stats aggregated since the last flush to every client, and returns the
+// stats of the given client since its last flush. Clients which didn't flush their stats
+// for longer than the client expiry are dropped.
+func (a *domainStatsAggregator) flush(clientID string, now time.Time) []DomainStats {
+	for id, c := range a.clients {
+		if id != clientID && now.Sub(c.lastFetch) > a.clientExpiry {
+			delete(a.clients, id)
+		}
+	}
+	client, ok := a.clients[clientID]
+	if !ok {
+		client = &domainStatsClient{stats: make(map[domainKey]*domainStats)}
+		a.clients[clientID] = client
+	}
+
+	for _, c := range a.clients {
+		for k, s := range a.stats {
+			if cs := a.get(c.stats, k); cs != nil {
+				cs.merge(s)
+			}
+		}
+	}
+	a.stats = make(map[domainKey]*domainStats)
+
+	ret := make([]DomainStats, 0, len(client.stats))
+	for k, s := range client.stats {
+		ds := DomainStats{
+			Server:       k.server.String(),
+			Timeouts:     s.timeouts,
+			CountByRcode: s.countByRcode,
+			Latency:      s.latency,
+		}
+		if k.question != nil {
+			ds.Domain = k.question.Get().(string)
+		}
+
+		ds.Queries = s.timeouts
+		for _, count := range s.countByRcode {
+			ds.Queries += count
+		}
+		if ds.Queries > 0 {
+			ds.NXDomainRate = float64(s.countByRcode[rcodeNXDomain]) / float64(ds.Queries)
+			ds.ServFailRate = float64(s.countByRcode[rcodeServFail]) / float64(ds.Queries)
+		}
+		ret = append(ret, ds)
+	}
+
+	client.stats = make(map[domainKey]*domainStats)
+	client.lastFetch = now
+	return ret
+}
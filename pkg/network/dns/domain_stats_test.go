@@ -0,0 +1,129 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package dns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"go4.org/intern"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	var h LatencyHistogram
+	h.Add(100)
+	h.Add(500)
+	h.Add(501)
+	h.Add(2000000)
+
+	require.Len(t, h.Buckets, len(LatencyBucketsMicros)+1)
+	assert.Equal(t, uint32(2), h.Buckets[0])
+	assert.Equal(t, uint32(1), h.Buckets[1])
+	assert.Equal(t, uint32(1), h.Buckets[len(LatencyBucketsMicros)])
+	assert.Equal(t, uint32(4), h.Count)
+	assert.Equal(t, uint64(2001101), h.SumUs)
+	assert.Equal(t, uint64(100), h.MinUs)
+	assert.Equal(t, uint64(2000000), h.MaxUs)
+}
+
+func TestDomainStatsAggregator(t *testing.T) {
+	server := util.AddressFromString("8.8.8.8")
+	foo := intern.GetByString("foo.com")
+	bar := intern.GetByString("bar.com")
+
+	a := newDomainStatsAggregator(10, time.Minute)
+	a.addResponse(server, foo, 0, 1000)
+	a.addResponse(server, foo, rcodeNXDomain, 2000)
+	a.addResponse(server, foo, rcodeServFail, 3000)
+	a.addTimeout(server, foo)
+	a.addResponse(server, bar, 0, 1000)
+
+	stats := TopDomainStats(a.flush("client", time.Now()), 0)
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, "foo.com", stats[0].Domain)
+	assert.Equal(t, "8.8.8.8", stats[0].Server)
+	assert.Equal(t, uint32(4), stats[0].Queries)
+	assert.Equal(t, uint32(1), stats[0].Timeouts)
+	assert.Equal(t, 0.25, stats[0].NXDomainRate)
+	assert.Equal(t, 0.25, stats[0].ServFailRate)
+	assert.Equal(t, uint32(3), stats[0].Latency.Count)
+	assert.Equal(t, uint64(6000), stats[0].Latency.SumUs)
+
+	assert.Equal(t, "bar.com", stats[1].Domain)
+	assert.Equal(t, uint32(1), stats[1].Queries)
+
+	// stats are reset after a flush
+	assert.Empty(t, a.flush("client", time.Now()))
+}
+
+func TestDomainStatsAggregatorMaxStats(t *testing.T) {
+	server := util.AddressFromString("8.8.8.8")
+
+	a := newDomainStatsAggregator(1, time.Minute)
+	a.addResponse(server, intern.GetByString("foo.com"), 0, 1000)
+	a.addResponse(server, intern.GetByString("bar.com"), 0, 1000)
+	a.addTimeout(server, intern.GetByString("baz.com"))
+
+	assert.Equal(t, 2, a.dropped)
+	assert.Len(t, a.flush("client", time.Now()), 1)
+}
+
+func TestDomainStatsAggregatorClients(t *testing.T) {
+	server := util.AddressFromString("8.8.8.8")
+	foo := intern.GetByString("foo.com")
+	now := time.Now()
+
+	a := newDomainStatsAggregator(10, time.Minute)
+	a.addResponse(server, foo, 0, 1000)
+	stats := a.flush("process-agent", now)
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint32(1), stats[0].Queries)
+
+	// a new client gets the stats aggregated since the last flush
+	a.addResponse(server, foo, rcodeNXDomain, 3000)
+	stats = a.flush("debug", now)
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint32(1), stats[0].Queries)
+	assert.Equal(t, uint64(3000), stats[0].Latency.SumUs)
+
+	// the stats fetched by the other client are kept for the first one
+	a.addResponse(server, foo, 0, 5000)
+	stats = a.flush("process-agent", now)
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint32(2), stats[0].Queries)
+	assert.Equal(t, map[uint32]uint32{0: 1, rcodeNXDomain: 1}, stats[0].CountByRcode)
+	assert.Equal(t, uint32(2), stats[0].Latency.Count)
+	assert.Equal(t, uint64(3000), stats[0].Latency.MinUs)
+	assert.Equal(t, uint64(5000), stats[0].Latency.MaxUs)
+	assert.Empty(t, a.flush("process-agent", now))
+
+	// clients which didn't fetch their stats in a while are dropped
+	a.flush("process-agent", now.Add(2*time.Minute))
+	assert.Len(t, a.clients, 1)
+	assert.Contains(t, a.clients, "process-agent")
+}
+
+func TestTopDomainStats(t *testing.T) {
+	stats := []DomainStats{
+		{Domain: "a.com", Server: "8.8.8.8", Queries: 1},
+		{Domain: "b.com", Server: "8.8.8.8", Queries: 5},
+		{Domain: "c.com", Server: "8.8.4.4", Queries: 3},
+		{Domain: "c.com", Server: "8.8.8.8", Queries: 3},
+	}
+
+	top := TopDomainStats(stats, 2)
+	require.Len(t, top, 2)
+	assert.Equal(t, "b.com", top[0].Domain)
+	assert.Equal(t, "c.com", top[1].Domain)
+	assert.Equal(t, "8.8.4.4", top[1].Server)
+
+	assert.Len(t, TopDomainStats(stats, 10), 4)
+}
@@ -74,7 +74,7 @@ func newSocketFilterSnooper(cfg *config.Config, source packetSource) (*socketFil
 	cache := newReverseDNSCache(dnsCacheSize, dnsCacheExpirationPeriod)
 	var statKeeper *dnsStatKeeper
 	if cfg.CollectDNSStats {
-		statKeeper = newDNSStatkeeper(cfg.DNSTimeout, cfg.MaxDNSStats)
+		statKeeper = newDNSStatkeeper(cfg.DNSTimeout, cfg.MaxDNSStats, cfg.ClientStateExpiry)
 		log.Infof("DNS Stats Collection has been enabled. Maximum number of stats objects: %d", cfg.MaxDNSStats)
 		if cfg.CollectDNSDomains {
 			log.Infof("DNS domain collection has been enabled")
@@ -121,6 +121,14 @@ func (s *socketFilterSnooper) GetDNSStats() StatsByKeyByNameByType {
 	return s.statKeeper.GetAndResetAllStats()
 }
 
+// GetDomainStats gets the Stats aggregated by domain and DNS server since the last call of the client
+func (s *socketFilterSnooper) GetDomainStats(clientID string) []DomainStats {
+	if s.statKeeper == nil {
+		return nil
+	}
+	return s.statKeeper.GetAndResetDomainStats(clientID)
+}
+
 // GetStats returns stats for use with telemetry
 func (s *socketFilterSnooper) GetStats() map[string]int64 {
 	stats := s.cache.Stats()
@@ -139,6 +147,7 @@ func (s *socketFilterSnooper) GetStats() map[string]int64 {
 		numStats, droppedStats := s.statKeeper.GetNumStats()
 		stats["num_stats"] = int64(numStats)
 		stats["dropped_stats"] = int64(droppedStats)
+		stats["dropped_domain_stats"] = int64(s.statKeeper.GetDroppedDomainStats())
 	}
 	return stats
 }
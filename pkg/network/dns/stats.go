@@ -69,9 +69,13 @@ type dnsStatKeeper struct {
 	droppedStats     int
 	lastNumStats     int32
 	lastDroppedStats int32
+
+	// domainStats aggregates the same responses by domain and server only, for each client
+	domainStats            *domainStatsAggregator
+	lastDroppedDomainStats int32
 }
 
-func newDNSStatkeeper(timeout time.Duration, maxStats int) *dnsStatKeeper {
+func newDNSStatkeeper(timeout time.Duration, maxStats int, clientExpiry time.Duration) *dnsStatKeeper {
 	statsKeeper := &dnsStatKeeper{
 		stats:            make(StatsByKeyByNameByType),
 		state:            make(map[stateKey]stateValue),
@@ -79,6 +83,7 @@ func newDNSStatkeeper(timeout time.Duration, maxStats int) *dnsStatKeeper {
 		exit:             make(chan struct{}),
 		maxSize:          maxStateMapSize,
 		maxStats:         maxStats,
+		domainStats:      newDomainStatsAggregator(maxStats, clientExpiry),
 	}
 
 	ticker := time.NewTicker(statsKeeper.expirationPeriod)
@@ -127,6 +132,11 @@ func (d *dnsStatKeeper) ProcessPacketInfo(info dnsPacketInfo, ts time.Time) {
 	d.deleteCount++
 
 	latency := microSecs(ts) - start.ts
+	if latency > uint64(d.expirationPeriod.Microseconds()) {
+		d.domainStats.addTimeout(info.key.ServerIP, start.question)
+	} else {
+		d.domainStats.addResponse(info.key.ServerIP, start.question, info.rCode, latency)
+	}
 
 	allStats, ok := d.stats[info.key]
 	if !ok {
@@ -185,6 +195,21 @@ func (d *dnsStatKeeper) GetAndResetAllStats() StatsByKeyByNameByType {
 	return ret
 }
 
+// GetAndResetDomainStats returns the stats aggregated by domain and DNS server since the last call of the client
+func (d *dnsStatKeeper) GetAndResetDomainStats(clientID string) []DomainStats {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	stats := d.domainStats.flush(clientID, time.Now())
+	atomic.StoreInt32(&d.lastDroppedDomainStats, int32(d.domainStats.dropped))
+	d.domainStats.dropped = 0
+	return stats
+}
+
+// GetDroppedDomainStats returns the number of domain stats dropped during the last aggregation interval
+func (d *dnsStatKeeper) GetDroppedDomainStats() int32 {
+	return atomic.LoadInt32(&d.lastDroppedDomainStats)
+}
+
 // Snapshot returns a deep copy of all DNS stats.
 // Please only use this for testing.
 func (d *dnsStatKeeper) Snapshot() StatsByKeyByNameByType {
@@ -221,6 +246,7 @@ func (d *dnsStatKeeper) removeExpiredStates(earliestTs time.Time) {
 		if v.ts < threshold {
 			delete(d.state, k)
 			d.deleteCount++
+			d.domainStats.addTimeout(k.key.ServerIP, v.question)
 			// When we expire a state, we need to increment timeout count for that key:domain
 			allStats, ok := d.stats[k.key]
 			if !ok {
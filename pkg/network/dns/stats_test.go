@@ -44,7 +44,7 @@ func testLatency(
 	expectedTimeouts uint32,
 ) {
 	var d = intern.GetByString("abc.com")
-	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000)
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, time.Minute)
 	key := getSampleDNSKey()
 	qPkt := dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}
 	then := time.Now()
@@ -81,7 +81,7 @@ func TestTimeout(t *testing.T) {
 }
 
 func TestExpiredStateRemoval(t *testing.T) {
-	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000)
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, time.Minute)
 	key := getSampleDNSKey()
 	var d = intern.GetByString("abc.com")
 	qPkt1 := dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}
@@ -125,7 +125,7 @@ func BenchmarkStats(b *testing.B) {
 			b.ResetTimer()
 			b.ReportAllocs()
 			for i := 0; i < b.N; i++ {
-				sk := newDNSStatkeeper(1000*time.Second, 10000)
+				sk := newDNSStatkeeper(1000*time.Second, 10000, time.Minute)
 				for j := 0; j < numPackets; j++ {
 					sk.ProcessPacketInfo(packets[j], ts)
 				}
@@ -133,3 +133,34 @@ func BenchmarkStats(b *testing.B) {
 		})
 	}
 }
+
+func TestDomainStatsAcrossClients(t *testing.T) {
+	d := intern.GetByString("abc.com")
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, time.Minute)
+	then := time.Now()
+
+	for i, rcode := range []uint8{0, 3, 3} {
+		key := getSampleDNSKey()
+		key.ClientPort = uint16(1000 + i)
+		qPkt := dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}
+		rPkt := dnsPacketInfo{transactionID: 1, key: key, pktType: failedResponse, rCode: rcode, queryType: TypeA}
+		if rcode == 0 {
+			rPkt.pktType = successfulResponse
+		}
+		sk.ProcessPacketInfo(qPkt, then)
+		sk.ProcessPacketInfo(rPkt, then.Add(time.Millisecond))
+	}
+
+	stats := sk.GetAndResetDomainStats("client")
+	require.Len(t, stats, 1)
+	assert.Equal(t, "abc.com", stats[0].Domain)
+	assert.Equal(t, "8.8.8.8", stats[0].Server)
+	assert.Equal(t, uint32(3), stats[0].Queries)
+	assert.Equal(t, map[uint32]uint32{0: 1, 3: 2}, stats[0].CountByRcode)
+	assert.InDelta(t, 2.0/3, stats[0].NXDomainRate, 0.001)
+	assert.Equal(t, uint32(3), stats[0].Latency.Count)
+
+	// per-connection stats are kept separately
+	assert.Len(t, sk.GetAndResetAllStats(), 3)
+	assert.Empty(t, sk.GetAndResetDomainStats("client"))
+}
@@ -46,6 +46,7 @@ type StatsByKeyByNameByType map[Key]map[*intern.Value]map[QueryType]Stats
 type ReverseDNS interface {
 	Resolve([]util.Address) map[util.Address][]string
 	GetDNSStats() StatsByKeyByNameByType
+	GetDomainStats(clientID string) []DomainStats
 	GetStats() map[string]int64
 	Close()
 }
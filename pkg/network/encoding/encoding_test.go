@@ -18,6 +18,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/network"
 	"github.com/DataDog/datadog-agent/pkg/network/dns"
 	"github.com/DataDog/datadog-agent/pkg/network/http"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/DataDog/sketches-go/ddsketch"
 	"github.com/DataDog/sketches-go/ddsketch/pb/sketchpb"
@@ -572,6 +573,46 @@ func TestHTTPSerializationWithLocalhostTraffic(t *testing.T) {
 	assert.Equal(t, out, result)
 }
 
+func TestProtocolSerialization(t *testing.T) {
+	in := &network.Connections{
+		BufferedData: network.BufferedData{
+			Conns: []network.ConnectionStats{
+				{
+					Source:   util.AddressFromString("10.1.1.1"),
+					Dest:     util.AddressFromString("10.2.2.2"),
+					SPort:    40000,
+					DPort:    5432,
+					Protocol: protocols.Postgres,
+				},
+				{
+					Source: util.AddressFromString("10.1.1.1"),
+					Dest:   util.AddressFromString("10.2.2.2"),
+					SPort:  40001,
+					DPort:  22,
+				},
+			},
+		},
+	}
+
+	for _, contentType := range []string{"application/json", "application/protobuf"} {
+		t.Run(contentType, func(t *testing.T) {
+			blob, err := GetMarshaler(contentType).Marshal(in)
+			require.NoError(t, err)
+
+			result, err := GetUnmarshaler(contentType).Unmarshal(blob)
+			require.NoError(t, err)
+
+			require.Len(t, result.Conns, 2)
+			var tags []string
+			for _, idx := range result.Conns[0].Tags {
+				tags = append(tags, result.Tags[idx])
+			}
+			assert.Equal(t, []string{"protocol:postgres"}, tags)
+			assert.Empty(t, result.Conns[1].Tags)
+		})
+	}
+}
+
 func TestPooledObjectGarbageRegression(t *testing.T) {
 	// This test ensures that no garbage data is accidentally
 	// left on pooled Connection objects used during serialization
@@ -12,6 +12,7 @@ import (
 	model "github.com/DataDog/agent-payload/v5/process"
 	"github.com/DataDog/datadog-agent/pkg/network"
 	"github.com/DataDog/datadog-agent/pkg/network/http"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -172,3 +173,15 @@ func BenchmarkConnectionReset(b *testing.B) {
 	}
 	runtime.KeepAlive(c)
 }
+
+func TestFormatProtocolTag(t *testing.T) {
+	tagsSet := network.NewTagsSet()
+
+	idx := formatTags(tagsSet, network.ConnectionStats{Protocol: protocols.Postgres})
+	require.Len(t, idx, 1)
+	assert.Equal(t, []string{"protocol:postgres"}, tagsSet.GetStrings())
+
+	// unclassified connections don't get a protocol tag
+	assert.Empty(t, formatTags(tagsSet, network.ConnectionStats{}))
+	assert.Equal(t, 1, tagsSet.Size())
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/network/dns"
 	"github.com/DataDog/datadog-agent/pkg/network/http"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/dustin/go-humanize"
 )
@@ -200,6 +201,9 @@ type ConnectionStats struct {
 	Tags             uint64
 
 	IsAssured bool
+
+	// Protocol is the application protocol classified from the connection payload
+	Protocol protocols.ProtocolType
 }
 
 // Via has info about the routing decision for a flow
@@ -317,6 +321,10 @@ func ConnectionSummary(c *ConnectionStats, names map[util.Address][]string) stri
 		)
 	}
 
+	if c.Protocol != protocols.Unknown {
+		str += fmt.Sprintf("[%s] ", c.Protocol)
+	}
+
 	str += fmt.Sprintf("(%s) %s sent (+%s), %s received (+%s)",
 		c.Direction,
 		humanize.Bytes(c.MonotonicSentBytes), humanize.Bytes(c.LastSentBytes),
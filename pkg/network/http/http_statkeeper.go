@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 )
 
 type httpStatKeeper struct {
@@ -30,6 +31,9 @@ type httpStatKeeper struct {
 	// map containing interned path strings
 	// this is rotated  with the stats map
 	interned map[string]string
+
+	// application protocols classified from the request fragments, by connection
+	classified map[Key]protocols.ProtocolType
 }
 
 func newHTTPStatkeeper(c *config.Config, telemetry *telemetry) *httpStatKeeper {
@@ -40,6 +44,7 @@ func newHTTPStatkeeper(c *config.Config, telemetry *telemetry) *httpStatKeeper {
 		replaceRules: c.HTTPReplaceRules,
 		buffer:       make([]byte, HTTPBufferSize),
 		interned:     make(map[string]string),
+		classified:   make(map[Key]protocols.ProtocolType),
 		telemetry:    telemetry,
 	}
 }
@@ -67,7 +72,17 @@ func (h *httpStatKeeper) GetAndResetAllStats() map[Key]RequestStats {
 	return ret
 }
 
+// GetAndResetProtocols returns the application protocols classified since the last call,
+// indexed by connection keys, which have no path nor method
+func (h *httpStatKeeper) GetAndResetProtocols() map[Key]protocols.ProtocolType {
+	ret := h.classified
+	h.classified = make(map[Key]protocols.ProtocolType)
+	return ret
+}
+
 func (h *httpStatKeeper) add(tx httpTX) {
+	h.classify(tx)
+
 	path, rejected := h.processHTTPPath(tx)
 	if rejected {
 		atomic.AddInt64(&h.telemetry.rejected, 1)
@@ -85,6 +100,18 @@ func (h *httpStatKeeper) add(tx httpTX) {
 	h.stats[key] = stats
 }
 
+// classify records the application protocol of the connection of the transaction
+func (h *httpStatKeeper) classify(tx httpTX) {
+	key := h.newKey(tx, "")
+	key.Method = MethodUnknown
+	if _, ok := h.classified[key]; ok || len(h.classified) >= h.maxEntries {
+		return
+	}
+	if protocol := protocols.Classify(tx.RequestFragment()); protocol != protocols.Unknown {
+		h.classified[key] = protocol
+	}
+}
+
 func (h *httpStatKeeper) newKey(tx httpTX, path string) Key {
 	return Key{
 		SrcIPHigh: uint64(tx.tup.saddr_h),
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -65,6 +66,28 @@ func TestProcessHTTPTransactions(t *testing.T) {
 	}
 }
 
+func TestClassifyHTTPTransactions(t *testing.T) {
+	cfg := &config.Config{MaxHTTPStatsBuffered: 1000}
+	sk := newHTTPStatkeeper(cfg, newTelemetry())
+	sourceIP := util.AddressFromString("1.1.1.1")
+	destIP := util.AddressFromString("2.2.2.2")
+
+	sk.Process([]httpTX{
+		generateIPv4HTTPTransaction(sourceIP, destIP, 1234, 8080, "/foo", 200, time.Millisecond),
+		generateIPv4HTTPTransaction(sourceIP, destIP, 1234, 8080, "/bar", 200, time.Millisecond),
+	})
+
+	classified := sk.GetAndResetProtocols()
+	require.Len(t, classified, 1)
+	for key, protocol := range classified {
+		assert.Equal(t, "", key.Path)
+		assert.Equal(t, MethodUnknown, key.Method)
+		assert.Equal(t, uint16(8080), key.DstPort)
+		assert.Equal(t, protocols.HTTP, protocol)
+	}
+	assert.Empty(t, sk.GetAndResetProtocols())
+}
+
 func generateIPv4HTTPTransaction(source util.Address, dest util.Address, sourcePort int, destPort int, path string, code int, latency time.Duration) httpTX {
 	var tx httpTX
 
@@ -65,6 +65,12 @@ func (tx *httpTX) Path(buffer []byte) []byte {
 	return nil
 }
 
+// RequestFragment returns the first bytes of the request captured in eBPF
+func (tx *httpTX) RequestFragment() []byte {
+	b := *(*[HTTPBufferSize]byte)(unsafe.Pointer(&tx.request_fragment))
+	return b[:strlen(b[:])]
+}
+
 // StatusClass returns an integer representing the status code class
 // Example: a 404 would return 400
 func (tx *httpTX) StatusClass() int {
@@ -17,6 +17,7 @@ import (
 	ddebpf "github.com/DataDog/datadog-agent/pkg/ebpf"
 	"github.com/DataDog/datadog-agent/pkg/network/config"
 	filterpkg "github.com/DataDog/datadog-agent/pkg/network/filter"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 	manager "github.com/DataDog/ebpf-manager"
 	"github.com/cilium/ebpf"
 )
@@ -36,6 +37,10 @@ type Monitor struct {
 	pollRequests           chan chan map[Key]RequestStats
 	statkeeper             *httpStatKeeper
 
+	// protocols classified until the last stats poll
+	protocolsMux sync.Mutex
+	protocols    map[Key]protocols.ProtocolType
+
 	// termination
 	mux           sync.Mutex
 	eventLoopWG   sync.WaitGroup
@@ -139,6 +144,9 @@ func (m *Monitor) Start() error {
 				m.process(transactions, nil)
 
 				stats := m.statkeeper.GetAndResetAllStats()
+				m.protocolsMux.Lock()
+				m.protocols = m.statkeeper.GetAndResetProtocols()
+				m.protocolsMux.Unlock()
 
 				delta := m.telemetry.reset()
 				delta.report()
@@ -173,6 +181,18 @@ func (m *Monitor) GetHTTPStats() map[Key]RequestStats {
 	return <-reply
 }
 
+// GetProtocols returns the application protocols classified from the HTTP request fragments
+// until the last call to GetHTTPStats, indexed by connection keys with no path nor method
+func (m *Monitor) GetProtocols() map[Key]protocols.ProtocolType {
+	if m == nil {
+		return nil
+	}
+
+	m.protocolsMux.Lock()
+	defer m.protocolsMux.Unlock()
+	return m.protocols
+}
+
 // Stop HTTP monitoring
 func (m *Monitor) Stop() {
 	if m == nil {
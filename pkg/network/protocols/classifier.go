@@ -0,0 +1,282 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package protocols
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// The classifiers below only look at the first bytes sent over a connection
+// (the same fragment the eBPF programs capture), so they must tolerate
+// truncated payloads and never rely on seeing a full message.
+
+var (
+	http2Preface      = []byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n")
+	grpcContentType   = []byte("application/grpc")
+	amqpHeader        = []byte("AMQP\x00\x00\x09\x01")
+	httpResponsePrefx = []byte("HTTP/1.")
+	httpMethods       = [][]byte{
+		[]byte("GET "),
+		[]byte("POST "),
+		[]byte("PUT "),
+		[]byte("DELETE "),
+		[]byte("HEAD "),
+		[]byte("OPTIONS "),
+		[]byte("PATCH "),
+		[]byte("CONNECT "),
+		[]byte("TRACE "),
+	}
+)
+
+const (
+	http2FrameHeaderSize  = 9
+	http2FrameTypeSetting = 0x4
+
+	postgresProtocolV3   = 196608 // 3.0
+	postgresSSLRequest   = 80877103
+	postgresGSSENCReq    = 80877104
+	postgresMaxStartupSz = 10000
+
+	mysqlProtocolV10 = 0x0a
+	mysqlComQuery    = 0x03
+	mysqlComPrepare  = 0x16
+	mysqlMaxPacket   = 1 << 24
+
+	kafkaMaxAPIKey     = 67
+	kafkaMaxAPIVersion = 15
+	kafkaHeaderSize    = 14
+
+	amqpFrameMethod = 1
+	amqpFrameEnd    = 0xce
+)
+
+// classifier associates a protocol with the function matching its first payload fragment
+type classifier struct {
+	protocol ProtocolType
+	match    func([]byte) bool
+}
+
+// classifiers are evaluated in order; more specific signatures go first
+var classifiers = []classifier{
+	{GRPC, isGRPC},
+	{HTTP2, isHTTP2},
+	{HTTP, isHTTP},
+	{AMQP, isAMQP},
+	{Postgres, isPostgres},
+	{MySQL, isMySQL},
+	{Kafka, isKafka},
+	{Redis, isRedis},
+}
+
+// Classify returns the application protocol of a connection based on the
+// first payload fragment seen in either direction.
+func Classify(fragment []byte) ProtocolType {
+	if len(fragment) == 0 {
+		return Unknown
+	}
+
+	for _, c := range classifiers {
+		if c.match(fragment) {
+			return c.protocol
+		}
+	}
+	return Unknown
+}
+
+func isHTTP(b []byte) bool {
+	if bytes.HasPrefix(b, httpResponsePrefx) {
+		return true
+	}
+	for _, m := range httpMethods {
+		if bytes.HasPrefix(b, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHTTP2(b []byte) bool {
+	if bytes.HasPrefix(b, http2Preface) {
+		return true
+	}
+
+	// servers start the connection with a SETTINGS frame on stream 0
+	if len(b) < http2FrameHeaderSize {
+		return false
+	}
+	length := uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+	streamID := binary.BigEndian.Uint32(b[5:9]) & 0x7fffffff
+	return b[3] == http2FrameTypeSetting && streamID == 0 && length%6 == 0
+}
+
+// isGRPC detects gRPC by looking for its content-type in the first HTTP/2
+// frames. This only works when the header value is not Huffman-encoded by
+// HPACK, otherwise the connection is reported as plain HTTP/2.
+func isGRPC(b []byte) bool {
+	return isHTTP2(b) && bytes.Contains(b, grpcContentType)
+}
+
+func isPostgres(b []byte) bool {
+	if len(b) < 8 {
+		return false
+	}
+
+	// untyped startup packets: StartupMessage, SSLRequest and GSSENCRequest
+	length := binary.BigEndian.Uint32(b[0:4])
+	code := binary.BigEndian.Uint32(b[4:8])
+	switch code {
+	case postgresSSLRequest, postgresGSSENCReq:
+		return length == 8
+	case postgresProtocolV3:
+		return length > 8 && length < postgresMaxStartupSz && bytes.Contains(b[8:], []byte("user\x00"))
+	}
+
+	// typed messages sent on an established session: simple query, parse and
+	// authentication requests from the backend
+	switch b[0] {
+	case 'Q', 'P':
+		length = binary.BigEndian.Uint32(b[1:5])
+		return length > 4 && int(length)+1 >= len(b) && isPrintable(b[5:min(len(b), 5+8)])
+	case 'R':
+		if len(b) < 9 {
+			return false
+		}
+		length = binary.BigEndian.Uint32(b[1:5])
+		return length >= 8 && length <= 32 && binary.BigEndian.Uint32(b[5:9]) <= 12
+	}
+	return false
+}
+
+func isMySQL(b []byte) bool {
+	if len(b) < 5 {
+		return false
+	}
+
+	length := int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+	seq := b[3]
+	if length == 0 || length >= mysqlMaxPacket || seq > 1 {
+		return false
+	}
+	// the packet must either fill the fragment or be truncated by it
+	if length+4 < len(b) {
+		return false
+	}
+
+	switch b[4] {
+	case mysqlProtocolV10:
+		// server greeting: protocol version followed by a NUL-terminated
+		// server version string
+		if seq != 0 {
+			return false
+		}
+		end := bytes.IndexByte(b[5:], 0)
+		return end > 0 && isPrintable(b[5:5+end])
+	case mysqlComQuery, mysqlComPrepare:
+		return seq == 0 && length > 1 && isPrintable(b[5:min(len(b), 5+8)])
+	}
+	return false
+}
+
+func isKafka(b []byte) bool {
+	if len(b) < kafkaHeaderSize {
+		return false
+	}
+
+	size := int32(binary.BigEndian.Uint32(b[0:4]))
+	apiKey := int16(binary.BigEndian.Uint16(b[4:6]))
+	apiVersion := int16(binary.BigEndian.Uint16(b[6:8]))
+	correlationID := int32(binary.BigEndian.Uint32(b[8:12]))
+	clientIDLen := int16(binary.BigEndian.Uint16(b[12:14]))
+
+	if size < kafkaHeaderSize-4 || apiKey < 0 || apiKey > kafkaMaxAPIKey {
+		return false
+	}
+	if apiVersion < 0 || apiVersion > kafkaMaxAPIVersion || correlationID < 0 {
+		return false
+	}
+	if clientIDLen == -1 {
+		return true
+	}
+	if clientIDLen < 0 || int32(clientIDLen) > size-10 {
+		return false
+	}
+
+	clientID := b[kafkaHeaderSize:min(len(b), kafkaHeaderSize+int(clientIDLen))]
+	return isPrintable(clientID)
+}
+
+func isRedis(b []byte) bool {
+	if len(b) < 4 {
+		return false
+	}
+
+	crlf := bytes.Index(b, []byte("\r\n"))
+	if crlf < 2 {
+		return false
+	}
+	line := b[1:crlf]
+
+	switch b[0] {
+	case '*':
+		// commands are sent as an array of bulk strings
+		return isDigits(line) && (len(b) == crlf+2 || b[crlf+2] == '$')
+	case '$', ':':
+		return isDigits(line) || (b[0] == '$' && bytes.Equal(line, []byte("-1")))
+	case '+', '-':
+		return isPrintable(line)
+	}
+	return false
+}
+
+func isAMQP(b []byte) bool {
+	if bytes.HasPrefix(b, amqpHeader) {
+		return true
+	}
+
+	// method frame: type (1B), channel (2B), size (4B), class/method ids,
+	// arguments and a frame-end marker
+	if len(b) < 12 || b[0] != amqpFrameMethod {
+		return false
+	}
+	size := binary.BigEndian.Uint32(b[3:7])
+	if size < 4 {
+		return false
+	}
+	if end := 7 + int(size); end < len(b) {
+		return b[end] == amqpFrameEnd
+	}
+	classID := binary.BigEndian.Uint16(b[7:9])
+	return classID == 10 || classID == 20 || classID == 40 || classID == 50 || classID == 60 || classID == 90
+}
+
+func isPrintable(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(b []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package protocols
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Each fixture in testdata is a captured payload fragment named after the
+// protocol it is expected to be classified as.
+func TestClassifyFixtures(t *testing.T) {
+	fixtures, err := filepath.Glob("testdata/*.bin")
+	require.NoError(t, err)
+	require.NotEmpty(t, fixtures)
+
+	byName := make(map[string]ProtocolType, MaxProtocols)
+	for p := Unknown; p < MaxProtocols; p++ {
+		byName[p.String()] = p
+	}
+
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".bin")
+		expected, ok := byName[strings.SplitN(name, "_", 2)[0]]
+		require.True(t, ok, "fixture %s is not prefixed by a known protocol", name)
+
+		t.Run(name, func(t *testing.T) {
+			payload, err := ioutil.ReadFile(fixture)
+			require.NoError(t, err)
+			assert.Equal(t, expected, Classify(payload))
+		})
+	}
+}
+
+func TestClassifyTruncated(t *testing.T) {
+	payload, err := ioutil.ReadFile("testdata/postgres_query.bin")
+	require.NoError(t, err)
+	assert.Equal(t, Postgres, Classify(payload[:12]))
+
+	payload, err = ioutil.ReadFile("testdata/mysql_greeting.bin")
+	require.NoError(t, err)
+	assert.Equal(t, MySQL, Classify(payload[:16]))
+
+	payload, err = ioutil.ReadFile("testdata/kafka_metadata_request.bin")
+	require.NoError(t, err)
+	assert.Equal(t, Kafka, Classify(payload[:18]))
+}
+
+func TestClassifyEmpty(t *testing.T) {
+	assert.Equal(t, Unknown, Classify(nil))
+	assert.Equal(t, Unknown, Classify([]byte{0}))
+	assert.Equal(t, Unknown, Classify([]byte("hello world")))
+}
+
+func TestProtocolTag(t *testing.T) {
+	assert.Equal(t, "", Unknown.Tag())
+	assert.Equal(t, "protocol:postgres", Postgres.Tag())
+	assert.Equal(t, "protocol:grpc", GRPC.Tag())
+	assert.Equal(t, "unknown", MaxProtocols.String())
+}
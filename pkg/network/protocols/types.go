@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package protocols
+
+// ProtocolType is the application protocol detected on a connection
+type ProtocolType uint8
+
+const (
+	// Unknown is used when the protocol could not be classified
+	Unknown ProtocolType = iota
+	// HTTP is plaintext HTTP/1.x
+	HTTP
+	// HTTP2 is HTTP/2 (h2c or decrypted h2)
+	HTTP2
+	// GRPC is gRPC over HTTP/2
+	GRPC
+	// Postgres is the PostgreSQL frontend/backend protocol
+	Postgres
+	// MySQL is the MySQL client/server protocol
+	MySQL
+	// Redis is the Redis serialization protocol (RESP)
+	Redis
+	// Kafka is the Kafka wire protocol
+	Kafka
+	// AMQP is the AMQP 0-9-1 protocol
+	AMQP
+
+	// MaxProtocols is the number of known protocol types
+	MaxProtocols
+)
+
+var protocolNames = [MaxProtocols]string{
+	Unknown:  "unknown",
+	HTTP:     "http",
+	HTTP2:    "http2",
+	GRPC:     "grpc",
+	Postgres: "postgres",
+	MySQL:    "mysql",
+	Redis:    "redis",
+	Kafka:    "kafka",
+	AMQP:     "amqp",
+}
+
+func (p ProtocolType) String() string {
+	if p >= MaxProtocols {
+		return protocolNames[Unknown]
+	}
+	return protocolNames[p]
+}
+
+// Tag returns the connection tag used to report the protocol in the payload.
+// An empty string is returned for unclassified connections.
+func (p ProtocolType) Tag() string {
+	if p == Unknown || p >= MaxProtocols {
+		return ""
+	}
+	return "protocol:" + protocolNames[p]
+}
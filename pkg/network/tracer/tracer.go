@@ -307,6 +307,12 @@ func (t *Tracer) Stop() {
 	t.conntracker.Close()
 }
 
+// GetDNSDomainStats returns the DNS stats aggregated by domain and server since the last call
+// of the client, limited to the top n most queried domains when n is positive
+func (t *Tracer) GetDNSDomainStats(clientID string, n int) []dns.DomainStats {
+	return dns.TopDomainStats(t.reverseDNS.GetDomainStats(clientID), n)
+}
+
 func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, error) {
 	t.bufferLock.Lock()
 	defer t.bufferLock.Unlock()
@@ -321,6 +327,7 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 
 	delta := t.state.GetDelta(clientID, latestTime, active, t.reverseDNS.GetDNSStats(), t.httpMonitor.GetHTTPStats())
 	t.activeBuffer.Reset()
+	network.SetConnectionProtocols(delta.Conns, t.httpMonitor.GetProtocols())
 
 	t.retryConntrack(delta.Conns)
 
@@ -12,6 +12,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/ebpf"
 	"github.com/DataDog/datadog-agent/pkg/network"
 	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
 )
 
 // Tracer is not implemented
@@ -30,6 +31,11 @@ func (t *Tracer) GetActiveConnections(_ string) (*network.Connections, error) {
 	return nil, ebpf.ErrNotImplemented
 }
 
+// GetDNSDomainStats is not implemented on this OS for Tracer
+func (t *Tracer) GetDNSDomainStats(_ string, _ int) []dns.DomainStats {
+	return nil
+}
+
 // GetStats is not implemented on this OS for Tracer
 func (t *Tracer) GetStats() (map[string]interface{}, error) {
 	return nil, ebpf.ErrNotImplemented
@@ -140,6 +140,12 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 	}, nil
 }
 
+// GetDNSDomainStats returns the DNS stats aggregated by domain and server since the last call
+// of the client, limited to the top n most queried domains when n is positive
+func (t *Tracer) GetDNSDomainStats(clientID string, n int) []dns.DomainStats {
+	return dns.TopDomainStats(t.reverseDNS.GetDomainStats(clientID), n)
+}
+
 // GetStats returns a map of statistics about the current tracer's internal state
 func (t *Tracer) GetStats() (map[string]interface{}, error) {
 	driverStats, err := t.driverInterface.GetStats()
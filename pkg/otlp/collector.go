@@ -22,6 +22,8 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/otlp/internal/logsagentexporter"
 	"github.com/DataDog/datadog-agent/pkg/otlp/internal/serializerexporter"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
 	"github.com/DataDog/datadog-agent/pkg/util/flavor"
@@ -30,7 +32,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/version"
 )
 
-func getComponents(s serializer.MetricSerializer) (
+func getComponents(s serializer.MetricSerializer, logsChan chan *message.Message) (
 	component.Factories,
 	error,
 ) {
@@ -51,6 +53,7 @@ func getComponents(s serializer.MetricSerializer) (
 	exporters, err := component.MakeExporterFactoryMap(
 		otlpexporter.NewFactory(),
 		serializerexporter.NewFactory(s),
+		logsagentexporter.NewFactory(logsChan),
 	)
 	if err != nil {
 		errs = append(errs, err)
@@ -91,6 +94,8 @@ type PipelineConfig struct {
 	MetricsEnabled bool
 	// TracesEnabled states whether OTLP traces support is enabled.
 	TracesEnabled bool
+	// LogsEnabled states whether OTLP logs support is enabled.
+	LogsEnabled bool
 
 	// Metrics contains configuration options for the serializer metrics exporter
 	Metrics map[string]interface{}
@@ -102,13 +107,14 @@ type Pipeline struct {
 }
 
 // NewPipeline defines a new OTLP pipeline.
-func NewPipeline(cfg PipelineConfig, s serializer.MetricSerializer) (*Pipeline, error) {
+// The logs received by the pipeline are sent to logsChan, read by the logs agent.
+func NewPipeline(cfg PipelineConfig, s serializer.MetricSerializer, logsChan chan *message.Message) (*Pipeline, error) {
 	buildInfo, err := getBuildInfo()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get build info: %w", err)
 	}
 
-	factories, err := getComponents(s)
+	factories, err := getComponents(s, logsChan)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get components: %w", err)
 	}
@@ -150,13 +156,13 @@ func (p *Pipeline) Stop() {
 }
 
 // BuildAndStart builds and starts an OTLP pipeline
-func BuildAndStart(ctx context.Context, cfg config.Config, s serializer.MetricSerializer) (*Pipeline, error) {
+func BuildAndStart(ctx context.Context, cfg config.Config, s serializer.MetricSerializer, logsChan chan *message.Message) (*Pipeline, error) {
 	pcfg, err := FromAgentConfig(config.Datadog)
 	if err != nil {
 		return nil, fmt.Errorf("config error: %w", err)
 	}
 
-	p, err := NewPipeline(pcfg, s)
+	p, err := NewPipeline(pcfg, s, logsChan)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build pipeline: %w", err)
 	}
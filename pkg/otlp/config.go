@@ -138,7 +138,12 @@ func fromExperimentalConfig(cfg config.Config) (PipelineConfig, error) {
 
 	metricsEnabled := cfg.GetBool(config.ExperimentalOTLPMetricsEnabled)
 	tracesEnabled := cfg.GetBool(config.ExperimentalOTLPTracesEnabled)
-	if !metricsEnabled && !tracesEnabled {
+	logsEnabled := cfg.GetBool(config.ExperimentalOTLPLogsEnabled)
+	if logsEnabled && !cfg.GetBool("logs_enabled") {
+		log.Warn("OTLP logs support is disabled, as log collection is disabled. Please enable log collection to collect and forward OTLP logs.")
+		logsEnabled = false
+	}
+	if !metricsEnabled && !tracesEnabled && !logsEnabled {
 		errs = append(errs, fmt.Errorf("at least one OTLP signal needs to be enabled"))
 	}
 
@@ -157,6 +162,7 @@ func fromExperimentalConfig(cfg config.Config) (PipelineConfig, error) {
 		TracePort:          tracePort,
 		MetricsEnabled:     metricsEnabled,
 		TracesEnabled:      tracesEnabled,
+		LogsEnabled:        logsEnabled,
 		Metrics:            metrics,
 	}, multierr.Combine(errs...)
 }
@@ -102,6 +102,21 @@ func TestFromAgentConfigReceiver(t *testing.T) {
 			path: "port/alldisabled.yaml",
 			err:  "at least one OTLP signal needs to be enabled",
 		},
+		{
+			path: "logs/enabled.yaml",
+			cfg: PipelineConfig{
+				OTLPReceiverConfig: testutil.OTLPConfigFromPorts("localhost", 0, 1234),
+				TracePort:          5003,
+				LogsEnabled:        true,
+				Metrics: map[string]interface{}{
+					"tag_cardinality": "low",
+				},
+			},
+		},
+		{
+			path: "logs/logsagentdisabled.yaml",
+			err:  "at least one OTLP signal needs to be enabled",
+		},
 		{
 			path: "receiver/noprotocols.yaml",
 			cfg: PipelineConfig{
@@ -0,0 +1,30 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logsagentexporter
+
+import (
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// exporterConfig defines configuration for the logs agent exporter.
+type exporterConfig struct {
+	// squash ensures fields are correctly decoded in embedded struct
+	config.ExporterSettings        `mapstructure:",squash"`
+	exporterhelper.TimeoutSettings `mapstructure:",squash"`
+	exporterhelper.QueueSettings   `mapstructure:",squash"`
+}
+
+var _ config.Exporter = (*exporterConfig)(nil)
+
+func newDefaultConfig() config.Exporter {
+	return &exporterConfig{
+		ExporterSettings: config.NewExporterSettings(config.NewComponentID(TypeStr)),
+		// Disable timeout; sending to the logs agent channel is not a network request.
+		TimeoutSettings: exporterhelper.TimeoutSettings{Timeout: 0},
+		QueueSettings:   exporterhelper.DefaultQueueSettings(),
+	}
+}
@@ -0,0 +1,163 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logsagentexporter
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	conventions "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/otlp/model/attributes"
+)
+
+// Attributes added to the content of the log messages.
+const (
+	messageKey        = "message"
+	ddTraceIDKey      = "dd.trace_id"
+	ddSpanIDKey       = "dd.span_id"
+	otelTraceIDKey    = "otel.trace_id"
+	otelSpanIDKey     = "otel.span_id"
+	otelSeverityText  = "otel.severity_text"
+	otelSeverityNum   = "otel.severity_number"
+	otelLogRecordName = "otel.name"
+)
+
+// exporter converts OTLP log records into log messages and sends them to the logs agent.
+type exporter struct {
+	logsChan chan *message.Message
+}
+
+func newExporter(logsChan chan *message.Message) *exporter {
+	return &exporter{logsChan: logsChan}
+}
+
+func (e *exporter) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		res := resourceInfoFromAttributes(rl.Resource().Attributes())
+
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				msg, err := convertLogRecord(logs.At(k), res)
+				if err != nil {
+					return err
+				}
+
+				select {
+				case e.logsChan <- msg:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resourceInfo holds the log message metadata extracted from resource attributes
+type resourceInfo struct {
+	hostname string
+	service  string
+	tags     []string
+}
+
+func resourceInfoFromAttributes(attrs pdata.AttributeMap) resourceInfo {
+	var res resourceInfo
+	if hostname, ok := attributes.HostnameFromAttributes(attrs); ok {
+		res.hostname = hostname
+	}
+	if service, ok := attrs.Get(conventions.AttributeServiceName); ok {
+		res.service = service.AsString()
+	}
+	res.tags = attributes.TagsFromAttributes(attrs)
+	return res
+}
+
+// convertLogRecord builds a log message from a log record. The record attributes and
+// its trace context are added to the JSON content of the message.
+func convertLogRecord(lr pdata.LogRecord, res resourceInfo) (*message.Message, error) {
+	content := lr.Attributes().AsRaw()
+	content[messageKey] = lr.Body().AsString()
+
+	if lr.Name() != "" {
+		content[otelLogRecordName] = lr.Name()
+	}
+	if lr.SeverityText() != "" {
+		content[otelSeverityText] = lr.SeverityText()
+	}
+	if lr.SeverityNumber() != pdata.SeverityNumberUNDEFINED {
+		content[otelSeverityNum] = int32(lr.SeverityNumber())
+	}
+
+	if traceID := lr.TraceID(); !traceID.IsEmpty() {
+		bytes := traceID.Bytes()
+		content[otelTraceIDKey] = traceID.HexString()
+		// Datadog trace IDs are the lower 64 bits of OpenTelemetry trace IDs.
+		content[ddTraceIDKey] = strconv.FormatUint(binary.BigEndian.Uint64(bytes[8:]), 10)
+	}
+	if spanID := lr.SpanID(); !spanID.IsEmpty() {
+		bytes := spanID.Bytes()
+		content[otelSpanIDKey] = spanID.HexString()
+		content[ddSpanIDKey] = strconv.FormatUint(binary.BigEndian.Uint64(bytes[:]), 10)
+	}
+
+	rawContent, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// The log source of the origin is set by the logs agent when it receives the message.
+	origin := message.NewOrigin(nil)
+	origin.SetService(res.service)
+	origin.SetTags(res.tags)
+
+	msg := message.NewMessage(rawContent, origin, statusFromSeverity(lr.SeverityNumber(), lr.SeverityText()), time.Now().UnixNano())
+	msg.Hostname = res.hostname
+	if ts := lr.Timestamp(); ts != 0 {
+		msg.Timestamp = ts.AsTime().UTC()
+	}
+	return msg, nil
+}
+
+// statusFromSeverity maps a log record severity to a log status.
+// See: https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/logs/data-model.md#severity-fields
+func statusFromSeverity(number pdata.SeverityNumber, text string) string {
+	switch {
+	case number >= pdata.SeverityNumberFATAL:
+		return message.StatusCritical
+	case number >= pdata.SeverityNumberERROR:
+		return message.StatusError
+	case number >= pdata.SeverityNumberWARN:
+		return message.StatusWarning
+	case number >= pdata.SeverityNumberINFO:
+		return message.StatusInfo
+	case number >= pdata.SeverityNumberTRACE:
+		return message.StatusDebug
+	}
+
+	// The severity number is optional, fallback to the severity text.
+	switch strings.ToLower(text) {
+	case "fatal", "critical":
+		return message.StatusCritical
+	case "error":
+		return message.StatusError
+	case "warn", "warning":
+		return message.StatusWarning
+	case "trace", "debug":
+		return message.StatusDebug
+	}
+	return message.StatusInfo
+}
@@ -0,0 +1,125 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+// +build test
+
+package logsagentexporter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+func TestNewFactory(t *testing.T) {
+	factory := NewFactory(make(chan *message.Message))
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, configtest.CheckConfigStruct(cfg))
+
+	set := componenttest.NewNopExporterCreateSettings()
+	exp, err := factory.CreateLogsExporter(context.Background(), set, cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, exp)
+
+	_, err = factory.CreateMetricsExporter(context.Background(), set, cfg)
+	assert.Error(t, err)
+}
+
+func testLogs() pdata.Logs {
+	ld := pdata.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("host.name", "my-host")
+	rl.Resource().Attributes().InsertString("service.name", "my-service")
+	rl.Resource().Attributes().InsertString("deployment.environment", "prod")
+
+	logs := rl.InstrumentationLibraryLogs().AppendEmpty().Logs()
+
+	lr := logs.AppendEmpty()
+	lr.Body().SetStringVal("something failed")
+	lr.SetSeverityNumber(pdata.SeverityNumberERROR2)
+	lr.SetSeverityText("ERROR")
+	lr.SetTimestamp(pdata.NewTimestampFromTime(time.Unix(1600000000, 0)))
+	lr.SetTraceID(pdata.NewTraceID([16]byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2}))
+	lr.SetSpanID(pdata.NewSpanID([8]byte{0, 0, 0, 0, 0, 0, 0, 3}))
+	lr.Attributes().InsertString("http.method", "GET")
+
+	lr = logs.AppendEmpty()
+	lr.Body().SetStringVal("hello")
+	lr.SetSeverityText("warning")
+
+	return ld
+}
+
+func TestConsumeLogs(t *testing.T) {
+	logsChan := make(chan *message.Message, 10)
+	exp := newExporter(logsChan)
+	require.NoError(t, exp.ConsumeLogs(context.Background(), testLogs()))
+	require.Len(t, logsChan, 2)
+
+	source := config.NewLogSource(config.OTLP, &config.LogsConfig{Type: config.OTLPType, Source: "otlp"})
+
+	msg := <-logsChan
+	msg.Origin.LogSource = source
+	assert.Equal(t, message.StatusError, msg.GetStatus())
+	assert.Equal(t, "my-host", msg.GetHostname())
+	assert.Equal(t, "my-service", msg.Origin.Service())
+	assert.Contains(t, msg.Origin.Tags(), "env:prod")
+	assert.Equal(t, time.Unix(1600000000, 0).UTC(), msg.Timestamp)
+
+	var content map[string]interface{}
+	require.NoError(t, json.Unmarshal(msg.Content, &content))
+	assert.Equal(t, map[string]interface{}{
+		"message":              "something failed",
+		"http.method":          "GET",
+		"otel.severity_text":   "ERROR",
+		"otel.severity_number": float64(pdata.SeverityNumberERROR2),
+		"otel.trace_id":        "00000000000000010000000000000002",
+		"otel.span_id":         "0000000000000003",
+		"dd.trace_id":          "2",
+		"dd.span_id":           "3",
+	}, content)
+
+	msg = <-logsChan
+	assert.Equal(t, message.StatusWarning, msg.GetStatus())
+	assert.True(t, msg.Timestamp.IsZero())
+}
+
+func TestConsumeLogsCanceled(t *testing.T) {
+	exp := newExporter(make(chan *message.Message))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, exp.ConsumeLogs(ctx, testLogs()), context.Canceled)
+}
+
+func TestStatusFromSeverity(t *testing.T) {
+	tests := []struct {
+		number pdata.SeverityNumber
+		text   string
+		status string
+	}{
+		{pdata.SeverityNumberTRACE3, "", message.StatusDebug},
+		{pdata.SeverityNumberDEBUG, "", message.StatusDebug},
+		{pdata.SeverityNumberINFO4, "", message.StatusInfo},
+		{pdata.SeverityNumberWARN, "", message.StatusWarning},
+		{pdata.SeverityNumberERROR, "info", message.StatusError},
+		{pdata.SeverityNumberFATAL4, "", message.StatusCritical},
+		{pdata.SeverityNumberUNDEFINED, "Fatal", message.StatusCritical},
+		{pdata.SeverityNumberUNDEFINED, "unknown", message.StatusInfo},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.status, statusFromSeverity(test.number, test.text), "%v %s", test.number, test.text)
+	}
+}
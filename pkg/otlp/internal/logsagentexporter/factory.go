@@ -0,0 +1,48 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logsagentexporter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+const (
+	// TypeStr defines the logs agent exporter type string.
+	TypeStr = "logsagent"
+)
+
+type factory struct {
+	logsChan chan *message.Message
+}
+
+// NewFactory creates a new logs agent exporter factory. The exporter sends
+// the converted log records to the given channel, read by the logs agent.
+func NewFactory(logsChan chan *message.Message) component.ExporterFactory {
+	f := &factory{logsChan}
+
+	return exporterhelper.NewFactory(
+		TypeStr,
+		newDefaultConfig,
+		exporterhelper.WithLogs(f.createLogsExporter),
+	)
+}
+
+func (f *factory) createLogsExporter(_ context.Context, params component.ExporterCreateSettings, c config.Exporter) (component.LogsExporter, error) {
+	cfg := c.(*exporterConfig)
+
+	exp := newExporter(f.logsChan)
+
+	return exporterhelper.NewLogsExporter(cfg, params, exp.ConsumeLogs,
+		exporterhelper.WithQueue(cfg.QueueSettings),
+		exporterhelper.WithTimeout(cfg.TimeoutSettings),
+	)
+}
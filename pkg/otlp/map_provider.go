@@ -96,6 +96,33 @@ func buildMetricsMap(cfg PipelineConfig) (*config.Map, error) {
 	return baseMap, err
 }
 
+// defaultLogsConfig is the logs OTLP pipeline configuration.
+const defaultLogsConfig string = `
+receivers:
+  otlp:
+
+processors:
+  batch:
+    timeout: 10s
+
+exporters:
+  logsagent:
+
+service:
+  telemetry:
+    metrics:
+      level: none
+  pipelines:
+    logs:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [logsagent]
+`
+
+func buildLogsMap() (*config.Map, error) {
+	return configutils.NewMapFromYAMLString(defaultLogsConfig)
+}
+
 func buildReceiverMap(otlpReceiverConfig map[string]interface{}) *config.Map {
 	return config.NewMapFromStringMap(map[string]interface{}{
 		"receivers": map[string]interface{}{"otlp": otlpReceiverConfig},
@@ -119,6 +146,13 @@ func buildMap(cfg PipelineConfig) (*config.Map, error) {
 		err = retMap.Merge(metricsMap)
 		errs = append(errs, err)
 	}
+	if cfg.LogsEnabled {
+		logsMap, err := buildLogsMap()
+		errs = append(errs, err)
+
+		err = retMap.Merge(logsMap)
+		errs = append(errs, err)
+	}
 	err := retMap.Merge(buildReceiverMap(cfg.OTLPReceiverConfig))
 	errs = append(errs, err)
 
@@ -232,6 +232,43 @@ func TestNewMap(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "only gRPC, only logs",
+			pcfg: PipelineConfig{
+				OTLPReceiverConfig: testutil.OTLPConfigFromPorts("bindhost", 1234, 0),
+				TracePort:          5003,
+				LogsEnabled:        true,
+			},
+			ocfg: map[string]interface{}{
+				"receivers": map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"protocols": map[string]interface{}{
+							"grpc": map[string]interface{}{
+								"endpoint": "bindhost:1234",
+							},
+						},
+					},
+				},
+				"processors": map[string]interface{}{
+					"batch": map[string]interface{}{
+						"timeout": "10s",
+					},
+				},
+				"exporters": map[string]interface{}{
+					"logsagent": nil,
+				},
+				"service": map[string]interface{}{
+					"telemetry": map[string]interface{}{"metrics": map[string]interface{}{"level": "none"}},
+					"pipelines": map[string]interface{}{
+						"logs": map[string]interface{}{
+							"receivers":  []interface{}{"otlp"},
+							"processors": []interface{}{"batch"},
+							"exporters":  []interface{}{"logsagent"},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, testInstance := range tests {
@@ -250,6 +287,7 @@ func TestUnmarshal(t *testing.T) {
 		TracePort:          5001,
 		MetricsEnabled:     true,
 		TracesEnabled:      true,
+		LogsEnabled:        true,
 		Metrics: map[string]interface{}{
 			"delta_ttl":                                2000,
 			"report_quantiles":                         false,
@@ -263,7 +301,7 @@ func TestUnmarshal(t *testing.T) {
 		},
 	})
 	require.NoError(t, err)
-	components, err := getComponents(&serializer.MockSerializer{})
+	components, err := getComponents(&serializer.MockSerializer{}, nil)
 	require.NoError(t, err)
 
 	cu := configunmarshaler.NewDefault()
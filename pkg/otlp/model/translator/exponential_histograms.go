@@ -0,0 +1,243 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/DataDog/datadog-agent/pkg/quantile"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// exponentialBucket is a bucket of an exponential histogram data point,
+// containing the values in (lowerBound, upperBound].
+type exponentialBucket struct {
+	lowerBound float64
+	upperBound float64
+	count      uint64
+}
+
+// exponentialBucketLowerBound returns the lower bound of the bucket of the given index
+// for the given scale, that is base^index with base = 2^(2^-scale).
+// See https://github.com/open-telemetry/opentelemetry-proto/blob/v0.11.0/opentelemetry/proto/metrics/v1/metrics.proto#L476-L496
+func exponentialBucketLowerBound(scale int32, index int32) float64 {
+	return math.Exp2(math.Ldexp(float64(index), -int(scale)))
+}
+
+// getExponentialBuckets returns the buckets of an exponential histogram data point
+// ordered by increasing bounds: negative buckets, then the zero bucket, then positive buckets.
+func getExponentialBuckets(p pdata.ExponentialHistogramDataPoint) []exponentialBucket {
+	scale := p.Scale()
+	negative, positive := p.Negative(), p.Positive()
+	buckets := make([]exponentialBucket, 0, len(negative.BucketCounts())+len(positive.BucketCounts())+1)
+
+	// Negative buckets hold the absolute values of negative measurements,
+	// the bucket of highest index contains the lowest values.
+	for i := len(negative.BucketCounts()) - 1; i >= 0; i-- {
+		index := negative.Offset() + int32(i)
+		buckets = append(buckets, exponentialBucket{
+			lowerBound: -exponentialBucketLowerBound(scale, index+1),
+			upperBound: -exponentialBucketLowerBound(scale, index),
+			count:      negative.BucketCounts()[i],
+		})
+	}
+
+	buckets = append(buckets, exponentialBucket{count: p.ZeroCount()})
+
+	for i, count := range positive.BucketCounts() {
+		index := positive.Offset() + int32(i)
+		buckets = append(buckets, exponentialBucket{
+			lowerBound: exponentialBucketLowerBound(scale, index),
+			upperBound: exponentialBucketLowerBound(scale, index+1),
+			count:      count,
+		})
+	}
+
+	return buckets
+}
+
+// getExponentialSketch converts the buckets of an exponential histogram data point into a sketch.
+// For cumulative data points, bucket counts are converted to deltas with the last submitted point:
+// when the series is continued (seriesContinued is true), a bucket absent from the last point had a
+// count of zero. The returned sketch is nil if there are no values to report.
+func (t *Translator) getExponentialSketch(
+	pointDims metricsDimensions,
+	p pdata.ExponentialHistogramDataPoint,
+	delta bool,
+	seriesContinued bool,
+) *quantile.Sketch {
+	buckets := getExponentialBuckets(p)
+	var counts []uint64
+	if delta {
+		counts = make([]uint64, len(buckets))
+		for i, b := range buckets {
+			counts[i] = b.count
+		}
+	} else {
+		counts = t.getExponentialBucketDeltas(pointDims, p, buckets, seriesContinued)
+		if counts == nil {
+			return nil
+		}
+	}
+
+	as := &quantile.Agent{}
+	min, max := math.Inf(1), math.Inf(-1)
+	for i, b := range buckets {
+		count := counts[i]
+		if count == 0 {
+			continue
+		}
+
+		min = math.Min(min, b.lowerBound)
+		max = math.Max(max, b.upperBound)
+
+		// InsertInterpolate doesn't work with an infinite bound, which can be reached for extreme indexes and scales.
+		lowerBound, upperBound := b.lowerBound, b.upperBound
+		if math.IsInf(upperBound, 0) {
+			upperBound = lowerBound
+		} else if math.IsInf(lowerBound, 0) {
+			lowerBound = upperBound
+		}
+		as.InsertInterpolate(lowerBound, upperBound, uint(count))
+	}
+
+	sketch := as.Finish()
+	if sketch == nil {
+		return nil
+	}
+
+	// Values of a bucket may be anywhere within its bounds,
+	// use the bounds of the outermost non-empty buckets.
+	sketch.Basic.Min = min
+	sketch.Basic.Max = max
+	return sketch
+}
+
+// getExponentialBucketDeltas submits the bucket counts of a cumulative data point and returns
+// their deltas with the last submitted point, or nil when there is no last point to compare with.
+//
+// The bounds of the buckets depend on the scale: after a scale change, the buckets can't be matched
+// with the ones of the last point and the point is handled like the first point of the series.
+// A bucket count lower than the last one means that the series was reset at an unknown time,
+// the counts of the point are then the deltas since the reset.
+func (t *Translator) getExponentialBucketDeltas(
+	pointDims metricsDimensions,
+	p pdata.ExponentialHistogramDataPoint,
+	buckets []exponentialBucket,
+	seriesContinued bool,
+) []uint64 {
+	startTs := uint64(p.StartTimestamp())
+	ts := uint64(p.Timestamp())
+
+	scaleDims := pointDims.WithSuffix("scale")
+	if dScale, ok := t.prevPts.Diff(scaleDims, startTs, ts, float64(p.Scale())); !ok || dScale != 0 {
+		seriesContinued = false
+	}
+
+	reset := false
+	deltas := make([]uint64, len(buckets))
+	for i, b := range buckets {
+		// The bucket bounds identify a bucket within a given scale, the zero bucket is (0, 0].
+		bucketDims := pointDims.AddTags(
+			fmt.Sprintf("lower_bound:%s", formatFloat(b.lowerBound)),
+			fmt.Sprintf("upper_bound:%s", formatFloat(b.upperBound)),
+		)
+		// Buckets are submitted even when the series isn't continued to start the next deltas from them.
+		dx, ok := t.prevPts.MonotonicDiff(bucketDims, startTs, ts, float64(b.count))
+		if dx < 0 {
+			reset = true
+		} else if ok {
+			deltas[i] = uint64(dx)
+		} else {
+			deltas[i] = b.count
+		}
+	}
+
+	if !seriesContinued {
+		return nil
+	}
+
+	if reset {
+		for i, b := range buckets {
+			deltas[i] = b.count
+		}
+	}
+	return deltas
+}
+
+// mapExponentialHistogramMetrics maps exponential histogram datapoints into Datadog metrics
+//
+// An exponential histogram data point has:
+//   - The count and the sum of values in the population
+//   - The count of values equal to zero
+//   - Positive and negative buckets, whose bounds are powers of a base
+//     defined by the scale of the data point.
+//
+// The buckets are inserted into a sketch and reported as a distribution,
+// unless buckets are disabled by the histogram mode. Count and sum are
+// reported as counts when SendCountSum is enabled.
+func (t *Translator) mapExponentialHistogramMetrics(
+	ctx context.Context,
+	consumer Consumer,
+	dims metricsDimensions,
+	slice pdata.ExponentialHistogramDataPointSlice,
+	delta bool,
+) {
+	for i := 0; i < slice.Len(); i++ {
+		p := slice.At(i)
+		startTs := uint64(p.StartTimestamp())
+		ts := uint64(p.Timestamp())
+		pointDims := dims.WithAttributeMap(p.Attributes())
+
+		countDims := pointDims.WithSuffix("count")
+		count, countOk := float64(p.Count()), true
+		if !delta {
+			count, countOk = t.prevPts.Diff(countDims, startTs, ts, count)
+		}
+
+		sumDims := pointDims.WithSuffix("sum")
+		sum, sumOk := p.Sum(), !t.isSkippable(sumDims.name, p.Sum())
+		if !delta && sumOk {
+			sum, sumOk = t.prevPts.Diff(sumDims, startTs, ts, sum)
+		}
+
+		if t.cfg.SendCountSum {
+			if countOk {
+				consumer.ConsumeTimeSeries(ctx, countDims.name, Count, ts, count, countDims.tags, countDims.host)
+			}
+			if sumOk {
+				consumer.ConsumeTimeSeries(ctx, sumDims.name, Count, ts, sum, sumDims.tags, sumDims.host)
+			}
+		}
+
+		if t.cfg.HistMode == HistogramModeNoBuckets {
+			continue
+		}
+
+		sketch := t.getExponentialSketch(pointDims, p, delta, countOk)
+		if sketch == nil {
+			continue
+		}
+
+		// The sum of the sketch is interpolated from the buckets, use the actual one when known.
+		if sumOk {
+			sketch.Basic.Sum = sum
+			sketch.Basic.Avg = sum / float64(sketch.Basic.Cnt)
+		}
+		consumer.ConsumeSketch(ctx, pointDims.name, ts, sketch, pointDims.tags, pointDims.host)
+	}
+}
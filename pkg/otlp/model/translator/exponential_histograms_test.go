@@ -0,0 +1,265 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/quantile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+func TestExponentialBucketLowerBound(t *testing.T) {
+	assert.Equal(t, 1.0, exponentialBucketLowerBound(0, 0))
+	assert.Equal(t, 8.0, exponentialBucketLowerBound(0, 3))
+	assert.Equal(t, 0.25, exponentialBucketLowerBound(0, -2))
+	assert.Equal(t, 16.0, exponentialBucketLowerBound(-1, 2))
+	assert.InDelta(t, math.Sqrt2, exponentialBucketLowerBound(1, 1), 1e-12)
+	assert.InDelta(t, math.Pow(2, 3.0/8.0), exponentialBucketLowerBound(3, 3), 1e-12)
+}
+
+func TestGetExponentialBuckets(t *testing.T) {
+	p := pdata.NewExponentialHistogramDataPoint()
+	p.SetScale(0)
+	p.SetZeroCount(3)
+	p.Negative().SetOffset(1)
+	p.Negative().SetBucketCounts([]uint64{1, 2})
+	p.Positive().SetOffset(-1)
+	p.Positive().SetBucketCounts([]uint64{4, 5})
+
+	assert.Equal(t, []exponentialBucket{
+		{lowerBound: -8, upperBound: -4, count: 2},
+		{lowerBound: -4, upperBound: -2, count: 1},
+		{lowerBound: 0, upperBound: 0, count: 3},
+		{lowerBound: 0.5, upperBound: 1, count: 4},
+		{lowerBound: 1, upperBound: 2, count: 5},
+	}, getExponentialBuckets(p))
+}
+
+func newExponentialHistogramDataPoint(ts pdata.Timestamp, zeroCount uint64, negative, positive []uint64, sum float64) pdata.ExponentialHistogramDataPoint {
+	p := pdata.NewExponentialHistogramDataPoint()
+	p.SetTimestamp(ts)
+	p.SetScale(1)
+	p.SetZeroCount(zeroCount)
+	p.Negative().SetBucketCounts(negative)
+	p.Positive().SetOffset(2)
+	p.Positive().SetBucketCounts(positive)
+	count := zeroCount
+	for _, c := range append(negative, positive...) {
+		count += c
+	}
+	p.SetCount(count)
+	p.SetSum(sum)
+	return p
+}
+
+func TestMapDeltaExponentialHistogramMetrics(t *testing.T) {
+	ts := seconds(0)
+	slice := pdata.NewExponentialHistogramDataPointSlice()
+	newExponentialHistogramDataPoint(ts, 5, []uint64{10}, []uint64{20, 0, 15}, 42).CopyTo(slice.AppendEmpty())
+
+	dims := newDims("expHist.test")
+	counts := []metric{
+		newCount(dims.WithSuffix("count"), uint64(ts), 50),
+		newCount(dims.WithSuffix("sum"), uint64(ts), 42),
+	}
+
+	tests := []struct {
+		name            string
+		histogramMode   HistogramMode
+		sendCountSum    bool
+		expectedMetrics []metric
+		expectSketch    bool
+	}{
+		{
+			name:            "No buckets: send count & sum metrics",
+			histogramMode:   HistogramModeNoBuckets,
+			sendCountSum:    true,
+			expectedMetrics: counts,
+		},
+		{
+			name:            "Distributions: do not send count & sum metrics",
+			histogramMode:   HistogramModeDistributions,
+			sendCountSum:    false,
+			expectedMetrics: []metric{},
+			expectSketch:    true,
+		},
+		{
+			name:            "Distributions: send count & sum metrics",
+			histogramMode:   HistogramModeDistributions,
+			sendCountSum:    true,
+			expectedMetrics: counts,
+			expectSketch:    true,
+		},
+	}
+
+	for _, testInstance := range tests {
+		t.Run(testInstance.name, func(t *testing.T) {
+			tr := newTranslator(t, zap.NewNop())
+			tr.cfg.HistMode = testInstance.histogramMode
+			tr.cfg.SendCountSum = testInstance.sendCountSum
+			consumer := &mockFullConsumer{}
+			tr.mapExponentialHistogramMetrics(context.Background(), consumer, dims, slice, true)
+			assert.ElementsMatch(t, consumer.metrics, testInstance.expectedMetrics)
+
+			if !testInstance.expectSketch {
+				assert.Empty(t, consumer.sketches)
+				return
+			}
+
+			require.Len(t, consumer.sketches, 1)
+			s := consumer.sketches[0]
+			assert.Equal(t, "expHist.test", s.name)
+			assert.Equal(t, uint64(ts), s.timestamp)
+			assert.Equal(t, int64(50), s.basic.Cnt)
+			assert.Equal(t, 42.0, s.basic.Sum)
+			assert.Equal(t, 42.0/50, s.basic.Avg)
+			// lowest negative bucket is (-sqrt(2), -1], highest positive bucket is (4, 4*sqrt(2)]
+			assert.InDelta(t, -math.Sqrt2, s.basic.Min, 1e-12)
+			assert.InDelta(t, 4*math.Sqrt2, s.basic.Max, 1e-12)
+		})
+	}
+}
+
+func TestMapCumulativeExponentialHistogramMetrics(t *testing.T) {
+	slice := pdata.NewExponentialHistogramDataPointSlice()
+	newExponentialHistogramDataPoint(seconds(0), 5, []uint64{10}, []uint64{20}, 42).CopyTo(slice.AppendEmpty())
+	// a new positive bucket appears on the second point
+	newExponentialHistogramDataPoint(seconds(2), 5, []uint64{10}, []uint64{30, 0, 7}, 60).CopyTo(slice.AppendEmpty())
+
+	tr := newTranslator(t, zap.NewNop())
+	tr.cfg.SendCountSum = true
+	consumer := &sketchRecorder{}
+	dims := newDims("expHist.test")
+	tr.mapExponentialHistogramMetrics(context.Background(), consumer, dims, slice, false)
+
+	assert.ElementsMatch(t, consumer.metrics, []metric{
+		newCount(dims.WithSuffix("count"), uint64(seconds(2)), 17),
+		newCount(dims.WithSuffix("sum"), uint64(seconds(2)), 18),
+	})
+
+	require.Len(t, consumer.sketches, 1)
+	sk := consumer.sketches[0]
+	assert.Equal(t, int64(17), sk.Basic.Cnt)
+	assert.Equal(t, 18.0, sk.Basic.Sum)
+	assert.InDelta(t, 2, sk.Basic.Min, 1e-12)
+	assert.InDelta(t, 4*math.Sqrt2, sk.Basic.Max, 1e-12)
+
+	// 10 values in (2, 2*sqrt(2)] and 7 values in (4, 4*sqrt(2)]
+	cfg := quantile.Default()
+	assert.InDelta(t, 2.4, sk.Quantile(cfg, 0.5), 0.5)
+	assert.InDelta(t, 4.8, sk.Quantile(cfg, 0.9), 0.9)
+}
+
+func TestMapCumulativeExponentialHistogramMetricsDownscale(t *testing.T) {
+	slice := pdata.NewExponentialHistogramDataPointSlice()
+	// (2, 2*sqrt(2)] at scale 1
+	newExponentialHistogramDataPoint(seconds(0), 5, []uint64{10}, []uint64{20}, 42).CopyTo(slice.AppendEmpty())
+	// the same values downscaled into (2, 4] at scale 0, plus new ones
+	p := newExponentialHistogramDataPoint(seconds(2), 5, []uint64{10}, []uint64{30}, 60)
+	p.SetScale(0)
+	p.Positive().SetOffset(1)
+	p.CopyTo(slice.AppendEmpty())
+	p = newExponentialHistogramDataPoint(seconds(4), 6, []uint64{10}, []uint64{37}, 80)
+	p.SetScale(0)
+	p.Positive().SetOffset(1)
+	p.CopyTo(slice.AppendEmpty())
+
+	tr := newTranslator(t, zap.NewNop())
+	consumer := &sketchRecorder{}
+	tr.mapExponentialHistogramMetrics(context.Background(), consumer, newDims("expHist.test"), slice, false)
+
+	// the buckets of the point following the scale change can't be compared with the last ones
+	require.Len(t, consumer.sketches, 1)
+	sk := consumer.sketches[0]
+	assert.Equal(t, int64(8), sk.Basic.Cnt)
+	assert.Equal(t, 0.0, sk.Basic.Min)
+	assert.Equal(t, 4.0, sk.Basic.Max)
+}
+
+func TestMapCumulativeExponentialHistogramMetricsReset(t *testing.T) {
+	slice := pdata.NewExponentialHistogramDataPointSlice()
+	newExponentialHistogramDataPoint(seconds(0), 5, []uint64{10}, []uint64{20}, 42).CopyTo(slice.AppendEmpty())
+	// the series was reset between the points, the start timestamp isn't known
+	newExponentialHistogramDataPoint(seconds(2), 0, []uint64{0}, []uint64{3}, 7).CopyTo(slice.AppendEmpty())
+
+	tr := newTranslator(t, zap.NewNop())
+	consumer := &sketchRecorder{}
+	tr.mapExponentialHistogramMetrics(context.Background(), consumer, newDims("expHist.test"), slice, false)
+
+	require.Len(t, consumer.sketches, 1)
+	sk := consumer.sketches[0]
+	assert.Equal(t, int64(3), sk.Basic.Cnt)
+	assert.InDelta(t, 2, sk.Basic.Min, 1e-12)
+	assert.InDelta(t, 2*math.Sqrt2, sk.Basic.Max, 1e-12)
+}
+
+func TestExponentialHistogramSketchQuantiles(t *testing.T) {
+	// 1000 values in each of the buckets of index 0 to 9 at scale 0: (1, 2], (2, 4], ..., (512, 1024]
+	p := pdata.NewExponentialHistogramDataPoint()
+	buckets := make([]uint64, 10)
+	for i := range buckets {
+		buckets[i] = 1000
+	}
+	p.Positive().SetBucketCounts(buckets)
+	p.SetCount(10_000)
+
+	tr := newTranslator(t, zap.NewNop())
+	sk := tr.getExponentialSketch(newDims("test"), p, true, true)
+	require.NotNil(t, sk)
+
+	cfg := quantile.Default()
+	for i := 0; i < 10; i++ {
+		q := (float64(i) + 0.5) / 10
+		lowerBound, upperBound := exponentialBucketLowerBound(0, int32(i)), exponentialBucketLowerBound(0, int32(i+1))
+		v := sk.Quantile(cfg, q)
+		assert.GreaterOrEqual(t, v, lowerBound*(1-1.0/128), "p%v", q)
+		assert.LessOrEqual(t, v, upperBound*(1+1.0/128), "p%v", q)
+	}
+}
+
+// sketchRecorder records the metrics and the full sketches it consumes.
+type sketchRecorder struct {
+	metrics  []metric
+	sketches []*quantile.Sketch
+}
+
+func (c *sketchRecorder) ConsumeTimeSeries(
+	_ context.Context,
+	name string,
+	typ MetricDataType,
+	ts uint64,
+	val float64,
+	tags []string,
+	host string,
+) {
+	c.metrics = append(c.metrics, metric{name: name, typ: typ, timestamp: ts, value: val, tags: tags, host: host})
+}
+
+func (c *sketchRecorder) ConsumeSketch(
+	_ context.Context,
+	_ string,
+	_ uint64,
+	sketch *quantile.Sketch,
+	_ []string,
+	_ string,
+) {
+	c.sketches = append(c.sketches, sketch)
+}
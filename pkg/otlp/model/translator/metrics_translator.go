@@ -442,6 +442,18 @@ func (t *Translator) MapMetrics(ctx context.Context, md pdata.Metrics, consumer
 						)
 						continue
 					}
+				case pdata.MetricDataTypeExponentialHistogram:
+					switch md.ExponentialHistogram().AggregationTemporality() {
+					case pdata.MetricAggregationTemporalityCumulative, pdata.MetricAggregationTemporalityDelta:
+						delta := md.ExponentialHistogram().AggregationTemporality() == pdata.MetricAggregationTemporalityDelta
+						t.mapExponentialHistogramMetrics(ctx, consumer, baseDims, md.ExponentialHistogram().DataPoints(), delta)
+					default: // pdata.AggregationTemporalityUnspecified or any other not supported type
+						t.logger.Debug("Unknown or unsupported aggregation temporality",
+							zap.String(metricName, md.Name()),
+							zap.Any("aggregation temporality", md.ExponentialHistogram().AggregationTemporality()),
+						)
+						continue
+					}
 				case pdata.MetricDataTypeSummary:
 					t.mapSummaryMetrics(ctx, consumer, baseDims, md.Summary().DataPoints())
 				default: // pdata.MetricDataTypeNone or any other not supported type
@@ -13,6 +13,7 @@ import (
 	"fmt"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
 )
 
@@ -28,6 +29,6 @@ type Pipeline struct{}
 func (p *Pipeline) Stop() {}
 
 // BuildAndStart builds and starts an OTLP pipeline
-func BuildAndStart(ctx context.Context, cfg config.Config, s serializer.MetricSerializer) (*Pipeline, error) {
+func BuildAndStart(ctx context.Context, cfg config.Config, s serializer.MetricSerializer, logsChan chan *message.Message) (*Pipeline, error) {
 	return nil, fmt.Errorf("Agent was built without OTLP support")
 }
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	model "github.com/DataDog/agent-payload/v5/process"
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
 	netEncoding "github.com/DataDog/datadog-agent/pkg/network/encoding"
 	procEncoding "github.com/DataDog/datadog-agent/pkg/process/encoding"
 	reqEncoding "github.com/DataDog/datadog-agent/pkg/process/encoding/request"
@@ -164,6 +165,38 @@ func (r *RemoteSysProbeUtil) GetConnections(clientID string) (*model.Connections
 	return conns, nil
 }
 
+// GetDNSDomainStats returns the DNS stats aggregated by domain and server since the last call of the client,
+// retrieved from the system probe service. When top is positive only the top most queried domains are returned.
+func (r *RemoteSysProbeUtil) GetDNSDomainStats(clientID string, top int) ([]dns.DomainStats, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s?client_id=%s&top=%d", dnsDomainsURL, clientID, top), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("dns domains request failed: Probe Path %s, url: %s, status code: %d", r.path, dnsDomainsURL, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []dns.DomainStats
+	if err := json.Unmarshal(body, &stats); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
 // GetStats returns the expvar stats of the system probe
 func (r *RemoteSysProbeUtil) GetStats() (map[string]interface{}, error) {
 	req, err := http.NewRequest("GET", statsURL, nil)
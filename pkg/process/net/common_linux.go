@@ -15,6 +15,7 @@ import (
 
 const (
 	connectionsURL = "http://unix/connections"
+	dnsDomainsURL  = "http://unix/dns/domains"
 	statsURL       = "http://unix/debug/stats"
 	procStatsURL   = "http://unix/proc/stats"
 	netType        = "unix"
@@ -11,6 +11,7 @@ package net
 import (
 	model "github.com/DataDog/agent-payload/v5/process"
 	"github.com/DataDog/datadog-agent/pkg/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
 )
 
 // RemoteSysProbeUtil is not supported
@@ -36,6 +37,11 @@ func (r *RemoteSysProbeUtil) GetConnections(clientID string) (*model.Connections
 	return nil, ebpf.ErrNotImplemented
 }
 
+// GetDNSDomainStats is not supported
+func (r *RemoteSysProbeUtil) GetDNSDomainStats(clientID string, top int) ([]dns.DomainStats, error) {
+	return nil, ebpf.ErrNotImplemented
+}
+
 // GetStats is not supported
 func (r *RemoteSysProbeUtil) GetStats() (map[string]interface{}, error) {
 	return nil, ebpf.ErrNotImplemented
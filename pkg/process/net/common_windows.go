@@ -12,6 +12,7 @@ import "fmt"
 
 const (
 	connectionsURL = "http://localhost:3333/connections"
+	dnsDomainsURL  = "http://localhost:3333/dns/domains"
 	statsURL       = "http://localhost:3333/debug/stats"
 	// procStatsURL is not used in windows, the value is added to avoid compilation error in windows
 	procStatsURL = "http://localhost:3333/proc/stats"
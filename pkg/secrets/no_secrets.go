@@ -29,3 +29,9 @@ func Decrypt(data []byte, origin string) ([]byte, error) {
 func GetDebugInfo() (*SecretInfo, error) {
 	return nil, fmt.Errorf("Secret feature is not available in this version of the agent")
 }
+
+// CheckRights placeholder when compiled without the 'secrets' build tag, the rights of
+// executables can't be checked so none is allowed to run
+func CheckRights(path string, allowGroupExec bool) error {
+	return fmt.Errorf("checking the rights of '%s' is not available in this version of the agent", path)
+}
@@ -212,3 +212,10 @@ func GetDebugInfo() (*SecretInfo, error) {
 	}
 	return info, nil
 }
+
+// CheckRights checks that the given executable can be run by the agent with the permission
+// model of the secret backend command: only the agent user, or one of its groups when
+// allowGroupExec is set, must have rights on it.
+func CheckRights(path string, allowGroupExec bool) error {
+	return checkRights(path, allowGroupExec)
+}
@@ -92,6 +92,10 @@ type Config struct {
 	EnableRuntimeCompiledConstants bool
 	// RuntimeCompiledConstantsIsSet is set if the runtime compiled constants option is user-set
 	RuntimeCompiledConstantsIsSet bool
+	// EnforcementEnabled defines if the 'kill' actions of the rules should be executed
+	EnforcementEnabled bool
+	// EnforcementKillAllowlist lists the executables that are never signaled by a 'kill' action
+	EnforcementKillAllowlist []string
 }
 
 // IsEnabled returns true if any feature is enabled. Has to be applied in config package too
@@ -134,6 +138,8 @@ func NewConfig(cfg *config.Config) (*Config, error) {
 		EnableRemoteConfig:                 aconfig.Datadog.GetBool("runtime_security_config.enable_remote_configuration"),
 		EnableRuntimeCompiledConstants:     aconfig.Datadog.GetBool("runtime_security_config.enable_runtime_compiled_constants"),
 		RuntimeCompiledConstantsIsSet:      aconfig.Datadog.IsSet("runtime_security_config.enable_runtime_compiled_constants"),
+		EnforcementEnabled:                 aconfig.Datadog.GetBool("runtime_security_config.enforcement.enabled"),
+		EnforcementKillAllowlist:           aconfig.Datadog.GetStringSlice("runtime_security_config.enforcement.kill_allowlist"),
 	}
 
 	// if runtime is enabled then we force fim
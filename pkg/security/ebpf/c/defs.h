@@ -218,6 +218,9 @@ enum event_type
     EVENT_PTRACE,
     EVENT_MMAP,
     EVENT_MPROTECT,
+    EVENT_BIND,
+    EVENT_CONNECT,
+    EVENT_DNS,
     EVENT_MAX, // has to be the last one
 
     EVENT_ALL = 0xffffffffffffffff // used as a mask for all the events
@@ -281,6 +284,14 @@ struct file_t {
     struct file_metadata_t metadata;
 };
 
+struct sock_addr_t {
+    u64 addr[2];
+    u16 family;
+    u16 port;
+    u16 protocol;
+    u16 padding;
+};
+
 struct tracepoint_raw_syscalls_sys_exit_t
 {
     unsigned short common_type;
@@ -0,0 +1,312 @@
+#ifndef _NETWORK_H_
+#define _NETWORK_H_
+
+#include <linux/in.h>
+#include <linux/in6.h>
+#include <linux/net.h>
+#include <linux/socket.h>
+#include <linux/uio.h>
+
+#include "bpf_endian.h"
+
+#define DNS_PORT 53
+#define DNS_HEADER_LENGTH 12
+#define DNS_MAX_LENGTH 256
+
+u64 __attribute__((always_inline)) get_skc_dport_offset() {
+    u64 offset;
+    LOAD_CONSTANT("sock_common_skc_dport_offset", offset);
+    return offset;
+}
+
+u64 __attribute__((always_inline)) get_iov_iter_count_offset() {
+    u64 offset;
+    LOAD_CONSTANT("iov_iter_count_offset", offset);
+    return offset;
+}
+
+// is_kernel_address returns whether the pointer belongs to the upper half of the address space, where
+// the kernel lives on the supported architectures
+int __attribute__((always_inline)) is_kernel_address(const void *ptr) {
+    return (s64)ptr < 0;
+}
+
+void __attribute__((always_inline)) read_sock_addr(struct sockaddr *address, struct sock_addr_t *sa) {
+    bpf_probe_read(&sa->family, sizeof(sa->family), &address->sa_family);
+
+    // ports and addresses are kept in network byte order, they are converted in user space
+    switch (sa->family) {
+    case AF_INET:
+        bpf_probe_read(&sa->port, sizeof(sa->port), &((struct sockaddr_in *)address)->sin_port);
+        bpf_probe_read(&sa->addr[0], sizeof(u32), &((struct sockaddr_in *)address)->sin_addr);
+        break;
+    case AF_INET6:
+        bpf_probe_read(&sa->port, sizeof(sa->port), &((struct sockaddr_in6 *)address)->sin6_port);
+        bpf_probe_read(&sa->addr, sizeof(sa->addr), &((struct sockaddr_in6 *)address)->sin6_addr);
+        break;
+    }
+}
+
+u16 __attribute__((always_inline)) get_sock_protocol(struct socket *sock) {
+    short type = 0;
+    bpf_probe_read(&type, sizeof(type), &sock->type);
+
+    switch (type) {
+    case SOCK_STREAM:
+        return IPPROTO_TCP;
+    case SOCK_DGRAM:
+        return IPPROTO_UDP;
+    }
+    return 0;
+}
+
+struct bind_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct span_context_t span;
+    struct container_context_t container;
+    struct syscall_t syscall;
+
+    struct sock_addr_t addr;
+};
+
+SYSCALL_KPROBE0(bind) {
+    struct policy_t policy = fetch_policy(EVENT_BIND);
+    if (is_discarded_by_process(policy.mode, EVENT_BIND)) {
+        return 0;
+    }
+
+    struct syscall_cache_t syscall = {
+        .type = EVENT_BIND,
+    };
+
+    cache_syscall(&syscall);
+    return 0;
+}
+
+SEC("kprobe/security_socket_bind")
+int kprobe_security_socket_bind(struct pt_regs *ctx) {
+    struct syscall_cache_t *syscall = peek_syscall(EVENT_BIND);
+    if (!syscall)
+        return 0;
+
+    struct socket *sock = (struct socket *)PT_REGS_PARM1(ctx);
+    struct sockaddr *address = (struct sockaddr *)PT_REGS_PARM2(ctx);
+
+    read_sock_addr(address, &syscall->net.addr);
+    syscall->net.addr.protocol = get_sock_protocol(sock);
+    return 0;
+}
+
+int __attribute__((always_inline)) sys_bind_ret(void *ctx, int retval) {
+    struct syscall_cache_t *syscall = pop_syscall(EVENT_BIND);
+    if (!syscall)
+        return 0;
+
+    struct bind_event_t event = {
+        .syscall.retval = retval,
+        .addr = syscall->net.addr,
+    };
+
+    struct proc_cache_t *entry = fill_process_context(&event.process);
+    fill_container_context(entry, &event.container);
+    fill_span_context(&event.span);
+
+    send_event(ctx, EVENT_BIND, event);
+    return 0;
+}
+
+SYSCALL_KRETPROBE(bind) {
+    return sys_bind_ret(ctx, (int)PT_REGS_RC(ctx));
+}
+
+SEC("tracepoint/syscalls/sys_exit_bind")
+int tracepoint_syscalls_sys_exit_bind(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_bind_ret(args, (int)args->ret);
+}
+
+struct connect_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct span_context_t span;
+    struct container_context_t container;
+    struct syscall_t syscall;
+
+    struct sock_addr_t addr;
+};
+
+SYSCALL_KPROBE0(connect) {
+    struct policy_t policy = fetch_policy(EVENT_CONNECT);
+    if (is_discarded_by_process(policy.mode, EVENT_CONNECT)) {
+        return 0;
+    }
+
+    struct syscall_cache_t syscall = {
+        .type = EVENT_CONNECT,
+    };
+
+    cache_syscall(&syscall);
+    return 0;
+}
+
+SEC("kprobe/security_socket_connect")
+int kprobe_security_socket_connect(struct pt_regs *ctx) {
+    struct syscall_cache_t *syscall = peek_syscall(EVENT_CONNECT);
+    if (!syscall)
+        return 0;
+
+    struct socket *sock = (struct socket *)PT_REGS_PARM1(ctx);
+    struct sockaddr *address = (struct sockaddr *)PT_REGS_PARM2(ctx);
+
+    read_sock_addr(address, &syscall->net.addr);
+    syscall->net.addr.protocol = get_sock_protocol(sock);
+    return 0;
+}
+
+int __attribute__((always_inline)) sys_connect_ret(void *ctx, int retval) {
+    struct syscall_cache_t *syscall = pop_syscall(EVENT_CONNECT);
+    if (!syscall)
+        return 0;
+
+    struct connect_event_t event = {
+        .syscall.retval = retval,
+        .addr = syscall->net.addr,
+    };
+
+    struct proc_cache_t *entry = fill_process_context(&event.process);
+    fill_container_context(entry, &event.container);
+    fill_span_context(&event.span);
+
+    send_event(ctx, EVENT_CONNECT, event);
+    return 0;
+}
+
+SYSCALL_KRETPROBE(connect) {
+    return sys_connect_ret(ctx, (int)PT_REGS_RC(ctx));
+}
+
+SEC("tracepoint/syscalls/sys_exit_connect")
+int tracepoint_syscalls_sys_exit_connect(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_connect_ret(args, (int)args->ret);
+}
+
+struct dns_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct span_context_t span;
+    struct container_context_t container;
+
+    u16 size;
+    u16 padding[3];
+    char payload[DNS_MAX_LENGTH];
+};
+
+// the DNS events don't fit on the eBPF stack, they are built in a per-CPU buffer
+struct bpf_map_def SEC("maps/dns_event_gen") dns_event_gen = {
+    .type = BPF_MAP_TYPE_PERCPU_ARRAY,
+    .key_size = sizeof(u32),
+    .value_size = sizeof(struct dns_event_t),
+    .max_entries = 1,
+    .pinning = 0,
+    .namespace = "",
+};
+
+// read_msg_payload reads the location of the first segment of the data sent with a message. The layout of the iterator
+// changed over time: since 6.4 the buffer pointer comes before the count field and overlays a struct iovec
+// with it, before it came right after the count field. The pointer either targets the user buffer itself
+// (ITER_UBUF, since 6.0), or an array of struct iovec copied in kernel memory (ITER_IOVEC) whose first
+// entry gives the user buffer. The value of the iterator types changed between versions, the address
+// space of the pointer tells them apart instead.
+int __attribute__((always_inline)) read_msg_payload(struct msghdr *msg, struct iovec *vec) {
+    u64 count_offset = get_iov_iter_count_offset();
+    u64 ptr_offset = count_offset == 16 ? count_offset + 8 : count_offset - 8;
+
+    void *iter = &msg->msg_iter;
+    void *ptr = NULL;
+    bpf_probe_read(&ptr, sizeof(ptr), iter + ptr_offset);
+    if (ptr == NULL) {
+        return -1;
+    }
+
+    if (is_kernel_address(ptr)) {
+        return bpf_probe_read(vec, sizeof(*vec), ptr);
+    }
+
+    vec->iov_base = ptr;
+    return bpf_probe_read(&vec->iov_len, sizeof(vec->iov_len), iter + count_offset);
+}
+
+// DNS requests are captured when sent over UDP to port 53. Only the first
+// segment of the message is copied, the question is parsed in user space.
+SEC("kprobe/security_socket_sendmsg")
+int kprobe_security_socket_sendmsg(struct pt_regs *ctx) {
+    struct policy_t policy = fetch_policy(EVENT_DNS);
+    if (is_discarded_by_process(policy.mode, EVENT_DNS)) {
+        return 0;
+    }
+
+    struct socket *sock = (struct socket *)PT_REGS_PARM1(ctx);
+    struct msghdr *msg = (struct msghdr *)PT_REGS_PARM2(ctx);
+
+    if (get_sock_protocol(sock) != IPPROTO_UDP) {
+        return 0;
+    }
+
+    // the destination is either given with the message (sendto, sendmsg) or
+    // was set on the socket (connect)
+    u16 port = 0;
+    struct sockaddr *address = NULL;
+    bpf_probe_read(&address, sizeof(address), &msg->msg_name);
+    if (address != NULL) {
+        struct sock_addr_t sa = {};
+        read_sock_addr(address, &sa);
+        port = sa.port;
+    } else {
+        struct sock *sk = NULL;
+        bpf_probe_read(&sk, sizeof(sk), &sock->sk);
+        if (sk == NULL) {
+            return 0;
+        }
+        bpf_probe_read(&port, sizeof(port), (void *)sk + get_skc_dport_offset());
+    }
+
+    if (port != bpf_htons(DNS_PORT)) {
+        return 0;
+    }
+
+    struct iovec vec = {};
+    if (read_msg_payload(msg, &vec) < 0) {
+        return 0;
+    }
+
+    u64 len = vec.iov_len;
+    if (len < DNS_HEADER_LENGTH) {
+        return 0;
+    }
+    if (len > DNS_MAX_LENGTH) {
+        len = DNS_MAX_LENGTH;
+    }
+
+    u32 key = 0;
+    struct dns_event_t *event = bpf_map_lookup_elem(&dns_event_gen, &key);
+    if (event == NULL) {
+        return 0;
+    }
+    // the buffer is reused, reset the fields which are not all overwritten
+    event->process = (struct process_context_t){};
+    event->span = (struct span_context_t){};
+    event->container = (struct container_context_t){};
+    event->size = len;
+    if (bpf_probe_read(&event->payload, len, vec.iov_base) < 0) {
+        return 0;
+    }
+
+    struct proc_cache_t *entry = fill_process_context(&event->process);
+    fill_container_context(entry, &event->container);
+    fill_span_context(&event->span);
+
+    send_event(ctx, EVENT_DNS, (*event));
+    return 0;
+}
+
+#endif
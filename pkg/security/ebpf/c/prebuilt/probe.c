@@ -45,6 +45,7 @@
 #include "ptrace.h"
 #include "mmap.h"
 #include "mprotect.h"
+#include "network.h"
 #include "raw_syscalls.h"
 
 struct invalidate_dentry_event_t {
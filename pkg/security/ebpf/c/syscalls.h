@@ -164,6 +164,10 @@ struct syscall_cache_t {
             u64 vm_protection;
             u64 req_protection;
         } mprotect;
+
+        struct {
+            struct sock_addr_t addr;
+        } net;
     };
 };
 
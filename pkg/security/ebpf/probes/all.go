@@ -40,6 +40,7 @@ func AllProbes() []*manager.Probe {
 	allProbes = append(allProbes, getPTraceProbes()...)
 	allProbes = append(allProbes, getMMapProbes()...)
 	allProbes = append(allProbes, getMProtectProbes()...)
+	allProbes = append(allProbes, getNetworkProbes()...)
 
 	allProbes = append(allProbes,
 		// Syscall monitor
@@ -99,6 +100,8 @@ func AllMaps() []*manager.Map {
 		// SELinux tables
 		{Name: "selinux_write_buffer"},
 		{Name: "selinux_enforce_status"},
+		// Network tables
+		{Name: "dns_event_gen"},
 		// Syscall monitor tables
 		{Name: "buffer_selector"},
 		{Name: "noisy_processes_fb"},
@@ -0,0 +1,89 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package module
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+
+	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+// Enforcer executes the actions of the rules that interact with the system
+type Enforcer struct {
+	enabled   bool
+	allowlist map[string]bool
+	killFnc   func(pid int, sig unix.Signal) error
+}
+
+// NewEnforcer returns a new enforcer. The processes whose executable is listed
+// in the allowlist are never signaled.
+func NewEnforcer(enabled bool, allowlist []string) *Enforcer {
+	e := &Enforcer{
+		enabled:   enabled,
+		allowlist: make(map[string]bool, len(allowlist)),
+		killFnc:   unix.Kill,
+	}
+	for _, path := range allowlist {
+		e.allowlist[filepath.Clean(path)] = true
+	}
+	return e
+}
+
+// Kill sends the signal of the given definition to the process of the event
+func (e *Enforcer) Kill(def *rules.KillDefinition, event *sprobe.Event) sprobe.ActionReport {
+	report := sprobe.ActionReport{Action: "kill", Signal: def.GetSignal()}
+
+	if !e.enabled {
+		return report.Skipped("enforcement disabled")
+	}
+
+	sig := unix.SignalNum(def.GetSignal())
+	if sig == 0 {
+		return report.Failed(fmt.Sprintf("unknown signal `%s`", def.Signal))
+	}
+
+	entry := event.ResolveProcessCacheEntry()
+	pid := int(entry.Pid)
+	report.Pid = entry.Pid
+
+	switch {
+	case pid <= 1:
+		return report.Skipped("protected process")
+	case pid == os.Getpid():
+		return report.Skipped("agent process")
+	case e.allowlist[filepath.Clean(entry.PathnameStr)]:
+		return report.Skipped("allowlisted executable")
+	}
+
+	if err := e.killFnc(pid, sig); err != nil {
+		return report.Failed(err.Error())
+	}
+	return report.Performed()
+}
+
+// applyActions executes the kill and tag actions of a rule that matched an event.
+// The 'set' actions are executed by the rule set itself.
+func (m *Module) applyActions(rule *rules.Rule, event *sprobe.Event) (reports []sprobe.ActionReport, tags []string) {
+	for _, action := range rule.Definition.Actions {
+		switch {
+		case action.Kill != nil:
+			reports = append(reports, m.enforcer.Kill(action.Kill, event))
+		case len(action.Tag) != 0:
+			for k, v := range action.Tag {
+				tags = append(tags, k+":"+v)
+			}
+		}
+	}
+	return reports, tags
+}
@@ -63,6 +63,7 @@ type Module struct {
 
 	selfTester *SelfTester
 	reloader   *debouncer.Debouncer
+	enforcer   *Enforcer
 }
 
 // Register the runtime security agent module
@@ -273,6 +274,7 @@ func (m *Module) Reload() error {
 	opts.
 		WithConstants(model.SECLConstants).
 		WithVariables(model.SECLVariables).
+		WithVariableScopers(model.SECLVariableScopers).
 		WithSupportedDiscarders(sprobe.SupportedDiscarders).
 		WithEventTypeEnabled(m.getEventTypeEnabled()).
 		WithReservedRuleIDs(sprobe.AllCustomRuleIDs()).
@@ -284,7 +286,8 @@ func (m *Module) Reload() error {
 	loadApproversErr := rules.LoadPolicies(policiesDir, approverRuleSet)
 
 	// switch SECLVariables to use the real Event structure and not the mock model.Event one
-	opts.WithVariables(sprobe.SECLVariables)
+	opts.WithVariables(sprobe.SECLVariables).
+		WithVariableScopers(sprobe.SECLVariableScopers)
 
 	ruleSet := m.probe.NewRuleSet(&opts)
 	loadErr := rules.LoadPolicies(policiesDir, ruleSet)
@@ -384,6 +387,19 @@ func (m *Module) EventDiscarderFound(rs *rules.RuleSet, event eval.Event, field
 func (m *Module) HandleEvent(event *sprobe.Event) {
 	if ruleSet := m.GetRuleSet(); ruleSet != nil {
 		ruleSet.Evaluate(event)
+
+		// process scoped variables are not reachable anymore once the process exited, and
+		// container scoped ones once the last process of the container exited
+		if event.GetEventType() == model.ExitEventType {
+			ruleSet.ReleaseVariableScope(rules.ProcessScope, event)
+
+			if ruleSet.HasVariableScope(rules.ContainerScope, event) {
+				containerID := event.ResolveContainerID(&event.ContainerContext)
+				if !m.probe.GetResolvers().ProcessResolver.HasOtherContainerProcesses(containerID, event.ProcessContext.Pid) {
+					ruleSet.ReleaseVariableScope(rules.ContainerScope, event)
+				}
+			}
+		}
 	}
 }
 
@@ -403,6 +419,10 @@ func (m *Module) RuleMatch(rule *rules.Rule, event eval.Event) {
 
 	id := event.(*sprobe.Event).ContainerContext.ID
 
+	// apply the actions of the rule before the process state changes
+	reports, actionTags := m.applyActions(rule, event.(*sprobe.Event))
+	event.(*sprobe.Event).ActionReports = reports
+
 	extTagsCb := func() []string {
 		var tags []string
 
@@ -415,6 +435,8 @@ func (m *Module) RuleMatch(rule *rules.Rule, event eval.Event) {
 			service = m.config.HostServiceName
 		}
 
+		tags = append(tags, actionTags...)
+
 		return append(tags, m.probe.GetResolvers().TagsResolver.Resolve(id)...)
 	}
 
@@ -545,6 +567,7 @@ func NewModule(cfg *sconfig.Config) (module.Module, error) {
 		apiServer:      NewAPIServer(cfg, probe, statsdClient),
 		grpcServer:     grpc.NewServer(),
 		rateLimiter:    NewRateLimiter(statsdClient, LimiterOpts{Limits: limits}),
+		enforcer:       NewEnforcer(cfg.EnforcementEnabled, cfg.EnforcementKillAllowlist),
 		sigupChan:      make(chan os.Signal, 1),
 		currentRuleSet: 1,
 		ctx:            ctx,
@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package probe
+
+const (
+	// ActionStatusPerformed is reported when the action succeeded
+	ActionStatusPerformed = "performed"
+	// ActionStatusSkipped is reported when the action was not executed on purpose
+	ActionStatusSkipped = "skipped"
+	// ActionStatusFailed is reported when the action failed
+	ActionStatusFailed = "failed"
+)
+
+// ActionReport describes the outcome of a rule action executed on an event
+type ActionReport struct {
+	Action string
+	Status string
+	Detail string
+	Signal string
+	Pid    uint32
+}
+
+// Performed returns a copy of the report marked as performed
+func (r ActionReport) Performed() ActionReport {
+	r.Status = ActionStatusPerformed
+	return r
+}
+
+// Skipped returns a copy of the report marked as skipped for the given reason
+func (r ActionReport) Skipped(reason string) ActionReport {
+	r.Status = ActionStatusSkipped
+	r.Detail = reason
+	return r
+}
+
+// Failed returns a copy of the report marked as failed with the given error
+func (r ActionReport) Failed(err string) ActionReport {
+	r.Status = ActionStatusFailed
+	r.Detail = err
+	return r
+}
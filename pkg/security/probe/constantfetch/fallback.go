@@ -40,6 +40,10 @@ func (f *FallbackConstantFetcher) appendRequest(id string) {
 		value = getSignalTTYOffset(f.kernelVersion)
 	case "tty_name_offset":
 		value = getTTYNameOffset(f.kernelVersion)
+	case "sock_common_skc_dport_offset":
+		value = getSockCommonSKCDportOffset(f.kernelVersion)
+	case "iov_iter_count_offset":
+		value = getIovIterCountOffset(f.kernelVersion)
 	}
 	f.res[id] = value
 }
@@ -149,3 +153,19 @@ func getTTYNameOffset(kv *kernel.Version) uint64 {
 
 	return nameOffset
 }
+
+func getSockCommonSKCDportOffset(kv *kernel.Version) uint64 {
+	// skc_dport follows the skc_addrpair and skc_hash unions
+	return 12
+}
+
+func getIovIterCountOffset(kv *kernel.Version) uint64 {
+	offset := uint64(16)
+
+	// since 6.4 the count follows the iovec pointer, they are overlaid with a struct iovec
+	if kv.Code != 0 && kv.Code >= kernel.Kernel6_4 {
+		offset = 24
+	}
+
+	return offset
+}
@@ -626,5 +626,8 @@ func init() {
 	SupportedDiscarders["mmap.file.path"] = true
 
 	allDiscarderHandlers["mprotect"] = processDiscarderWrapper(model.MProtectEventType, nil)
+	allDiscarderHandlers["bind"] = processDiscarderWrapper(model.BindEventType, nil)
+	allDiscarderHandlers["connect"] = processDiscarderWrapper(model.ConnectEventType, nil)
+	allDiscarderHandlers["dns"] = processDiscarderWrapper(model.DNSEventType, nil)
 	allDiscarderHandlers["ptrace"] = processDiscarderWrapper(model.PTraceEventType, nil)
 }
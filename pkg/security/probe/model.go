@@ -47,6 +47,9 @@ type Event struct {
 	processCacheEntry   *model.ProcessCacheEntry
 	pathResolutionError error
 	scrubber            *pconfig.DataScrubber
+
+	// ActionReports holds the outcome of the actions of the rule that matched the event
+	ActionReports []ActionReport
 }
 
 // Retain the event
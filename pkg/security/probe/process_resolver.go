@@ -752,6 +752,19 @@ func (p *ProcessResolver) Get(pid uint32) *model.ProcessCacheEntry {
 	return p.entryCache[pid]
 }
 
+// HasOtherContainerProcesses returns whether a process other than pid runs in the given container
+func (p *ProcessResolver) HasOtherContainerProcesses(containerID string, pid uint32) bool {
+	p.RLock()
+	defer p.RUnlock()
+
+	for entryPid, entry := range p.entryCache {
+		if entryPid != pid && entry.ContainerID == containerID {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateUID updates the credentials of the provided pid
 func (p *ProcessResolver) UpdateUID(pid uint32, e *Event) {
 	if e.ProcessContext.Pid != e.ProcessContext.Tid {
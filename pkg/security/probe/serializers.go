@@ -241,6 +241,40 @@ type PTraceEventSerializer struct {
 	Tracee  *ProcessContextSerializer `json:"tracee,omitempty" jsonschema_description:"process context of the tracee"`
 }
 
+// NetworkAddressSerializer serializes a socket address to JSON
+type NetworkAddressSerializer struct {
+	Family string `json:"family" jsonschema_description:"Address family"`
+	IP     string `json:"ip,omitempty" jsonschema_description:"IP address"`
+	Port   uint16 `json:"port" jsonschema_description:"Port number"`
+}
+
+// BindEventSerializer serializes a bind event to JSON
+type BindEventSerializer struct {
+	Addr     NetworkAddressSerializer `json:"addr" jsonschema_description:"Bound address"`
+	Protocol string                   `json:"protocol" jsonschema_description:"Layer 4 protocol of the socket"`
+}
+
+// ConnectEventSerializer serializes a connect event to JSON
+type ConnectEventSerializer struct {
+	Addr     NetworkAddressSerializer `json:"addr" jsonschema_description:"Connected address"`
+	Protocol string                   `json:"protocol" jsonschema_description:"Layer 4 protocol of the socket"`
+}
+
+// DNSQuestionSerializer serializes a DNS question to JSON
+type DNSQuestionSerializer struct {
+	Class string `json:"class" jsonschema_description:"class looked up by the DNS question"`
+	Type  string `json:"type" jsonschema_description:"a two octet code which specifies the DNS question type"`
+	Name  string `json:"name" jsonschema_description:"the queried domain name"`
+	Size  uint16 `json:"size" jsonschema_description:"the total DNS request size in bytes"`
+	Count uint16 `json:"count" jsonschema_description:"the total count of questions in the DNS request"`
+}
+
+// DNSEventSerializer serializes a DNS event to JSON
+type DNSEventSerializer struct {
+	ID       uint16                `json:"id" jsonschema_description:"id is the unique identifier of the DNS request"`
+	Question DNSQuestionSerializer `json:"question,omitempty" jsonschema_description:"question is a DNS question for the DNS request"`
+}
+
 // DDContextSerializer serializes a span context to JSON
 // easyjson:json
 type DDContextSerializer struct {
@@ -248,6 +282,16 @@ type DDContextSerializer struct {
 	TraceID uint64 `json:"trace_id,omitempty" jsonschema_description:"Trace ID used for APM correlation"`
 }
 
+// ActionSerializer serializes the outcome of a rule action to JSON
+// easyjson:json
+type ActionSerializer struct {
+	Action string `json:"action" jsonschema_description:"Action type"`
+	Status string `json:"status" jsonschema_description:"Action status: performed, skipped or failed"`
+	Detail string `json:"detail,omitempty" jsonschema_description:"Reason for which the action was skipped or failed"`
+	Signal string `json:"signal,omitempty" jsonschema_description:"Signal sent by a kill action"`
+	Pid    uint32 `json:"pid,omitempty" jsonschema_description:"Process ID targeted by the action"`
+}
+
 // EventSerializer serializes an event to JSON
 // easyjson:json
 type EventSerializer struct {
@@ -258,11 +302,15 @@ type EventSerializer struct {
 	*MMapEventSerializer       `json:"mmap,omitempty"`
 	*MProtectEventSerializer   `json:"mprotect,omitempty"`
 	*PTraceEventSerializer     `json:"ptrace,omitempty"`
+	*BindEventSerializer       `json:"bind,omitempty"`
+	*ConnectEventSerializer    `json:"connect,omitempty"`
+	*DNSEventSerializer        `json:"dns,omitempty"`
 	UserContextSerializer      UserContextSerializer       `json:"usr,omitempty"`
 	ProcessContextSerializer   ProcessContextSerializer    `json:"process,omitempty"`
 	DDContextSerializer        DDContextSerializer         `json:"dd,omitempty"`
 	ContainerContextSerializer *ContainerContextSerializer `json:"container,omitempty"`
 	Date                       time.Time                   `json:"date,omitempty"`
+	Actions                    []ActionSerializer          `json:"actions,omitempty" jsonschema_description:"Actions executed when the rule matched"`
 }
 
 func getInUpperLayer(r *Resolvers, f *model.FileFields) *bool {
@@ -539,6 +587,41 @@ func newMProtectEventSerializer(e *Event) *MProtectEventSerializer {
 	}
 }
 
+func newNetworkAddressSerializer(addr *model.NetworkAddress) NetworkAddressSerializer {
+	return NetworkAddressSerializer{
+		Family: model.AddressFamily(addr.Family).String(),
+		IP:     addr.IP,
+		Port:   addr.Port,
+	}
+}
+
+func newBindEventSerializer(e *Event) *BindEventSerializer {
+	return &BindEventSerializer{
+		Addr:     newNetworkAddressSerializer(&e.Bind.Addr),
+		Protocol: model.L4Protocol(e.Bind.Protocol).String(),
+	}
+}
+
+func newConnectEventSerializer(e *Event) *ConnectEventSerializer {
+	return &ConnectEventSerializer{
+		Addr:     newNetworkAddressSerializer(&e.Connect.Addr),
+		Protocol: model.L4Protocol(e.Connect.Protocol).String(),
+	}
+}
+
+func newDNSEventSerializer(e *Event) *DNSEventSerializer {
+	return &DNSEventSerializer{
+		ID: e.DNS.ID,
+		Question: DNSQuestionSerializer{
+			Class: model.QClass(e.DNS.Class).String(),
+			Type:  model.QType(e.DNS.Type).String(),
+			Name:  e.DNS.Name,
+			Size:  e.DNS.Size,
+			Count: e.DNS.Count,
+		},
+	}
+}
+
 func newPTraceEventSerializer(e *Event) *PTraceEventSerializer {
 	ptes := &PTraceEventSerializer{
 		Request: model.PTraceRequest(e.PTrace.Request).String(),
@@ -580,6 +663,16 @@ func NewEventSerializer(event *Event) *EventSerializer {
 		}
 	}
 
+	for _, report := range event.ActionReports {
+		s.Actions = append(s.Actions, ActionSerializer{
+			Action: report.Action,
+			Status: report.Status,
+			Detail: report.Detail,
+			Signal: report.Signal,
+			Pid:    report.Pid,
+		})
+	}
+
 	s.UserContextSerializer.User = s.ProcessContextSerializer.User
 	s.UserContextSerializer.Group = s.ProcessContextSerializer.Group
 
@@ -756,6 +849,15 @@ func NewEventSerializer(event *Event) *EventSerializer {
 	case model.PTraceEventType:
 		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.PTrace.Retval)
 		s.PTraceEventSerializer = newPTraceEventSerializer(event)
+	case model.BindEventType:
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.Bind.Retval)
+		s.BindEventSerializer = newBindEventSerializer(event)
+	case model.ConnectEventType:
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.Connect.Retval)
+		s.ConnectEventSerializer = newConnectEventSerializer(event)
+	case model.DNSEventType:
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(0)
+		s.DNSEventSerializer = newDNSEventSerializer(event)
 	}
 
 	return s
@@ -9,7 +9,10 @@
 package probe
 
 import (
+	"fmt"
+
 	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
 )
 
 var (
@@ -21,4 +24,21 @@ var (
 			},
 		},
 	}
+
+	// SECLVariableScopers set of scopers used by the variables set by rule actions
+	SECLVariableScopers = map[rules.VariableScope]rules.VariableScoper{
+		rules.ProcessScope: func(ctx *eval.Context) string {
+			ev := (*Event)(ctx.Object)
+			entry := ev.ResolveProcessCacheEntry()
+			if entry.Pid == 0 {
+				return ""
+			}
+			// the cookie identifies the exec, so that a reused pid doesn't inherit the variables
+			return fmt.Sprintf("%d/%d", entry.Pid, entry.Cookie)
+		},
+		rules.ContainerScope: func(ctx *eval.Context) string {
+			ev := (*Event)(ctx.Object)
+			return ev.ResolveContainerID(&ev.ContainerContext)
+		},
+	}
 )
@@ -66,6 +66,7 @@ func identToEvaluator(obj *ident, opts *Opts, state *State) (interface{}, lexer.
 
 	if state.macros != nil {
 		if macro, ok := state.macros[*obj.Ident]; ok {
+			state.UpdateMacros(*obj.Ident, macro)
 			return macro.Value, obj.Pos, nil
 		}
 	}
@@ -161,6 +162,7 @@ func arrayToEvaluator(array *ast.Array, opts *Opts, state *State) (interface{},
 	} else if array.Ident != nil {
 		if state.macros != nil {
 			if macro, ok := state.macros[*array.Ident]; ok {
+				state.UpdateMacros(*array.Ident, macro)
 				return macro.Value, array.Pos, nil
 			}
 		}
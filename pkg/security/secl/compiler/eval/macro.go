@@ -29,6 +29,8 @@ type MacroEvaluator struct {
 	Value       interface{}
 	EventTypes  []EventType
 	FieldValues map[Field][]FieldValue
+	// Macros holds the IDs of the macros referenced by the macro
+	Macros []MacroID
 }
 
 // GetEvaluator - Returns the MacroEvaluator of the Macro corresponding to the SECL `Expression`
@@ -83,6 +85,7 @@ func macroToEvaluator(macro *ast.Macro, model Model, opts *Opts, field Field) (*
 		Value:       eval,
 		EventTypes:  events,
 		FieldValues: state.fieldValues,
+		Macros:      state.UsedMacros(),
 	}, nil
 }
 
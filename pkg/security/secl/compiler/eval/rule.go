@@ -34,6 +34,8 @@ type RuleEvaluator struct {
 	Eval        BoolEvalFnc
 	EventTypes  []EventType
 	FieldValues map[Field][]FieldValue
+	// Macros holds the IDs of the macros referenced by the rule, directly or through other macros
+	Macros []MacroID
 
 	partialEvals map[Field]BoolEvalFnc
 }
@@ -255,6 +257,7 @@ func ruleToEvaluator(rule *ast.Rule, model Model, opts *Opts) (*RuleEvaluator, e
 		Eval:        evalBool.EvalFnc,
 		EventTypes:  events,
 		FieldValues: state.fieldValues,
+		Macros:      state.UsedMacros(),
 	}, nil
 }
 
@@ -5,6 +5,8 @@
 
 package eval
 
+import "sort"
+
 type registerInfo struct {
 	iterator  Iterator
 	field     Field
@@ -18,6 +20,7 @@ type State struct {
 	events        map[EventType]bool
 	fieldValues   map[Field][]FieldValue
 	macros        map[MacroID]*MacroEvaluator
+	usedMacros    map[MacroID]bool
 	registersInfo map[RegisterID]*registerInfo
 }
 
@@ -28,6 +31,24 @@ func (s *State) UpdateFields(field Field) {
 	}
 }
 
+// UpdateMacros records the use of a macro, and of the macros it references, by the rule
+func (s *State) UpdateMacros(id MacroID, macro *MacroEvaluator) {
+	s.usedMacros[id] = true
+	for _, nested := range macro.Macros {
+		s.usedMacros[nested] = true
+	}
+}
+
+// UsedMacros returns the sorted list of the macros used by the rule
+func (s *State) UsedMacros() []MacroID {
+	macros := make([]MacroID, 0, len(s.usedMacros))
+	for id := range s.usedMacros {
+		macros = append(macros, id)
+	}
+	sort.Strings(macros)
+	return macros
+}
+
 // UpdateFieldValues updates the field values
 func (s *State) UpdateFieldValues(field Field, value FieldValue) error {
 	values, ok := s.fieldValues[field]
@@ -49,6 +70,7 @@ func newState(model Model, field Field, macros map[MacroID]*MacroEvaluator) *Sta
 		model:         model,
 		events:        make(map[EventType]bool),
 		fieldValues:   make(map[Field][]FieldValue),
+		usedMacros:    make(map[MacroID]bool),
 		registersInfo: make(map[RegisterID]*registerInfo),
 	}
 }
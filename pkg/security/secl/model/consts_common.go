@@ -389,6 +389,59 @@ var (
 		"BPF_SK_SKB_VERDICT":           BpfSkSkbVerdict,
 	}
 
+	// addressFamilyConstants are the supported network address families
+	addressFamilyConstants = map[string]uint16{
+		"AF_UNSPEC": AFUnspec,
+		"AF_UNIX":   AFUnix,
+		"AF_INET":   AFInet,
+		"AF_INET6":  AFInet6,
+	}
+
+	// l4ProtocolConstants are the supported layer 4 protocols
+	l4ProtocolConstants = map[string]uint16{
+		"IPPROTO_IP":      0,
+		"IPPROTO_ICMP":    1,
+		"IPPROTO_TCP":     6,
+		"IPPROTO_UDP":     17,
+		"IPPROTO_IPV6":    41,
+		"IPPROTO_ICMPV6":  58,
+		"IPPROTO_SCTP":    132,
+		"IPPROTO_UDPLITE": 136,
+		"IPPROTO_RAW":     255,
+	}
+
+	// dnsQTypeConstants are the DNS question types
+	dnsQTypeConstants = map[string]uint16{
+		"A":      1,
+		"NS":     2,
+		"CNAME":  5,
+		"SOA":    6,
+		"PTR":    12,
+		"HINFO":  13,
+		"MX":     15,
+		"TXT":    16,
+		"AAAA":   28,
+		"SRV":    33,
+		"NAPTR":  35,
+		"DS":     43,
+		"RRSIG":  46,
+		"DNSKEY": 48,
+		"HTTPS":  65,
+		"AXFR":   252,
+		"ANY":    255,
+		"CAA":    257,
+	}
+
+	// dnsQClassConstants are the DNS question classes
+	dnsQClassConstants = map[string]uint16{
+		"CLASS_INET":   1,
+		"CLASS_CSNET":  2,
+		"CLASS_CHAOS":  3,
+		"CLASS_HESIOD": 4,
+		"CLASS_NONE":   254,
+		"CLASS_ANY":    255,
+	}
+
 	// SECLConstants are constants available in runtime security agent rules
 	SECLConstants = map[string]interface{}{
 		// boolean
@@ -411,6 +464,10 @@ var (
 	vmStrings                 = map[int]string{}
 	protStrings               = map[int]string{}
 	mmapFlagStrings           = map[int]string{}
+	addressFamilyStrings      = map[uint16]string{}
+	l4ProtocolStrings         = map[uint16]string{}
+	dnsQTypeStrings           = map[uint16]string{}
+	dnsQClassStrings          = map[uint16]string{}
 )
 
 // File flags
@@ -541,6 +598,30 @@ func initMMapFlagsConstants() {
 	}
 }
 
+func initNetworkConstants() {
+	for k, v := range addressFamilyConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: int(v)}
+		addressFamilyStrings[v] = k
+	}
+
+	for k, v := range l4ProtocolConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: int(v)}
+		l4ProtocolStrings[v] = k
+	}
+}
+
+func initDNSConstants() {
+	for k, v := range dnsQTypeConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: int(v)}
+		dnsQTypeStrings[v] = k
+	}
+
+	for k, v := range dnsQClassConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: int(v)}
+		dnsQClassStrings[v] = k
+	}
+}
+
 func initConstants() {
 	initErrorConstants()
 	initOpenConstants()
@@ -556,6 +637,8 @@ func initConstants() {
 	initVMConstants()
 	initProtConstansts()
 	initMMapFlagsConstants()
+	initNetworkConstants()
+	initDNSConstants()
 }
 
 func bitmaskToStringArray(bitmask int, intToStrMap map[int]string) []string {
@@ -1371,3 +1454,55 @@ type MMapFlag int
 func (mmf MMapFlag) String() string {
 	return bitmaskToString(int(mmf), mmapFlagStrings)
 }
+
+// Address families
+const (
+	// AFUnspec unspecified address family
+	AFUnspec uint16 = 0
+	// AFUnix local socket address family
+	AFUnix uint16 = 1
+	// AFInet IPv4 address family
+	AFInet uint16 = 2
+	// AFInet6 IPv6 address family
+	AFInet6 uint16 = 10
+)
+
+// AddressFamily represents a network address family
+type AddressFamily uint16
+
+func (af AddressFamily) String() string {
+	if str, exists := addressFamilyStrings[uint16(af)]; exists {
+		return str
+	}
+	return fmt.Sprintf("%d", af)
+}
+
+// L4Protocol represents a layer 4 protocol value
+type L4Protocol uint16
+
+func (proto L4Protocol) String() string {
+	if str, exists := l4ProtocolStrings[uint16(proto)]; exists {
+		return str
+	}
+	return fmt.Sprintf("%d", proto)
+}
+
+// QType represents a DNS question type
+type QType uint16
+
+func (qt QType) String() string {
+	if str, exists := dnsQTypeStrings[uint16(qt)]; exists {
+		return str
+	}
+	return fmt.Sprintf("%d", qt)
+}
+
+// QClass represents a DNS question class
+type QClass uint16
+
+func (qc QClass) String() string {
+	if str, exists := dnsQClassStrings[uint16(qc)]; exists {
+		return str
+	}
+	return fmt.Sprintf("%d", qc)
+}
@@ -18,4 +18,10 @@ var (
 
 	// ErrNonPrintable returned when a string contains non printable char
 	ErrNonPrintable = errors.New("non printable")
+
+	// ErrDNSNamePointerNotSupported returned when a DNS name uses message compression
+	ErrDNSNamePointerNotSupported = errors.New("dns name pointer not supported")
+
+	// ErrDNSNameMalformed returned when a DNS name is malformed
+	ErrDNSNameMalformed = errors.New("dns name malformed")
 )
@@ -67,6 +67,12 @@ const (
 	MMapEventType
 	// MProtectEventType MProtect event
 	MProtectEventType
+	// BindEventType Bind event
+	BindEventType
+	// ConnectEventType Connect event
+	ConnectEventType
+	// DNSEventType DNS event
+	DNSEventType
 	// MaxEventType is used internally to get the maximum number of kernel events.
 	MaxEventType
 
@@ -146,6 +152,12 @@ func (t EventType) String() string {
 		return "mmap"
 	case MProtectEventType:
 		return "mprotect"
+	case BindEventType:
+		return "bind"
+	case ConnectEventType:
+		return "connect"
+	case DNSEventType:
+		return "dns"
 
 	case CustomLostReadEventType:
 		return "lost_events_read"
@@ -148,6 +148,10 @@ type Event struct {
 	MMap     MMapEvent     `field:"mmap" event:"mmap"`         // [7.34] [Kernel] [Experimental] A mmap command was executed
 	MProtect MProtectEvent `field:"mprotect" event:"mprotect"` // [7.34] [Kernel] [Experimental] A mprotect command was executed
 
+	Bind    BindEvent    `field:"bind" event:"bind"`       // [7.35] [Network] [Experimental] A bind command was executed
+	Connect ConnectEvent `field:"connect" event:"connect"` // [7.35] [Network] [Experimental] A connect command was executed
+	DNS     DNSEvent     `field:"dns" event:"dns"`         // [7.35] [Network] [Experimental] A DNS request was sent
+
 	Mount            MountEvent            `field:"-"`
 	Umount           UmountEvent           `field:"-"`
 	InvalidateDentry InvalidateDentryEvent `field:"-"`
@@ -638,3 +642,36 @@ type MProtectEvent struct {
 	VMProtection  int    `field:"vm_protection"`
 	ReqProtection int    `field:"req_protection"`
 }
+
+// NetworkAddress represents a socket address
+type NetworkAddress struct {
+	Family uint16 `field:"family"` // Address family
+	IP     string `field:"ip"`     // IP address
+	Port   uint16 `field:"port"`   // Port number
+}
+
+// BindEvent represents a bind event
+type BindEvent struct {
+	SyscallEvent
+
+	Addr     NetworkAddress `field:"addr"`
+	Protocol uint16         `field:"protocol"` // Layer 4 protocol of the socket
+}
+
+// ConnectEvent represents a connect event
+type ConnectEvent struct {
+	SyscallEvent
+
+	Addr     NetworkAddress `field:"addr"`
+	Protocol uint16         `field:"protocol"` // Layer 4 protocol of the socket
+}
+
+// DNSEvent represents a DNS request event
+type DNSEvent struct {
+	ID    uint16 `field:"id"`              // DNS request ID
+	Name  string `field:"question.name"`   // The queried domain name
+	Type  uint16 `field:"question.type"`   // A two octet code which specifies the DNS question type
+	Class uint16 `field:"question.class"`  // The class looked up by the DNS question
+	Size  uint16 `field:"question.length"` // The total DNS request size in bytes
+	Count uint16 `field:"question.count"`  // Indicates the total number of questions in the DNS request
+}
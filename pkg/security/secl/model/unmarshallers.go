@@ -6,6 +6,9 @@
 package model
 
 import (
+	"encoding/binary"
+	"net"
+	"strings"
 	"time"
 	"unsafe"
 )
@@ -654,3 +657,131 @@ func (e *MProtectEvent) UnmarshalBinary(data []byte) (int, error) {
 	e.ReqProtection = int(ByteOrder.Uint32(data[read+24 : read+32]))
 	return read + 32, nil
 }
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *NetworkAddress) UnmarshalBinary(data []byte) (int, error) {
+	if len(data) < 20 {
+		return 0, ErrNotEnoughData
+	}
+
+	e.Family = ByteOrder.Uint16(data[16:18])
+	switch e.Family {
+	case AFInet:
+		e.IP = net.IP(data[0:4]).String()
+	case AFInet6:
+		e.IP = net.IP(data[0:16]).String()
+	}
+	// the port is kept in network byte order by the kernel
+	e.Port = binary.BigEndian.Uint16(data[18:20])
+	return 20, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *BindEvent) UnmarshalBinary(data []byte) (int, error) {
+	read, err := UnmarshalBinary(data, &e.SyscallEvent, &e.Addr)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(data)-read < 4 {
+		return 0, ErrNotEnoughData
+	}
+
+	e.Protocol = ByteOrder.Uint16(data[read : read+2])
+	return read + 4, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *ConnectEvent) UnmarshalBinary(data []byte) (int, error) {
+	read, err := UnmarshalBinary(data, &e.SyscallEvent, &e.Addr)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(data)-read < 4 {
+		return 0, ErrNotEnoughData
+	}
+
+	e.Protocol = ByteOrder.Uint16(data[read : read+2])
+	return read + 4, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *DNSEvent) UnmarshalBinary(data []byte) (int, error) {
+	if len(data) < 8 {
+		return 0, ErrNotEnoughData
+	}
+
+	e.Size = ByteOrder.Uint16(data[0:2])
+	if len(data)-8 < int(e.Size) {
+		return 0, ErrNotEnoughData
+	}
+
+	if err := e.decodeRequest(data[8 : 8+int(e.Size)]); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// decodeRequest decodes the header and the first question of a DNS request, the
+// fields of the header are in network byte order
+func (e *DNSEvent) decodeRequest(payload []byte) error {
+	if len(payload) < 12 {
+		return ErrNotEnoughData
+	}
+
+	e.ID = binary.BigEndian.Uint16(payload[0:2])
+	e.Count = binary.BigEndian.Uint16(payload[4:6])
+
+	name, read, err := decodeDNSName(payload[12:])
+	if err != nil {
+		return err
+	}
+	e.Name = name
+
+	question := payload[12+read:]
+	if len(question) < 4 {
+		return ErrNotEnoughData
+	}
+	e.Type = binary.BigEndian.Uint16(question[0:2])
+	e.Class = binary.BigEndian.Uint16(question[2:4])
+	return nil
+}
+
+// decodeDNSName decodes a DNS name made of length prefixed labels and returns it
+// along with the number of bytes read
+func decodeDNSName(data []byte) (string, int, error) {
+	var labels []string
+	var i int
+	for {
+		if i >= len(data) {
+			return "", 0, ErrNotEnoughData
+		}
+
+		length := int(data[i])
+		i++
+		if length == 0 {
+			break
+		}
+
+		// the two upper bits are set for message compression pointers, which
+		// can't be used in the first question of a request
+		if length&0xc0 == 0xc0 {
+			return "", 0, ErrDNSNamePointerNotSupported
+		}
+		if length&0xc0 != 0 {
+			return "", 0, ErrDNSNameMalformed
+		}
+
+		if i+length > len(data) {
+			return "", 0, ErrNotEnoughData
+		}
+		labels = append(labels, string(data[i:i+length]))
+		i += length
+	}
+
+	if len(labels) == 0 {
+		return "", 0, ErrDNSNameMalformed
+	}
+	return strings.Join(labels, "."), i, nil
+}
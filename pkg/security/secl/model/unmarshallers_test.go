@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalNetworkAddress(t *testing.T) {
+	data := make([]byte, 20)
+	copy(data, []byte{127, 0, 0, 1})
+	ByteOrder.PutUint16(data[16:18], AFInet)
+	data[18], data[19] = 0x10, 0x92
+
+	var addr NetworkAddress
+	read, err := addr.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, read)
+	assert.Equal(t, "127.0.0.1", addr.IP)
+	assert.Equal(t, uint16(4242), addr.Port)
+
+	data = make([]byte, 20)
+	data[15] = 1
+	ByteOrder.PutUint16(data[16:18], AFInet6)
+
+	read, err = addr.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, read)
+	assert.Equal(t, "::1", addr.IP)
+
+	_, err = addr.UnmarshalBinary(data[:10])
+	assert.Equal(t, ErrNotEnoughData, err)
+}
+
+func newDNSEventData(payload []byte) []byte {
+	data := make([]byte, 8+len(payload))
+	ByteOrder.PutUint16(data[0:2], uint16(len(payload)))
+	copy(data[8:], payload)
+	return data
+}
+
+func TestUnmarshalDNSEvent(t *testing.T) {
+	header := []byte{0x12, 0x34, 0x01, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+	t.Run("question", func(t *testing.T) {
+		payload := append(append([]byte{}, header...),
+			0x07, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0x03, 'c', 'o', 'm', 0x00,
+			0x00, 0x1c, 0x00, 0x01,
+		)
+
+		var e DNSEvent
+		_, err := e.UnmarshalBinary(newDNSEventData(payload))
+		assert.NoError(t, err)
+		assert.Equal(t, uint16(0x1234), e.ID)
+		assert.Equal(t, uint16(1), e.Count)
+		assert.Equal(t, uint16(len(payload)), e.Size)
+		assert.Equal(t, "example.com", e.Name)
+		assert.Equal(t, "AAAA", QType(e.Type).String())
+		assert.Equal(t, "CLASS_INET", QClass(e.Class).String())
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		payload := append(append([]byte{}, header...), 0xc0, 0x0c, 0x00, 0x01, 0x00, 0x01)
+
+		var e DNSEvent
+		_, err := e.UnmarshalBinary(newDNSEventData(payload))
+		assert.Equal(t, ErrDNSNamePointerNotSupported, err)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		payload := append(append([]byte{}, header...), 0x07, 'e', 'x', 'a')
+
+		var e DNSEvent
+		_, err := e.UnmarshalBinary(newDNSEventData(payload))
+		assert.Equal(t, ErrNotEnoughData, err)
+	})
+
+	t.Run("empty-name", func(t *testing.T) {
+		payload := append(append([]byte{}, header...), 0x00, 0x00, 0x01, 0x00, 0x01)
+
+		var e DNSEvent
+		_, err := e.UnmarshalBinary(newDNSEventData(payload))
+		assert.Equal(t, ErrDNSNameMalformed, err)
+	})
+}
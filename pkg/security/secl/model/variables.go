@@ -9,7 +9,10 @@
 package model
 
 import (
+	"strconv"
+
 	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
 )
 
 var (
@@ -21,4 +24,17 @@ var (
 			},
 		},
 	}
+
+	// SECLVariableScopers set of scopers used by the variables set by rule actions
+	SECLVariableScopers = map[rules.VariableScope]rules.VariableScoper{
+		rules.ProcessScope: func(ctx *eval.Context) string {
+			if pid := (*Event)(ctx.Object).ProcessContext.Process.Pid; pid != 0 {
+				return strconv.FormatUint(uint64(pid), 10)
+			}
+			return ""
+		},
+		rules.ContainerScope: func(ctx *eval.Context) string {
+			return (*Event)(ctx.Object).ContainerContext.ID
+		},
+	}
 )
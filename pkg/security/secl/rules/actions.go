@@ -0,0 +1,123 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// VariableScope describes the scope of a variable set by a rule action
+type VariableScope string
+
+const (
+	// GlobalScope variables are shared by all the events
+	GlobalScope VariableScope = ""
+	// ProcessScope variables are bound to the process of the event
+	ProcessScope VariableScope = "process"
+	// ContainerScope variables are bound to the container of the event
+	ContainerScope VariableScope = "container"
+)
+
+var variableNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*$`)
+
+// ActionDefinition describes an action executed when a rule matches. Exactly one
+// of the action kinds has to be defined.
+type ActionDefinition struct {
+	Set  *SetDefinition    `yaml:"set"`
+	Kill *KillDefinition   `yaml:"kill"`
+	Tag  map[string]string `yaml:"tag"`
+}
+
+// SetDefinition describes the 'set' action, setting the value of a variable that
+// can be referenced by the expressions of other rules as ${name}
+type SetDefinition struct {
+	Name  string        `yaml:"name"`
+	Value interface{}   `yaml:"value"`
+	Scope VariableScope `yaml:"scope"`
+}
+
+// KillDefinition describes the 'kill' action, sending a signal to the process of the event
+type KillDefinition struct {
+	Signal string `yaml:"signal"`
+}
+
+// Check returns an error if the action is invalid
+func (a *ActionDefinition) Check() error {
+	var kinds int
+	if a.Set != nil {
+		kinds++
+	}
+	if a.Kill != nil {
+		kinds++
+	}
+	if len(a.Tag) != 0 {
+		kinds++
+	}
+
+	switch {
+	case kinds == 0:
+		return errors.New("no action defined")
+	case kinds > 1:
+		return errors.New("only one action kind can be defined per action")
+	}
+
+	if a.Set != nil {
+		return a.Set.Check()
+	}
+	if a.Kill != nil {
+		return a.Kill.Check()
+	}
+	return nil
+}
+
+// VariableName returns the name used to reference the variable in the rule expressions
+func (s *SetDefinition) VariableName() string {
+	if s.Scope == GlobalScope {
+		return s.Name
+	}
+	return string(s.Scope) + "." + s.Name
+}
+
+// Check returns an error if the 'set' action is invalid
+func (s *SetDefinition) Check() error {
+	if !variableNamePattern.MatchString(s.Name) {
+		return fmt.Errorf("invalid variable name `%s`", s.Name)
+	}
+
+	switch s.Scope {
+	case GlobalScope, ProcessScope, ContainerScope:
+	default:
+		return fmt.Errorf("invalid scope `%s` for variable `%s`", s.Scope, s.Name)
+	}
+
+	switch s.Value.(type) {
+	case int, string:
+	default:
+		return fmt.Errorf("unsupported value type %T for variable `%s`, only integers and strings are supported", s.Value, s.Name)
+	}
+
+	return nil
+}
+
+// GetSignal returns the signal name, SIGKILL being the default
+func (k *KillDefinition) GetSignal() string {
+	if k.Signal == "" {
+		return "SIGKILL"
+	}
+	return strings.ToUpper(k.Signal)
+}
+
+// Check returns an error if the 'kill' action is invalid
+func (k *KillDefinition) Check() error {
+	if !strings.HasPrefix(k.GetSignal(), "SIG") {
+		return fmt.Errorf("invalid signal `%s`", k.Signal)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+type matchRecorder struct {
+	matches []string
+}
+
+func (r *matchRecorder) RuleMatch(rule *Rule, event eval.Event) {
+	r.matches = append(r.matches, rule.ID)
+}
+
+func (r *matchRecorder) EventDiscarderFound(rs *RuleSet, event eval.Event, field string, eventType eval.EventType) {
+}
+
+func newActionsTestRuleSet() *RuleSet {
+	var opts Opts
+	opts.
+		WithConstants(testConstants).
+		WithEventTypeEnabled(map[eval.EventType]bool{"*": true}).
+		WithVariableScopers(map[VariableScope]VariableScoper{
+			ProcessScope: func(ctx *eval.Context) string {
+				return (*testEvent)(ctx.Object).process.name
+			},
+		})
+
+	return NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, &opts)
+}
+
+func TestActionCheck(t *testing.T) {
+	tests := []struct {
+		action ActionDefinition
+		err    string
+	}{
+		{action: ActionDefinition{}, err: "no action defined"},
+		{action: ActionDefinition{Kill: &KillDefinition{}, Tag: map[string]string{"a": "b"}}, err: "only one action kind"},
+		{action: ActionDefinition{Set: &SetDefinition{Name: "1abc", Value: 1}}, err: "invalid variable name"},
+		{action: ActionDefinition{Set: &SetDefinition{Name: "abc", Value: 1, Scope: "host"}}, err: "invalid scope"},
+		{action: ActionDefinition{Set: &SetDefinition{Name: "abc", Value: 1.5}}, err: "unsupported value type"},
+		{action: ActionDefinition{Kill: &KillDefinition{Signal: "TERM"}}, err: "invalid signal"},
+		{action: ActionDefinition{Kill: &KillDefinition{}}},
+		{action: ActionDefinition{Kill: &KillDefinition{Signal: "sigusr1"}}},
+		{action: ActionDefinition{Set: &SetDefinition{Name: "abc", Value: "value", Scope: ContainerScope}}},
+		{action: ActionDefinition{Tag: map[string]string{"severity": "high"}}},
+	}
+
+	for _, test := range tests {
+		err := test.action.Check()
+		if test.err == "" {
+			assert.NoError(t, err)
+		} else if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), test.err)
+		}
+	}
+
+	assert.Equal(t, "SIGKILL", (&KillDefinition{}).GetSignal())
+	assert.Equal(t, "SIGUSR1", (&KillDefinition{Signal: "sigusr1"}).GetSignal())
+}
+
+func TestSetActionScopedVariable(t *testing.T) {
+	rs := newActionsTestRuleSet()
+	recorder := &matchRecorder{}
+	rs.AddListener(recorder)
+
+	// the rule referencing the variable is defined before the one setting it
+	ruleDefs := []*RuleDefinition{
+		{
+			ID:         "shell_after_download",
+			Expression: `mkdir.filename == "/tmp/test" && ${process.downloaded} == 1`,
+		},
+		{
+			ID:         "download",
+			Expression: `open.filename == "/tmp/payload"`,
+			Actions: []ActionDefinition{
+				{Set: &SetDefinition{Name: "downloaded", Value: 1, Scope: ProcessScope}},
+			},
+		},
+	}
+	if err := rs.AddRules(ruleDefs); err != nil {
+		t.Fatal(err)
+	}
+
+	mkdir := &testEvent{kind: "mkdir", process: testProcess{name: "curl"}, mkdir: testMkdir{filename: "/tmp/test"}}
+	assert.False(t, rs.Evaluate(mkdir))
+
+	open := &testEvent{kind: "open", process: testProcess{name: "curl"}, open: testOpen{filename: "/tmp/payload"}}
+	assert.True(t, rs.Evaluate(open))
+	assert.True(t, rs.Evaluate(mkdir))
+
+	// the variable is bound to the process that triggered the 'set' action
+	other := &testEvent{kind: "mkdir", process: testProcess{name: "wget"}, mkdir: testMkdir{filename: "/tmp/test"}}
+	assert.False(t, rs.Evaluate(other))
+
+	assert.True(t, rs.HasVariableScope(ProcessScope, mkdir))
+	assert.False(t, rs.HasVariableScope(ProcessScope, other))
+
+	rs.ReleaseVariableScope(ProcessScope, mkdir)
+	assert.False(t, rs.HasVariableScope(ProcessScope, mkdir))
+	assert.False(t, rs.Evaluate(mkdir))
+
+	assert.Equal(t, []string{"download", "shell_after_download"}, recorder.matches)
+}
+
+func TestSetActionErrors(t *testing.T) {
+	rs := newActionsTestRuleSet()
+	rs.opts.WithVariables(map[string]eval.VariableValue{
+		"process.pid": {IntFnc: func(ctx *eval.Context) int { return 0 }},
+	})
+
+	_, err := rs.AddRule(&RuleDefinition{
+		ID:         "builtin_conflict",
+		Expression: `open.filename == "/tmp/payload"`,
+		Actions:    []ActionDefinition{{Set: &SetDefinition{Name: "pid", Value: 1, Scope: ProcessScope}}},
+	})
+	assert.Error(t, err)
+
+	_, err = rs.AddRule(&RuleDefinition{
+		ID:         "unsupported_scope",
+		Expression: `open.filename == "/tmp/payload"`,
+		Actions:    []ActionDefinition{{Set: &SetDefinition{Name: "id", Value: 1, Scope: ContainerScope}}},
+	})
+	assert.Error(t, err)
+
+	_, err = rs.AddRule(&RuleDefinition{
+		ID:         "int_var",
+		Expression: `open.filename == "/tmp/payload"`,
+		Actions:    []ActionDefinition{{Set: &SetDefinition{Name: "count", Value: 1}}},
+	})
+	assert.NoError(t, err)
+
+	_, err = rs.AddRule(&RuleDefinition{
+		ID:         "type_conflict",
+		Expression: `open.filename == "/tmp/other"`,
+		Actions:    []ActionDefinition{{Set: &SetDefinition{Name: "count", Value: "one"}}},
+	})
+	assert.Error(t, err)
+}
+
+func TestLoadPolicyActions(t *testing.T) {
+	policy, err := LoadPolicy(strings.NewReader(`
+rules:
+  - id: download
+    expression: open.filename == "/tmp/payload"
+    actions:
+      - set:
+          name: downloaded
+          value: 1
+          scope: process
+      - kill:
+          signal: SIGTERM
+      - tag:
+          severity: high
+`), "test.policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions := policy.Rules[0].Actions
+	if assert.Len(t, actions, 3) {
+		assert.Equal(t, &SetDefinition{Name: "downloaded", Value: 1, Scope: ProcessScope}, actions[0].Set)
+		assert.Equal(t, "SIGTERM", actions[1].Kill.GetSignal())
+		assert.Equal(t, map[string]string{"severity": "high"}, actions[2].Tag)
+	}
+}
@@ -15,6 +15,7 @@ type Opts struct {
 	SupportedDiscarders map[eval.Field]bool
 	ReservedRuleIDs     []RuleID
 	EventTypeEnabled    map[eval.EventType]bool
+	VariableScopers     map[VariableScope]VariableScoper
 	Logger              Logger
 }
 
@@ -30,6 +31,12 @@ func (o *Opts) WithVariables(variables map[string]eval.VariableValue) *Opts {
 	return o
 }
 
+// WithVariableScopers set the scopers used by the variables set by rule actions
+func (o *Opts) WithVariableScopers(scopers map[VariableScope]VariableScoper) *Opts {
+	o.VariableScopers = scopers
+	return o
+}
+
 // WithLegacyFields set legacy fields
 func (o *Opts) WithLegacyFields(fields map[eval.Field]eval.Field) *Opts {
 	o.Opts.WithLegacyFields(fields)
@@ -35,11 +35,12 @@ type RuleID = string
 
 // RuleDefinition holds the definition of a rule
 type RuleDefinition struct {
-	ID          RuleID            `yaml:"id"`
-	Version     string            `yaml:"version"`
-	Expression  string            `yaml:"expression"`
-	Description string            `yaml:"description"`
-	Tags        map[string]string `yaml:"tags"`
+	ID          RuleID             `yaml:"id"`
+	Version     string             `yaml:"version"`
+	Expression  string             `yaml:"expression"`
+	Description string             `yaml:"description"`
+	Tags        map[string]string  `yaml:"tags"`
+	Actions     []ActionDefinition `yaml:"actions"`
 	Policy      *Policy
 }
 
@@ -79,9 +80,10 @@ type RuleSet struct {
 	eventCtor        func() eval.Event
 	listeners        []RuleSetListener
 	// fields holds the list of event field queries (like "process.uid") used by the entire set of rules
-	fields []string
-	logger Logger
-	pool   *eval.ContextPool
+	fields    []string
+	logger    Logger
+	pool      *eval.ContextPool
+	variables *VariableStore
 }
 
 // ListRuleIDs returns the list of RuleIDs from the ruleset
@@ -152,6 +154,13 @@ func (rs *RuleSet) AddMacro(macroDef *MacroDefinition) (*eval.Macro, error) {
 func (rs *RuleSet) AddRules(rules []*RuleDefinition) *multierror.Error {
 	var result *multierror.Error
 
+	// declare the variables set by rule actions first so that rules can
+	// reference variables set by rules defined after them. Errors are
+	// reported when the rules are added.
+	for _, ruleDef := range rules {
+		_ = rs.registerVariables(ruleDef)
+	}
+
 	for _, ruleDef := range rules {
 		if _, err := rs.AddRule(ruleDef); err != nil {
 			result = multierror.Append(result, err)
@@ -196,6 +205,10 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 		return nil, &ErrRuleLoad{Definition: ruleDef, Err: ErrDefinitionIDConflict}
 	}
 
+	if err := rs.registerVariables(ruleDef); err != nil {
+		return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+	}
+
 	var tags []string
 	for k, v := range ruleDef.Tags {
 		tags = append(tags, k+":"+v)
@@ -250,6 +263,75 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 	return rule.Rule, nil
 }
 
+// registerVariables checks the actions of a rule and declares the variables they set
+func (rs *RuleSet) registerVariables(ruleDef *RuleDefinition) error {
+	for i, action := range ruleDef.Actions {
+		if err := action.Check(); err != nil {
+			return errors.Wrapf(err, "invalid action #%d", i)
+		}
+
+		if action.Set == nil {
+			continue
+		}
+
+		name := action.Set.VariableName()
+		if _, declared := rs.variables.definitions[name]; !declared {
+			if _, exists := rs.opts.Variables[name]; exists {
+				return fmt.Errorf("variable `%s` conflicts with a builtin variable", name)
+			}
+		}
+
+		value, err := rs.variables.register(action.Set)
+		if err != nil {
+			return err
+		}
+
+		// copy the variables as they may be shared with other rule sets
+		variables := make(map[string]eval.VariableValue, len(rs.opts.Variables)+1)
+		for k, v := range rs.opts.Variables {
+			variables[k] = v
+		}
+		variables[name] = value
+		rs.opts.Variables = variables
+	}
+
+	return nil
+}
+
+// runSetActions assigns the variables of the 'set' actions of a rule that matched
+func (rs *RuleSet) runSetActions(ctx *eval.Context, rule *Rule) {
+	if rule.Definition == nil {
+		return
+	}
+
+	for _, action := range rule.Definition.Actions {
+		if action.Set == nil {
+			continue
+		}
+
+		if err := rs.variables.Set(ctx, action.Set); err != nil {
+			rs.logger.Debugf("rule `%s` couldn't set variable `%s`: %s", rule.ID, action.Set.VariableName(), err)
+		}
+	}
+}
+
+// HasVariableScope returns whether variables are bound to the scope instance of the given event
+func (rs *RuleSet) HasVariableScope(scope VariableScope, event eval.Event) bool {
+	ctx := rs.pool.Get(event.GetPointer())
+	defer rs.pool.Put(ctx)
+
+	return rs.variables.Has(scope, ctx)
+}
+
+// ReleaseVariableScope drops the variables bound to the scope instance of the given event,
+// for instance when the process or the container of the event exits
+func (rs *RuleSet) ReleaseVariableScope(scope VariableScope, event eval.Event) {
+	ctx := rs.pool.Get(event.GetPointer())
+	defer rs.pool.Put(ctx)
+
+	rs.variables.Release(scope, ctx)
+}
+
 // NotifyRuleMatch notifies all the ruleset listeners that an event matched a rule
 func (rs *RuleSet) NotifyRuleMatch(rule *Rule, event eval.Event) {
 	for _, listener := range rs.listeners {
@@ -371,6 +453,7 @@ func (rs *RuleSet) Evaluate(event eval.Event) bool {
 		if rule.GetEvaluator().Eval(ctx) {
 			rs.logger.Tracef("Rule `%s` matches with event `%s`\n", rule.ID, event)
 
+			rs.runSetActions(ctx, rule)
 			rs.NotifyRuleMatch(rule, event)
 			result = true
 		}
@@ -465,5 +548,6 @@ func NewRuleSet(model eval.Model, eventCtor func() eval.Event, opts *Opts) *Rule
 		loadedPolicies:   make(map[string]string),
 		logger:           logger,
 		pool:             eval.NewContextPool(),
+		variables:        NewVariableStore(opts.VariableScopers),
 	}
 }
@@ -0,0 +1,464 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package tester
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+)
+
+// The structures below mirror the JSON schema of the events serialized by the
+// runtime security probe (see docs/cloud-workload-security/backend.schema.json).
+// Only the parts that can be mapped to SECL fields are decoded.
+
+type fileJSON struct {
+	Path           string   `json:"path"`
+	Name           string   `json:"name"`
+	Inode          *uint64  `json:"inode"`
+	Mode           *uint32  `json:"mode"`
+	InUpperLayer   *bool    `json:"in_upper_layer"`
+	MountID        *uint32  `json:"mount_id"`
+	Filesystem     string   `json:"filesystem"`
+	UID            *uint32  `json:"uid"`
+	GID            *uint32  `json:"gid"`
+	User           string   `json:"user"`
+	Group          string   `json:"group"`
+	XAttrName      string   `json:"attribute_name"`
+	XAttrNamespace string   `json:"attribute_namespace"`
+	Flags          []string `json:"flags"`
+}
+
+type fileEventJSON struct {
+	fileJSON
+	Destination *fileJSON `json:"destination"`
+}
+
+type credentialsJSON struct {
+	UID          uint32                 `json:"uid"`
+	User         string                 `json:"user"`
+	GID          uint32                 `json:"gid"`
+	Group        string                 `json:"group"`
+	EUID         uint32                 `json:"euid"`
+	EUser        string                 `json:"euser"`
+	EGID         uint32                 `json:"egid"`
+	EGroup       string                 `json:"egroup"`
+	FSUID        uint32                 `json:"fsuid"`
+	FSUser       string                 `json:"fsuser"`
+	FSGID        uint32                 `json:"fsgid"`
+	FSGroup      string                 `json:"fsgroup"`
+	CapEffective []string               `json:"cap_effective"`
+	CapPermitted []string               `json:"cap_permitted"`
+	Destination  map[string]interface{} `json:"destination"`
+}
+
+type processJSON struct {
+	Pid         uint32           `json:"pid"`
+	PPid        uint32           `json:"ppid"`
+	Tid         uint32           `json:"tid"`
+	UID         uint32           `json:"uid"`
+	GID         uint32           `json:"gid"`
+	User        string           `json:"user"`
+	Group       string           `json:"group"`
+	Comm        string           `json:"comm"`
+	TTY         string           `json:"tty"`
+	Credentials *credentialsJSON `json:"credentials"`
+	Executable  *fileJSON        `json:"executable"`
+	Container   *struct {
+		ID string `json:"id"`
+	} `json:"container"`
+	Argv0         string   `json:"argv0"`
+	Args          []string `json:"args"`
+	ArgsTruncated bool     `json:"args_truncated"`
+	Envs          []string `json:"envs"`
+	EnvsTruncated bool     `json:"envs_truncated"`
+}
+
+type processContextJSON struct {
+	processJSON
+	Parent    *processJSON   `json:"parent"`
+	Ancestors []*processJSON `json:"ancestors"`
+}
+
+type eventJSON struct {
+	Evt struct {
+		Name    string `json:"name"`
+		Outcome string `json:"outcome"`
+	} `json:"evt"`
+	File      *fileEventJSON      `json:"file"`
+	Process   *processContextJSON `json:"process"`
+	Container *struct {
+		ID string `json:"id"`
+	} `json:"container"`
+	BPF *struct {
+		Cmd string `json:"cmd"`
+	} `json:"bpf"`
+	Date time.Time `json:"date"`
+
+	// ExpectedRules is not part of the serialized schema. It can be added to
+	// handwritten events to list the rules that are expected to match.
+	ExpectedRules *[]string `json:"expected_rules"`
+}
+
+// TestEvent is an event decoded from its JSON representation
+type TestEvent struct {
+	*model.Event
+
+	// ExpectedRules lists the rules expected to match the event, nil if no expectation was given
+	ExpectedRules []string
+}
+
+// DecodeEvents reads events serialized as a JSON array, or as a stream of JSON
+// objects, and converts them to SECL events
+func DecodeEvents(r io.Reader) ([]*TestEvent, error) {
+	reader := bufio.NewReader(r)
+
+	var raws []json.RawMessage
+	first, err := peekNonSpace(reader)
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(reader)
+	if first == '[' {
+		if err := decoder.Decode(&raws); err != nil {
+			return nil, errors.Wrap(err, "failed to decode events")
+		}
+	} else {
+		for {
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode event %d", len(raws))
+			}
+			raws = append(raws, raw)
+		}
+	}
+
+	events := make([]*TestEvent, 0, len(raws))
+	for i, raw := range raws {
+		event, err := DecodeEvent(raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode event %d", i)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+		if _, err := reader.ReadByte(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// DecodeEvent converts a serialized event to a SECL event
+func DecodeEvent(data []byte) (*TestEvent, error) {
+	var ej eventJSON
+	if err := json.Unmarshal(data, &ej); err != nil {
+		return nil, err
+	}
+
+	eventType := model.ParseEvalEventType(ej.Evt.Name)
+	if eventType == model.UnknownEventType {
+		return nil, fmt.Errorf("unknown event type `%s`", ej.Evt.Name)
+	}
+
+	ev := &model.Event{
+		Type:      uint64(eventType),
+		Timestamp: ej.Date,
+	}
+	name := eventType.String()
+
+	if ej.Process != nil {
+		decodeProcessContext(ej.Process, &ev.ProcessContext)
+		if eventType == model.ExecEventType {
+			ev.Exec.Process = ev.ProcessContext.Process
+		}
+
+		if creds := ej.Process.Credentials; creds != nil {
+			for key, value := range creds.Destination {
+				if err := setField(ev, name+"."+key, value); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if ej.Container != nil {
+		ev.ContainerContext.ID = ej.Container.ID
+	}
+
+	if ej.File != nil && eventType != model.ExecEventType {
+		if err := decodeFile(ev, name+".file", &ej.File.fileJSON); err != nil {
+			return nil, err
+		}
+		if ej.File.Destination != nil {
+			if err := decodeFile(ev, name+".file.destination", ej.File.Destination); err != nil {
+				return nil, err
+			}
+		}
+		if len(ej.File.Flags) != 0 {
+			if err := setField(ev, name+".flags", toInterfaces(ej.File.Flags)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if ej.BPF != nil {
+		if err := setField(ev, "bpf.cmd", ej.BPF.Cmd); err != nil {
+			return nil, err
+		}
+	}
+
+	if retval, ok := outcomeToRetval(ej.Evt.Outcome); ok {
+		if err := setField(ev, name+".retval", retval); err != nil {
+			return nil, err
+		}
+	}
+
+	event := &TestEvent{Event: ev}
+	if ej.ExpectedRules != nil {
+		event.ExpectedRules = append([]string{}, *ej.ExpectedRules...)
+	}
+
+	return event, nil
+}
+
+func decodeFile(ev *model.Event, prefix string, f *fileJSON) error {
+	name := f.Name
+	if name == "" && f.Path != "" {
+		name = path.Base(f.Path)
+	}
+
+	values := map[string]interface{}{
+		"path":       f.Path,
+		"name":       name,
+		"filesystem": f.Filesystem,
+		"user":       f.User,
+		"group":      f.Group,
+	}
+	if f.Inode != nil {
+		values["inode"] = int(*f.Inode)
+	}
+	if f.Mode != nil {
+		values["mode"] = int(*f.Mode)
+	}
+	if f.InUpperLayer != nil {
+		values["in_upper_layer"] = *f.InUpperLayer
+	}
+	if f.MountID != nil {
+		values["mount_id"] = int(*f.MountID)
+	}
+	if f.UID != nil {
+		values["uid"] = int(*f.UID)
+	}
+	if f.GID != nil {
+		values["gid"] = int(*f.GID)
+	}
+	// extended attributes are reported on the destination of the event
+	if f.XAttrName != "" {
+		values["name"] = f.XAttrName
+	}
+	if f.XAttrNamespace != "" {
+		values["namespace"] = f.XAttrNamespace
+	}
+
+	for key, value := range values {
+		if str, ok := value.(string); ok && str == "" {
+			continue
+		}
+		if err := setField(ev, prefix+"."+key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeProcess(pj *processJSON, process *model.Process) {
+	process.Pid = pj.Pid
+	process.PPid = pj.PPid
+	process.Tid = pj.Tid
+	process.Comm = pj.Comm
+	process.TTYName = pj.TTY
+
+	process.UID, process.User = pj.UID, pj.User
+	process.GID, process.Group = pj.GID, pj.Group
+	if creds := pj.Credentials; creds != nil {
+		process.Credentials = model.Credentials{
+			UID:          creds.UID,
+			GID:          creds.GID,
+			User:         creds.User,
+			Group:        creds.Group,
+			EUID:         creds.EUID,
+			EGID:         creds.EGID,
+			EUser:        creds.EUser,
+			EGroup:       creds.EGroup,
+			FSUID:        creds.FSUID,
+			FSGID:        creds.FSGID,
+			FSUser:       creds.FSUser,
+			FSGroup:      creds.FSGroup,
+			CapEffective: uint64(constantsToInt(toInterfaces(creds.CapEffective))),
+			CapPermitted: uint64(constantsToInt(toInterfaces(creds.CapPermitted))),
+		}
+	}
+
+	if exe := pj.Executable; exe != nil {
+		process.PathnameStr = exe.Path
+		process.BasenameStr = exe.Name
+		if process.BasenameStr == "" && exe.Path != "" {
+			process.BasenameStr = path.Base(exe.Path)
+		}
+		process.Filesystem = exe.Filesystem
+		process.FileFields.User = exe.User
+		process.FileFields.Group = exe.Group
+		if exe.Inode != nil {
+			process.FileFields.Inode = *exe.Inode
+		}
+		if exe.Mode != nil {
+			process.FileFields.Mode = uint16(*exe.Mode)
+		}
+		if exe.InUpperLayer != nil {
+			process.FileFields.InUpperLayer = *exe.InUpperLayer
+		}
+		if exe.MountID != nil {
+			process.FileFields.MountID = *exe.MountID
+		}
+		if exe.UID != nil {
+			process.FileFields.UID = *exe.UID
+		}
+		if exe.GID != nil {
+			process.FileFields.GID = *exe.GID
+		}
+	}
+
+	if pj.Container != nil {
+		process.ContainerID = pj.Container.ID
+	}
+
+	process.Argv0 = pj.Argv0
+	process.Argv = pj.Args
+	process.Args = strings.Join(pj.Args, " ")
+	process.ArgsTruncated = pj.ArgsTruncated
+	process.Envs = pj.Envs
+	process.EnvsTruncated = pj.EnvsTruncated
+}
+
+func decodeProcessContext(pcj *processContextJSON, pc *model.ProcessContext) {
+	decodeProcess(&pcj.processJSON, &pc.Process)
+
+	ancestors := pcj.Ancestors
+	if len(ancestors) == 0 && pcj.Parent != nil {
+		ancestors = []*processJSON{pcj.Parent}
+	}
+
+	parent := pc
+	for _, aj := range ancestors {
+		ancestor := &model.ProcessCacheEntry{}
+		decodeProcess(aj, &ancestor.Process)
+		parent.Ancestor = ancestor
+		parent = &ancestor.ProcessContext
+	}
+}
+
+// setField sets the value of a SECL field, converting the JSON value to the
+// type of the field. Fields that don't exist for the event are ignored.
+func setField(ev *model.Event, field eval.Field, value interface{}) error {
+	kind, err := ev.GetFieldType(field)
+	if err != nil {
+		return nil
+	}
+
+	switch kind {
+	case reflect.Int:
+		switch v := value.(type) {
+		case int:
+		case float64:
+			value = int(v)
+		case string:
+			value = constantsToInt([]interface{}{v})
+		case []interface{}:
+			value = constantsToInt(v)
+		default:
+			return fmt.Errorf("invalid value `%v` for field `%s`", value, field)
+		}
+	case reflect.String:
+		if values, ok := value.([]interface{}); ok {
+			for _, v := range values {
+				if err := ev.SetFieldValue(field, v); err != nil {
+					return errors.Wrapf(err, "invalid value `%v` for field `%s`", v, field)
+				}
+			}
+			return nil
+		}
+	}
+
+	if err := ev.SetFieldValue(field, value); err != nil {
+		return errors.Wrapf(err, "invalid value `%v` for field `%s`", value, field)
+	}
+	return nil
+}
+
+// constantsToInt ORs the values of the given SECL constants
+func constantsToInt(names []interface{}) int {
+	var result int
+	for _, name := range names {
+		str, ok := name.(string)
+		if !ok {
+			continue
+		}
+		if constant, ok := model.SECLConstants[str].(*eval.IntEvaluator); ok {
+			result |= constant.Value
+		}
+	}
+	return result
+}
+
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+// outcomeToRetval converts the outcome of a serialized event to a syscall return value
+func outcomeToRetval(outcome string) (int, bool) {
+	switch outcome {
+	case "Success":
+		return 0, true
+	case "Refused":
+		return constantsToInt([]interface{}{"EACCES"}), true
+	case "Error":
+		return constantsToInt([]interface{}{"EINVAL"}), true
+	}
+	return 0, false
+}
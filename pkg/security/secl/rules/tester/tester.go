@@ -0,0 +1,178 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package tester evaluates policies against recorded or handwritten events,
+// without requiring the runtime security probe to be loaded.
+package tester
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+// RuleMatchReport describes a rule that matched an event
+type RuleMatchReport struct {
+	ID     rules.RuleID    `json:"id"`
+	Macros []rules.MacroID `json:"macros,omitempty"`
+}
+
+// DiscarderReport describes a discarder that would have been generated for an event
+type DiscarderReport struct {
+	Field eval.Field  `json:"field"`
+	Value interface{} `json:"value"`
+}
+
+// EventReport holds the result of the evaluation of an event
+type EventReport struct {
+	Index         int               `json:"index"`
+	EventType     eval.EventType    `json:"event_type"`
+	MatchedRules  []RuleMatchReport `json:"matched_rules"`
+	Discarders    []DiscarderReport `json:"discarders,omitempty"`
+	ExpectedRules []rules.RuleID    `json:"expected_rules,omitempty"`
+	Passed        bool              `json:"passed"`
+}
+
+// Report holds the result of the evaluation of a set of events
+type Report struct {
+	Events []*EventReport `json:"events"`
+	Passed bool           `json:"passed"`
+}
+
+// Tester evaluates events against a rule set
+type Tester struct {
+	ruleSet *rules.RuleSet
+	current *EventReport
+}
+
+// NewTester returns a tester evaluating events against the policies of the given
+// directory. The options have to provide the constants, variables and legacy fields
+// of the SECL model, and the fields for which discarders are reported.
+func NewTester(policiesDir string, opts *rules.Opts) (*Tester, *multierror.Error) {
+	m := &model.Model{}
+
+	t := &Tester{
+		ruleSet: rules.NewRuleSet(m, m.NewEvent, opts),
+	}
+	t.ruleSet.AddListener(t)
+
+	if err := rules.LoadPolicies(policiesDir, t.ruleSet); err.ErrorOrNil() != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// RuleSet returns the rule set used by the tester
+func (t *Tester) RuleSet() *rules.RuleSet {
+	return t.ruleSet
+}
+
+// RuleMatch is called by the rule set when a rule matches
+func (t *Tester) RuleMatch(rule *rules.Rule, event eval.Event) {
+	if t.current == nil {
+		return
+	}
+
+	t.current.MatchedRules = append(t.current.MatchedRules, RuleMatchReport{
+		ID:     rule.ID,
+		Macros: rule.GetEvaluator().Macros,
+	})
+}
+
+// EventDiscarderFound is called by the rule set when a discarder is found
+func (t *Tester) EventDiscarderFound(rs *rules.RuleSet, event eval.Event, field eval.Field, eventType eval.EventType) {
+	if t.current == nil {
+		return
+	}
+
+	value, err := event.GetFieldValue(field)
+	if err != nil {
+		return
+	}
+
+	t.current.Discarders = append(t.current.Discarders, DiscarderReport{
+		Field: field,
+		Value: value,
+	})
+}
+
+// TestEvent evaluates a single event
+func (t *Tester) TestEvent(index int, event *TestEvent) *EventReport {
+	t.current = &EventReport{
+		Index:         index,
+		EventType:     event.GetType(),
+		MatchedRules:  []RuleMatchReport{},
+		ExpectedRules: event.ExpectedRules,
+	}
+	defer func() { t.current = nil }()
+
+	t.ruleSet.Evaluate(event.Event)
+
+	sort.Slice(t.current.Discarders, func(i, j int) bool {
+		return t.current.Discarders[i].Field < t.current.Discarders[j].Field
+	})
+
+	t.current.Passed = event.ExpectedRules == nil || sameRules(t.current.MatchedRules, event.ExpectedRules)
+
+	return t.current
+}
+
+// TestEvents evaluates the given events
+func (t *Tester) TestEvents(events []*TestEvent) *Report {
+	report := &Report{Passed: true}
+	for i, event := range events {
+		eventReport := t.TestEvent(i, event)
+		report.Events = append(report.Events, eventReport)
+		report.Passed = report.Passed && eventReport.Passed
+	}
+	return report
+}
+
+// TestReader decodes the events of the given reader and evaluates them
+func (t *Tester) TestReader(r io.Reader) (*Report, error) {
+	events, err := DecodeEvents(r)
+	if err != nil {
+		return nil, err
+	}
+	return t.TestEvents(events), nil
+}
+
+func sameRules(matched []RuleMatchReport, expected []rules.RuleID) bool {
+	if len(matched) != len(expected) {
+		return false
+	}
+
+	ids := make(map[rules.RuleID]bool, len(matched))
+	for _, rule := range matched {
+		ids[rule.ID] = true
+	}
+	for _, id := range expected {
+		if !ids[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a summary of the evaluation of an event
+func (r *EventReport) String() string {
+	status := "PASS"
+	if !r.Passed {
+		status = "FAIL"
+	}
+
+	ids := make([]string, len(r.MatchedRules))
+	for i, rule := range r.MatchedRules {
+		ids[i] = rule.ID
+	}
+	return fmt.Sprintf("%s event %d (%s): matched %v, expected %v", status, r.Index, r.EventType, ids, r.ExpectedRules)
+}
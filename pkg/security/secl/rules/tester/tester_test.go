@@ -0,0 +1,111 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package tester
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+func newTestTester(t *testing.T) *Tester {
+	var opts rules.Opts
+	opts.
+		WithConstants(model.SECLConstants).
+		WithLegacyFields(model.SECLLegacyFields).
+		WithEventTypeEnabled(map[eval.EventType]bool{"*": true}).
+		WithSupportedDiscarders(map[eval.Field]bool{"open.file.path": true})
+
+	tester, err := NewTester("testdata/policies", &opts)
+	require.NoError(t, err.ErrorOrNil())
+
+	return tester
+}
+
+func TestTesterEvents(t *testing.T) {
+	tester := newTestTester(t)
+
+	f, err := os.Open("testdata/events.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	report, err := tester.TestReader(f)
+	require.NoError(t, err)
+	require.Len(t, report.Events, 4)
+
+	for _, event := range report.Events {
+		assert.True(t, event.Passed, event.String())
+	}
+	assert.True(t, report.Passed)
+
+	shadow := report.Events[0]
+	assert.Equal(t, "open", shadow.EventType)
+	for _, rule := range shadow.MatchedRules {
+		if rule.ID == "shadow_read_from_shell" {
+			assert.Equal(t, []rules.MacroID{"shell_parent", "shells"}, rule.Macros)
+		} else {
+			assert.Empty(t, rule.Macros)
+		}
+	}
+	assert.Empty(t, shadow.Discarders)
+
+	other := report.Events[1]
+	assert.Empty(t, other.MatchedRules)
+	assert.Equal(t, []DiscarderReport{{Field: "open.file.path", Value: "/tmp/test"}}, other.Discarders)
+}
+
+func TestTesterExpectations(t *testing.T) {
+	tester := newTestTester(t)
+
+	events := `{"evt": {"name": "open"}, "file": {"path": "/etc/shadow"}, "expected_rules": ["shadow_read"]}
+{"evt": {"name": "open"}, "file": {"path": "/etc/hosts"}}`
+
+	report, err := tester.TestReader(strings.NewReader(events))
+	require.NoError(t, err)
+	require.Len(t, report.Events, 2)
+
+	// shadow_read_from_shell doesn't match without a shell ancestor
+	assert.True(t, report.Events[0].Passed, report.Events[0].String())
+	// no expectation
+	assert.True(t, report.Events[1].Passed)
+
+	report, err = tester.TestReader(strings.NewReader(`{"evt": {"name": "open"}, "file": {"path": "/etc/hosts"}, "expected_rules": ["shadow_read"]}`))
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+}
+
+func TestDecodeEvent(t *testing.T) {
+	event, err := DecodeEvent([]byte(`{
+		"evt": {"name": "setuid", "outcome": "Success"},
+		"process": {
+			"pid": 12, "comm": "su",
+			"credentials": {"uid": 1000, "user": "user", "destination": {"uid": 0, "user": "root", "euid": 0}},
+			"container": {"id": "abc"}
+		}
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "setuid", event.GetType())
+	assert.Equal(t, uint32(12), event.ProcessContext.Pid)
+	assert.Equal(t, uint32(1000), event.ProcessContext.UID)
+	assert.Equal(t, "abc", event.ProcessContext.ContainerID)
+	assert.Equal(t, uint32(0), event.SetUID.UID)
+	assert.Equal(t, "root", event.SetUID.User)
+	assert.Nil(t, event.ExpectedRules)
+
+	_, err = DecodeEvent([]byte(`{"evt": {"name": "unknown"}}`))
+	assert.Error(t, err)
+
+	_, err = DecodeEvent([]byte(`{"evt": {"name": "open"}, "file": {"inode": "abc"}}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,164 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+// maxScopeInstances limits the number of scope instances (processes, containers)
+// for which variables can be stored at the same time
+const maxScopeInstances = 16384
+
+// VariableScoper returns the key of the scope instance (a process, a container)
+// the event of the given context belongs to. An empty key means that the event
+// doesn't belong to any instance of the scope.
+type VariableScoper func(ctx *eval.Context) string
+
+// VariableStore holds the values of the variables set by the rule actions
+type VariableStore struct {
+	sync.RWMutex
+	scopers map[VariableScope]VariableScoper
+	// values are indexed by scope, then scope instance key, then variable name
+	values map[VariableScope]map[string]map[string]interface{}
+	// definitions holds the first definition seen for each variable
+	definitions map[string]*SetDefinition
+}
+
+// NewVariableStore returns a new variable store using the given scopers
+func NewVariableStore(scopers map[VariableScope]VariableScoper) *VariableStore {
+	return &VariableStore{
+		scopers:     scopers,
+		values:      make(map[VariableScope]map[string]map[string]interface{}),
+		definitions: make(map[string]*SetDefinition),
+	}
+}
+
+func (s *VariableStore) scopeKey(scope VariableScope, ctx *eval.Context) (string, bool) {
+	if scope == GlobalScope {
+		return "", true
+	}
+
+	scoper, exists := s.scopers[scope]
+	if !exists {
+		return "", false
+	}
+
+	key := scoper(ctx)
+	return key, key != ""
+}
+
+func (s *VariableStore) get(scope VariableScope, ctx *eval.Context, name string) (interface{}, bool) {
+	key, ok := s.scopeKey(scope, ctx)
+	if !ok {
+		return nil, false
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	value, exists := s.values[scope][key][name]
+	return value, exists
+}
+
+// Set assigns the value of the given definition in the scope of the event of the given context
+func (s *VariableStore) Set(ctx *eval.Context, def *SetDefinition) error {
+	key, ok := s.scopeKey(def.Scope, ctx)
+	if !ok {
+		return fmt.Errorf("event doesn't belong to any %s scope", def.Scope)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	instances, exists := s.values[def.Scope]
+	if !exists {
+		instances = make(map[string]map[string]interface{})
+		s.values[def.Scope] = instances
+	}
+
+	values, exists := instances[key]
+	if !exists {
+		if len(instances) >= maxScopeInstances {
+			return fmt.Errorf("too many %s scope instances", def.Scope)
+		}
+		values = make(map[string]interface{})
+		instances[key] = values
+	}
+	values[def.Name] = def.Value
+
+	return nil
+}
+
+// Has returns whether variables are bound to the scope instance of the event of the given context
+func (s *VariableStore) Has(scope VariableScope, ctx *eval.Context) bool {
+	key, ok := s.scopeKey(scope, ctx)
+	if !ok {
+		return false
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	_, exists := s.values[scope][key]
+	return exists
+}
+
+// Release drops all the variables of the scope instance of the event of the given context
+func (s *VariableStore) Release(scope VariableScope, ctx *eval.Context) {
+	key, ok := s.scopeKey(scope, ctx)
+	if !ok || scope == GlobalScope {
+		return
+	}
+
+	s.Lock()
+	delete(s.values[scope], key)
+	s.Unlock()
+}
+
+// register declares the variable of a 'set' definition and returns the value
+// used by the evaluators to read it
+func (s *VariableStore) register(def *SetDefinition) (eval.VariableValue, error) {
+	if def.Scope != GlobalScope {
+		if _, exists := s.scopers[def.Scope]; !exists {
+			return eval.VariableValue{}, fmt.Errorf("scope `%s` not supported for variable `%s`", def.Scope, def.Name)
+		}
+	}
+
+	name := def.VariableName()
+	if existing, exists := s.definitions[name]; exists {
+		if fmt.Sprintf("%T", existing.Value) != fmt.Sprintf("%T", def.Value) {
+			return eval.VariableValue{}, fmt.Errorf("variable `%s` already defined with a different type", name)
+		}
+	} else {
+		s.definitions[name] = def
+	}
+
+	scope, varName := def.Scope, def.Name
+	switch def.Value.(type) {
+	case int:
+		return eval.VariableValue{
+			IntFnc: func(ctx *eval.Context) int {
+				value, _ := s.get(scope, ctx, varName)
+				i, _ := value.(int)
+				return i
+			},
+		}, nil
+	case string:
+		return eval.VariableValue{
+			StringFnc: func(ctx *eval.Context) string {
+				value, _ := s.get(scope, ctx, varName)
+				str, _ := value.(string)
+				return str
+			},
+		}, nil
+	}
+
+	return eval.VariableValue{}, fmt.Errorf("unsupported value type %T for variable `%s`", def.Value, def.Name)
+}
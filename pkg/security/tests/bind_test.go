@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build functionaltests
+// +build functionaltests
+
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+
+	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+func TestBindEvent(t *testing.T) {
+	ruleDefs := []*rules.RuleDefinition{
+		{
+			ID:         "test_bind_af_inet",
+			Expression: `bind.addr.family == AF_INET && bind.addr.port == 4242 && bind.protocol == IPPROTO_TCP`,
+		},
+	}
+
+	test, err := newTestModule(t, nil, ruleDefs, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	t.Run("bind-af-inet", func(t *testing.T) {
+		test.WaitSignal(t, func() error {
+			fd, err := unix.Socket(unix.AF_INET, unix.SOCK_STREAM, 0)
+			if err != nil {
+				return fmt.Errorf("couldn't create socket: %w", err)
+			}
+			defer unix.Close(fd)
+
+			if err = unix.Bind(fd, &unix.SockaddrInet4{Port: 4242, Addr: [4]byte{127, 0, 0, 1}}); err != nil {
+				return fmt.Errorf("couldn't bind socket: %w", err)
+			}
+			return nil
+		}, func(event *sprobe.Event, r *rules.Rule) {
+			assert.Equal(t, "bind", event.GetType(), "wrong event type")
+			assert.Equal(t, model.AFInet, event.Bind.Addr.Family, "wrong address family")
+			assert.Equal(t, "127.0.0.1", event.Bind.Addr.IP, "wrong address")
+			assert.Equal(t, uint16(4242), event.Bind.Addr.Port, "wrong port")
+			assert.Equal(t, int64(0), event.Bind.Retval, "wrong retval")
+
+			if !validateBindSchema(t, event) {
+				t.Error(event.String())
+			}
+		})
+	})
+}
@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build functionaltests
+// +build functionaltests
+
+package tests
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+func TestConnectEvent(t *testing.T) {
+	ruleDefs := []*rules.RuleDefinition{
+		{
+			ID:         "test_connect_af_inet",
+			Expression: `connect.addr.family == AF_INET && connect.addr.port == 4343 && connect.protocol == IPPROTO_TCP`,
+		},
+	}
+
+	test, err := newTestModule(t, nil, ruleDefs, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	listener, err := net.Listen("tcp4", "127.0.0.1:4343")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	t.Run("connect-af-inet", func(t *testing.T) {
+		test.WaitSignal(t, func() error {
+			conn, err := net.Dial("tcp4", "127.0.0.1:4343")
+			if err != nil {
+				return fmt.Errorf("couldn't connect: %w", err)
+			}
+			return conn.Close()
+		}, func(event *sprobe.Event, r *rules.Rule) {
+			assert.Equal(t, "connect", event.GetType(), "wrong event type")
+			assert.Equal(t, model.AFInet, event.Connect.Addr.Family, "wrong address family")
+			assert.Equal(t, "127.0.0.1", event.Connect.Addr.IP, "wrong address")
+			assert.Equal(t, uint16(4343), event.Connect.Addr.Port, "wrong port")
+
+			if !validateConnectSchema(t, event) {
+				t.Error(event.String())
+			}
+		})
+	})
+}
@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build functionaltests
+// +build functionaltests
+
+package tests
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+// dnsQuery is a request for the A record of testsuite.datadog.test
+var dnsQuery = []byte{
+	0x12, 0x34, 0x01, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x09, 't', 'e', 's', 't', 's', 'u', 'i', 't', 'e',
+	0x07, 'd', 'a', 't', 'a', 'd', 'o', 'g',
+	0x04, 't', 'e', 's', 't',
+	0x00,
+	0x00, 0x01, 0x00, 0x01,
+}
+
+func TestDNSEvent(t *testing.T) {
+	ruleDefs := []*rules.RuleDefinition{
+		{
+			ID:         "test_dns_request",
+			Expression: `dns.question.name == "testsuite.datadog.test" && dns.question.type == A && dns.question.class == CLASS_INET`,
+		},
+	}
+
+	test, err := newTestModule(t, nil, ruleDefs, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	t.Run("dns-request", func(t *testing.T) {
+		test.WaitSignal(t, func() error {
+			conn, err := net.Dial("udp", "127.0.0.1:53")
+			if err != nil {
+				return fmt.Errorf("couldn't create socket: %w", err)
+			}
+			defer conn.Close()
+
+			if _, err = conn.Write(dnsQuery); err != nil {
+				return fmt.Errorf("couldn't send dns request: %w", err)
+			}
+			return nil
+		}, func(event *sprobe.Event, r *rules.Rule) {
+			assert.Equal(t, "dns", event.GetType(), "wrong event type")
+			assert.Equal(t, uint16(0x1234), event.DNS.ID, "wrong request id")
+			assert.Equal(t, uint16(1), event.DNS.Count, "wrong question count")
+			assert.Equal(t, uint16(len(dnsQuery)), event.DNS.Size, "wrong request size")
+
+			if !validateDNSSchema(t, event) {
+				t.Error(event.String())
+			}
+		})
+	})
+}
@@ -116,3 +116,15 @@ func validateMProtectSchema(t *testing.T, event *sprobe.Event) bool {
 func validatePTraceSchema(t *testing.T, event *sprobe.Event) bool {
 	return validateSchema(t, event, "file:///schemas/ptrace.schema.json")
 }
+
+func validateBindSchema(t *testing.T, event *sprobe.Event) bool {
+	return validateSchema(t, event, "file:///schemas/bind.schema.json")
+}
+
+func validateConnectSchema(t *testing.T, event *sprobe.Event) bool {
+	return validateSchema(t, event, "file:///schemas/connect.schema.json")
+}
+
+func validateDNSSchema(t *testing.T, event *sprobe.Event) bool {
+	return validateSchema(t, event, "file:///schemas/dns.schema.json")
+}
@@ -26,3 +26,39 @@ func validateCredentials(p *gosnmp.SnmpPacket, c *Config) error {
 
 	return errors.New("Unknown community string")
 }
+
+// validateUserCredentials checks that a decoded SNMPv3 packet was sent by the given user,
+// with the security level and the engine ID expected for this user.
+// Authentication and decryption are performed by GoSNMP when the packet is decoded.
+func validateUserCredentials(p *gosnmp.SnmpPacket, user *UserV3) error {
+	if p.Version != gosnmp.Version3 || p.SecurityModel != gosnmp.UserSecurityModel {
+		return fmt.Errorf("Unsupported version: %s", p.Version)
+	}
+
+	params, ok := p.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	if !ok {
+		return errors.New("Unsupported security parameters")
+	}
+
+	if params.UserName != user.Username {
+		return errors.New("Unknown user")
+	}
+
+	expected, err := user.BuildV3Params(0)
+	if err != nil {
+		return err
+	}
+	if p.MsgFlags&gosnmp.AuthPriv != expected.MsgFlags {
+		return fmt.Errorf("Unexpected security level for user %s", user.Username)
+	}
+
+	engineID, err := user.authoritativeEngineID()
+	if err != nil {
+		return err
+	}
+	if engineID != "" && params.AuthoritativeEngineID != engineID {
+		return fmt.Errorf("Unknown authoritative engine ID for user %s", user.Username)
+	}
+
+	return nil
+}
@@ -6,9 +6,11 @@
 package traps
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/gosnmplib"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/gosnmp/gosnmp"
 )
@@ -18,11 +20,26 @@ func IsEnabled() bool {
 	return config.Datadog.GetBool("snmp_traps_enabled")
 }
 
+// UserV3 contains the definition of one SNMPv3 user with its authentication
+// (auth) and privacy (priv) credentials.
+// YAML field tags provided for test marshalling purposes.
+type UserV3 struct {
+	Username     string `mapstructure:"user" yaml:"user"`
+	AuthKey      string `mapstructure:"authKey" yaml:"authKey,omitempty"`
+	AuthProtocol string `mapstructure:"authProtocol" yaml:"authProtocol,omitempty"`
+	PrivKey      string `mapstructure:"privKey" yaml:"privKey,omitempty"`
+	PrivProtocol string `mapstructure:"privProtocol" yaml:"privProtocol,omitempty"`
+	// EngineID is the hex encoded authoritative engine ID of the devices
+	// sending traps as this user. Any engine ID is accepted when empty.
+	EngineID string `mapstructure:"engineID" yaml:"engineID,omitempty"`
+}
+
 // Config contains configuration for SNMP trap listeners.
 // YAML field tags provided for test marshalling purposes.
 type Config struct {
 	Port             uint16   `mapstructure:"port" yaml:"port"`
 	CommunityStrings []string `mapstructure:"community_strings" yaml:"community_strings"`
+	Users            []UserV3 `mapstructure:"users" yaml:"users"`
 	BindHost         string   `mapstructure:"bind_host" yaml:"bind_host"`
 	StopTimeout      int      `mapstructure:"stop_timeout" yaml:"stop_timeout"`
 }
@@ -36,8 +53,14 @@ func ReadConfig() (*Config, error) {
 	}
 
 	// Validate required fields.
-	if c.CommunityStrings == nil || len(c.CommunityStrings) == 0 {
-		return nil, errors.New("`community_strings` is required and must be non-empty")
+	if len(c.CommunityStrings) == 0 && len(c.Users) == 0 {
+		return nil, errors.New("`community_strings` or `users` is required and must be non-empty")
+	}
+
+	for _, user := range c.Users {
+		if _, err := user.BuildV3Params(c.Port); err != nil {
+			return nil, fmt.Errorf("invalid SNMPv3 user `%s`: %w", user.Username, err)
+		}
 	}
 
 	// Set defaults.
@@ -69,3 +92,70 @@ func (c *Config) BuildV2Params() *gosnmp.GoSNMP {
 		Logger:    gosnmp.NewLogger(&trapLogger{}),
 	}
 }
+
+// BuildV3Params returns a valid GoSNMP SNMPv3 params structure for the given user.
+func (u *UserV3) BuildV3Params(port uint16) (*gosnmp.GoSNMP, error) {
+	if u.Username == "" {
+		return nil, errors.New("`user` is required")
+	}
+
+	authProtocol, err := gosnmplib.GetAuthProtocol(u.AuthProtocol)
+	if err != nil {
+		return nil, err
+	}
+
+	privProtocol, err := gosnmplib.GetPrivProtocol(u.PrivProtocol)
+	if err != nil {
+		return nil, err
+	}
+
+	if authProtocol != gosnmp.NoAuth && u.AuthKey == "" {
+		return nil, errors.New("`authKey` is required when `authProtocol` is set")
+	}
+	if privProtocol != gosnmp.NoPriv && u.PrivKey == "" {
+		return nil, errors.New("`privKey` is required when `privProtocol` is set")
+	}
+
+	if _, err := u.authoritativeEngineID(); err != nil {
+		return nil, err
+	}
+
+	msgFlags := gosnmp.NoAuthNoPriv
+	if privProtocol != gosnmp.NoPriv {
+		// Auth is needed if privacy is used.
+		// "The User-based Security Model also prescribes that a message needs to be authenticated if privacy is in use."
+		// https://tools.ietf.org/html/rfc3414#section-1.4.3
+		if authProtocol == gosnmp.NoAuth {
+			return nil, errors.New("`authProtocol` is required when `privProtocol` is set")
+		}
+		msgFlags = gosnmp.AuthPriv
+	} else if authProtocol != gosnmp.NoAuth {
+		msgFlags = gosnmp.AuthNoPriv
+	}
+
+	return &gosnmp.GoSNMP{
+		Port:          port,
+		Transport:     "udp",
+		Version:       gosnmp.Version3,
+		MsgFlags:      msgFlags,
+		SecurityModel: gosnmp.UserSecurityModel,
+		SecurityParameters: &gosnmp.UsmSecurityParameters{
+			UserName:                 u.Username,
+			AuthenticationProtocol:   authProtocol,
+			AuthenticationPassphrase: u.AuthKey,
+			PrivacyProtocol:          privProtocol,
+			PrivacyPassphrase:        u.PrivKey,
+			Logger:                   gosnmp.NewLogger(&trapLogger{}),
+		},
+		Logger: gosnmp.NewLogger(&trapLogger{}),
+	}, nil
+}
+
+// authoritativeEngineID returns the decoded engine ID of the user, if any.
+func (u *UserV3) authoritativeEngineID() (string, error) {
+	engineID, err := hex.DecodeString(u.EngineID)
+	if err != nil {
+		return "", fmt.Errorf("`engineID` must be an hexadecimal string: %w", err)
+	}
+	return string(engineID), nil
+}
@@ -71,3 +71,57 @@ func TestStopTimeout(t *testing.T) {
 
 	assert.Equal(t, 11, config.StopTimeout)
 }
+
+func TestUsersOnly(t *testing.T) {
+	Configure(t, Config{
+		Users: []UserV3{{Username: "user", AuthKey: "password", AuthProtocol: "sha"}},
+	})
+	config, err := ReadConfig()
+	assert.NoError(t, err)
+	assert.Len(t, config.Users, 1)
+
+	params, err := config.Users[0].BuildV3Params(config.Port)
+	assert.NoError(t, err)
+	assert.Equal(t, gosnmp.Version3, params.Version)
+	assert.Equal(t, gosnmp.AuthNoPriv, params.MsgFlags)
+	assert.Equal(t, gosnmp.UserSecurityModel, params.SecurityModel)
+
+	securityParams := params.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	assert.Equal(t, "user", securityParams.UserName)
+	assert.Equal(t, gosnmp.SHA, securityParams.AuthenticationProtocol)
+	assert.Equal(t, gosnmp.NoPriv, securityParams.PrivacyProtocol)
+}
+
+func TestUsersSecurityLevel(t *testing.T) {
+	tests := []struct {
+		user     UserV3
+		msgFlags gosnmp.SnmpV3MsgFlags
+	}{
+		{UserV3{Username: "user"}, gosnmp.NoAuthNoPriv},
+		{UserV3{Username: "user", AuthKey: "password", AuthProtocol: "md5"}, gosnmp.AuthNoPriv},
+		{UserV3{Username: "user", AuthKey: "password", AuthProtocol: "sha", PrivKey: "private", PrivProtocol: "aes256c"}, gosnmp.AuthPriv},
+	}
+
+	for _, test := range tests {
+		params, err := test.user.BuildV3Params(defaultPort)
+		assert.NoError(t, err)
+		assert.Equal(t, test.msgFlags, params.MsgFlags)
+	}
+}
+
+func TestInvalidUsers(t *testing.T) {
+	users := []UserV3{
+		{AuthKey: "password", AuthProtocol: "sha"},
+		{Username: "user", AuthKey: "password", AuthProtocol: "unknown"},
+		{Username: "user", AuthProtocol: "sha"},
+		{Username: "user", PrivKey: "private", PrivProtocol: "aes"},
+		{Username: "user", AuthKey: "password", AuthProtocol: "sha", PrivProtocol: "aes"},
+		{Username: "user", EngineID: "not-hex"},
+	}
+
+	for _, user := range users {
+		Configure(t, Config{Users: []UserV3{user}})
+		_, err := ReadConfig()
+		assert.Error(t, err)
+	}
+}
@@ -7,8 +7,10 @@ package traps
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/gosnmp/gosnmp"
 )
 
@@ -18,8 +20,19 @@ const (
 )
 
 // FormatPacketToJSON converts an SNMP trap packet to a JSON-serializable object.
-func FormatPacketToJSON(packet *SnmpPacket) (map[string]interface{}, error) {
-	return formatTrapPDUs(packet.Content.Variables)
+// When a resolver is given, the trap and variable OIDs are resolved to their symbolic names,
+// and enum and bits values are decoded. Raw OIDs and values are always included.
+func FormatPacketToJSON(packet *SnmpPacket, resolver OIDResolver) (map[string]interface{}, error) {
+	data, err := formatTrapPDUs(packet.Content.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolver != nil {
+		enrichTrapData(data, packet.Content.Variables[2:], resolver)
+	}
+
+	return data, nil
 }
 
 // GetTags returns a list of tags associated to an SNMP trap packet.
@@ -34,6 +47,8 @@ func formatVersion(packet *SnmpPacket) string {
 	switch packet.Content.Version {
 	case gosnmp.Version2c:
 		return "2"
+	case gosnmp.Version3:
+		return "3"
 	default:
 		return "unknown"
 	}
@@ -148,3 +163,73 @@ func formatValue(variable gosnmp.SnmpPDU) interface{} {
 		return variable.Value
 	}
 }
+
+// enrichTrapData adds the symbolic names of the trap and of its variables to the formatted data.
+// Resolved variables are also added at the top level, keyed by their name.
+func enrichTrapData(data map[string]interface{}, variables []gosnmp.SnmpPDU, resolver OIDResolver) {
+	trapOID, _ := data["oid"].(string)
+	if trap, err := resolver.GetTrapMetadata(trapOID); err == nil {
+		data["name"] = trap.Name
+		data["mib"] = trap.MIBName
+	} else {
+		log.Debugf("Unable to resolve trap OID: %v", err)
+	}
+
+	parsedVariables, _ := data["variables"].([]map[string]interface{})
+	for i, variable := range variables {
+		metadata, err := resolver.GetVariableMetadata(variable.Name)
+		if err != nil {
+			log.Debugf("Unable to resolve variable OID: %v", err)
+			continue
+		}
+
+		parsedVariables[i]["name"] = metadata.Name
+		value := resolveValue(variable, metadata)
+		if _, found := data[metadata.Name]; !found {
+			data[metadata.Name] = value
+		}
+	}
+}
+
+// resolveValue decodes the value of a variable using the enumeration or the bits
+// definition of its metadata. The formatted raw value is returned if it can't be decoded.
+func resolveValue(variable gosnmp.SnmpPDU, metadata VariableMetadata) interface{} {
+	if len(metadata.Enumeration) != 0 {
+		if value, ok := variable.Value.(int); ok {
+			if name, found := metadata.Enumeration[value]; found {
+				return name
+			}
+		}
+		log.Debugf("Unable to resolve enum value %v of variable %s", variable.Value, metadata.Name)
+	}
+
+	if len(metadata.Bits) != 0 {
+		if value, ok := variable.Value.([]byte); ok {
+			return resolveBits(value, metadata.Bits)
+		}
+		log.Debugf("Unable to resolve bits value %v of variable %s", variable.Value, metadata.Name)
+	}
+
+	return formatValue(variable)
+}
+
+// resolveBits returns the names of the bits set in a BITS value. Bit 0 is the most
+// significant bit of the first octet. Unknown bits are returned as their position.
+// See: https://tools.ietf.org/html/rfc2578#section-7.1.4
+func resolveBits(value []byte, bits map[int]string) []string {
+	names := []string{}
+	for i, octet := range value {
+		for j := 0; j < 8; j++ {
+			if octet&(0x80>>j) == 0 {
+				continue
+			}
+			position := i*8 + j
+			if name, found := bits[position]; found {
+				names = append(names, name)
+			} else {
+				names = append(names, strconv.Itoa(position))
+			}
+		}
+	}
+	return names
+}
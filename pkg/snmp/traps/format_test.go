@@ -28,7 +28,7 @@ func createTestPacket() *SnmpPacket {
 func TestFormatPacketToJSON(t *testing.T) {
 	packet := createTestPacket()
 
-	data, err := FormatPacketToJSON(packet)
+	data, err := FormatPacketToJSON(packet, nil)
 	require.NoError(t, err)
 
 	assert.Equal(t, "1.3.6.1.4.1.8072.2.3.0.1", data["oid"])
@@ -49,13 +49,56 @@ func TestFormatPacketToJSON(t *testing.T) {
 	assert.Equal(t, heartBeatName["value"], "test")
 }
 
+func TestFormatPacketToJSONWithResolver(t *testing.T) {
+	packet := createTestPacket()
+	packet.Content.Variables = []gosnmp.SnmpPDU{
+		{Name: "1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(1000)},
+		{Name: "1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.OctetString, Value: "1.3.6.1.6.3.1.1.5.3"},
+		{Name: "1.3.6.1.2.1.2.2.1.1.12", Type: gosnmp.Integer, Value: 12},
+		{Name: "1.3.6.1.2.1.2.2.1.7.12", Type: gosnmp.Integer, Value: 1},
+		{Name: "1.3.6.1.2.1.2.2.1.8.12", Type: gosnmp.Integer, Value: 7},
+		{Name: "1.3.6.1.4.1.99999.1.1.0", Type: gosnmp.Integer, Value: 42},
+		{Name: "1.3.6.1.4.1.99999.1.2.0", Type: gosnmp.OctetString, Value: []byte{0xc0, 0x60}},
+		{Name: "1.3.6.1.4.1.99999.2.1.0", Type: gosnmp.OctetString, Value: []byte("unknown")},
+	}
+
+	data, err := FormatPacketToJSON(packet, newTestResolver(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.3.6.1.6.3.1.1.5.3", data["oid"])
+	assert.Equal(t, "linkDown", data["name"])
+	assert.Equal(t, "IF-MIB", data["mib"])
+
+	assert.Equal(t, 12, data["ifIndex"])
+	assert.Equal(t, "up", data["ifAdminStatus"])
+	assert.Equal(t, "lowerLayerDown", data["ifOperStatus"])
+	// Unknown enum values are kept as is
+	assert.Equal(t, 42, data["customStatus"])
+	assert.Equal(t, []string{"power", "fan", "temperature", "10"}, data["customFlags"])
+
+	variables, ok := data["variables"].([]map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, 6, len(variables))
+
+	ifOperStatus := variables[2]
+	assert.Equal(t, "1.3.6.1.2.1.2.2.1.8.12", ifOperStatus["oid"])
+	assert.Equal(t, "ifOperStatus", ifOperStatus["name"])
+	assert.Equal(t, "integer", ifOperStatus["type"])
+	assert.Equal(t, 7, ifOperStatus["value"])
+
+	unknown := variables[5]
+	assert.Equal(t, "1.3.6.1.4.1.99999.2.1.0", unknown["oid"])
+	assert.NotContains(t, unknown, "name")
+	assert.Equal(t, "unknown", unknown["value"])
+}
+
 func TestFormatPacketToJSONShouldFailIfNotEnoughVariables(t *testing.T) {
 	packet := createTestPacket()
 
 	packet.Content.Variables = []gosnmp.SnmpPDU{
 		// No variables at all.
 	}
-	_, err := FormatPacketToJSON(packet)
+	_, err := FormatPacketToJSON(packet, nil)
 	require.Error(t, err)
 
 	packet.Content.Variables = []gosnmp.SnmpPDU{
@@ -64,7 +107,7 @@ func TestFormatPacketToJSONShouldFailIfNotEnoughVariables(t *testing.T) {
 		{Name: "1.3.6.1.4.1.8072.2.3.2.1", Type: gosnmp.Integer, Value: 1024},
 		{Name: "1.3.6.1.4.1.8072.2.3.2.2", Type: gosnmp.OctetString, Value: "test"},
 	}
-	_, err = FormatPacketToJSON(packet)
+	_, err = FormatPacketToJSON(packet, nil)
 	require.Error(t, err)
 
 	packet.Content.Variables = []gosnmp.SnmpPDU{
@@ -73,7 +116,7 @@ func TestFormatPacketToJSONShouldFailIfNotEnoughVariables(t *testing.T) {
 		{Name: "1.3.6.1.4.1.8072.2.3.2.1", Type: gosnmp.Integer, Value: 1024},
 		{Name: "1.3.6.1.4.1.8072.2.3.2.2", Type: gosnmp.OctetString, Value: "test"},
 	}
-	_, err = FormatPacketToJSON(packet)
+	_, err = FormatPacketToJSON(packet, nil)
 	require.Error(t, err)
 }
 
@@ -86,11 +129,22 @@ func TestGetTags(t *testing.T) {
 	})
 }
 
-func TestGetTagsForUnsupportedVersionShouldStillSucceed(t *testing.T) {
+func TestGetTagsV3(t *testing.T) {
 	packet := createTestPacket()
 	packet.Content.Version = gosnmp.Version3
 	packet.Content.Community = ""
 	tags := GetTags(packet)
+	assert.Equal(t, tags, []string{
+		"snmp_version:3",
+		"snmp_device:127.0.0.1",
+	})
+}
+
+func TestGetTagsForUnsupportedVersionShouldStillSucceed(t *testing.T) {
+	packet := createTestPacket()
+	packet.Content.Version = gosnmp.Version1
+	packet.Content.Community = ""
+	tags := GetTags(packet)
 	assert.Equal(t, tags, []string{
 		"snmp_version:unknown",
 		"snmp_device:127.0.0.1",
@@ -0,0 +1,200 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2020-present Datadog, Inc.
+
+package traps
+
+import (
+	"errors"
+	"net"
+	"sync/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/gosnmp/gosnmp"
+)
+
+const maxPacketSize = 4096
+
+// userV3Params associates a SNMPv3 user with the GoSNMP params used to decode its packets.
+type userV3Params struct {
+	user   UserV3
+	params *gosnmp.GoSNMP
+}
+
+// trapListener receives trap packets on a UDP socket.
+// The GoSNMP TrapListener only supports a single set of SNMPv3 security parameters,
+// so packets are decoded here, trying the parameters of each configured user.
+type trapListener struct {
+	config   *Config
+	packets  PacketsChannel
+	v2Params *gosnmp.GoSNMP
+	users    []userV3Params
+	conn     *net.UDPConn
+	finished chan struct{}
+	closing  int32
+}
+
+func newTrapListener(c *Config, packets PacketsChannel) (*trapListener, error) {
+	l := &trapListener{
+		config:   c,
+		packets:  packets,
+		v2Params: c.BuildV2Params(),
+		finished: make(chan struct{}),
+	}
+
+	for _, user := range c.Users {
+		params, err := user.BuildV3Params(c.Port)
+		if err != nil {
+			return nil, err
+		}
+		l.users = append(l.users, userV3Params{user: user, params: params})
+	}
+
+	return l, nil
+}
+
+// Listen binds the listener socket and starts receiving packets in the background.
+func (l *trapListener) Listen() error {
+	addr, err := net.ResolveUDPAddr("udp", l.config.Addr())
+	if err != nil {
+		return err
+	}
+
+	l.conn, err = net.ListenUDP("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Start listening for traps on %s", l.config.Addr())
+	go l.run()
+
+	return nil
+}
+
+func (l *trapListener) run() {
+	defer close(l.finished)
+
+	var buf [maxPacketSize]byte
+	for {
+		n, remote, err := l.conn.ReadFromUDP(buf[:])
+		if err != nil {
+			if atomic.LoadInt32(&l.closing) == 1 {
+				return
+			}
+			log.Debugf("Error reading packet on listener %s: %v", l.config.Addr(), err)
+			continue
+		}
+
+		msg := make([]byte, n)
+		copy(msg, buf[:n])
+		l.handlePacket(msg, remote)
+	}
+}
+
+func (l *trapListener) handlePacket(msg []byte, remote *net.UDPAddr) {
+	version, err := packetVersion(msg)
+	if err != nil {
+		log.Debugf("Invalid packet from %s on listener %s: %v", remote.String(), l.config.Addr(), err)
+		trapsPacketsDecodeErrors.Add(1)
+		return
+	}
+
+	var p *gosnmp.SnmpPacket
+	if version == gosnmp.Version3 {
+		p = l.decodeV3(msg)
+		if p == nil {
+			log.Warnf("Invalid credentials from %s on listener %s, dropping SNMPv3 packet", remote.String(), l.config.Addr())
+			trapsPacketsAuthErrors.Add(1)
+			return
+		}
+		trapsPacketsV3.Add(1)
+	} else {
+		p = l.v2Params.UnmarshalTrap(msg, false)
+		if p == nil {
+			log.Debugf("Failed to decode packet from %s on listener %s", remote.String(), l.config.Addr())
+			trapsPacketsDecodeErrors.Add(1)
+			return
+		}
+		if err := validateCredentials(p, l.config); err != nil {
+			log.Warnf("Invalid credentials from %s on listener %s, dropping packet", remote.String(), l.config.Addr())
+			trapsPacketsAuthErrors.Add(1)
+			return
+		}
+	}
+
+	log.Debugf("Packet received from %s on listener %s", remote.String(), l.config.Addr())
+	trapsPackets.Add(1)
+	l.packets <- &SnmpPacket{Content: p, Addr: remote}
+
+	if p.PDUType == gosnmp.InformRequest {
+		l.respondToInform(p, remote)
+	}
+}
+
+// decodeV3 decodes a SNMPv3 packet with the parameters of the first user that
+// can authenticate and decrypt it. It returns nil if there is no such user.
+func (l *trapListener) decodeV3(msg []byte) *gosnmp.SnmpPacket {
+	for _, u := range l.users {
+		// GoSNMP decrypts packets in place, each user needs its own copy.
+		buf := make([]byte, len(msg))
+		copy(buf, msg)
+
+		p := u.params.UnmarshalTrap(buf, false)
+		if p == nil {
+			continue
+		}
+		if err := validateUserCredentials(p, &u.user); err != nil {
+			continue
+		}
+		return p
+	}
+	return nil
+}
+
+// respondToInform acknowledges an Inform request by sending back the same
+// variables in a response PDU.
+// See: https://tools.ietf.org/html/rfc3416#section-4.2.7
+func (l *trapListener) respondToInform(p *gosnmp.SnmpPacket, remote *net.UDPAddr) {
+	response := *p
+	response.PDUType = gosnmp.GetResponse
+	response.Error = gosnmp.NoError
+	response.ErrorIndex = 0
+
+	out, err := response.MarshalMsg()
+	if err != nil {
+		log.Errorf("Failed to marshal Inform response for %s: %v", remote.String(), err)
+		return
+	}
+
+	if _, err := l.conn.WriteTo(out, remote); err != nil {
+		log.Errorf("Failed to send Inform response to %s: %v", remote.String(), err)
+	}
+}
+
+// Close stops the listener and waits for the packets loop to return.
+func (l *trapListener) Close() {
+	atomic.StoreInt32(&l.closing, 1)
+	l.conn.Close()
+	<-l.finished
+}
+
+// packetVersion reads the SNMP version from the header of a raw packet.
+// An SNMP message is a BER sequence starting with the version as an integer.
+// See: https://tools.ietf.org/html/rfc3416#section-3
+func packetVersion(msg []byte) (gosnmp.SnmpVersion, error) {
+	if len(msg) < 2 || gosnmp.PDUType(msg[0]) != gosnmp.Sequence {
+		return 0, errors.New("invalid packet header")
+	}
+
+	cursor := 2
+	if msg[1]&0x80 != 0 {
+		cursor += int(msg[1] & 0x7f)
+	}
+
+	if len(msg) < cursor+3 || gosnmp.Asn1BER(msg[cursor]) != gosnmp.Integer || msg[cursor+1] != 1 {
+		return 0, errors.New("invalid packet version")
+	}
+
+	return gosnmp.SnmpVersion(msg[cursor+2]), nil
+}
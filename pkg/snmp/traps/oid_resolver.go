@@ -0,0 +1,160 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2020-present Datadog, Inc.
+
+package traps
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"gopkg.in/yaml.v2"
+)
+
+// ddTrapDBPrefix is the prefix of the trap database files shipped with the Agent.
+// They are loaded first so that user provided files can override their definitions.
+const ddTrapDBPrefix = "dd_traps_db"
+
+// TrapMetadata is the metadata of a trap, generated from a MIB NOTIFICATION-TYPE.
+type TrapMetadata struct {
+	Name        string `yaml:"name" json:"name"`
+	MIBName     string `yaml:"mib" json:"mib"`
+	Description string `yaml:"descr" json:"descr"`
+}
+
+// VariableMetadata is the metadata of a trap variable, generated from a MIB OBJECT-TYPE.
+// Enumeration and Bits map integer values and bit positions to their symbolic names.
+type VariableMetadata struct {
+	Name        string         `yaml:"name" json:"name"`
+	Description string         `yaml:"descr" json:"descr"`
+	Enumeration map[int]string `yaml:"enum" json:"enum"`
+	Bits        map[int]string `yaml:"bits" json:"bits"`
+}
+
+// trapDBFileContent is the content of a compiled trap database file.
+// Traps and variables are indexed by their OID in relative form.
+type trapDBFileContent struct {
+	Traps     map[string]TrapMetadata     `yaml:"traps" json:"traps"`
+	Variables map[string]VariableMetadata `yaml:"vars" json:"vars"`
+}
+
+// OIDResolver resolves trap and variable OIDs to their symbolic names.
+type OIDResolver interface {
+	GetTrapMetadata(trapOID string) (TrapMetadata, error)
+	GetVariableMetadata(varOID string) (VariableMetadata, error)
+}
+
+// MultiFilesOIDResolver is an OIDResolver backed by the trap database files of a directory.
+type MultiFilesOIDResolver struct {
+	traps     map[string]TrapMetadata
+	variables map[string]VariableMetadata
+}
+
+// GetTrapDBDirectory returns the directory containing the trap database files.
+func GetTrapDBDirectory() string {
+	return filepath.Join(config.Datadog.GetString("confd_path"), "snmp.d", "traps_db")
+}
+
+// NewMultiFilesOIDResolver loads the trap database files of the given directory and returns
+// a resolver for them. JSON and YAML files are supported.
+func NewMultiFilesOIDResolver(directory string) (*MultiFilesOIDResolver, error) {
+	resolver := &MultiFilesOIDResolver{
+		traps:     make(map[string]TrapMetadata),
+		variables: make(map[string]VariableMetadata),
+	}
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trap database directory `%s`: %w", directory, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		files = append(files, entry.Name())
+	}
+
+	// Files shipped with the Agent are loaded first, the others in lexicographical order.
+	sort.SliceStable(files, func(i, j int) bool {
+		iDD, jDD := strings.HasPrefix(files[i], ddTrapDBPrefix), strings.HasPrefix(files[j], ddTrapDBPrefix)
+		if iDD != jDD {
+			return iDD
+		}
+		return files[i] < files[j]
+	})
+
+	for _, file := range files {
+		if err := resolver.loadFile(filepath.Join(directory, file)); err != nil {
+			log.Warnf("Unable to load trap database file `%s`: %v", file, err)
+			continue
+		}
+		log.Debugf("Loaded trap database file `%s`", file)
+	}
+
+	return resolver, nil
+}
+
+func (r *MultiFilesOIDResolver) loadFile(path string) error {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var content trapDBFileContent
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(buf, &content)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(buf, &content)
+	default:
+		return fmt.Errorf("unsupported file extension, expected .json, .yaml or .yml")
+	}
+	if err != nil {
+		return err
+	}
+
+	for oid, trap := range content.Traps {
+		r.traps[normalizeOID(oid)] = trap
+	}
+	for oid, variable := range content.Variables {
+		r.variables[normalizeOID(oid)] = variable
+	}
+
+	return nil
+}
+
+// GetTrapMetadata returns the metadata of the given trap OID.
+func (r *MultiFilesOIDResolver) GetTrapMetadata(trapOID string) (TrapMetadata, error) {
+	trap, ok := r.traps[normalizeOID(trapOID)]
+	if !ok {
+		return TrapMetadata{}, fmt.Errorf("trap OID %s is not defined", trapOID)
+	}
+	return trap, nil
+}
+
+// GetVariableMetadata returns the metadata of the given variable OID. Variable OIDs
+// usually include an instance suffix (e.g. the index of a table row), so the longest
+// known prefix of the OID is used.
+func (r *MultiFilesOIDResolver) GetVariableMetadata(varOID string) (VariableMetadata, error) {
+	oid := normalizeOID(varOID)
+	for {
+		if variable, ok := r.variables[oid]; ok {
+			return variable, nil
+		}
+
+		lastDot := strings.LastIndex(oid, ".")
+		if lastDot == -1 {
+			return VariableMetadata{}, fmt.Errorf("variable OID %s is not defined", varOID)
+		}
+		oid = oid[:lastDot]
+	}
+}
@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2020-present Datadog, Inc.
+
+package traps
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestResolver(t *testing.T) *MultiFilesOIDResolver {
+	resolver, err := NewMultiFilesOIDResolver(filepath.Join("testdata", "traps_db"))
+	require.NoError(t, err)
+	return resolver
+}
+
+func TestResolveTrap(t *testing.T) {
+	resolver := newTestResolver(t)
+
+	trap, err := resolver.GetTrapMetadata("1.3.6.1.6.3.1.1.5.3")
+	assert.NoError(t, err)
+	assert.Equal(t, "linkDown", trap.Name)
+	assert.Equal(t, "IF-MIB", trap.MIBName)
+
+	trap, err = resolver.GetTrapMetadata(".1.3.6.1.4.1.99999.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "customStatusChange", trap.Name)
+
+	_, err = resolver.GetTrapMetadata("1.3.6.1.4.1.99999.0.2")
+	assert.Error(t, err)
+}
+
+func TestResolveTrapUserFilesOverrideDatadogFiles(t *testing.T) {
+	resolver := newTestResolver(t)
+
+	trap, err := resolver.GetTrapMetadata("1.3.6.1.4.1.8072.2.3.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "customHeartbeat", trap.Name)
+	assert.Equal(t, "CUSTOM-MIB", trap.MIBName)
+}
+
+func TestResolveVariable(t *testing.T) {
+	resolver := newTestResolver(t)
+
+	// Column of a table, with the index of the row
+	variable, err := resolver.GetVariableMetadata("1.3.6.1.2.1.2.2.1.8.12")
+	assert.NoError(t, err)
+	assert.Equal(t, "ifOperStatus", variable.Name)
+	assert.Equal(t, "lowerLayerDown", variable.Enumeration[7])
+
+	// Scalar instance
+	variable, err = resolver.GetVariableMetadata(".1.3.6.1.4.1.99999.1.2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "customFlags", variable.Name)
+	assert.Equal(t, "temperature", variable.Bits[9])
+
+	_, err = resolver.GetVariableMetadata("1.3.6.1.4.1.99999.2.1")
+	assert.Error(t, err)
+}
+
+func TestResolverMissingDirectory(t *testing.T) {
+	_, err := NewMultiFilesOIDResolver(filepath.Join("testdata", "missing"))
+	assert.Error(t, err)
+}
@@ -22,12 +22,13 @@ type SnmpPacket struct {
 // PacketsChannel is the type of channels of trap packets.
 type PacketsChannel = chan *SnmpPacket
 
-// TrapServer manages an SNMPv2 trap listener.
+// TrapServer manages an SNMPv2 and SNMPv3 trap listener.
 type TrapServer struct {
 	Addr     string
 	config   *Config
-	listener *gosnmp.TrapListener
+	listener *trapListener
 	packets  PacketsChannel
+	resolver OIDResolver
 }
 
 var (
@@ -62,6 +63,14 @@ func GetPacketsChannel() PacketsChannel {
 	return serverInstance.packets
 }
 
+// GetOIDResolver returns the resolver of trap OIDs of the global trap server, if any.
+func GetOIDResolver() OIDResolver {
+	if serverInstance == nil {
+		return nil
+	}
+	return serverInstance.resolver
+}
+
 // NewTrapServer configures and returns a running SNMP traps server.
 func NewTrapServer() (*TrapServer, error) {
 	config, err := ReadConfig()
@@ -71,7 +80,7 @@ func NewTrapServer() (*TrapServer, error) {
 
 	packets := make(PacketsChannel, packetsChanSize)
 
-	listener, err := startSNMPv2Listener(config, packets)
+	listener, err := startSNMPListener(config, packets)
 	if err != nil {
 		return nil, err
 	}
@@ -82,44 +91,24 @@ func NewTrapServer() (*TrapServer, error) {
 		packets:  packets,
 	}
 
+	resolver, err := NewMultiFilesOIDResolver(GetTrapDBDirectory())
+	if err != nil {
+		log.Warnf("Trap OIDs won't be resolved: %v", err)
+	} else {
+		server.resolver = resolver
+	}
+
 	return server, nil
 }
 
-func startSNMPv2Listener(c *Config, packets PacketsChannel) (*gosnmp.TrapListener, error) {
-	listener := gosnmp.NewTrapListener()
-	listener.Params = c.BuildV2Params()
-
-	listener.OnNewTrap = func(p *gosnmp.SnmpPacket, u *net.UDPAddr) {
-		if err := validateCredentials(p, c); err != nil {
-			log.Warnf("Invalid credentials from %s on listener %s, dropping packet", u.String(), c.Addr())
-			trapsPacketsAuthErrors.Add(1)
-			return
-		}
-		log.Debugf("Packet received from %s on listener %s", u.String(), c.Addr())
-		trapsPackets.Add(1)
-		packets <- &SnmpPacket{Content: p, Addr: u}
+func startSNMPListener(c *Config, packets PacketsChannel) (*trapListener, error) {
+	listener, err := newTrapListener(c, packets)
+	if err != nil {
+		return nil, err
 	}
 
-	errors := make(chan error, 1)
-
-	// Start actually listening in the background.
-	go func() {
-		log.Infof("Start listening for traps on %s", c.Addr())
-		err := listener.Listen(c.Addr())
-		if err != nil {
-			errors <- err
-		}
-	}()
-
-	select {
-	// Wait for listener to be started and listening to traps.
-	// See: https://godoc.org/github.com/gosnmp/gosnmp#TrapListener.Listening
-	case <-listener.Listening():
-		break
-	// If the listener failed to start (eg because it couldn't bind to a socket),
-	// we'll get an error here.
-	case err := <-errors:
-		close(errors)
+	// Listen fails if the listener couldn't bind to its socket.
+	if err := listener.Listen(); err != nil {
 		return nil, err
 	}
 
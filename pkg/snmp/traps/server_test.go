@@ -56,3 +56,93 @@ func TestStartFailure(t *testing.T) {
 	require.Nil(t, failedServer)
 	require.Error(t, err)
 }
+
+var testEngineID = "\x80\x00\x1f\x88\x80\x10\x6b\x6a\x3d\x5c\x82\x4d\x61"
+
+func TestServerV3(t *testing.T) {
+	users := []UserV3{
+		{Username: "noauth"},
+		{Username: "authonly", AuthKey: "password", AuthProtocol: "sha"},
+		{Username: "authpriv", AuthKey: "password", AuthProtocol: "sha256", PrivKey: "private", PrivProtocol: "aes"},
+		{Username: "authprivdes", AuthKey: "password", AuthProtocol: "md5", PrivKey: "private", PrivProtocol: "des"},
+	}
+	config := Config{Port: GetPort(t), Users: users}
+	Configure(t, config)
+
+	err := StartServer()
+	require.NoError(t, err)
+	defer StopServer()
+
+	for _, user := range users {
+		t.Run(user.Username, func(t *testing.T) {
+			sendTestV3Trap(t, config, user, testEngineID)
+			packet := receivePacket(t)
+			require.NotNil(t, packet)
+			assertIsValidV3Packet(t, packet, user)
+			assertV2Variables(t, packet)
+		})
+	}
+}
+
+func TestServerV2AndV3(t *testing.T) {
+	user := UserV3{Username: "user", AuthKey: "password", AuthProtocol: "sha", PrivKey: "private", PrivProtocol: "aes"}
+	config := Config{Port: GetPort(t), CommunityStrings: []string{"public"}, Users: []UserV3{user}}
+	Configure(t, config)
+
+	err := StartServer()
+	require.NoError(t, err)
+	defer StopServer()
+
+	sendTestV2Trap(t, config, "public")
+	packet := receivePacket(t)
+	require.NotNil(t, packet)
+	assertIsValidV2Packet(t, packet, config)
+
+	sendTestV3Trap(t, config, user, testEngineID)
+	packet = receivePacket(t)
+	require.NotNil(t, packet)
+	assertIsValidV3Packet(t, packet, user)
+}
+
+func TestServerV3BadCredentials(t *testing.T) {
+	user := UserV3{Username: "user", AuthKey: "password", AuthProtocol: "sha", PrivKey: "private", PrivProtocol: "aes"}
+	config := Config{Port: GetPort(t), Users: []UserV3{user}}
+	Configure(t, config)
+
+	err := StartServer()
+	require.NoError(t, err)
+	defer StopServer()
+
+	badCredentials := []UserV3{
+		// Unknown user
+		{Username: "unknown", AuthKey: "password", AuthProtocol: "sha", PrivKey: "private", PrivProtocol: "aes"},
+		// Wrong authentication key
+		{Username: "user", AuthKey: "wrong-password", AuthProtocol: "sha", PrivKey: "private", PrivProtocol: "aes"},
+		// Lower security level than configured
+		{Username: "user", AuthKey: "password", AuthProtocol: "sha"},
+		{Username: "user"},
+	}
+
+	for _, badUser := range badCredentials {
+		sendTestV3Trap(t, config, badUser, testEngineID)
+		assertNoPacketReceived(t)
+	}
+}
+
+func TestServerV3EngineID(t *testing.T) {
+	user := UserV3{Username: "user", AuthKey: "password", AuthProtocol: "sha", EngineID: "80001f8880106b6a3d5c824d61"}
+	config := Config{Port: GetPort(t), Users: []UserV3{user}}
+	Configure(t, config)
+
+	err := StartServer()
+	require.NoError(t, err)
+	defer StopServer()
+
+	sendTestV3Trap(t, config, user, testEngineID)
+	packet := receivePacket(t)
+	require.NotNil(t, packet)
+	assertIsValidV3Packet(t, packet, user)
+
+	sendTestV3Trap(t, config, user, "\x80\x00\x1f\x88\x04other")
+	assertNoPacketReceived(t)
+}
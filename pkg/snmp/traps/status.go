@@ -11,14 +11,18 @@ import (
 )
 
 var (
-	trapsExpvars           = expvar.NewMap("snmp_traps")
-	trapsPackets           = expvar.Int{}
-	trapsPacketsAuthErrors = expvar.Int{}
+	trapsExpvars             = expvar.NewMap("snmp_traps")
+	trapsPackets             = expvar.Int{}
+	trapsPacketsV3           = expvar.Int{}
+	trapsPacketsAuthErrors   = expvar.Int{}
+	trapsPacketsDecodeErrors = expvar.Int{}
 )
 
 func init() {
 	trapsExpvars.Set("Packets", &trapsPackets)
+	trapsExpvars.Set("PacketsV3", &trapsPacketsV3)
 	trapsExpvars.Set("PacketsAuthErrors", &trapsPacketsAuthErrors)
+	trapsExpvars.Set("PacketsDecodeErrors", &trapsPacketsDecodeErrors)
 }
 
 // GetStatus returns key-value data for use in status reporting of the traps server.
@@ -87,6 +87,29 @@ func sendTestV2Trap(t *testing.T, trapConfig Config, community string) *gosnmp.G
 	return params
 }
 
+func sendTestV3Trap(t *testing.T, trapConfig Config, user UserV3, engineID string) *gosnmp.GoSNMP {
+	params, err := user.BuildV3Params(trapConfig.Port)
+	require.NoError(t, err)
+	params.Timeout = 1 * time.Second // Must be non-zero when sending traps.
+	params.Retries = 1               // Must be non-zero when sending traps.
+
+	// The sender of a trap is the authoritative SNMP engine.
+	securityParams := params.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	securityParams.AuthoritativeEngineID = engineID
+	securityParams.AuthoritativeEngineBoots = 1
+	securityParams.AuthoritativeEngineTime = uint32(time.Now().Unix())
+
+	err = params.Connect()
+	require.NoError(t, err)
+	defer params.Conn.Close()
+
+	trap := gosnmp.SnmpTrap{Variables: NetSNMPExampleHeartbeatNotificationVariables}
+	_, err = params.SendTrap(trap)
+	require.NoError(t, err)
+
+	return params
+}
+
 // receivePacket waits for a received trap packet and returns it.
 func receivePacket(t *testing.T) *SnmpPacket {
 	select {
@@ -109,6 +132,11 @@ func assertIsValidV2Packet(t *testing.T, packet *SnmpPacket, trapConfig Config)
 	require.True(t, communityValid)
 }
 
+func assertIsValidV3Packet(t *testing.T, packet *SnmpPacket, user UserV3) {
+	require.Equal(t, gosnmp.Version3, packet.Content.Version)
+	require.NoError(t, validateUserCredentials(packet.Content, &user))
+}
+
 func assertV2Variables(t *testing.T, packet *SnmpPacket) {
 	variables := packet.Content.Variables
 	assert.Equal(t, 4, len(variables))
@@ -130,3 +130,20 @@ func TestStructToMap(t *testing.T) {
 		},
 	}, StructToMap(top))
 }
+
+func TestCut(t *testing.T) {
+	before, after, found := Cut("Package: curl", ":")
+	assert.Equal(t, "Package", before)
+	assert.Equal(t, " curl", after)
+	assert.True(t, found)
+
+	before, after, found = Cut("OK - load|load1=0.5;1;2|", "|")
+	assert.Equal(t, "OK - load", before)
+	assert.Equal(t, "load1=0.5;1;2|", after)
+	assert.True(t, found)
+
+	before, after, found = Cut("no separator", "|")
+	assert.Equal(t, "no separator", before)
+	assert.Equal(t, "", after)
+	assert.False(t, found)
+}
@@ -0,0 +1,18 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package common
+
+import "strings"
+
+// Cut slices s around the first instance of sep, returning the text before and after sep.
+// The found result reports whether sep appears in s. If sep does not appear in s, Cut
+// returns s, "", false. It behaves like strings.Cut, which isn't available in go1.16.
+func Cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
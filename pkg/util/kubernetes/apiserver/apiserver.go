@@ -31,8 +31,10 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 
 	apiv1 "github.com/DataDog/datadog-agent/pkg/clusteragent/api/v1"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers/names"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/cache"
+	"github.com/DataDog/datadog-agent/pkg/util/flavor"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/common"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/retry"
@@ -357,7 +359,9 @@ func (c *APIClient) connect() error {
 			return err
 		}
 	}
-	if config.Datadog.GetBool("external_metrics_provider.use_datadogmetric_crd") {
+	// the DatadogMetric and DatadogCheck custom resources share the informers of the datadoghq types,
+	// which are only watched by the cluster agent
+	if config.Datadog.GetBool("external_metrics_provider.use_datadogmetric_crd") || (flavor.GetFlavor() == flavor.ClusterAgent && config.IsConfigProviderEnabled(names.KubeCRDRegisterName)) {
 		if c.DDInformerFactory, err = getDDInformerFactory(); err != nil {
 			log.Errorf("Error getting datadoghq Client: %s", err.Error())
 			return err
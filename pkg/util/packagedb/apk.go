@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package packagedb
+
+import (
+	"io"
+)
+
+// ParseApkInstalled parses the apk installed database and returns the installed packages.
+// Each field of a stanza is on a "K:value" line where K is a single letter.
+// See: https://wiki.alpinelinux.org/wiki/Apk_spec#Installed_Database_V2
+func ParseApkInstalled(r io.Reader) ([]Package, error) {
+	var pkgs []Package
+
+	err := parseStanzas(r, func(lines []string) {
+		var pkg Package
+		for _, line := range lines {
+			if len(line) < 2 || line[1] != ':' {
+				continue
+			}
+			switch value := line[2:]; line[0] {
+			case 'P':
+				pkg.Name = value
+			case 'V':
+				pkg.Version = value
+			case 'A':
+				pkg.Arch = value
+			case 'o':
+				pkg.Source = value
+			}
+		}
+
+		if pkg.Name == "" {
+			return
+		}
+		if pkg.Source == "" {
+			pkg.Source = pkg.Name
+		}
+		pkgs = append(pkgs, pkg)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgs, nil
+}
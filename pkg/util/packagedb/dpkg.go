@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package packagedb
+
+import (
+	"io"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/common"
+)
+
+// ParseDpkgStatus parses the dpkg status database and returns the installed packages.
+// Each field of a stanza is on a "Field: value" line, optionally followed by continuation
+// lines starting with a space.
+// See: https://man7.org/linux/man-pages/man1/dpkg-query.1.html
+func ParseDpkgStatus(r io.Reader) ([]Package, error) {
+	var pkgs []Package
+
+	err := parseStanzas(r, func(lines []string) {
+		fields := make(map[string]string)
+		for _, line := range lines {
+			// Continuation lines are only used by fields that are not collected
+			if line[0] == ' ' || line[0] == '\t' {
+				continue
+			}
+			if key, value, ok := common.Cut(line, ":"); ok {
+				fields[key] = strings.TrimSpace(value)
+			}
+		}
+
+		// Packages removed but not purged are still in the database, with a status
+		// such as "deinstall ok config-files".
+		status := strings.Fields(fields["Status"])
+		if fields["Package"] == "" || len(status) != 3 || status[2] != "installed" {
+			return
+		}
+		pkgs = append(pkgs, Package{
+			Name:    fields["Package"],
+			Version: fields["Version"],
+			Arch:    fields["Architecture"],
+			Source:  dpkgSourceName(fields["Source"], fields["Package"]),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgs, nil
+}
+
+// dpkgSourceName returns the name of the source package from the Source field,
+// which contains the source version when it differs from the package version, e.g. "openssl (1.1.1n-0)".
+// The Source field is omitted when the source package has the same name as the package.
+func dpkgSourceName(source string, name string) string {
+	if source == "" {
+		return name
+	}
+	return strings.Fields(source)[0]
+}
@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package packagedb reads the databases of the package managers of a host to list
+// the packages installed on it.
+package packagedb
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+)
+
+const (
+	// DpkgStatusPath is the path of the dpkg status database
+	DpkgStatusPath = "/var/lib/dpkg/status"
+	// ApkInstalledPath is the path of the apk installed database
+	ApkInstalledPath = "/lib/apk/db/installed"
+	// RpmDatabasePath is the path of the rpm database directory
+	RpmDatabasePath = "/var/lib/rpm"
+)
+
+// ErrRPMDatabaseUnsupported is returned on hosts using rpm, whose database can't be read natively
+var ErrRPMDatabaseUnsupported = errors.New("reading the rpm package database is not supported")
+
+// Package is a package installed on the host
+type Package struct {
+	Name    string
+	Version string
+	Arch    string
+	// Source is the source package the package was built from
+	Source string
+}
+
+// ReadFile opens the database at path and parses it with parser
+func ReadFile(path string, parser func(io.Reader) ([]Package, error)) ([]Package, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parser(f)
+}
+
+// parseStanzas calls fn with the lines of each stanza of a database made of stanzas
+// separated by empty lines, which is the format of both the dpkg and apk databases.
+func parseStanzas(r io.Reader, fn func(lines []string)) error {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(lines) > 0 {
+				fn(lines)
+			}
+			lines = lines[:0]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(lines) > 0 {
+		fn(lines)
+	}
+
+	return nil
+}
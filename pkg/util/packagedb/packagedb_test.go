@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package packagedb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDpkgStatus(t *testing.T) {
+	pkgs, err := ReadFile("testdata/dpkg/var/lib/dpkg/status", ParseDpkgStatus)
+	require.NoError(t, err)
+	assert.Equal(t, []Package{
+		{Name: "libssl1.1", Version: "1.1.1n-0+deb11u3", Arch: "amd64", Source: "openssl"},
+		{Name: "bash", Version: "5.1-2+deb11u1", Arch: "amd64", Source: "bash"},
+		{Name: "libsystemd0", Version: "247.3-7+deb11u1", Arch: "amd64", Source: "systemd"},
+	}, pkgs)
+}
+
+func TestParseApkInstalled(t *testing.T) {
+	pkgs, err := ReadFile("testdata/apk/lib/apk/db/installed", ParseApkInstalled)
+	require.NoError(t, err)
+	assert.Equal(t, []Package{
+		{Name: "musl", Version: "1.2.3-r0", Arch: "x86_64", Source: "musl"},
+		{Name: "libcrypto1.1", Version: "1.1.1q-r0", Arch: "x86_64", Source: "openssl"},
+	}, pkgs)
+}
+
+func TestParseStanzas(t *testing.T) {
+	var stanzas [][]string
+	err := parseStanzas(strings.NewReader("a\nb\n\n\nc\n"), func(lines []string) {
+		stanzas = append(stanzas, append([]string{}, lines...))
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, stanzas)
+}
+
+func TestReadFileMissing(t *testing.T) {
+	_, err := ReadFile("testdata/missing", ParseDpkgStatus)
+	assert.Error(t, err)
+}
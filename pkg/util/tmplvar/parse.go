@@ -16,6 +16,9 @@ var tmplVarRegex = regexp.MustCompile(`%%.+?%%`)
 // TemplateVar is the info for a parsed template variable.
 type TemplateVar struct {
 	Raw, Name, Key []byte
+	// Modifiers are the `|` separated values following the variable, e.g. `%%port_http|default:9090%%`,
+	// trimmed of their surrounding spaces
+	Modifiers [][]byte
 }
 
 // ParseString returns parsed template variables found in the input string.
@@ -28,21 +31,25 @@ func Parse(b []byte) []TemplateVar {
 	var parsed []TemplateVar
 	vars := tmplVarRegex.FindAll(b, -1)
 	for _, v := range vars {
-		name, key := parseTemplateVar(v)
-		parsed = append(parsed, TemplateVar{v, name, key})
+		name, key, modifiers := parseTemplateVar(v)
+		parsed = append(parsed, TemplateVar{v, name, key, modifiers})
 	}
 	return parsed
 }
 
-// parseTemplateVar extracts the name of the var and the key (or index if it can be
-// cast to an int)
-func parseTemplateVar(v []byte) (name, key []byte) {
+// parseTemplateVar extracts the name of the var, the key (or index if it can be
+// cast to an int) and the modifiers, which may contain spaces and `%`
+func parseTemplateVar(v []byte) (name, key []byte, modifiers [][]byte) {
+	pipes := bytes.Split(bytes.TrimSuffix(bytes.TrimPrefix(v, []byte("%%")), []byte("%%")), []byte("|"))
+	for _, modifier := range pipes[1:] {
+		modifiers = append(modifiers, bytes.TrimSpace(modifier))
+	}
 	stripped := bytes.Map(func(r rune) rune {
 		if unicode.IsSpace(r) || r == '%' {
 			return -1
 		}
 		return r
-	}, v)
+	}, pipes[0])
 	split := bytes.SplitN(stripped, []byte("_"), 2)
 	name = split[0]
 	if len(split) == 2 {
@@ -50,5 +57,5 @@ func parseTemplateVar(v []byte) (name, key []byte) {
 	} else {
 		key = []byte("")
 	}
-	return name, key
+	return name, key, modifiers
 }
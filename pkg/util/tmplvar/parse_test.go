@@ -15,39 +15,74 @@ import (
 func TestParseTemplateVar(t *testing.T) {
 	testCases := []struct {
 		tmpl, name, key string
+		modifiers       []string
 	}{
 		{
 			"%%host%%",
 			"host",
 			"",
+			nil,
 		},
 		{
 			"%%host_0%%",
 			"host",
 			"0",
+			nil,
 		},
 		{
 			"%%host 0%%",
 			"host0",
 			"",
+			nil,
 		},
 		{
 			"%%host_0_1%%",
 			"host",
 			"0_1",
+			nil,
 		},
 		{
 			"%%host_network_name%%",
 			"host",
 			"network_name",
+			nil,
+		},
+		{
+			"%%port_http|default:9090%%",
+			"port",
+			"http",
+			[]string{"default:9090"},
+		},
+		{
+			"%%label_app | lower | urlencode%%",
+			"label",
+			"app",
+			[]string{"lower", "urlencode"},
+		},
+		{
+			"%%label_team|default:%%",
+			"label",
+			"team",
+			[]string{"default:"},
+		},
+		{
+			"%%label_motd | default:100% up time%%",
+			"label",
+			"motd",
+			[]string{"default:100% up time"},
 		},
 	}
 
 	for i, testCase := range testCases {
 		t.Run(fmt.Sprintf("#%d", i), func(t *testing.T) {
-			name, key := parseTemplateVar([]byte(testCase.tmpl))
+			name, key, modifiers := parseTemplateVar([]byte(testCase.tmpl))
 			assert.Equal(t, testCase.name, string(name))
 			assert.Equal(t, testCase.key, string(key))
+			var modifierStrings []string
+			for _, modifier := range modifiers {
+				modifierStrings = append(modifierStrings, string(modifier))
+			}
+			assert.Equal(t, testCase.modifiers, modifierStrings)
 		})
 	}
 }